@@ -0,0 +1,34 @@
+package backoff
+
+import (
+	"math/rand"
+	"time"
+)
+
+// MaxDelay caps the backoff window, so a long retry budget keeps retrying
+// every half minute or so instead of sleeping for ever longer stretches.
+// A configured base delay above it is used as the cap instead.
+const MaxDelay = 30 * time.Second
+
+// Cap is the longest delay WithJitter picks for base: MaxDelay, or base
+// itself when it is longer
+func Cap(base time.Duration) time.Duration {
+	return max(base, MaxDelay)
+}
+
+// WithJitter doubles base for each attempt, up to MaxDelay, and picks a
+// random delay in the upper half of that window, so concurrent callers
+// don't retry in lockstep
+func WithJitter(base time.Duration, attempt int) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+	limit := Cap(base)
+	window := base
+	for i := 0; i < attempt && window < limit; i++ {
+		window *= 2
+	}
+	window = min(window, limit)
+	half := window / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
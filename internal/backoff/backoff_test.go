@@ -0,0 +1,34 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithJitter(t *testing.T) {
+	base := 100 * time.Millisecond
+	for attempt, window := range []time.Duration{base, 2 * base, 4 * base, 8 * base} {
+		for i := 0; i < 50; i++ {
+			if d := WithJitter(base, attempt); d < window/2 || d > window {
+				t.Fatalf("attempt %d: delay %v outside [%v, %v]", attempt, d, window/2, window)
+			}
+		}
+	}
+
+	if d := WithJitter(0, 3); d != 0 {
+		t.Errorf("zero base delay = %v, want 0", d)
+	}
+}
+
+func TestWithJitter_MaxDelay(t *testing.T) {
+	for _, attempt := range []int{10, 40, 100} {
+		if d := WithJitter(time.Second, attempt); d < MaxDelay/2 || d > MaxDelay {
+			t.Errorf("attempt %d: delay %v outside [%v, %v]", attempt, d, MaxDelay/2, MaxDelay)
+		}
+	}
+
+	// A base above the cap is kept rather than cut down
+	if d := WithJitter(time.Minute, 5); d < 30*time.Second || d > time.Minute {
+		t.Errorf("delay %v outside [30s, 1m] for a one-minute base", d)
+	}
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/akrishnanDG/glue-to-ccsr/internal/migrator"
+	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// NewApplyCmd creates the apply command
+func NewApplyCmd() *cobra.Command {
+	var configFile string
+	var allowProd bool
+
+	cmd := &cobra.Command{
+		Use:   "apply <plan-file>",
+		Short: "Register the schemas of a plan written by the plan command",
+		Long: `Run a migration plan written by the plan command. Schemas are registered
+level by level with the plan's target subjects, skipping extraction and
+mapping, so edits to the plan take effect. Each schema's versions are still
+read from the source while it is registered, so the config's aws settings
+must give access to it, and its confluent_cloud settings name the target.
+
+  glue-to-ccsr apply plan.yaml --config config.yaml
+
+Checkpointing, resume, output.dry_run and the report settings behave as they
+do for migrate.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.NewDefaultConfig()
+			if configFile != "" {
+				loadedCfg, err := config.LoadFromFile(configFile)
+				if err != nil {
+					return fmt.Errorf("failed to load config file: %w", err)
+				}
+				cfg = loadedCfg
+			}
+
+			plan, err := migrator.LoadPlan(args[0])
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			return runMigrator(ctx, cfg, allowProd, func(ctx context.Context, m *migrator.Migrator) (*migrator.Result, error) {
+				return m.Apply(ctx, plan)
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&configFile, "config", "c", "", "Config file path")
+	cmd.Flags().BoolVar(&allowProd, "allow-prod", false, "Allow applying against a confluent_cloud.environment: prod target")
+
+	return cmd
+}
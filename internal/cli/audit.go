@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/akrishnanDG/glue-to-ccsr/internal/mapper"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/report"
+	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// NewNormalizeAuditCmd creates the normalize-audit command
+func NewNormalizeAuditCmd() *cobra.Command {
+	var configFile string
+	var reportFile string
+	var format string
+	var alternate config.NormalizationConfig
+
+	cmd := &cobra.Command{
+		Use:   "normalize-audit",
+		Short: "Show subjects that would newly collide under different normalization settings",
+		Long: `Map the schemas listed in a prior JSON migration report under the current
+normalization settings and under an alternate, and report subjects that are
+distinct today but would collide with the alternate settings. The report
+supplies the schema names, so no AWS or Schema Registry calls are made.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.NewDefaultConfig()
+			if configFile != "" {
+				loadedCfg, err := config.LoadFromFile(configFile)
+				if err != nil {
+					return fmt.Errorf("failed to load config file: %w", err)
+				}
+				cfg = loadedCfg
+			}
+			if format != "table" && format != "json" {
+				return fmt.Errorf("unsupported format: %s (expected table or json)", format)
+			}
+
+			alt := cfg.Normalization
+			changed := false
+			flags := cmd.Flags()
+			if flags.Changed("normalize-case") {
+				alt.NormalizeCase = alternate.NormalizeCase
+				changed = true
+			}
+			if flags.Changed("normalize-dots") {
+				alt.NormalizeDots = alternate.NormalizeDots
+				changed = true
+			}
+			if flags.Changed("dot-replacement") {
+				alt.DotReplacement = alternate.DotReplacement
+				changed = true
+			}
+			if flags.Changed("invalid-char-replacement") {
+				alt.InvalidCharReplacement = alternate.InvalidCharReplacement
+				changed = true
+			}
+			if !changed {
+				return fmt.Errorf("no alternate setting given (use --normalize-case, --normalize-dots, --dot-replacement or --invalid-char-replacement)")
+			}
+
+			prior, err := report.Load(reportFile)
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			collisions, err := mapper.AuditNormalization(ctx, cfg, alt, reportSchemas(prior))
+			if err != nil {
+				return err
+			}
+			return writeNormalizeAudit(cmd.OutOrStdout(), collisions, format)
+		},
+	}
+
+	cmd.Flags().StringVarP(&configFile, "config", "c", "", "Config file path")
+	cmd.Flags().StringVar(&reportFile, "report", "", "Prior JSON migration report listing the schemas (required)")
+	cmd.Flags().StringVar(&alternate.NormalizeCase, "normalize-case", "", "Alternate case normalization: keep, kebab, snake, lower")
+	cmd.Flags().StringVar(&alternate.NormalizeDots, "normalize-dots", "", "Alternate dot handling: keep, replace, extract-last")
+	cmd.Flags().StringVar(&alternate.DotReplacement, "dot-replacement", "", "Alternate dot replacement character")
+	cmd.Flags().StringVar(&alternate.InvalidCharReplacement, "invalid-char-replacement", "", "Alternate invalid character replacement")
+	cmd.Flags().StringVar(&format, "format", "table", "Output format: table, json")
+	cmd.MarkFlagRequired("report")
+
+	return cmd
+}
+
+// reportSchemas rebuilds the source schemas listed in a report; definitions
+// are not recorded, so naming relies on schema names alone
+func reportSchemas(r *models.MigrationReport) []*models.GlueSchema {
+	schemas := make([]*models.GlueSchema, 0, len(r.Schemas))
+	for _, s := range r.Schemas {
+		schemas = append(schemas, &models.GlueSchema{
+			RegistryName: s.SourceRegistry,
+			Name:         s.SourceSchema,
+			DataFormat:   models.SchemaType(s.SchemaType),
+		})
+	}
+	return schemas
+}
+
+// writeNormalizeAudit writes the collisions found by a normalization audit to w
+func writeNormalizeAudit(w io.Writer, collisions []mapper.NormalizationCollision, format string) error {
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if collisions == nil {
+			collisions = []mapper.NormalizationCollision{}
+		}
+		return enc.Encode(collisions)
+	}
+
+	fmt.Fprintf(w, "Would newly collide (%d):\n", len(collisions))
+	for _, c := range collisions {
+		fmt.Fprintf(w, "  %s\n", c.Subject)
+		for _, s := range c.Schemas {
+			fmt.Fprintf(w, "    - %s (currently %s)\n", s.Schema, s.Current)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/akrishnanDG/glue-to-ccsr/internal/compare"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/extractor"
+	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// NewCompareRegistriesCmd creates the compare-registries command
+func NewCompareRegistriesCmd() *cobra.Command {
+	var configFile string
+	var region string
+	var profile string
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "compare-registries <registry-a> <registry-b>",
+		Short: "Show schema drift between two Glue registries",
+		Long: `Extract two AWS Glue registries in the same account and report schemas
+that exist only in one of them, and schemas present in both whose latest
+definitions differ. Definitions are compared after canonicalization, so
+formatting and key order are ignored.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.NewDefaultConfig()
+			if configFile != "" {
+				loadedCfg, err := config.LoadFromFile(configFile)
+				if err != nil {
+					return fmt.Errorf("failed to load config file: %w", err)
+				}
+				cfg = loadedCfg
+			}
+			if cmd.Flags().Changed("aws-region") {
+				cfg.AWS.Region = region
+			}
+			if cmd.Flags().Changed("aws-profile") {
+				cfg.AWS.Profile = profile
+			}
+			if format != "table" && format != "json" {
+				return fmt.Errorf("unsupported format: %s (expected table or json)", format)
+			}
+
+			// Definitions are what gets compared, so they can't be deferred
+			// or capped
+			cfg.AWS.LazyDefinitions = false
+			cfg.Migration.MaxVersionsPerSchema = 0
+			if format == "json" {
+				cfg.Output.Quiet = true
+			}
+
+			ext, err := extractor.New(cfg)
+			if err != nil {
+				return fmt.Errorf("failed to create extractor: %w", err)
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			schemasA, err := ext.ExtractRegistry(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			schemasB, err := ext.ExtractRegistry(ctx, args[1])
+			if err != nil {
+				return err
+			}
+
+			drift := compare.Registries(schemasA, schemasB)
+			return writeDrift(cmd.OutOrStdout(), args[0], args[1], drift, format)
+		},
+	}
+
+	cmd.Flags().StringVarP(&configFile, "config", "c", "", "Config file path")
+	cmd.Flags().StringVar(&region, "aws-region", "", "AWS region")
+	cmd.Flags().StringVar(&profile, "aws-profile", "", "AWS profile name")
+	cmd.Flags().StringVar(&format, "format", "table", "Output format: table, json")
+
+	return cmd
+}
+
+// writeDrift writes the comparison of registries a and b to w
+func writeDrift(w io.Writer, a, b string, drift *compare.Drift, format string) error {
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			RegistryA string `json:"registry_a"`
+			RegistryB string `json:"registry_b"`
+			*compare.Drift
+		}{a, b, drift})
+	}
+
+	fmt.Fprintf(w, "Comparing %s with %s\n\n", a, b)
+	writeDriftSection(w, fmt.Sprintf("Only in %s", a), drift.OnlyInA)
+	writeDriftSection(w, fmt.Sprintf("Only in %s", b), drift.OnlyInB)
+	writeDriftSection(w, "Latest definition differs", drift.Differing)
+	fmt.Fprintf(w, "Identical: %d\n", drift.Identical)
+	return nil
+}
+
+func writeDriftSection(w io.Writer, title string, names []string) {
+	fmt.Fprintf(w, "%s (%d):\n", title, len(names))
+	for _, name := range names {
+		fmt.Fprintf(w, "  - %s\n", name)
+	}
+	fmt.Fprintln(w)
+}
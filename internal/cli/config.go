@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// NewConfigCmd creates the config command
+func NewConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect configuration",
+	}
+
+	cmd.AddCommand(newConfigShowCmd())
+
+	return cmd
+}
+
+func newConfigShowCmd() *cobra.Command {
+	var configFile string
+
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "Print the effective configuration with secrets redacted",
+		Long: `Print the configuration the tool will use after applying defaults,
+the config file and environment variables. Secrets are redacted.
+
+To include CLI flag overrides, use:
+  glue-to-ccsr migrate --config config.yaml [flags] --dump-config`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.NewDefaultConfig()
+			if configFile != "" {
+				loadedCfg, err := config.LoadFromFile(configFile)
+				if err != nil {
+					return fmt.Errorf("failed to load config file: %w", err)
+				}
+				cfg = loadedCfg
+			}
+			applyEnv(cfg)
+			return writeConfig(cmd.OutOrStdout(), cfg)
+		},
+	}
+
+	cmd.Flags().StringVarP(&configFile, "config", "c", "", "Config file path")
+
+	return cmd
+}
+
+// writeConfig writes cfg to w as YAML with secrets redacted
+func writeConfig(w io.Writer, cfg *config.Config) error {
+	data, err := yaml.Marshal(cfg.Redacted())
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	_, err = w.Write(data)
+	return err
+}
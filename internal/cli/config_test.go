@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+func TestMigrateDumpConfig_CLIOverrideAndRedaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := `aws:
+  region: us-west-2
+  registry_names: [payments]
+confluent_cloud:
+  url: https://psrc-test.confluent.cloud
+  api_key: file-key
+  api_secret: super-secret-value
+concurrency:
+  workers: 3
+`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cmd := NewMigrateCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--config", path, "--workers", "7", "--dump-config"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Contains(out.String(), "super-secret-value") {
+		t.Fatalf("dumped config leaks the API secret:\n%s", out.String())
+	}
+
+	var dumped config.Config
+	if err := yaml.Unmarshal(out.Bytes(), &dumped); err != nil {
+		t.Fatalf("dumped config is not valid YAML: %v", err)
+	}
+	if dumped.Concurrency.Workers != 7 {
+		t.Errorf("workers = %d, expected CLI override 7 over file value 3", dumped.Concurrency.Workers)
+	}
+	if dumped.AWS.Region != "us-west-2" {
+		t.Errorf("region = %q, expected file value 'us-west-2'", dumped.AWS.Region)
+	}
+	if dumped.ConfluentCloud.APISecret != "********" {
+		t.Errorf("api_secret = %q, expected it to be redacted", dumped.ConfluentCloud.APISecret)
+	}
+	if dumped.ConfluentCloud.APIKey != "file-key" {
+		t.Errorf("api_key = %q, expected 'file-key'", dumped.ConfluentCloud.APIKey)
+	}
+}
@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/akrishnanDG/glue-to-ccsr/internal/extractor"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/keyvalue"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/mapper"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/report"
+	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// NewDetectRolesCmd creates the detect-roles command
+func NewDetectRolesCmd() *cobra.Command {
+	var configFile string
+	var reportFile string
+	var outputFile string
+
+	cmd := &cobra.Command{
+		Use:   "detect-roles",
+		Short: "Write the detected key/value roles as a role override file",
+		Long: `Run key/value detection over every schema and write the detected roles, with
+the reason for each as a comment, in the key_value.role_override_file format.
+Edit the roles you disagree with and point role_override_file at the result.
+Schemas come from a prior JSON migration report with --report, making no AWS
+calls; otherwise they are read using the config's aws settings.
+
+  glue-to-ccsr detect-roles --config config.yaml --output roles.yaml`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.NewDefaultConfig()
+			if configFile != "" {
+				loadedCfg, err := config.LoadFromFile(configFile)
+				if err != nil {
+					return fmt.Errorf("failed to load config file: %w", err)
+				}
+				cfg = loadedCfg
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			var schemas []*models.GlueSchema
+			if reportFile != "" {
+				prior, err := report.Load(reportFile)
+				if err != nil {
+					return err
+				}
+				schemas = reportSchemas(prior)
+			} else {
+				cfg.Output.Quiet = true
+				src, err := extractor.NewSource(cfg)
+				if err != nil {
+					return fmt.Errorf("failed to create extractor: %w", err)
+				}
+				schemas, err = src.ExtractAll(ctx)
+				if err != nil {
+					return err
+				}
+			}
+
+			entries, err := mapper.DetectRoles(cfg, schemas)
+			if err != nil {
+				return err
+			}
+			if outputFile == "" {
+				return keyvalue.WriteOverrideSeed(cmd.OutOrStdout(), entries)
+			}
+			return writeOverrideSeedFile(outputFile, entries, cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().StringVarP(&configFile, "config", "c", "", "Config file path")
+	cmd.Flags().StringVar(&reportFile, "report", "", "Prior JSON migration report listing the schemas (default: extract from the source)")
+	cmd.Flags().StringVarP(&outputFile, "output", "o", "", "File to write the override seed to (default: stdout)")
+
+	return cmd
+}
+
+// writeOverrideSeedFile writes the override seed to path and prints a summary
+// to w
+func writeOverrideSeedFile(path string, entries []keyvalue.OverrideSeedEntry, w io.Writer) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	if err := keyvalue.WriteOverrideSeed(f, entries); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	fmt.Fprintf(w, "Wrote detected roles for %d schemas to %s\n", len(entries), path)
+	return nil
+}
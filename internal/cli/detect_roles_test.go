@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
+)
+
+func TestDetectRolesCmd_SourceDir(t *testing.T) {
+	dir := t.TempDir()
+	sourceDir := filepath.Join(dir, "export")
+	for _, schema := range []*models.GlueSchema{
+		{Name: "OrderEvent", RegistryName: "orders", DataFormat: models.SchemaTypeAvro, Versions: []models.GlueSchemaVersion{
+			{VersionNumber: 1, Definition: `{"type":"record","name":"OrderEvent","fields":[{"name":"total","type":"double"}]}`},
+		}},
+		{Name: "OrderKey", RegistryName: "orders", DataFormat: models.SchemaTypeAvro, Versions: []models.GlueSchemaVersion{
+			{VersionNumber: 1, Definition: `{"type":"record","name":"OrderKey","fields":[{"name":"order_id","type":"string"}]}`},
+		}},
+	} {
+		data, err := json.Marshal(schema)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(filepath.Join(sourceDir, schema.RegistryName), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(sourceDir, schema.RegistryName, schema.Name+".json"), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	configFile := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(configFile, []byte("aws:\n  source_dir: "+sourceDir+"\n  registry_all: true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := NewDetectRolesCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--config", configFile})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := out.String()
+	for _, want := range []string{
+		"orders.OrderEvent: value # Built-in pattern",
+		"orders.OrderKey: key # Built-in pattern",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in the override seed, got:\n%s", want, got)
+		}
+	}
+}
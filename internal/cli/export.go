@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/akrishnanDG/glue-to-ccsr/internal/extractor"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
+	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// NewExportCmd creates the export command
+func NewExportCmd() *cobra.Command {
+	cfg := config.NewDefaultConfig()
+	var configFile string
+	var outputDir string
+	var noProgress bool
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Dump extracted Glue schemas to a local directory",
+		Long: `Extract schemas from AWS Glue Schema Registry and write each one, with all
+of its versions, as JSON to <output-dir>/<registry>/<schema>.json. Nothing
+is sent to Confluent Cloud, so the output can be audited or kept as an
+offline copy of the source registry.
+
+  glue-to-ccsr export --config config.yaml --output-dir ./glue-export`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if configFile != "" {
+				loadedCfg, err := config.LoadFromFile(configFile)
+				if err != nil {
+					return fmt.Errorf("failed to load config file: %w", err)
+				}
+				cfg = mergeConfigs(loadedCfg, cfg, cmd)
+			}
+			if noProgress {
+				cfg.Output.Progress = false
+			}
+			if err := prepareExport(cfg); err != nil {
+				return err
+			}
+
+			ext, err := extractor.New(cfg)
+			if err != nil {
+				return fmt.Errorf("failed to create extractor: %w", err)
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			return runExport(ctx, ext, outputDir, cmd.OutOrStdout())
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&configFile, "config", "c", "", "Config file path")
+	flags.StringVarP(&outputDir, "output-dir", "o", "glue-export", "Directory to write the schema files to")
+
+	// AWS Source
+	flags.StringVar(&cfg.AWS.Region, "aws-region", cfg.AWS.Region, "AWS region")
+	flags.StringVar(&cfg.AWS.Profile, "aws-profile", "", "AWS profile name")
+	flags.StringVar(&cfg.AWS.AccessKeyID, "aws-access-key-id", "", "AWS access key ID")
+	flags.StringVar(&cfg.AWS.SecretAccessKey, "aws-secret-access-key", "", "AWS secret access key")
+	flags.StringSliceVar(&cfg.AWS.RegistryNames, "aws-registry-name", nil, "AWS Glue registry name (can be repeated)")
+	flags.BoolVar(&cfg.AWS.RegistryAll, "aws-registry-all", false, "Export all registries")
+	flags.BoolVarP(&cfg.Output.Quiet, "quiet", "q", false, "Suppress progress bars")
+	flags.BoolVar(&noProgress, "no-progress", false, "Log progress instead of drawing progress bars")
+
+	return cmd
+}
+
+// prepareExport adjusts cfg for an export and validates it. The files must
+// carry every definition, so they are never deferred or capped, and nothing
+// is written to the target, so its settings are not required.
+func prepareExport(cfg *config.Config) error {
+	cfg.AWS.LazyDefinitions = false
+	cfg.Migration.MaxVersionsPerSchema = 0
+	cfg.Output.DryRun = true
+
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("configuration validation failed: %w", err)
+	}
+	return nil
+}
+
+// runExport streams every schema selected by the aws settings to dir as
+// <registry>/<schema>.json, then prints a summary to w. Each schema is
+// written as soon as it is fetched, so large registries are never held in
+// memory whole.
+func runExport(ctx context.Context, ext *extractor.GlueExtractor, dir string, w io.Writer) error {
+	registries := make(map[string]bool)
+	exported := 0
+	versions := 0
+	err := ext.ExtractStream(ctx, func(schema *models.GlueSchema) error {
+		registryDir := filepath.Join(dir, schema.RegistryName)
+		if err := os.MkdirAll(registryDir, 0755); err != nil {
+			return fmt.Errorf("failed to create export directory: %w", err)
+		}
+
+		data, err := json.MarshalIndent(schema, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode schema %s.%s: %w", schema.RegistryName, schema.Name, err)
+		}
+		path := filepath.Join(registryDir, schema.Name+".json")
+		if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+
+		registries[schema.RegistryName] = true
+		exported++
+		versions += len(schema.Versions)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Exported %d schemas (%d versions) from %d registries to %s\n", exported, versions, len(registries), dir)
+	return nil
+}
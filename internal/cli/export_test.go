@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gluetypes "github.com/aws/aws-sdk-go-v2/service/glue/types"
+	"golang.org/x/time/rate"
+
+	"github.com/akrishnanDG/glue-to-ccsr/internal/extractor"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/extractor/gluetest"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
+	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
+)
+
+func TestRunExport(t *testing.T) {
+	orderV1 := `{"type":"record","name":"Order","fields":[{"name":"id","type":"string"}]}`
+	orderV2 := `{"type":"record","name":"Order","fields":[{"name":"id","type":"string"},{"name":"total","type":"double","default":0}]}`
+	mock := &gluetest.Client{Schemas: map[string]map[string]*gluetest.Schema{
+		"payments": {
+			"OrderEvent":  {Versions: []string{orderV1, orderV2}, Format: gluetypes.DataFormatAvro},
+			"RefundEvent": {Definition: `{"type":"record","name":"Refund","fields":[]}`, Format: gluetypes.DataFormatAvro},
+		},
+		"archive": {
+			"OrderEvent": {Definition: orderV1, Format: gluetypes.DataFormatAvro},
+		},
+	}}
+
+	cfg := config.NewDefaultConfig()
+	cfg.AWS.RegistryAll = true
+	cfg.AWS.RegistryExclude = []string{"archive"}
+	cfg.AWS.SchemaFilter = "Order*"
+	cfg.Output.Quiet = true
+	ext := extractor.NewWithClient(cfg, mock, rate.NewLimiter(rate.Inf, 1))
+
+	dir := t.TempDir()
+	var out bytes.Buffer
+	if err := runExport(context.Background(), ext, dir, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "payments", "OrderEvent.json"))
+	if err != nil {
+		t.Fatalf("expected payments/OrderEvent.json: %v", err)
+	}
+	var schema models.GlueSchema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("failed to decode exported schema: %v", err)
+	}
+	if schema.RegistryName != "payments" || schema.Name != "OrderEvent" {
+		t.Errorf("exported schema = %s.%s, want payments.OrderEvent", schema.RegistryName, schema.Name)
+	}
+	if len(schema.Versions) != 2 {
+		t.Fatalf("got %d versions, want 2", len(schema.Versions))
+	}
+	for i, want := range []string{orderV1, orderV2} {
+		if schema.Versions[i].VersionNumber != int64(i+1) || schema.Versions[i].Definition != want {
+			t.Errorf("version %d = %+v, want definition %s", i+1, schema.Versions[i], want)
+		}
+	}
+
+	// The schema filter and registry exclude must keep the others out
+	for _, path := range []string{
+		filepath.Join(dir, "payments", "RefundEvent.json"),
+		filepath.Join(dir, "archive"),
+	} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("expected %s not to be exported", path)
+		}
+	}
+
+	if !strings.Contains(out.String(), "Exported 1 schemas (2 versions) from 1 registries") {
+		t.Errorf("summary = %q", out.String())
+	}
+}
+
+func TestPrepareExport(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.AWS.Region = "us-east-1"
+	cfg.AWS.RegistryAll = true
+	cfg.AWS.LazyDefinitions = true
+	cfg.Migration.MaxVersionsPerSchema = 3
+
+	// No Confluent Cloud settings are needed to export
+	if err := prepareExport(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.AWS.LazyDefinitions || cfg.Migration.MaxVersionsPerSchema != 0 {
+		t.Error("expected lazy definitions and the version cap to be turned off")
+	}
+
+	cfg.AWS.RegistryAll = false
+	if err := prepareExport(cfg); err == nil || !strings.Contains(err.Error(), "aws.registry_names") {
+		t.Errorf("expected a registry selection error, got %v", err)
+	}
+}
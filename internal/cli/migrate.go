@@ -2,7 +2,10 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
@@ -10,6 +13,8 @@ import (
 
 	"github.com/akrishnanDG/glue-to-ccsr/internal/logging"
 	"github.com/akrishnanDG/glue-to-ccsr/internal/migrator"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/report"
 	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
 	"github.com/spf13/cobra"
 )
@@ -18,6 +23,9 @@ import (
 func NewMigrateCmd() *cobra.Command {
 	cfg := config.NewDefaultConfig()
 	var configFile string
+	var dumpConfig bool
+	var allowProd bool
+	var noProgress bool
 
 	cmd := &cobra.Command{
 		Use:   "migrate",
@@ -47,7 +55,14 @@ See config.example.yaml for all available options.`,
 				// Merge loaded config with CLI flags (CLI flags take precedence)
 				cfg = mergeConfigs(loadedCfg, cfg, cmd)
 			}
-			return runMigrate(cmd.Context(), cfg)
+			if noProgress {
+				cfg.Output.Progress = false
+			}
+			if dumpConfig {
+				applyEnv(cfg)
+				return writeConfig(cmd.OutOrStdout(), cfg)
+			}
+			return runMigrate(cmd.Context(), cfg, allowProd)
 		},
 	}
 
@@ -56,6 +71,7 @@ See config.example.yaml for all available options.`,
 	
 	// Config file (primary way to configure)
 	flags.StringVarP(&configFile, "config", "c", "", "Config file path (recommended)")
+	flags.BoolVar(&dumpConfig, "dump-config", false, "Print the effective configuration (secrets redacted) and exit")
 	
 	// AWS Source
 	flags.StringVar(&cfg.AWS.Region, "aws-region", cfg.AWS.Region, "AWS region")
@@ -64,6 +80,9 @@ See config.example.yaml for all available options.`,
 	flags.StringVar(&cfg.AWS.SecretAccessKey, "aws-secret-access-key", "", "AWS secret access key")
 	flags.StringSliceVar(&cfg.AWS.RegistryNames, "aws-registry-name", nil, "AWS Glue registry name (can be repeated)")
 	flags.BoolVar(&cfg.AWS.RegistryAll, "aws-registry-all", false, "Migrate all registries")
+	flags.StringVar(&cfg.AWS.SourceDir, "source-dir", "", "Read schemas from an export directory instead of AWS Glue")
+	flags.StringSliceVar(&cfg.AWS.SchemaInclude, "include-schema", nil, "Only migrate schemas matching this glob (can be repeated)")
+	flags.StringSliceVar(&cfg.AWS.SchemaExclude, "exclude-schema", nil, "Skip schemas matching this glob, even if included (can be repeated)")
 	
 	// Confluent Cloud Target
 	flags.StringVar(&cfg.ConfluentCloud.URL, "cc-sr-url", "", "Confluent Cloud Schema Registry URL")
@@ -72,11 +91,25 @@ See config.example.yaml for all available options.`,
 	
 	// Naming
 	flags.StringVar(&cfg.Naming.NameMappingFile, "name-mapping-file", "", "YAML file with custom schema-to-subject name mappings")
+	flags.StringVar(&cfg.LLM.SeedCache, "seed-cache", "", "Seed the LLM cache from a prior JSON report")
+	flags.StringVar(&cfg.Output.MapFileTemplate, "map-file-template", "", "Write a name mapping file for the planned subjects to this path and exit")
 
 	// Common Options
 	flags.BoolVar(&cfg.Output.DryRun, "dry-run", false, "Preview without making changes")
 	flags.IntVar(&cfg.Concurrency.Workers, "workers", cfg.Concurrency.Workers, "Number of parallel workers")
 	flags.StringVar(&cfg.Output.LogLevel, "log-level", cfg.Output.LogLevel, "Log level: debug, info, warn, error")
+	flags.BoolVar(&cfg.Output.JSONLogs, "json-logs", false, "Write logs as JSON with a run ID and per-schema events")
+	flags.StringVar(&cfg.Output.Format, "format", cfg.Output.Format, "Report format: table, json, csv, html")
+	flags.BoolVar(&cfg.Output.ReportStdout, "report-stdout", false, "Write the report to stdout (table format is written as JSON)")
+	flags.BoolVarP(&cfg.Output.Quiet, "quiet", "q", false, "Suppress progress bars and the summary")
+	flags.BoolVar(&noProgress, "no-progress", false, "Log progress every 10% instead of drawing progress bars")
+	flags.BoolVar(&cfg.Output.ExplainCollisions, "explain-collisions", false, "Show how each naming collision was resolved")
+	flags.BoolVar(&cfg.Checkpoint.Resume, "resume", false, "Resume from the checkpoint file")
+	flags.BoolVar(&cfg.Checkpoint.Force, "force", false, "Resume even if the checkpoint was written for a different target URL or config")
+	flags.StringVar(&cfg.Checkpoint.ContinueFrom, "continue-from", "", "Skip schemas before this registry:schema in migration order")
+	flags.BoolVar(&cfg.Checkpoint.RetryFailed, "retry-failed", false, "With --resume, migrate only the schemas the checkpoint recorded as failed")
+	flags.BoolVar(&cfg.Migration.SkipDependencyGraph, "no-graph", false, "Skip dependency ordering and migrate all schemas as one level")
+	flags.BoolVar(&allowProd, "allow-prod", false, "Allow a real run against a target labeled as production")
 
 	// Note: Confluent Cloud flags are not marked as required here because they're optional for --dry-run
 	// Validation happens in the config.Validate() method based on dry-run mode
@@ -110,6 +143,15 @@ func mergeConfigs(fileConfig, cliConfig *config.Config, cmd *cobra.Command) *con
 	if flags.Changed("aws-registry-all") {
 		merged.AWS.RegistryAll = cliConfig.AWS.RegistryAll
 	}
+	if flags.Changed("source-dir") {
+		merged.AWS.SourceDir = cliConfig.AWS.SourceDir
+	}
+	if flags.Changed("include-schema") {
+		merged.AWS.SchemaInclude = cliConfig.AWS.SchemaInclude
+	}
+	if flags.Changed("exclude-schema") {
+		merged.AWS.SchemaExclude = cliConfig.AWS.SchemaExclude
+	}
 	
 	// Confluent Cloud config
 	if flags.Changed("cc-sr-url") {
@@ -126,6 +168,12 @@ func mergeConfigs(fileConfig, cliConfig *config.Config, cmd *cobra.Command) *con
 	if flags.Changed("name-mapping-file") {
 		merged.Naming.NameMappingFile = cliConfig.Naming.NameMappingFile
 	}
+	if flags.Changed("seed-cache") {
+		merged.LLM.SeedCache = cliConfig.LLM.SeedCache
+	}
+	if flags.Changed("map-file-template") {
+		merged.Output.MapFileTemplate = cliConfig.Output.MapFileTemplate
+	}
 
 	// Common options
 	if flags.Changed("workers") {
@@ -137,46 +185,139 @@ func mergeConfigs(fileConfig, cliConfig *config.Config, cmd *cobra.Command) *con
 	if flags.Changed("log-level") {
 		merged.Output.LogLevel = cliConfig.Output.LogLevel
 	}
+	if flags.Changed("json-logs") {
+		merged.Output.JSONLogs = cliConfig.Output.JSONLogs
+	}
+	if flags.Changed("format") {
+		merged.Output.Format = cliConfig.Output.Format
+	}
+	if flags.Changed("report-stdout") {
+		merged.Output.ReportStdout = cliConfig.Output.ReportStdout
+	}
+	if flags.Changed("quiet") {
+		merged.Output.Quiet = cliConfig.Output.Quiet
+	}
+	if flags.Changed("explain-collisions") {
+		merged.Output.ExplainCollisions = cliConfig.Output.ExplainCollisions
+	}
+	if flags.Changed("resume") {
+		merged.Checkpoint.Resume = cliConfig.Checkpoint.Resume
+	}
+	if flags.Changed("force") {
+		merged.Checkpoint.Force = cliConfig.Checkpoint.Force
+	}
+	if flags.Changed("continue-from") {
+		merged.Checkpoint.ContinueFrom = cliConfig.Checkpoint.ContinueFrom
+	}
+	if flags.Changed("retry-failed") {
+		merged.Checkpoint.RetryFailed = cliConfig.Checkpoint.RetryFailed
+	}
+	if flags.Changed("no-graph") {
+		merged.Migration.SkipDependencyGraph = cliConfig.Migration.SkipDependencyGraph
+	}
 	
 	return merged
 }
 
-func runMigrate(ctx context.Context, cfg *config.Config) error {
-	// Load API keys from environment if not provided
+// applyEnv fills in credentials from the environment when not already set
+func applyEnv(cfg *config.Config) {
 	if cfg.ConfluentCloud.APIKey == "" {
 		cfg.ConfluentCloud.APIKey = os.Getenv("CC_API_KEY")
 	}
 	if cfg.ConfluentCloud.APISecret == "" {
 		cfg.ConfluentCloud.APISecret = os.Getenv("CC_API_SECRET")
 	}
+	if cfg.ConfluentCloud.BearerToken == "" {
+		cfg.ConfluentCloud.BearerToken = os.Getenv("CC_BEARER_TOKEN")
+	}
 	if cfg.LLM.APIKey == "" {
 		switch cfg.LLM.Provider {
 		case "openai":
 			cfg.LLM.APIKey = os.Getenv("OPENAI_API_KEY")
 		case "anthropic":
 			cfg.LLM.APIKey = os.Getenv("ANTHROPIC_API_KEY")
+		case "azure":
+			cfg.LLM.APIKey = os.Getenv("AZURE_OPENAI_API_KEY")
 		}
 	}
+}
+
+// checkProdGuard refuses a real run against a target whose
+// confluent_cloud.environment marks it as production unless --allow-prod is
+// given. Dry runs never write, so they always pass.
+func checkProdGuard(cfg *config.Config, allowProd bool) error {
+	if cfg.Output.DryRun || allowProd || !cfg.ConfluentCloud.IsProduction() {
+		return nil
+	}
+	return fmt.Errorf("refusing to migrate into %s: confluent_cloud.environment is %q. "+
+		"Review the plan with --dry-run first, then re-run with --allow-prod to write to this target",
+		cfg.ConfluentCloud.URL, cfg.ConfluentCloud.Environment)
+}
+
+func runMigrate(ctx context.Context, cfg *config.Config, allowProd bool) error {
+	// Scaffolding a mapping file only plans, so it never touches the target
+	if cfg.Output.MapFileTemplate != "" {
+		cfg.Output.DryRun = true
+	}
+
+	// Retrying failures registers only them, and each is read in full again
+	// as it is registered, so the rest are planned from their latest
+	// definition
+	if cfg.Checkpoint.Resume && cfg.Checkpoint.RetryFailed {
+		cfg.AWS.LazyDefinitions = true
+	}
+
+	return runMigrator(ctx, cfg, allowProd, func(ctx context.Context, m *migrator.Migrator) (*migrator.Result, error) {
+		return m.Run(ctx)
+	})
+}
+
+// runMigrator validates cfg, sets up logging and signal handling, and runs
+// a migrator with run, writing the report and summary. migrate and apply
+// share it.
+func runMigrator(ctx context.Context, cfg *config.Config, allowProd bool, run func(context.Context, *migrator.Migrator) (*migrator.Result, error)) error {
+	// Load API keys from environment if not provided
+	applyEnv(cfg)
 
 	// Validate configuration
 	if err := cfg.Validate(); err != nil {
 		return fmt.Errorf("configuration validation failed: %w", err)
 	}
 
+	if err := checkProdGuard(cfg, allowProd); err != nil {
+		return err
+	}
+
+	// Fail fast if checkpoint, cache or report files can't be written
+	if err := cfg.Preflight(); err != nil {
+		return fmt.Errorf("preflight check failed: %w", err)
+	}
+
 	// Set up structured logging
-	logging.Setup(cfg.Output.LogLevel, cfg.Output.LogFile)
+	logging.Setup(cfg.Output.LogLevel, cfg.Output.LogFile, cfg.Output.JSONLogs)
+	for _, warning := range cfg.Warnings {
+		slog.Warn(warning)
+	}
 
 	// Create context with cancellation
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	// Handle signals for graceful shutdown
+	// Handle signals for graceful shutdown. With drain_on_interrupt the
+	// schemas in progress finish first, so a second signal exits at once.
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 	go func() {
 		<-sigCh
-		fmt.Println("\nReceived interrupt signal, shutting down gracefully...")
+		if cfg.Concurrency.DrainOnInterrupt {
+			fmt.Fprintln(os.Stderr, "\nReceived interrupt signal, finishing schemas in progress (interrupt again to exit immediately)...")
+		} else {
+			fmt.Fprintln(os.Stderr, "\nReceived interrupt signal, shutting down gracefully...")
+		}
 		cancel()
+		<-sigCh
+		fmt.Fprintln(os.Stderr, "Received second interrupt signal, exiting")
+		os.Exit(130)
 	}()
 
 	// Create and run migrator
@@ -186,15 +327,32 @@ func runMigrate(ctx context.Context, cfg *config.Config) error {
 	}
 
 	startTime := time.Now()
-	result, err := m.Run(ctx)
+	result, err := run(ctx, m)
 	duration := time.Since(startTime)
 
 	if err != nil {
 		return fmt.Errorf("migration failed: %w", err)
 	}
 
-	// Print summary
-	printMigrationSummary(result, duration, cfg.Output.DryRun)
+	if cfg.Output.ReportFile != "" {
+		if err := report.WriteFile(cfg.Output.ReportFile, result.Report, cfg.Output.Format); err != nil {
+			return err
+		}
+		slog.Info("wrote migration report", "file", cfg.Output.ReportFile, "format", cfg.Output.Format)
+	}
+
+	// Print summary or report
+	if err := writeOutput(os.Stdout, cfg, result, duration); err != nil {
+		return err
+	}
+
+	if result.NotStarted > 0 {
+		msg := fmt.Sprintf("migration interrupted: %d schemas not started", result.NotStarted)
+		if cfg.Checkpoint.File != "" {
+			msg += fmt.Sprintf("; progress saved to %s, resume with --resume", cfg.Checkpoint.File)
+		}
+		return errors.New(msg)
+	}
 
 	// Return error if any schemas failed (unless dry-run)
 	if !cfg.Output.DryRun && result.Failed > 0 {
@@ -204,45 +362,92 @@ func runMigrate(ctx context.Context, cfg *config.Config) error {
 	return nil
 }
 
-func printMigrationSummary(result *migrator.Result, duration time.Duration, dryRun bool) {
-	fmt.Println()
-	fmt.Println("═══════════════════════════════════════════════════════════════")
+// writeOutput writes the end-of-run output to w: the report when
+// --report-stdout is set, otherwise the summary unless --quiet is set.
+func writeOutput(w io.Writer, cfg *config.Config, result *migrator.Result, duration time.Duration) error {
+	if cfg.Output.ReportStdout {
+		if err := report.Write(w, result.Report, cfg.Output.Format); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+		return nil
+	}
+	if !cfg.Output.Quiet {
+		printMigrationSummary(w, result, duration, cfg.Output.DryRun)
+	}
+	return nil
+}
+
+func printMigrationSummary(w io.Writer, result *migrator.Result, duration time.Duration, dryRun bool) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "═══════════════════════════════════════════════════════════════")
 	if dryRun {
-		fmt.Println("                     DRY RUN COMPLETE")
+		fmt.Fprintln(w, "                     DRY RUN COMPLETE")
 	} else {
-		if result.Failed > 0 {
-			fmt.Println("              MIGRATION COMPLETED WITH ERRORS")
+		if result.NotStarted > 0 {
+			fmt.Fprintln(w, "                     MIGRATION INTERRUPTED")
+		} else if result.Failed > 0 {
+			fmt.Fprintln(w, "              MIGRATION COMPLETED WITH ERRORS")
 		} else {
-			fmt.Println("                MIGRATION COMPLETED SUCCESSFULLY")
+			fmt.Fprintln(w, "                MIGRATION COMPLETED SUCCESSFULLY")
 		}
 	}
-	fmt.Println("═══════════════════════════════════════════════════════════════")
-	fmt.Printf("  Duration:        %s\n", duration.Round(time.Second))
-	fmt.Printf("  Registries:      %d\n", result.RegistriesProcessed)
-	fmt.Printf("  Schemas:         %d\n", result.SchemasProcessed)
-	fmt.Printf("  Versions:        %d\n", result.VersionsProcessed)
-	fmt.Printf("  Successful:      %d\n", result.Successful)
+	fmt.Fprintln(w, "═══════════════════════════════════════════════════════════════")
+	fmt.Fprintf(w, "  Duration:        %s\n", duration.Round(time.Second))
+	fmt.Fprintf(w, "  Registries:      %d\n", result.RegistriesProcessed)
+	fmt.Fprintf(w, "  Schemas:         %d\n", result.SchemasProcessed)
+	fmt.Fprintf(w, "  Versions:        %d\n", result.VersionsProcessed)
+	fmt.Fprintf(w, "  Successful:      %d\n", result.Successful)
 	if result.Failed > 0 {
-		fmt.Printf("  Failed:          %d [ERROR]\n", result.Failed)
+		fmt.Fprintf(w, "  Failed:          %d [ERROR]\n", result.Failed)
 	} else {
-		fmt.Printf("  Failed:          %d\n", result.Failed)
+		fmt.Fprintf(w, "  Failed:          %d\n", result.Failed)
+	}
+	fmt.Fprintf(w, "  Skipped:         %d\n", result.Skipped)
+	if result.NotStarted > 0 {
+		fmt.Fprintf(w, "  Not started:     %d (interrupted)\n", result.NotStarted)
+	}
+	if dryRun && result.AlreadyExists > 0 {
+		fmt.Fprintf(w, "  Existing:        %d (already in target)\n", result.AlreadyExists)
 	}
-	fmt.Printf("  Skipped:         %d\n", result.Skipped)
 	if result.LLMCalls > 0 {
-		fmt.Printf("  LLM Calls:       %d (cost: $%.2f)\n", result.LLMCalls, result.LLMCost)
+		fmt.Fprintf(w, "  LLM Calls:       %d (cost: $%.2f)\n", result.LLMCalls, result.LLMCost)
+	}
+	if result.EstimatedLLMCalls > 0 {
+		fmt.Fprintf(w, "  LLM Calls:       %d estimated (cost: ~$%.4f)\n", result.EstimatedLLMCalls, result.EstimatedLLMCost)
+	}
+	fmt.Fprintln(w, "═══════════════════════════════════════════════════════════════")
+
+	if !dryRun && result.Report != nil && result.Report.Reconciliation != nil {
+		printReconciliation(w, result.Report.Reconciliation)
 	}
-	fmt.Println("═══════════════════════════════════════════════════════════════")
 	
 	// Print errors if any
 	if result.Failed > 0 && len(result.Errors) > 0 {
-		fmt.Println()
-		fmt.Println("ERRORS:")
-		fmt.Println("───────")
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "ERRORS:")
+		fmt.Fprintln(w, "───────")
 		for i, err := range result.Errors {
 			if err != nil {
-				fmt.Printf("  %d. %v\n", i+1, err)
+				fmt.Fprintf(w, "  %d. %v\n", i+1, err)
 			}
 		}
-		fmt.Println()
+		fmt.Fprintln(w)
+	}
+}
+
+// printReconciliation prints the end state of the planned subjects, listing
+// the ones that failed or are missing from the target
+func printReconciliation(w io.Writer, rec *models.ReconciliationReport) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "RECONCILIATION:")
+	fmt.Fprintln(w, "───────────────")
+	fmt.Fprintf(w, "  Registered:      %d\n", rec.Registered)
+	fmt.Fprintf(w, "  Skipped:         %d (already existed)\n", rec.Skipped)
+	fmt.Fprintf(w, "  Resumed:         %d (already migrated)\n", rec.Resumed)
+	fmt.Fprintf(w, "  Failed:          %d\n", rec.Failed)
+	for _, s := range rec.Subjects {
+		if s.Status == "failed" {
+			fmt.Fprintf(w, "    - %s (%s): %s\n", s.Subject, s.SourceSchema, s.Reason)
+		}
 	}
 }
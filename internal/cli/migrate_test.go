@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/akrishnanDG/glue-to-ccsr/internal/migrator"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
+	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
+)
+
+func newTestResult() *migrator.Result {
+	return &migrator.Result{
+		RegistriesProcessed: 1,
+		SchemasProcessed:    1,
+		VersionsProcessed:   2,
+		Successful:          1,
+		Report: &models.MigrationReport{
+			DryRun: true,
+			Schemas: []models.SchemaReport{
+				{SourceRegistry: "payments", SourceSchema: "Order", TargetSubject: "order-value", Status: "ready"},
+			},
+		},
+	}
+}
+
+func TestWriteOutput_ReportStdout(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Output.DryRun = true
+	cfg.Output.ReportStdout = true
+
+	var buf bytes.Buffer
+	if err := writeOutput(&buf, cfg, newTestResult(), time.Second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !json.Valid(buf.Bytes()) {
+		t.Fatalf("stdout is not valid JSON:\n%s", buf.String())
+	}
+	if strings.Contains(buf.String(), "═") || strings.Contains(buf.String(), "DRY RUN COMPLETE") {
+		t.Errorf("stdout contains decorative summary:\n%s", buf.String())
+	}
+
+	var got models.MigrationReport
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("failed to decode report: %v", err)
+	}
+	if len(got.Schemas) != 1 || got.Schemas[0].TargetSubject != "order-value" {
+		t.Errorf("unexpected schemas in report: %+v", got.Schemas)
+	}
+}
+
+func TestWriteOutput_Quiet(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Output.Quiet = true
+
+	var buf bytes.Buffer
+	if err := writeOutput(&buf, cfg, newTestResult(), time.Second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no output with --quiet, got:\n%s", buf.String())
+	}
+
+	// --report-stdout still emits the report when combined with --quiet
+	cfg.Output.ReportStdout = true
+	if err := writeOutput(&buf, cfg, newTestResult(), time.Second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !json.Valid(buf.Bytes()) {
+		t.Errorf("expected JSON report with --quiet --report-stdout, got:\n%s", buf.String())
+	}
+}
+
+func TestWriteOutput_Reconciliation(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	result := newTestResult()
+	result.Report.DryRun = false
+	result.Report.Reconciliation = &models.ReconciliationReport{
+		Registered: 1,
+		Skipped:    1,
+		Resumed:    1,
+		Failed:     1,
+		Subjects: []models.ReconciledSubject{
+			{Subject: "order-value", SourceSchema: "payments.Order", Status: "registered"},
+			{Subject: "refund-value", SourceSchema: "payments.Refund", Status: "resumed", Reason: "already migrated"},
+			{Subject: "charge-value", SourceSchema: "payments.Charge", Status: "skipped", Reason: "every version already in target"},
+			{Subject: "user-value", SourceSchema: "payments.User", Status: "failed", Reason: "registered but not found in target"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := writeOutput(&buf, cfg, result, time.Second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"RECONCILIATION",
+		"Skipped:         1 (already existed)",
+		"Resumed:         1 (already migrated)",
+		"user-value (payments.User): registered but not found in target",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("summary is missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "order-value (") {
+		t.Errorf("summary lists a registered subject:\n%s", out)
+	}
+}
+
+func TestRunMigrate_ProdRequiresAllowProd(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte(`{"id":1}`))
+	}))
+	defer server.Close()
+
+	cfg := config.NewDefaultConfig()
+	cfg.AWS.SourceDir = t.TempDir()
+	cfg.AWS.RegistryAll = true
+	cfg.ConfluentCloud.URL = server.URL
+	cfg.ConfluentCloud.APIKey = "test-key"
+	cfg.ConfluentCloud.APISecret = "test-secret"
+	cfg.ConfluentCloud.Environment = "Prod"
+	cfg.Output.Quiet = true
+
+	err := runMigrate(context.Background(), cfg, false)
+	if err == nil || !strings.Contains(err.Error(), "--allow-prod") {
+		t.Fatalf("expected a refusal pointing at --allow-prod, got %v", err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 0 {
+		t.Errorf("expected no requests to a prod target, got %d", n)
+	}
+
+	if err := checkProdGuard(cfg, true); err != nil {
+		t.Errorf("expected --allow-prod to pass the guard, got %v", err)
+	}
+	cfg.Output.DryRun = true
+	if err := checkProdGuard(cfg, false); err != nil {
+		t.Errorf("expected a dry run to pass the guard, got %v", err)
+	}
+	cfg.Output.DryRun = false
+	cfg.ConfluentCloud.Environment = "staging"
+	if err := checkProdGuard(cfg, false); err != nil {
+		t.Errorf("expected a staging target to pass the guard, got %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/akrishnanDG/glue-to-ccsr/internal/extractor"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/mapper"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/report"
+	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// NewNamingDiffCmd creates the naming-diff command
+func NewNamingDiffCmd() *cobra.Command {
+	var reportFile string
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "naming-diff <old-config> <new-config>",
+		Short: "Show which subjects change between two config files",
+		Long: `Plan subject names under two config files and list every schema whose
+subject changes, as old_subject -> new_subject. Schemas come from a prior
+JSON migration report with --report, making no AWS or Schema Registry calls;
+otherwise they are extracted from Glue using the old config's aws settings.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			oldCfg, err := config.LoadFromFile(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to load old config file: %w", err)
+			}
+			newCfg, err := config.LoadFromFile(args[1])
+			if err != nil {
+				return fmt.Errorf("failed to load new config file: %w", err)
+			}
+			if format != "table" && format != "json" {
+				return fmt.Errorf("unsupported format: %s (expected table or json)", format)
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			var schemas []*models.GlueSchema
+			if reportFile != "" {
+				prior, err := report.Load(reportFile)
+				if err != nil {
+					return err
+				}
+				schemas = reportSchemas(prior)
+			} else {
+				if format == "json" {
+					oldCfg.Output.Quiet = true
+				}
+				ext, err := extractor.New(oldCfg)
+				if err != nil {
+					return fmt.Errorf("failed to create extractor: %w", err)
+				}
+				schemas, err = ext.ExtractAll(ctx)
+				if err != nil {
+					return err
+				}
+				schemas, _ = mapper.SplitUnsupported(schemas)
+			}
+
+			changes, err := mapper.DiffNaming(ctx, oldCfg, newCfg, schemas)
+			if err != nil {
+				return err
+			}
+			return writeNamingDiff(cmd.OutOrStdout(), changes, len(schemas), format)
+		},
+	}
+
+	cmd.Flags().StringVar(&reportFile, "report", "", "Prior JSON migration report listing the schemas (default: extract from Glue)")
+	cmd.Flags().StringVar(&format, "format", "table", "Output format: table, json")
+
+	return cmd
+}
+
+// writeNamingDiff writes the subjects that change between two configs to w
+func writeNamingDiff(w io.Writer, changes []mapper.SubjectChange, total int, format string) error {
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if changes == nil {
+			changes = []mapper.SubjectChange{}
+		}
+		return enc.Encode(changes)
+	}
+
+	fmt.Fprintf(w, "Changed subjects (%d of %d schemas):\n", len(changes), total)
+	for _, c := range changes {
+		fmt.Fprintf(w, "  %s: %s → %s\n", c.Schema, c.Old, c.New)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
+)
+
+func TestNamingDiffCmd_NormalizeCase(t *testing.T) {
+	dir := t.TempDir()
+	oldConfig := filepath.Join(dir, "old.yaml")
+	newConfig := filepath.Join(dir, "new.yaml")
+	reportFile := filepath.Join(dir, "report.json")
+
+	if err := os.WriteFile(oldConfig, []byte("normalization:\n  normalize_case: keep\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(newConfig, []byte("normalization:\n  normalize_case: kebab\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(models.MigrationReport{Schemas: []models.SchemaReport{
+		{SourceRegistry: "payments", SourceSchema: "UserEvent", SchemaType: "AVRO"},
+		{SourceRegistry: "payments", SourceSchema: "order-event", SchemaType: "AVRO"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(reportFile, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := NewNamingDiffCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{oldConfig, newConfig, "--report", reportFile})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := out.String()
+	if !strings.Contains(got, "payments:UserEvent: UserEvent-value → user-event-value") {
+		t.Errorf("expected UserEvent's subject change to be listed, got:\n%s", got)
+	}
+	if strings.Contains(got, "payments:order-event:") {
+		t.Errorf("order-event is already kebab case and should not be listed, got:\n%s", got)
+	}
+	if !strings.Contains(got, "(1 of 2 schemas)") {
+		t.Errorf("expected the summary to count 1 of 2 schemas, got:\n%s", got)
+	}
+}
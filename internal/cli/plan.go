@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/akrishnanDG/glue-to-ccsr/internal/logging"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/migrator"
+	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// NewPlanCmd creates the plan command
+func NewPlanCmd() *cobra.Command {
+	var configFile string
+	var outFile string
+
+	cmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Write the migration plan to a file for review",
+		Long: `Extract, order, map and validate the schemas as migrate would, then write the
+resulting plan instead of registering anything. The plan lists every
+schema's target context and subject, its references and the dependency
+levels. Review it, edit target subjects under mappings if needed, and run
+it with apply:
+
+  glue-to-ccsr plan --config config.yaml --out plan.yaml
+  glue-to-ccsr apply plan.yaml --config config.yaml
+
+The plan is written as JSON when --out ends in .json and as YAML otherwise.
+Nothing is sent to Confluent Cloud.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.NewDefaultConfig()
+			if configFile != "" {
+				loadedCfg, err := config.LoadFromFile(configFile)
+				if err != nil {
+					return fmt.Errorf("failed to load config file: %w", err)
+				}
+				cfg = loadedCfg
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			return runPlan(ctx, cfg, outFile, cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().StringVarP(&configFile, "config", "c", "", "Config file path")
+	cmd.Flags().StringVar(&outFile, "out", "plan.yaml", "File to write the plan to (.json for JSON, otherwise YAML)")
+
+	return cmd
+}
+
+// runPlan plans the migration described by cfg and writes the plan to path,
+// printing a summary to w
+func runPlan(ctx context.Context, cfg *config.Config, path string, w io.Writer) error {
+	applyEnv(cfg)
+
+	// Planning never writes to the target, but the plan must carry real LLM
+	// names rather than dry-run estimates
+	cfg.Output.DryRun = true
+	cfg.LLM.EstimateInDryRun = false
+
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("configuration validation failed: %w", err)
+	}
+
+	logging.Setup(cfg.Output.LogLevel, cfg.Output.LogFile, cfg.Output.JSONLogs)
+	for _, warning := range cfg.Warnings {
+		slog.Warn(warning)
+	}
+
+	m, err := migrator.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create migrator: %w", err)
+	}
+	plan, err := m.Plan(ctx)
+	if err != nil {
+		return fmt.Errorf("planning failed: %w", err)
+	}
+	if err := migrator.WritePlan(path, plan); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Wrote plan for %d schemas in %d dependency levels to %s\n", len(plan.Mappings), len(plan.Levels), path)
+	if len(plan.Errors) > 0 {
+		fmt.Fprintf(w, "%d validation errors are listed under errors; apply will refuse the plan until they are fixed\n", len(plan.Errors))
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
+)
+
+func TestPlanAndApplyCmd(t *testing.T) {
+	dir := t.TempDir()
+	sourceDir := filepath.Join(dir, "export")
+	schema := &models.GlueSchema{Name: "OrderEvent", RegistryName: "orders", DataFormat: models.SchemaTypeAvro, Versions: []models.GlueSchemaVersion{
+		{VersionNumber: 1, Definition: `{"type":"record","name":"OrderEvent","fields":[{"name":"total","type":"double"}]}`},
+	}}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(sourceDir, "orders"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sourceDir, "orders", "OrderEvent.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var registered []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/versions") {
+			registered = append(registered, r.URL.Path)
+			w.Write([]byte(`{"id": 1}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	configFile := filepath.Join(dir, "config.yaml")
+	config := "aws:\n  source_dir: " + sourceDir + "\n  registry_all: true\n" +
+		"confluent_cloud:\n  url: " + server.URL + "\n  api_key: key\n  api_secret: secret\n" +
+		"concurrency:\n  retry_attempts: 0\n" +
+		"output:\n  quiet: true\n"
+	if err := os.WriteFile(configFile, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	planFile := filepath.Join(dir, "plan.yaml")
+	cmd := NewPlanCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--config", configFile, "--out", planFile})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("plan failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "Wrote plan for 1 schemas in 1 dependency levels") {
+		t.Errorf("summary = %q", out.String())
+	}
+	if len(registered) != 0 {
+		t.Fatalf("plan must not register anything, got %v", registered)
+	}
+
+	written, err := os.ReadFile(planFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(written), "target_subject: order-event-value") {
+		t.Fatalf("expected the planned subject in the plan, got:\n%s", written)
+	}
+
+	// Rename the subject in the mappings, as a reviewer would
+	edited := strings.Replace(string(written), "target_subject: order-event-value", "target_subject: orders-value", 1)
+	if err := os.WriteFile(planFile, []byte(edited), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd = NewApplyCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{planFile, "--config", configFile})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("apply failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(registered) != 1 || registered[0] != "/subjects/orders-value/versions" {
+		t.Errorf("expected the edited subject to be registered, got %v", registered)
+	}
+}
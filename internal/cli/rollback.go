@@ -0,0 +1,259 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/akrishnanDG/glue-to-ccsr/internal/loader"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/worker"
+	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// NewRollbackCmd creates the rollback command
+func NewRollbackCmd() *cobra.Command {
+	var configFile string
+	var checkpointFile string
+	var permanent bool
+	var allowProd bool
+
+	cmd := &cobra.Command{
+		Use:   "rollback",
+		Short: "Delete the subjects a checkpointed migration registered",
+		Long: `Undo a migration recorded in a checkpoint file by deleting the target subject
+of every schema it completed, in reverse migration order so referencing
+subjects go before the ones they reference. Subjects are soft-deleted unless
+--permanent is given. Rolled-back schemas are removed from the checkpoint,
+and the file is deleted once none are left.
+
+A subject that already existed before the migration is never deleted, since
+that would remove data the migration didn't write; only the versions added
+above its latest version at the time are.
+A target labeled as production by confluent_cloud.environment needs
+--allow-prod.
+
+  glue-to-ccsr rollback --config config.yaml --checkpoint state.json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.NewDefaultConfig()
+			if configFile != "" {
+				loadedCfg, err := config.LoadFromFile(configFile)
+				if err != nil {
+					return fmt.Errorf("failed to load config file: %w", err)
+				}
+				cfg = loadedCfg
+			}
+			applyEnv(cfg)
+
+			if checkpointFile == "" {
+				checkpointFile = cfg.Checkpoint.File
+			}
+			if checkpointFile == "" {
+				return fmt.Errorf("no checkpoint file: pass --checkpoint or set checkpoint.file")
+			}
+			if cfg.ConfluentCloud.URL == "" {
+				return fmt.Errorf("confluent_cloud.url is required to roll back")
+			}
+			if err := checkRollbackProdGuard(cfg, allowProd); err != nil {
+				return err
+			}
+
+			ldr, err := loader.New(cfg)
+			if err != nil {
+				return fmt.Errorf("failed to create loader: %w", err)
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			return runRollback(ctx, ldr, worker.NewCheckpointManager(checkpointFile), permanent, cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().StringVarP(&configFile, "config", "c", "", "Config file path")
+	cmd.Flags().StringVar(&checkpointFile, "checkpoint", "", "Checkpoint file to roll back (default: checkpoint.file)")
+	cmd.Flags().BoolVar(&permanent, "permanent", false, "Permanently delete subjects instead of soft-deleting them")
+	cmd.Flags().BoolVar(&allowProd, "allow-prod", false, "Allow deleting subjects from a target labeled as production")
+
+	return cmd
+}
+
+// checkRollbackProdGuard refuses to delete from a target whose
+// confluent_cloud.environment marks it as production unless --allow-prod is
+// given. Unlike checkProdGuard, output.dry_run doesn't let it pass, since
+// rollback deletes regardless.
+func checkRollbackProdGuard(cfg *config.Config, allowProd bool) error {
+	if allowProd || !cfg.ConfluentCloud.IsProduction() {
+		return nil
+	}
+	return fmt.Errorf("refusing to roll back %s: confluent_cloud.environment is %q. "+
+		"Re-run with --allow-prod to delete subjects from this target",
+		cfg.ConfluentCloud.URL, cfg.ConfluentCloud.Environment)
+}
+
+// subjectDeleter is the Schema Registry calls rollback makes
+type subjectDeleter interface {
+	DeleteSubject(ctx context.Context, subject string, permanent bool) ([]int, error)
+	ListVersions(ctx context.Context, subject string) ([]int, error)
+	DeleteVersion(ctx context.Context, subject string, version int, permanent bool) error
+}
+
+// runRollback deletes the subjects of every completed schema in the
+// checkpoint, printing each deletion to w, then saves the schemas that
+// could not be rolled back or removes the checkpoint when none are left.
+// A subject that is already gone counts as rolled back. A subject recorded
+// as existing before the migration keeps the versions it had then and loses
+// only the ones above. Checkpoints that don't record this fall back to
+// skip_existing: a schema whose versions all existed in the target is
+// dropped from the checkpoint without deleting anything, and one where only
+// some did is left in place.
+func runRollback(ctx context.Context, deleter subjectDeleter, checkpoint *worker.CheckpointManager, permanent bool, w io.Writer) error {
+	state, err := checkpoint.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+
+	mode := "soft"
+	if permanent {
+		mode = "permanent"
+	}
+
+	total := len(state.CompletedSchemas)
+	failed := 0
+	for _, key := range rollbackOrder(state) {
+		entry := state.CompletedSchemas[key]
+		if existing := len(entry.SkippedExistingVersions); existing > 0 && entry.PreexistingVersions == nil {
+			subject := completedSubjects(entry)[0]
+			if existing < entry.Versions {
+				fmt.Fprintf(w, "Skipped %s (%s): %d of %d versions existed before the migration; delete the others by hand\n", subject, key, existing, entry.Versions)
+				failed++
+				continue
+			}
+			fmt.Fprintf(w, "Skipped %s (%s): every version existed before the migration\n", subject, key)
+			delete(state.CompletedSchemas, key)
+			if state.CompletedCount > 0 {
+				state.CompletedCount--
+			}
+			continue
+		}
+
+		rolledBack := true
+		for _, subject := range completedSubjects(entry) {
+			if before, ok := entry.PreexistingVersions[subject]; ok {
+				if !rollbackAddedVersions(ctx, deleter, key, subject, before, permanent, w) {
+					rolledBack = false
+				}
+				continue
+			}
+			versions, err := deleter.DeleteSubject(ctx, subject, permanent)
+			switch {
+			case errors.Is(err, loader.ErrSubjectNotFound):
+				fmt.Fprintf(w, "Skipped %s (%s): already deleted\n", subject, key)
+			case err != nil:
+				fmt.Fprintf(w, "Failed to delete %s (%s): %v\n", subject, key, err)
+				rolledBack = false
+			default:
+				fmt.Fprintf(w, "Deleted %s (%s): versions %v\n", subject, key, versions)
+			}
+		}
+		if !rolledBack {
+			failed++
+			continue
+		}
+		delete(state.CompletedSchemas, key)
+		if state.CompletedCount > 0 {
+			state.CompletedCount--
+		}
+	}
+
+	if len(state.CompletedSchemas) == 0 && len(state.FailedSchemas) == 0 {
+		if err := checkpoint.Delete(); err != nil {
+			return fmt.Errorf("failed to remove checkpoint: %w", err)
+		}
+	} else if err := checkpoint.Save(state); err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+
+	fmt.Fprintf(w, "Rolled back %d of %d schemas (%s delete)\n", total-failed, total, mode)
+	if failed > 0 {
+		return fmt.Errorf("rollback incomplete: %d schemas could not be deleted and remain in the checkpoint", failed)
+	}
+	return nil
+}
+
+// rollbackAddedVersions deletes the versions of a subject above before, its
+// latest version when the migration started, printing the outcome to w, and
+// reports whether the subject is rolled back
+func rollbackAddedVersions(ctx context.Context, deleter subjectDeleter, key, subject string, before int, permanent bool, w io.Writer) bool {
+	versions, err := deleter.ListVersions(ctx, subject)
+	if errors.Is(err, loader.ErrSubjectNotFound) {
+		fmt.Fprintf(w, "Skipped %s (%s): already deleted\n", subject, key)
+		return true
+	}
+	if err != nil {
+		fmt.Fprintf(w, "Failed to list versions of %s (%s): %v\n", subject, key, err)
+		return false
+	}
+
+	var deleted []int
+	for _, version := range versions {
+		if version <= before {
+			continue
+		}
+		err := deleter.DeleteVersion(ctx, subject, version, permanent)
+		if err != nil && !errors.Is(err, loader.ErrVersionNotFound) {
+			fmt.Fprintf(w, "Failed to delete %s version %d (%s): %v\n", subject, version, key, err)
+			return false
+		}
+		deleted = append(deleted, version)
+	}
+	if len(deleted) == 0 {
+		fmt.Fprintf(w, "Skipped %s (%s): no versions above %d, its latest before the migration\n", subject, key, before)
+		return true
+	}
+	fmt.Fprintf(w, "Deleted %s (%s): versions %v, keeping versions up to %d from before the migration\n", subject, key, deleted, before)
+	return true
+}
+
+// rollbackOrder returns the completed schema keys in reverse migration
+// order, followed by any the order doesn't list, sorted
+func rollbackOrder(state *models.MigrationState) []string {
+	var keys []string
+	listed := make(map[string]bool, len(state.MigrationOrder))
+	for i := len(state.MigrationOrder) - 1; i >= 0; i-- {
+		key := state.MigrationOrder[i]
+		if _, completed := state.CompletedSchemas[key]; completed && !listed[key] {
+			keys = append(keys, key)
+		}
+		listed[key] = true
+	}
+
+	var unlisted []string
+	for key := range state.CompletedSchemas {
+		if !listed[key] {
+			unlisted = append(unlisted, key)
+		}
+	}
+	sort.Strings(unlisted)
+	return append(keys, unlisted...)
+}
+
+// completedSubjects returns the qualified target subjects a completed schema
+// was registered under
+func completedSubjects(entry models.CompletedSchema) []string {
+	subjects := []string{entry.TargetSubject}
+	if entry.KeySubject != "" {
+		subjects = append(subjects, entry.KeySubject)
+	}
+	if entry.TargetContext != "" {
+		for i := range subjects {
+			subjects[i] = entry.TargetContext + ":" + subjects[i]
+		}
+	}
+	return subjects
+}
@@ -0,0 +1,315 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/akrishnanDG/glue-to-ccsr/internal/loader"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/worker"
+	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
+)
+
+// writeRollbackCheckpoint writes a checkpoint in which payments:Address and
+// then payments:OrderEvent, registered with a key subject in a context,
+// completed
+func writeRollbackCheckpoint(t *testing.T, path string) {
+	t.Helper()
+	state := models.NewMigrationState("")
+	state.MigrationOrder = []string{"payments:Address", "payments:OrderEvent"}
+	state.CompletedSchemas["payments:Address"] = models.CompletedSchema{
+		SourceRegistry: "payments", SourceSchema: "Address", TargetSubject: "address-value", Versions: 1, CompletedAt: time.Now(),
+	}
+	state.CompletedSchemas["payments:OrderEvent"] = models.CompletedSchema{
+		SourceRegistry: "payments", SourceSchema: "OrderEvent", TargetContext: ".payments",
+		TargetSubject: "order-event-value", KeySubject: "order-event-key", Versions: 2, CompletedAt: time.Now(),
+	}
+	state.CompletedCount = 2
+	if err := worker.NewCheckpointManager(path).Save(state); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// deleteRecorder is a Schema Registry that records DELETE calls and rejects
+// the subjects in fail
+func deleteRecorder(t *testing.T, fail map[string]bool) (*httptest.Server, *[]string) {
+	t.Helper()
+	var mu sync.Mutex
+	var deletes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Errorf("rollback made a %s %s call", r.Method, r.URL.Path)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		deletes = append(deletes, r.URL.RequestURI())
+		if fail[strings.TrimPrefix(r.URL.Path, "/subjects/")] {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"error_code":42206,"message":"One or more references exist to the schema"}`))
+			return
+		}
+		w.Write([]byte(`[1]`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &deletes
+}
+
+func newRollbackLoader(t *testing.T, url string) *loader.ConfluentLoader {
+	t.Helper()
+	cfg := config.NewDefaultConfig()
+	cfg.ConfluentCloud.URL = url
+	ldr, err := loader.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ldr
+}
+
+func TestRollbackCmd_DeletesCompletedSubjects(t *testing.T) {
+	server, deletes := deleteRecorder(t, nil)
+	dir := t.TempDir()
+	checkpointFile := filepath.Join(dir, "state.json")
+	writeRollbackCheckpoint(t, checkpointFile)
+	configFile := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(configFile, []byte("confluent_cloud:\n  url: "+server.URL+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := NewRollbackCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--config", configFile, "--checkpoint", checkpointFile})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// OrderEvent was migrated last, so it goes first
+	want := []string{
+		"/subjects/.payments:order-event-value",
+		"/subjects/.payments:order-event-key",
+		"/subjects/address-value",
+	}
+	if !reflect.DeepEqual(*deletes, want) {
+		t.Errorf("DELETE calls = %v, want %v", *deletes, want)
+	}
+	for _, line := range []string{
+		"Deleted .payments:order-event-value (payments:OrderEvent)",
+		"Deleted address-value (payments:Address)",
+		"Rolled back 2 of 2 schemas (soft delete)",
+	} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("expected %q in output, got:\n%s", line, out.String())
+		}
+	}
+	if _, err := os.Stat(checkpointFile); !os.IsNotExist(err) {
+		t.Errorf("expected the checkpoint to be removed once everything is rolled back")
+	}
+}
+
+func TestRunRollback_PermanentKeepsFailures(t *testing.T) {
+	server, deletes := deleteRecorder(t, map[string]bool{"address-value": true})
+	checkpointFile := filepath.Join(t.TempDir(), "state.json")
+	writeRollbackCheckpoint(t, checkpointFile)
+	checkpoint := worker.NewCheckpointManager(checkpointFile)
+
+	var out bytes.Buffer
+	err := runRollback(context.Background(), newRollbackLoader(t, server.URL), checkpoint, true, &out)
+	if err == nil {
+		t.Fatal("expected an error when a subject can't be deleted")
+	}
+
+	// Each subject is soft-deleted before it is permanently deleted
+	want := []string{
+		"/subjects/.payments:order-event-value",
+		"/subjects/.payments:order-event-value?permanent=true",
+		"/subjects/.payments:order-event-key",
+		"/subjects/.payments:order-event-key?permanent=true",
+		"/subjects/address-value",
+	}
+	if !reflect.DeepEqual(*deletes, want) {
+		t.Errorf("DELETE calls = %v, want %v", *deletes, want)
+	}
+
+	state, err := checkpoint.Load()
+	if err != nil {
+		t.Fatalf("expected the checkpoint to be kept: %v", err)
+	}
+	if _, ok := state.CompletedSchemas["payments:Address"]; !ok || len(state.CompletedSchemas) != 1 {
+		t.Errorf("expected only the failed Address to remain, got %v", state.CompletedSchemas)
+	}
+	if state.CompletedCount != 1 {
+		t.Errorf("CompletedCount = %d, want 1", state.CompletedCount)
+	}
+	if !strings.Contains(out.String(), "Rolled back 1 of 2 schemas (permanent delete)") {
+		t.Errorf("summary missing, got:\n%s", out.String())
+	}
+}
+
+func TestRunRollback_SkipsPreexistingVersions(t *testing.T) {
+	server, deletes := deleteRecorder(t, nil)
+	checkpointFile := filepath.Join(t.TempDir(), "state.json")
+	state := models.NewMigrationState("")
+	state.MigrationOrder = []string{"payments:Address", "payments:Customer", "payments:Order"}
+	state.CompletedSchemas["payments:Address"] = models.CompletedSchema{
+		SourceRegistry: "payments", SourceSchema: "Address", TargetSubject: "address-value",
+		Versions: 2, SkippedExistingVersions: []int64{1, 2}, CompletedAt: time.Now(),
+	}
+	state.CompletedSchemas["payments:Customer"] = models.CompletedSchema{
+		SourceRegistry: "payments", SourceSchema: "Customer", TargetSubject: "customer-value",
+		Versions: 3, SkippedExistingVersions: []int64{1}, CompletedAt: time.Now(),
+	}
+	state.CompletedSchemas["payments:Order"] = models.CompletedSchema{
+		SourceRegistry: "payments", SourceSchema: "Order", TargetSubject: "order-value", Versions: 1, CompletedAt: time.Now(),
+	}
+	state.CompletedCount = 3
+	checkpoint := worker.NewCheckpointManager(checkpointFile)
+	if err := checkpoint.Save(state); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	err := runRollback(context.Background(), newRollbackLoader(t, server.URL), checkpoint, false, &out)
+	if err == nil {
+		t.Fatal("expected an error for the partly pre-existing Customer")
+	}
+
+	// Only the subject the migration created from scratch is deleted
+	if want := []string{"/subjects/order-value"}; !reflect.DeepEqual(*deletes, want) {
+		t.Errorf("DELETE calls = %v, want %v", *deletes, want)
+	}
+	for _, line := range []string{
+		"Skipped customer-value (payments:Customer): 1 of 3 versions existed before the migration",
+		"Skipped address-value (payments:Address): every version existed before the migration",
+		"Rolled back 2 of 3 schemas (soft delete)",
+	} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("expected %q in output, got:\n%s", line, out.String())
+		}
+	}
+
+	state, err = checkpoint.Load()
+	if err != nil {
+		t.Fatalf("expected the checkpoint to be kept: %v", err)
+	}
+	if _, ok := state.CompletedSchemas["payments:Customer"]; !ok || len(state.CompletedSchemas) != 1 {
+		t.Errorf("expected only Customer to remain, got %v", state.CompletedSchemas)
+	}
+}
+
+func TestRunRollback_KeepsVersionsFromBeforeMigration(t *testing.T) {
+	var mu sync.Mutex
+	var deletes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/subjects/.payments:order-event-value/versions":
+			w.Write([]byte(`[1,2,3,4]`))
+		case r.Method == "GET" && r.URL.Path == "/subjects/address-value/versions":
+			w.Write([]byte(`[1]`))
+		case r.Method == "DELETE" && strings.Contains(r.URL.Path, "/versions/"):
+			deletes = append(deletes, r.URL.RequestURI())
+			w.Write([]byte(`1`))
+		case r.Method == "DELETE":
+			deletes = append(deletes, r.URL.RequestURI())
+			w.Write([]byte(`[1]`))
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	checkpointFile := filepath.Join(t.TempDir(), "state.json")
+	writeRollbackCheckpoint(t, checkpointFile)
+	checkpoint := worker.NewCheckpointManager(checkpointFile)
+	state, err := checkpoint.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The value subject held two versions before the migration and the
+	// address subject one, which skip_existing found; the key subject is new
+	order := state.CompletedSchemas["payments:OrderEvent"]
+	order.PreexistingVersions = map[string]int{".payments:order-event-value": 2}
+	state.CompletedSchemas["payments:OrderEvent"] = order
+	address := state.CompletedSchemas["payments:Address"]
+	address.SkippedExistingVersions = []int64{1}
+	address.PreexistingVersions = map[string]int{"address-value": 1}
+	state.CompletedSchemas["payments:Address"] = address
+	if err := checkpoint.Save(state); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runRollback(context.Background(), newRollbackLoader(t, server.URL), checkpoint, true, &out); err != nil {
+		t.Fatalf("runRollback: %v\n%s", err, out.String())
+	}
+
+	// Only the versions the migration added are deleted from the subject
+	// that existed; the new key subject is deleted whole
+	want := []string{
+		"/subjects/.payments:order-event-value/versions/3",
+		"/subjects/.payments:order-event-value/versions/3?permanent=true",
+		"/subjects/.payments:order-event-value/versions/4",
+		"/subjects/.payments:order-event-value/versions/4?permanent=true",
+		"/subjects/.payments:order-event-key",
+		"/subjects/.payments:order-event-key?permanent=true",
+	}
+	if !reflect.DeepEqual(deletes, want) {
+		t.Errorf("DELETE calls = %v, want %v", deletes, want)
+	}
+	for _, line := range []string{
+		"Deleted .payments:order-event-value (payments:OrderEvent): versions [3 4], keeping versions up to 2 from before the migration",
+		"Skipped address-value (payments:Address): no versions above 1, its latest before the migration",
+		"Rolled back 2 of 2 schemas (permanent delete)",
+	} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("expected %q in output, got:\n%s", line, out.String())
+		}
+	}
+	if _, err := os.Stat(checkpointFile); !os.IsNotExist(err) {
+		t.Errorf("expected the checkpoint to be removed, stat err = %v", err)
+	}
+}
+
+func TestRollbackCmd_ProdGuard(t *testing.T) {
+	server, deletes := deleteRecorder(t, nil)
+	dir := t.TempDir()
+	checkpointFile := filepath.Join(dir, "state.json")
+	writeRollbackCheckpoint(t, checkpointFile)
+	configFile := filepath.Join(dir, "config.yaml")
+	contents := "confluent_cloud:\n  url: " + server.URL + "\n  environment: prod\noutput:\n  dry_run: true\n"
+	if err := os.WriteFile(configFile, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := NewRollbackCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--config", configFile, "--checkpoint", checkpointFile})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "--allow-prod") {
+		t.Fatalf("expected a refusal pointing at --allow-prod, got %v", err)
+	}
+	if len(*deletes) != 0 {
+		t.Errorf("expected no DELETE calls against a prod target, got %v", *deletes)
+	}
+
+	cmd = NewRollbackCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--config", configFile, "--checkpoint", checkpointFile, "--allow-prod"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error with --allow-prod: %v", err)
+	}
+	if len(*deletes) != 3 {
+		t.Errorf("expected 3 DELETE calls with --allow-prod, got %v", *deletes)
+	}
+}
@@ -24,7 +24,16 @@ Features:
 
 	// Add subcommands
 	rootCmd.AddCommand(NewMigrateCmd())
+	rootCmd.AddCommand(NewPlanCmd())
+	rootCmd.AddCommand(NewApplyCmd())
+	rootCmd.AddCommand(NewRollbackCmd())
 	rootCmd.AddCommand(NewValidateCmd())
+	rootCmd.AddCommand(NewConfigCmd())
+	rootCmd.AddCommand(NewCompareRegistriesCmd())
+	rootCmd.AddCommand(NewNormalizeAuditCmd())
+	rootCmd.AddCommand(NewNamingDiffCmd())
+	rootCmd.AddCommand(NewExportCmd())
+	rootCmd.AddCommand(NewDetectRolesCmd())
 	rootCmd.AddCommand(NewVersionCmd(version, buildTime))
 
 	return rootCmd
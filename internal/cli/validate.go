@@ -1,8 +1,14 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 
+	"github.com/akrishnanDG/glue-to-ccsr/internal/extractor"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/loader"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
 	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
 	"github.com/spf13/cobra"
 )
@@ -10,6 +16,7 @@ import (
 // NewValidateCmd creates the validate command
 func NewValidateCmd() *cobra.Command {
 	var configFile string
+	var checkConnectivity bool
 
 	cmd := &cobra.Command{
 		Use:   "validate",
@@ -17,7 +24,11 @@ func NewValidateCmd() *cobra.Command {
 		Long: `Validate the configuration file or command-line arguments without
 actually performing the migration.
 
-This is useful for checking your configuration before running a migration.`,
+This is useful for checking your configuration before running a migration.
+With --check-connectivity, it also makes read-only calls to AWS Glue (listing
+the selected registries) and Confluent Cloud (listing subjects) to confirm
+the credentials work. Each check is printed as OK or FAIL, and the command
+exits non-zero if any check fails.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var cfg *config.Config
 			var err error
@@ -30,17 +41,148 @@ This is useful for checking your configuration before running a migration.`,
 			} else {
 				cfg = config.NewDefaultConfig()
 			}
+			// Credentials from the environment count, as they do for migrate
+			applyEnv(cfg)
 
-			if err := cfg.Validate(); err != nil {
-				return fmt.Errorf("validation failed:\n%w", err)
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
 			}
 
-			fmt.Println("✓ Configuration is valid")
-			return nil
+			var checks []connectivityCheck
+			if checkConnectivity {
+				checks = connectivityChecks(cfg)
+			}
+			return runValidate(ctx, cfg, checks, cmd.OutOrStdout())
 		},
 	}
 
 	cmd.Flags().StringVarP(&configFile, "config", "c", "", "Configuration file path")
+	cmd.Flags().BoolVar(&checkConnectivity, "check-connectivity", false, "Also make read-only calls to AWS Glue and Confluent Cloud")
 
 	return cmd
 }
+
+// registryLister is the Glue call probed by --check-connectivity
+type registryLister interface {
+	ListRegistries(ctx context.Context) ([]*models.GlueRegistry, error)
+}
+
+// subjectLister is the Schema Registry call probed by --check-connectivity
+type subjectLister interface {
+	GetSubjects(ctx context.Context) ([]string, error)
+}
+
+// connectivityCheck is one read-only probe. A check with a skip reason is
+// reported without running.
+type connectivityCheck struct {
+	name string
+	skip string
+	run  func(ctx context.Context) (string, error)
+}
+
+// connectivityChecks builds the probes for cfg. A client that can't be
+// created becomes a failing check rather than an error, so the other checks
+// still run.
+func connectivityChecks(cfg *config.Config) []connectivityCheck {
+	var checks []connectivityCheck
+
+	if cfg.AWS.SourceDir != "" {
+		checks = append(checks, connectivityCheck{name: "AWS Glue", skip: "aws.source_dir is set"})
+	} else if ext, err := extractor.New(cfg); err != nil {
+		checks = append(checks, failedCheck("AWS Glue", fmt.Errorf("failed to create extractor: %w", err)))
+	} else {
+		checks = append(checks, glueCheck(ext))
+	}
+
+	if cfg.ConfluentCloud.URL == "" {
+		checks = append(checks, connectivityCheck{name: "Confluent Cloud", skip: "confluent_cloud.url is not set"})
+	} else if ldr, err := loader.New(cfg); err != nil {
+		checks = append(checks, failedCheck("Confluent Cloud", fmt.Errorf("failed to create loader: %w", err)))
+	} else {
+		checks = append(checks, confluentCheck(ldr))
+	}
+
+	return checks
+}
+
+// glueCheck lists the registries selected by the aws settings
+func glueCheck(lister registryLister) connectivityCheck {
+	return connectivityCheck{
+		name: "AWS Glue",
+		run: func(ctx context.Context) (string, error) {
+			registries, err := lister.ListRegistries(ctx)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%d registries", len(registries)), nil
+		},
+	}
+}
+
+// confluentCheck lists the target's subjects
+func confluentCheck(lister subjectLister) connectivityCheck {
+	return connectivityCheck{
+		name: "Confluent Cloud",
+		run: func(ctx context.Context) (string, error) {
+			subjects, err := lister.GetSubjects(ctx)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%d subjects", len(subjects)), nil
+		},
+	}
+}
+
+// failedCheck reports err without making a call
+func failedCheck(name string, err error) connectivityCheck {
+	return connectivityCheck{
+		name: name,
+		run: func(ctx context.Context) (string, error) {
+			return "", err
+		},
+	}
+}
+
+// runValidate validates cfg, runs checks, and prints one OK, FAIL or SKIP
+// line per result to w. It returns an error if anything failed.
+func runValidate(ctx context.Context, cfg *config.Config, checks []connectivityCheck, w io.Writer) error {
+	failed := 0
+
+	if err := cfg.Validate(); err != nil {
+		var fieldErrs config.ValidationErrors
+		if errors.As(err, &fieldErrs) {
+			for _, fieldErr := range fieldErrs {
+				fmt.Fprintf(w, "FAIL  %s\n", fieldErr.Error())
+			}
+			failed += len(fieldErrs)
+		} else {
+			fmt.Fprintf(w, "FAIL  configuration: %v\n", err)
+			failed++
+		}
+	} else {
+		fmt.Fprintln(w, "OK    configuration")
+	}
+	for _, warning := range cfg.Warnings {
+		fmt.Fprintf(w, "WARN  %s\n", warning)
+	}
+
+	for _, check := range checks {
+		if check.skip != "" {
+			fmt.Fprintf(w, "SKIP  %s: %s\n", check.name, check.skip)
+			continue
+		}
+		detail, err := check.run(ctx)
+		if err != nil {
+			fmt.Fprintf(w, "FAIL  %s: %v\n", check.name, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(w, "OK    %s: %s\n", check.name, detail)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("validation failed: %d problems found", failed)
+	}
+	return nil
+}
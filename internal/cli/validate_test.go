@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/time/rate"
+
+	"github.com/akrishnanDG/glue-to-ccsr/internal/extractor"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/extractor/gluetest"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/loader"
+	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
+)
+
+func TestValidateCmd_InvalidConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "confluent_cloud:\n  url: https://psrc-test.confluent.cloud\noutput:\n  format: xml\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CC_API_KEY", "")
+	t.Setenv("CC_API_SECRET", "")
+	t.Setenv("CC_BEARER_TOKEN", "")
+
+	cmd := NewValidateCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--config", path})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected an error for an invalid config")
+	}
+
+	got := out.String()
+	for _, want := range []string{
+		"FAIL  aws.registry_names: ",
+		"FAIL  confluent_cloud.api_key: ",
+		"FAIL  confluent_cloud.api_secret: ",
+		"FAIL  output.format: ",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "OK    configuration") {
+		t.Errorf("an invalid config must not be reported OK, got:\n%s", got)
+	}
+}
+
+func TestValidateCmd_CredentialsFromEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "aws:\n  region: us-east-1\n  registry_all: true\nconfluent_cloud:\n  url: https://psrc-test.confluent.cloud\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CC_API_KEY", "env-key")
+	t.Setenv("CC_API_SECRET", "env-secret")
+
+	cmd := NewValidateCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--config", path})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected CC_API_KEY and CC_API_SECRET to satisfy validation: %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "OK    configuration") {
+		t.Errorf("expected the configuration to be reported OK, got:\n%s", out.String())
+	}
+}
+
+func TestRunValidate_Connectivity(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/subjects" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`["orders-value","payments-value"]`))
+	}))
+	defer server.Close()
+
+	cfg := config.NewDefaultConfig()
+	cfg.AWS.RegistryNames = []string{"payments"}
+	cfg.ConfluentCloud.URL = server.URL
+	cfg.ConfluentCloud.APIKey = "key"
+	cfg.ConfluentCloud.APISecret = "secret"
+
+	mock := &gluetest.Client{Schemas: map[string]map[string]*gluetest.Schema{"payments": {}}}
+	ext := extractor.NewWithClient(cfg, mock, rate.NewLimiter(rate.Inf, 1))
+	ldr, err := loader.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	checks := []connectivityCheck{glueCheck(ext), confluentCheck(ldr)}
+	if err := runValidate(context.Background(), cfg, checks, &out); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, out.String())
+	}
+	for _, want := range []string{
+		"OK    configuration",
+		"OK    AWS Glue: 1 registries",
+		"OK    Confluent Cloud: 2 subjects",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in output, got:\n%s", want, out.String())
+		}
+	}
+
+	// A rejected probe is reported and fails the run
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error_code":401,"message":"Unauthorized"}`, http.StatusUnauthorized)
+	})
+	out.Reset()
+	if err := runValidate(context.Background(), cfg, []connectivityCheck{confluentCheck(ldr)}, &out); err == nil {
+		t.Fatal("expected an error when the Confluent probe fails")
+	}
+	if !strings.Contains(out.String(), "FAIL  Confluent Cloud: ") {
+		t.Errorf("expected a Confluent Cloud failure, got:\n%s", out.String())
+	}
+}
+
+func TestConnectivityChecks_Skips(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.AWS.SourceDir = t.TempDir()
+	cfg.AWS.RegistryAll = true
+	cfg.Output.DryRun = true
+
+	var out bytes.Buffer
+	if err := runValidate(context.Background(), cfg, connectivityChecks(cfg), &out); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, out.String())
+	}
+	for _, want := range []string{
+		"SKIP  AWS Glue: aws.source_dir is set",
+		"SKIP  Confluent Cloud: confluent_cloud.url is not set",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in output, got:\n%s", want, out.String())
+		}
+	}
+}
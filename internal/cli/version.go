@@ -1,19 +1,61 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"runtime"
 
 	"github.com/spf13/cobra"
 )
 
+// versionInfo is the build metadata printed by the version command
+type versionInfo struct {
+	Version   string `json:"version"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+}
+
 // NewVersionCmd creates the version command
 func NewVersionCmd(version, buildTime string) *cobra.Command {
-	return &cobra.Command{
+	var format string
+
+	cmd := &cobra.Command{
 		Use:   "version",
 		Short: "Print version information",
-		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Printf("glue-to-ccsr %s\n", version)
-			fmt.Printf("Build time: %s\n", buildTime)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			info := versionInfo{
+				Version:   version,
+				BuildTime: buildTime,
+				GoVersion: runtime.Version(),
+				OS:        runtime.GOOS,
+				Arch:      runtime.GOARCH,
+			}
+			return writeVersion(cmd.OutOrStdout(), info, format)
 		},
 	}
+
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: text, json")
+
+	return cmd
+}
+
+// writeVersion renders info to w as text or JSON
+func writeVersion(w io.Writer, info versionInfo, format string) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(info)
+	case "text":
+		fmt.Fprintf(w, "glue-to-ccsr %s\n", info.Version)
+		fmt.Fprintf(w, "Build time: %s\n", info.BuildTime)
+		fmt.Fprintf(w, "Go version: %s\n", info.GoVersion)
+		fmt.Fprintf(w, "OS/Arch:    %s/%s\n", info.OS, info.Arch)
+		return nil
+	default:
+		return fmt.Errorf("unsupported format: %s (expected text or json)", format)
+	}
 }
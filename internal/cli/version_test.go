@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"runtime"
+	"testing"
+)
+
+func TestVersionCmd_JSON(t *testing.T) {
+	cmd := NewVersionCmd("v1.2.3", "2024-05-01T12:00:00Z")
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--format", "json"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var info versionInfo
+	if err := json.Unmarshal(out.Bytes(), &info); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, out.String())
+	}
+	if info.Version != "v1.2.3" {
+		t.Errorf("version = %q, expected v1.2.3", info.Version)
+	}
+	if info.BuildTime != "2024-05-01T12:00:00Z" {
+		t.Errorf("build_time = %q, expected the injected build time", info.BuildTime)
+	}
+	if info.GoVersion != runtime.Version() || info.OS != runtime.GOOS || info.Arch != runtime.GOARCH {
+		t.Errorf("runtime fields = %q %s/%s, expected %q %s/%s", info.GoVersion, info.OS, info.Arch, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	}
+}
+
+func TestVersionCmd_UnsupportedFormat(t *testing.T) {
+	cmd := NewVersionCmd("v1.2.3", "unknown")
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--format", "yaml"})
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
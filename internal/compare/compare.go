@@ -0,0 +1,152 @@
+package compare
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
+)
+
+// Drift describes how the schemas of two registries differ, by schema name
+type Drift struct {
+	OnlyInA   []string `json:"only_in_a"`
+	OnlyInB   []string `json:"only_in_b"`
+	Differing []string `json:"differing"`
+	Identical int      `json:"identical"`
+}
+
+// HasDrift reports whether the registries differ in any way
+func (d *Drift) HasDrift() bool {
+	return len(d.OnlyInA) > 0 || len(d.OnlyInB) > 0 || len(d.Differing) > 0
+}
+
+// Registries compares two registries' schemas by name. Schemas present in
+// both differ when their data formats or canonicalized latest definitions
+// don't match.
+func Registries(a, b []*models.GlueSchema) *Drift {
+	drift := &Drift{
+		OnlyInA:   []string{},
+		OnlyInB:   []string{},
+		Differing: []string{},
+	}
+
+	byName := make(map[string]*models.GlueSchema, len(b))
+	for _, schema := range b {
+		byName[schema.Name] = schema
+	}
+
+	seen := make(map[string]bool, len(a))
+	for _, schemaA := range a {
+		seen[schemaA.Name] = true
+		schemaB, ok := byName[schemaA.Name]
+		if !ok {
+			drift.OnlyInA = append(drift.OnlyInA, schemaA.Name)
+			continue
+		}
+		if schemaA.DataFormat != schemaB.DataFormat ||
+			Canonicalize(schemaA.DataFormat, latestDefinition(schemaA)) != Canonicalize(schemaB.DataFormat, latestDefinition(schemaB)) {
+			drift.Differing = append(drift.Differing, schemaA.Name)
+			continue
+		}
+		drift.Identical++
+	}
+
+	for _, schemaB := range b {
+		if !seen[schemaB.Name] {
+			drift.OnlyInB = append(drift.OnlyInB, schemaB.Name)
+		}
+	}
+
+	sort.Strings(drift.OnlyInA)
+	sort.Strings(drift.OnlyInB)
+	sort.Strings(drift.Differing)
+	return drift
+}
+
+// Canonicalize returns a form of definition that ignores formatting.
+// Avro and JSON Schema are re-encoded with sorted object keys; Protobuf
+// has its whitespace collapsed. Unparseable JSON is compared trimmed.
+func Canonicalize(format models.SchemaType, definition string) string {
+	switch format {
+	case models.SchemaTypeAvro, models.SchemaTypeJSON:
+		var v interface{}
+		if err := json.Unmarshal([]byte(definition), &v); err != nil {
+			return strings.TrimSpace(definition)
+		}
+		out, err := json.Marshal(v)
+		if err != nil {
+			return strings.TrimSpace(definition)
+		}
+		return string(out)
+	default:
+		return strings.Join(strings.Fields(definition), " ")
+	}
+}
+
+// CanonicalJSON re-encodes an Avro or JSON Schema definition with sorted
+// object keys and no insignificant whitespace. Unlike Canonicalize it keeps
+// numbers as written and does not HTML-escape strings, so the result is
+// safe to register in place of the original.
+func CanonicalJSON(definition string) (string, error) {
+	dec := json.NewDecoder(strings.NewReader(definition))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// DocOnlyChange reports whether two Avro definitions are identical apart
+// from their "doc" attributes. Definitions that don't parse never match.
+func DocOnlyChange(previous, next string) bool {
+	var prev, cur interface{}
+	if err := json.Unmarshal([]byte(previous), &prev); err != nil {
+		return false
+	}
+	if err := json.Unmarshal([]byte(next), &cur); err != nil {
+		return false
+	}
+	a, errA := json.Marshal(stripDocs(prev))
+	b, errB := json.Marshal(stripDocs(cur))
+	return errA == nil && errB == nil && string(a) == string(b)
+}
+
+// stripDocs removes "doc" attributes from a decoded Avro schema
+func stripDocs(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			if k == "doc" {
+				continue
+			}
+			out[k] = stripDocs(val)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, val := range t {
+			out[i] = stripDocs(val)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func latestDefinition(schema *models.GlueSchema) string {
+	if len(schema.Versions) == 0 {
+		return ""
+	}
+	return schema.Versions[len(schema.Versions)-1].Definition
+}
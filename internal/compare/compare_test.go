@@ -0,0 +1,100 @@
+package compare
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
+)
+
+func avroSchema(name string, definitions ...string) *models.GlueSchema {
+	schema := &models.GlueSchema{Name: name, DataFormat: models.SchemaTypeAvro}
+	for i, def := range definitions {
+		schema.Versions = append(schema.Versions, models.GlueSchemaVersion{
+			VersionNumber: int64(i + 1),
+			Definition:    def,
+		})
+	}
+	return schema
+}
+
+func TestRegistries_Categories(t *testing.T) {
+	a := []*models.GlueSchema{
+		avroSchema("only-a", `{"type":"string"}`),
+		avroSchema("same", `{"type":"record","name":"Same","fields":[]}`),
+		avroSchema("changed", `{"type":"record","name":"Changed","fields":[]}`),
+	}
+	b := []*models.GlueSchema{
+		// Formatting and key order differ but the definition is the same
+		avroSchema("same", `{
+  "name": "Same",
+  "type": "record",
+  "fields": []
+}`),
+		// An older version matches but the latest does not
+		avroSchema("changed",
+			`{"type":"record","name":"Changed","fields":[]}`,
+			`{"type":"record","name":"Changed","fields":[{"name":"id","type":"long"}]}`),
+		avroSchema("only-b", `{"type":"int"}`),
+	}
+
+	drift := Registries(a, b)
+
+	if !reflect.DeepEqual(drift.OnlyInA, []string{"only-a"}) {
+		t.Errorf("OnlyInA = %v, want [only-a]", drift.OnlyInA)
+	}
+	if !reflect.DeepEqual(drift.OnlyInB, []string{"only-b"}) {
+		t.Errorf("OnlyInB = %v, want [only-b]", drift.OnlyInB)
+	}
+	if !reflect.DeepEqual(drift.Differing, []string{"changed"}) {
+		t.Errorf("Differing = %v, want [changed]", drift.Differing)
+	}
+	if drift.Identical != 1 {
+		t.Errorf("Identical = %d, want 1", drift.Identical)
+	}
+	if !drift.HasDrift() {
+		t.Error("expected HasDrift to be true")
+	}
+}
+
+func TestRegistries_NoDrift(t *testing.T) {
+	a := []*models.GlueSchema{avroSchema("same", `{"type":"string"}`)}
+	b := []*models.GlueSchema{avroSchema("same", ` {"type": "string"} `)}
+
+	drift := Registries(a, b)
+	if drift.HasDrift() {
+		t.Errorf("expected no drift, got %+v", drift)
+	}
+}
+
+func TestCanonicalize_Protobuf(t *testing.T) {
+	a := "syntax = \"proto3\";\nmessage User {\n  string id = 1;\n}\n"
+	b := "syntax = \"proto3\"; message User { string id = 1; }"
+	if Canonicalize(models.SchemaTypeProtobuf, a) != Canonicalize(models.SchemaTypeProtobuf, b) {
+		t.Error("expected Protobuf definitions differing only in whitespace to canonicalize equally")
+	}
+}
+
+func TestDocOnlyChange(t *testing.T) {
+	base := `{"type":"record","name":"User","doc":"A user","fields":[{"name":"id","type":"string","doc":"Identifier"}]}`
+
+	tests := []struct {
+		name string
+		next string
+		want bool
+	}{
+		{"doc text changed", `{"type":"record","name":"User","doc":"A registered user","fields":[{"name":"id","type":"string","doc":"Unique identifier"}]}`, true},
+		{"doc removed", `{"type":"record","name":"User","fields":[{"name":"id","type":"string"}]}`, true},
+		{"field added", `{"type":"record","name":"User","doc":"A user","fields":[{"name":"id","type":"string","doc":"Identifier"},{"name":"email","type":"string"}]}`, false},
+		{"type changed", `{"type":"record","name":"User","doc":"A user","fields":[{"name":"id","type":"long","doc":"Identifier"}]}`, false},
+		{"unparseable", `not json`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DocOnlyChange(base, tt.next); got != tt.want {
+				t.Errorf("DocOnlyChange() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package extractor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
+	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
+)
+
+// FileSource reads schemas from a directory written by the export command,
+// laid out as <dir>/<registry>/<schema>.json, instead of calling AWS
+type FileSource struct {
+	config *config.Config
+	dir    string
+}
+
+// NewFileSource creates a FileSource reading from aws.source_dir
+func NewFileSource(cfg *config.Config) *FileSource {
+	return &FileSource{
+		config: cfg,
+		dir:    cfg.AWS.SourceDir,
+	}
+}
+
+// ExtractAll reads all schemas from the registries selected by the aws
+// settings, applying registry_exclude, schema_filter and tag_filter as Glue
+// extraction does
+func (f *FileSource) ExtractAll(ctx context.Context) ([]*models.GlueSchema, error) {
+	registries, err := f.registries()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get registries: %w", err)
+	}
+
+	var allSchemas []*models.GlueSchema
+	for _, registry := range registries {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		schemas, err := f.readRegistry(registry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read schemas from registry %s: %w", registry, err)
+		}
+		allSchemas = append(allSchemas, schemas...)
+	}
+
+	return allSchemas, nil
+}
+
+// GetSchema reads a single schema with all its versions
+func (f *FileSource) GetSchema(ctx context.Context, registryName, schemaName string) (*models.GlueSchema, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return f.readSchema(filepath.Join(f.dir, registryName, schemaName+".json"))
+}
+
+// GetLatestSchema reads a single schema. The export holds every definition,
+// so they all come back filled in.
+func (f *FileSource) GetLatestSchema(ctx context.Context, registryName, schemaName string) (*models.GlueSchema, error) {
+	return f.GetSchema(ctx, registryName, schemaName)
+}
+
+// registries lists the registry directories to read, sorted by name when
+// aws.registry_all is set
+func (f *FileSource) registries() ([]string, error) {
+	if !f.config.AWS.RegistryAll {
+		for _, name := range f.config.AWS.RegistryNames {
+			info, err := os.Stat(filepath.Join(f.dir, name))
+			if err != nil {
+				return nil, fmt.Errorf("failed to get registry %s: %w", name, err)
+			}
+			if !info.IsDir() {
+				return nil, fmt.Errorf("failed to get registry %s: not a directory", name)
+			}
+		}
+		return f.config.AWS.RegistryNames, nil
+	}
+
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return nil, err
+	}
+	var registries []string
+	for _, entry := range entries {
+		if entry.IsDir() && !isExcluded(f.config.AWS, entry.Name()) {
+			registries = append(registries, entry.Name())
+		}
+	}
+	return registries, nil
+}
+
+// ExtractStream reads the same schemas as ExtractAll, handing each one to fn
+// as it is read instead of collecting them
+func (f *FileSource) ExtractStream(ctx context.Context, fn func(*models.GlueSchema) error) error {
+	registries, err := f.registries()
+	if err != nil {
+		return fmt.Errorf("failed to get registries: %w", err)
+	}
+
+	for _, registry := range registries {
+		if err := f.streamRegistry(ctx, registry, fn); err != nil {
+			return fmt.Errorf("failed to read schemas from registry %s: %w", registry, err)
+		}
+	}
+	return nil
+}
+
+// readRegistry reads every schema file in a registry directory, in name order
+func (f *FileSource) readRegistry(registryName string) ([]*models.GlueSchema, error) {
+	var schemas []*models.GlueSchema
+	err := f.streamRegistry(context.Background(), registryName, func(schema *models.GlueSchema) error {
+		schemas = append(schemas, schema)
+		return nil
+	})
+	return schemas, err
+}
+
+// streamRegistry reads the schema files in a registry directory in name
+// order, handing each one that passes the tag filter to fn
+func (f *FileSource) streamRegistry(ctx context.Context, registryName string, fn func(*models.GlueSchema) error) error {
+	entries, err := os.ReadDir(filepath.Join(f.dir, registryName))
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		schemaName := strings.TrimSuffix(entry.Name(), ".json")
+		if f.config.AWS.SchemaFilter != "" {
+			matched, err := matchPattern(f.config.AWS, f.config.AWS.SchemaFilter, schemaName)
+			if err != nil {
+				return fmt.Errorf("invalid schema filter pattern: %w", err)
+			}
+			if !matched {
+				continue
+			}
+		}
+		if !isSchemaIncluded(f.config.AWS, schemaName) {
+			continue
+		}
+		names = append(names, schemaName)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		schema, err := f.readSchema(filepath.Join(f.dir, registryName, name+".json"))
+		if err != nil {
+			return err
+		}
+		if !matchesTags(f.config.AWS.TagFilter, schema.Tags) {
+			continue
+		}
+		if err := fn(schema); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readSchema decodes one exported schema file, ordering its versions
+func (f *FileSource) readSchema(path string) (*models.GlueSchema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("schema file %s not found", path)
+		}
+		return nil, err
+	}
+
+	var schema models.GlueSchema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("failed to parse schema file %s: %w", path, err)
+	}
+	if schema.Name == "" || schema.RegistryName == "" {
+		return nil, fmt.Errorf("schema file %s is missing name or registry_name", path)
+	}
+	sortVersions(schema.Versions)
+	return &schema, nil
+}
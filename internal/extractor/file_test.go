@@ -0,0 +1,131 @@
+package extractor
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
+	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
+)
+
+// writeExport writes schemas to dir in the layout produced by the export
+// command
+func writeExport(t *testing.T, dir string, schemas ...*models.GlueSchema) {
+	t.Helper()
+	for _, schema := range schemas {
+		registryDir := filepath.Join(dir, schema.RegistryName)
+		if err := os.MkdirAll(registryDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		data, err := json.MarshalIndent(schema, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(registryDir, schema.Name+".json"), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestFileSource_RoundTrip
+// ---------------------------------------------------------------------------
+
+func TestFileSource_RoundTrip(t *testing.T) {
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	order := &models.GlueSchema{
+		Name:          "OrderEvent",
+		RegistryName:  "payments",
+		ARN:           "arn:aws:glue:us-east-1:123456789012:schema/payments/OrderEvent",
+		DataFormat:    models.SchemaTypeAvro,
+		Compatibility: "BACKWARD",
+		Tags:          map[string]string{"team": "payments"},
+		LatestVersion: 2,
+		CreatedTime:   created,
+		Versions: []models.GlueSchemaVersion{
+			{VersionNumber: 1, SchemaVersionID: "ver-001", Definition: `{"type":"record","name":"Order","fields":[]}`, Status: "AVAILABLE", CreatedTime: created},
+			{VersionNumber: 2, SchemaVersionID: "ver-002", Definition: `{"type":"record","name":"Order","fields":[{"name":"id","type":"string"}]}`, Status: "AVAILABLE", CreatedTime: created},
+		},
+	}
+	refund := &models.GlueSchema{Name: "RefundEvent", RegistryName: "payments", DataFormat: models.SchemaTypeJSON}
+	archived := &models.GlueSchema{Name: "OrderEvent", RegistryName: "archive", DataFormat: models.SchemaTypeAvro}
+
+	dir := t.TempDir()
+	writeExport(t, dir, order, refund, archived)
+
+	cfg := config.NewDefaultConfig()
+	cfg.AWS.SourceDir = dir
+	cfg.AWS.RegistryAll = true
+	cfg.AWS.RegistryExclude = []string{"archive"}
+	cfg.AWS.SchemaFilter = "Order*"
+	src := NewFileSource(cfg)
+
+	schemas, err := src.ExtractAll(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(schemas) != 1 {
+		t.Fatalf("got %d schemas, want only payments.OrderEvent", len(schemas))
+	}
+	if !reflect.DeepEqual(schemas[0], order) {
+		t.Errorf("round-tripped schema = %+v, want %+v", schemas[0], order)
+	}
+
+	var streamed []*models.GlueSchema
+	err = src.ExtractStream(context.Background(), func(schema *models.GlueSchema) error {
+		streamed = append(streamed, schema)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ExtractStream: %v", err)
+	}
+	if !reflect.DeepEqual(streamed, schemas) {
+		t.Errorf("streamed %+v, want the same schemas as ExtractAll", streamed)
+	}
+
+	got, err := src.GetSchema(context.Background(), "payments", "RefundEvent")
+	if err != nil {
+		t.Fatalf("GetSchema: %v", err)
+	}
+	if got.Name != "RefundEvent" || got.DataFormat != models.SchemaTypeJSON {
+		t.Errorf("GetSchema = %+v, want payments.RefundEvent", got)
+	}
+
+	if _, err := src.GetSchema(context.Background(), "payments", "Missing"); err == nil {
+		t.Error("expected an error for a schema not in the export")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestFileSource_RegistryNames
+// ---------------------------------------------------------------------------
+
+func TestFileSource_RegistryNames(t *testing.T) {
+	dir := t.TempDir()
+	writeExport(t, dir,
+		&models.GlueSchema{Name: "OrderEvent", RegistryName: "payments", DataFormat: models.SchemaTypeAvro},
+		&models.GlueSchema{Name: "UserEvent", RegistryName: "users", DataFormat: models.SchemaTypeAvro},
+	)
+
+	cfg := config.NewDefaultConfig()
+	cfg.AWS.SourceDir = dir
+	cfg.AWS.RegistryNames = []string{"users"}
+
+	schemas, err := NewFileSource(cfg).ExtractAll(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(schemas) != 1 || schemas[0].RegistryName != "users" {
+		t.Errorf("schemas = %+v, want only the users registry", schemas)
+	}
+
+	cfg.AWS.RegistryNames = []string{"missing"}
+	if _, err := NewFileSource(cfg).ExtractAll(context.Background()); err == nil {
+		t.Error("expected an error for a registry not in the export")
+	}
+}
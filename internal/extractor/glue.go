@@ -2,19 +2,29 @@ package extractor
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/glue"
 	"github.com/aws/aws-sdk-go-v2/service/glue/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/backoff"
 	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/worker"
 	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
 	"github.com/schollz/progressbar/v3"
+	"golang.org/x/sync/errgroup"
 	"golang.org/x/time/rate"
 )
 
@@ -26,65 +36,282 @@ type GlueAPI interface {
 	GetSchema(ctx context.Context, params *glue.GetSchemaInput, optFns ...func(*glue.Options)) (*glue.GetSchemaOutput, error)
 	ListSchemaVersions(ctx context.Context, params *glue.ListSchemaVersionsInput, optFns ...func(*glue.Options)) (*glue.ListSchemaVersionsOutput, error)
 	GetSchemaVersion(ctx context.Context, params *glue.GetSchemaVersionInput, optFns ...func(*glue.Options)) (*glue.GetSchemaVersionOutput, error)
+	GetTags(ctx context.Context, params *glue.GetTagsInput, optFns ...func(*glue.Options)) (*glue.GetTagsOutput, error)
 }
 
 // GlueExtractor extracts schemas from AWS Glue Schema Registry
 type GlueExtractor struct {
-	client      GlueAPI
-	config      *config.Config
-	rateLimiter *rate.Limiter
+	client GlueAPI
+	config *config.Config
+
+	// versionSem bounds in-flight GetSchemaVersion calls across all schemas
+	versionSem chan struct{}
+
+	// pendingPollInterval spaces GetSchemaVersion polls while waiting on a
+	// PENDING version
+	pendingPollInterval time.Duration
+
+	// tagsDenied is set once glue:GetTags is denied, after which tags are
+	// no longer requested for metadata
+	tagsDenied atomic.Bool
+
+	// tagsMu guards tagCache, the tags fetched so far by schema ARN, so a
+	// schema's tags are fetched once however often it is read
+	tagsMu   sync.Mutex
+	tagCache map[string]map[string]string
 }
 
+// defaultPendingPollInterval is how often a PENDING version is re-checked
+const defaultPendingPollInterval = 5 * time.Second
+
 // New creates a new GlueExtractor
 func New(cfg *config.Config) (*GlueExtractor, error) {
-	// Load AWS configuration
+	awsCfg, err := LoadAWSConfig(context.Background(), cfg.AWS)
+	if err != nil {
+		return nil, err
+	}
+
+	// retryingClient does the retrying, so the SDK's own retryer is turned
+	// off rather than multiplying attempts and bypassing the rate limit
+	client := glue.NewFromConfig(awsCfg, func(o *glue.Options) {
+		o.Retryer = aws.NopRetryer{}
+	})
+
+	// Create rate limiter
+	limiter := rate.NewLimiter(rate.Limit(cfg.Concurrency.AWSRateLimit), 1)
+
+	return &GlueExtractor{
+		client:              newRetryingClient(cfg, client, limiter),
+		config:              cfg,
+		versionSem:          newVersionSemaphore(cfg),
+		pendingPollInterval: defaultPendingPollInterval,
+	}, nil
+}
+
+// LoadAWSConfig loads the AWS configuration for the aws settings: explicit
+// keys when both are set, else the named profile, else the default credential
+// chain, then assumes aws.role_arn if configured
+func LoadAWSConfig(ctx context.Context, awsSettings config.AWSConfig) (aws.Config, error) {
 	opts := []func(*awsconfig.LoadOptions) error{
-		awsconfig.WithRegion(cfg.AWS.Region),
+		awsconfig.WithRegion(awsSettings.Region),
 	}
 
 	// Use explicit credentials if provided
-	if cfg.AWS.AccessKeyID != "" && cfg.AWS.SecretAccessKey != "" {
+	if awsSettings.AccessKeyID != "" && awsSettings.SecretAccessKey != "" {
 		opts = append(opts, awsconfig.WithCredentialsProvider(
 			aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
 				return aws.Credentials{
-					AccessKeyID:     cfg.AWS.AccessKeyID,
-					SecretAccessKey: cfg.AWS.SecretAccessKey,
+					AccessKeyID:     awsSettings.AccessKeyID,
+					SecretAccessKey: awsSettings.SecretAccessKey,
 				}, nil
 			}),
 		))
-	} else if cfg.AWS.Profile != "" {
+	} else if awsSettings.Profile != "" {
 		// Use named profile
-		opts = append(opts, awsconfig.WithSharedConfigProfile(cfg.AWS.Profile))
+		opts = append(opts, awsconfig.WithSharedConfigProfile(awsSettings.Profile))
 	}
 	// Otherwise, use default credential chain (env vars, ~/.aws/credentials, etc.)
 
-	awsCfg, err := awsconfig.LoadDefaultConfig(context.Background(), opts...)
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+		return aws.Config{}, fmt.Errorf("failed to load AWS config: %w", err)
 	}
+	assumeRole(&awsCfg, awsSettings)
+	return awsCfg, nil
+}
 
-	client := glue.NewFromConfig(awsCfg)
-
-	// Create rate limiter
-	limiter := rate.NewLimiter(rate.Limit(cfg.Concurrency.AWSRateLimit), 1)
-
-	return &GlueExtractor{
-		client:      client,
-		config:      cfg,
-		rateLimiter: limiter,
-	}, nil
+// assumeRole swaps awsCfg's credentials for ones from assuming aws.role_arn,
+// using the credentials already loaded to call STS. It does nothing when no
+// role is configured.
+func assumeRole(awsCfg *aws.Config, awsSettings config.AWSConfig) {
+	if awsSettings.RoleARN == "" {
+		return
+	}
+	provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(*awsCfg), awsSettings.RoleARN, func(o *stscreds.AssumeRoleOptions) {
+		if awsSettings.ExternalID != "" {
+			o.ExternalID = aws.String(awsSettings.ExternalID)
+		}
+		if awsSettings.SessionName != "" {
+			o.RoleSessionName = awsSettings.SessionName
+		}
+	})
+	awsCfg.Credentials = aws.NewCredentialsCache(provider)
 }
 
 // NewWithClient creates a GlueExtractor with an injected client (for testing).
 func NewWithClient(cfg *config.Config, client GlueAPI, limiter *rate.Limiter) *GlueExtractor {
 	return &GlueExtractor{
-		client:      client,
-		config:      cfg,
-		rateLimiter: limiter,
+		client:              newRetryingClient(cfg, client, limiter),
+		config:              cfg,
+		versionSem:          newVersionSemaphore(cfg),
+		pendingPollInterval: defaultPendingPollInterval,
+	}
+}
+
+// glueRetryableCodes are the Glue error codes worth retrying: throttling and
+// transient service-side failures. Anything else, such as AccessDenied or
+// EntityNotFound, fails immediately.
+var glueRetryableCodes = map[string]bool{
+	"ThrottlingException":       true,
+	"Throttling":                true,
+	"RequestLimitExceeded":      true,
+	"TooManyRequestsException":  true,
+	"InternalServiceException":  true,
+	"OperationTimeoutException": true,
+	"ServiceUnavailable":        true,
+}
+
+// retryingClient wraps a GlueAPI so every call is retried on throttling and
+// 5xx errors with exponential backoff and jitter. It makes up to
+// concurrency.retry_attempts retries, starting from concurrency.retry_delay.
+// Every attempt, retries included, waits on the aws_rate_limit limiter.
+type retryingClient struct {
+	api      GlueAPI
+	limiter  *rate.Limiter
+	attempts int
+	delay    time.Duration
+}
+
+func newRetryingClient(cfg *config.Config, api GlueAPI, limiter *rate.Limiter) *retryingClient {
+	return &retryingClient{
+		api:      api,
+		limiter:  limiter,
+		attempts: cfg.Concurrency.RetryAttempts,
+		delay:    cfg.Concurrency.RetryDelay,
 	}
 }
 
-// ExtractAll extracts all schemas from all specified registries
+// do runs call until it succeeds, fails with a non-retryable error or runs
+// out of attempts. Backoff sleeps end early when ctx is cancelled.
+func (c *retryingClient) do(ctx context.Context, op string, call func() error) error {
+	for attempt := 0; ; attempt++ {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return err
+		}
+		err := call()
+		if err == nil || attempt >= c.attempts || !isRetryableGlueError(err) {
+			return err
+		}
+
+		delay := backoff.WithJitter(c.delay, attempt)
+		slog.Debug("retrying throttled Glue call", "operation", op, "attempt", attempt+1, "delay", delay, "error", err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// isAccessDenied reports whether err is Glue refusing the call for lack of
+// IAM permissions
+func isAccessDenied(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "AccessDeniedException", "AccessDenied":
+			return true
+		}
+	}
+	var respErr interface{ HTTPStatusCode() int }
+	return errors.As(err, &respErr) && respErr.HTTPStatusCode() == 403
+}
+
+// isRetryableGlueError reports whether err is throttling or a server error
+func isRetryableGlueError(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && glueRetryableCodes[apiErr.ErrorCode()] {
+		return true
+	}
+	var respErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &respErr) && respErr.HTTPStatusCode() >= 500 {
+		return true
+	}
+	return false
+}
+
+func (c *retryingClient) ListRegistries(ctx context.Context, params *glue.ListRegistriesInput, optFns ...func(*glue.Options)) (*glue.ListRegistriesOutput, error) {
+	var out *glue.ListRegistriesOutput
+	err := c.do(ctx, "ListRegistries", func() (err error) {
+		out, err = c.api.ListRegistries(ctx, params, optFns...)
+		return err
+	})
+	return out, err
+}
+
+func (c *retryingClient) GetRegistry(ctx context.Context, params *glue.GetRegistryInput, optFns ...func(*glue.Options)) (*glue.GetRegistryOutput, error) {
+	var out *glue.GetRegistryOutput
+	err := c.do(ctx, "GetRegistry", func() (err error) {
+		out, err = c.api.GetRegistry(ctx, params, optFns...)
+		return err
+	})
+	return out, err
+}
+
+func (c *retryingClient) ListSchemas(ctx context.Context, params *glue.ListSchemasInput, optFns ...func(*glue.Options)) (*glue.ListSchemasOutput, error) {
+	var out *glue.ListSchemasOutput
+	err := c.do(ctx, "ListSchemas", func() (err error) {
+		out, err = c.api.ListSchemas(ctx, params, optFns...)
+		return err
+	})
+	return out, err
+}
+
+func (c *retryingClient) GetSchema(ctx context.Context, params *glue.GetSchemaInput, optFns ...func(*glue.Options)) (*glue.GetSchemaOutput, error) {
+	var out *glue.GetSchemaOutput
+	err := c.do(ctx, "GetSchema", func() (err error) {
+		out, err = c.api.GetSchema(ctx, params, optFns...)
+		return err
+	})
+	return out, err
+}
+
+func (c *retryingClient) ListSchemaVersions(ctx context.Context, params *glue.ListSchemaVersionsInput, optFns ...func(*glue.Options)) (*glue.ListSchemaVersionsOutput, error) {
+	var out *glue.ListSchemaVersionsOutput
+	err := c.do(ctx, "ListSchemaVersions", func() (err error) {
+		out, err = c.api.ListSchemaVersions(ctx, params, optFns...)
+		return err
+	})
+	return out, err
+}
+
+func (c *retryingClient) GetSchemaVersion(ctx context.Context, params *glue.GetSchemaVersionInput, optFns ...func(*glue.Options)) (*glue.GetSchemaVersionOutput, error) {
+	var out *glue.GetSchemaVersionOutput
+	err := c.do(ctx, "GetSchemaVersion", func() (err error) {
+		out, err = c.api.GetSchemaVersion(ctx, params, optFns...)
+		return err
+	})
+	return out, err
+}
+
+func (c *retryingClient) GetTags(ctx context.Context, params *glue.GetTagsInput, optFns ...func(*glue.Options)) (*glue.GetTagsOutput, error) {
+	var out *glue.GetTagsOutput
+	err := c.do(ctx, "GetTags", func() (err error) {
+		out, err = c.api.GetTags(ctx, params, optFns...)
+		return err
+	})
+	return out, err
+}
+
+// newVersionSemaphore sizes the shared version-fetch semaphore from
+// VersionFetchConcurrency, falling back to the worker count.
+func newVersionSemaphore(cfg *config.Config) chan struct{} {
+	size := cfg.Concurrency.VersionFetchConcurrency
+	if size <= 0 {
+		size = cfg.Concurrency.Workers
+	}
+	if size <= 0 {
+		size = 10
+	}
+	return make(chan struct{}, size)
+}
+
+// ExtractAll extracts all schemas from all specified registries. Up to
+// concurrency.workers registries are listed and fetched at once, all sharing
+// the rate limiter; schemas come back grouped by registry in registry order.
+// With migration.since, schemas not updated since are returned too, marked
+// Unchanged, so references to them can be resolved. The first error stops
+// the remaining registries and is returned.
 func (e *GlueExtractor) ExtractAll(ctx context.Context) ([]*models.GlueSchema, error) {
 	// Get list of registries to process
 	registries, err := e.getRegistries(ctx)
@@ -92,25 +319,124 @@ func (e *GlueExtractor) ExtractAll(ctx context.Context) ([]*models.GlueSchema, e
 		return nil, fmt.Errorf("failed to get registries: %w", err)
 	}
 
+	// List every registry first so a single progress bar covers them all
+	schemaNames := make([][]string, len(registries))
+	unchanged := make([]map[string]bool, len(registries))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(e.registryWorkers())
+	for i, registry := range registries {
+		g.Go(func() error {
+			if !e.config.Migration.Since.IsZero() {
+				unchanged[i] = make(map[string]bool)
+			}
+			names, err := e.listRegistrySchemas(gctx, registry.Name, unchanged[i])
+			if err != nil {
+				return fmt.Errorf("failed to extract schemas from registry %s: %w", registry.Name, err)
+			}
+			schemaNames[i] = names
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	total := 0
+	for _, names := range schemaNames {
+		total += len(names)
+	}
+	progress := e.newFetchProgress(total)
+
+	registrySchemas := make([][]*models.GlueSchema, len(registries))
+	g, gctx = errgroup.WithContext(ctx)
+	g.SetLimit(e.registryWorkers())
+	for i, registry := range registries {
+		g.Go(func() error {
+			schemas, err := e.fetchSchemasParallel(gctx, registry.Name, schemaNames[i], unchanged[i], progress)
+			if err != nil {
+				return fmt.Errorf("failed to extract schemas from registry %s: %w", registry.Name, err)
+			}
+			registrySchemas[i] = schemas
+			return nil
+		})
+	}
+	err = g.Wait()
+	progress.Finish()
+	if err != nil {
+		return nil, err
+	}
+
 	var allSchemas []*models.GlueSchema
+	for _, schemas := range registrySchemas {
+		allSchemas = append(allSchemas, schemas...)
+	}
+
+	return allSchemas, nil
+}
+
+// registryWorkers is how many registries ExtractAll works on at once
+func (e *GlueExtractor) registryWorkers() int {
+	if e.config.Concurrency.Workers <= 0 {
+		return 10
+	}
+	return e.config.Concurrency.Workers
+}
+
+// ExtractStream extracts the same schemas as ExtractAll but hands each one to
+// fn as soon as its batch of concurrency.batch_size is fetched, so at most one
+// batch is held in memory. Schemas within a batch arrive in no particular
+// order. An error from fn stops extraction and is returned.
+func (e *GlueExtractor) ExtractStream(ctx context.Context, fn func(*models.GlueSchema) error) error {
+	registries, err := e.getRegistries(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get registries: %w", err)
+	}
 
 	for _, registry := range registries {
-		schemas, err := e.extractRegistrySchemas(ctx, registry.Name)
-		if err != nil {
-			return nil, fmt.Errorf("failed to extract schemas from registry %s: %w", registry.Name, err)
+		if err := e.streamRegistrySchemas(ctx, registry.Name, fn); err != nil {
+			return fmt.Errorf("failed to extract schemas from registry %s: %w", registry.Name, err)
 		}
-		allSchemas = append(allSchemas, schemas...)
 	}
+	return nil
+}
 
-	return allSchemas, nil
+// ListRegistries returns the registries selected by the aws settings without
+// reading their schemas
+func (e *GlueExtractor) ListRegistries(ctx context.Context) ([]*models.GlueRegistry, error) {
+	registries, err := e.getRegistries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get registries: %w", err)
+	}
+	return registries, nil
+}
+
+// ExtractRegistry extracts all schemas from a single registry, ignoring the
+// configured registry selection
+func (e *GlueExtractor) ExtractRegistry(ctx context.Context, registryName string) ([]*models.GlueSchema, error) {
+	schemas, err := e.extractRegistrySchemas(ctx, registryName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract schemas from registry %s: %w", registryName, err)
+	}
+	return schemas, nil
 }
 
 // GetSchema gets a single schema with all its versions
 func (e *GlueExtractor) GetSchema(ctx context.Context, registryName, schemaName string) (*models.GlueSchema, error) {
-	// Wait for rate limiter
-	if err := e.rateLimiter.Wait(ctx); err != nil {
-		return nil, err
-	}
+	return e.getSchema(ctx, registryName, schemaName, false, false)
+}
+
+// GetLatestSchema gets a single schema with all its versions listed but only
+// the latest one's definition fetched
+func (e *GlueExtractor) GetLatestSchema(ctx context.Context, registryName, schemaName string) (*models.GlueSchema, error) {
+	return e.getSchema(ctx, registryName, schemaName, true, false)
+}
+
+// getSchema gets a single schema. With latestOnly every version is listed
+// but only the latest definition is fetched, leaving the others empty. An
+// unchanged schema is read the same way, listing the versions created before
+// migration.since too, and is marked Unchanged.
+func (e *GlueExtractor) getSchema(ctx context.Context, registryName, schemaName string, latestOnly, unchanged bool) (*models.GlueSchema, error) {
+	latestOnly = latestOnly || unchanged
 
 	// Get schema metadata
 	schemaInput := &glue.GetSchemaInput{
@@ -133,6 +459,7 @@ func (e *GlueExtractor) GetSchema(ctx context.Context, registryName, schemaName
 		DataFormat:    models.SchemaType(schemaResp.DataFormat),
 		Compatibility: string(schemaResp.Compatibility),
 		LatestVersion: aws.ToInt64(schemaResp.LatestSchemaVersion),
+		Unchanged:     unchanged,
 	}
 
 	if schemaResp.CreatedTime != nil {
@@ -143,15 +470,90 @@ func (e *GlueExtractor) GetSchema(ctx context.Context, registryName, schemaName
 	}
 
 	// Get all versions
-	versions, err := e.getSchemaVersions(ctx, registryName, schemaName)
+	versions, err := e.getSchemaVersions(ctx, registryName, schemaName, latestOnly, unchanged)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get schema versions: %w", err)
 	}
-	schema.Versions = versions
+	schema.Versions, err = e.resolvePending(ctx, registryName, schemaName, versions)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pending versions are settled first so the definition fetched is the
+	// one that will be registered
+	if n := len(schema.Versions); latestOnly && n > 0 && schema.Versions[n-1].Definition == "" {
+		latest := &schema.Versions[n-1]
+		fetched, err := e.fetchVersionsParallel(ctx, registryName, schemaName, []int64{latest.VersionNumber})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get schema versions: %w", err)
+		}
+		if len(fetched) > 0 {
+			latest.Definition = fetched[0].Definition
+		}
+	}
+
+	// Tags are only needed when they are migrated or filtered on. Policies
+	// written before tags were migrated by default lack glue:GetTags, so a
+	// denial only drops the tags; tag_filter fails on it in selectSchemas.
+	if FetchesTags(e.config) && schema.ARN != "" && !e.tagsDenied.Load() {
+		schema.Tags, err = e.getTags(ctx, schema.ARN)
+		if isAccessDenied(err) {
+			if e.tagsDenied.CompareAndSwap(false, true) {
+				slog.Warn("glue:GetTags is denied; migrating without Glue tags", "error", err)
+			}
+			schema.Tags, err = nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	return schema, nil
 }
 
+// getTags fetches the tags of a schema, reusing any fetched earlier in the
+// run such as by aws.tag_filter
+func (e *GlueExtractor) getTags(ctx context.Context, arn string) (map[string]string, error) {
+	e.tagsMu.Lock()
+	tags, ok := e.tagCache[arn]
+	e.tagsMu.Unlock()
+	if ok {
+		return tags, nil
+	}
+
+	tagsResp, err := e.client.GetTags(ctx, &glue.GetTagsInput{ResourceArn: aws.String(arn)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schema tags: %w", err)
+	}
+
+	e.tagsMu.Lock()
+	defer e.tagsMu.Unlock()
+	if e.tagCache == nil {
+		e.tagCache = make(map[string]map[string]string)
+	}
+	e.tagCache[arn] = tagsResp.Tags
+	return tagsResp.Tags, nil
+}
+
+// FetchesTags reports whether schema tags are fetched from Glue: when they
+// are migrated as subject metadata or schema properties, or aws.tag_filter
+// is set
+func FetchesTags(cfg *config.Config) bool {
+	return len(cfg.AWS.TagFilter) > 0 ||
+		(cfg.Metadata.Strategy != "skip" && cfg.Metadata.TagsAsProperties) ||
+		(cfg.Metadata.Strategy == "migrate" && cfg.Metadata.MigrateTags)
+}
+
+// matchesTags reports whether tags carry every key/value pair in filter
+func matchesTags(filter, tags map[string]string) bool {
+	for k, v := range filter {
+		if got, ok := tags[k]; !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
+
 func (e *GlueExtractor) getRegistries(ctx context.Context) ([]*models.GlueRegistry, error) {
 	var registries []*models.GlueRegistry
 
@@ -165,7 +567,7 @@ func (e *GlueExtractor) getRegistries(ctx context.Context) ([]*models.GlueRegist
 		// Filter out excluded registries
 		var filtered []*models.GlueRegistry
 		for _, reg := range registries {
-			if !e.isExcluded(reg.Name) {
+			if !isExcluded(e.config.AWS, reg.Name) {
 				filtered = append(filtered, reg)
 			}
 		}
@@ -189,10 +591,6 @@ func (e *GlueExtractor) listAllRegistries(ctx context.Context) ([]*models.GlueRe
 	var nextToken *string
 
 	for {
-		if err := e.rateLimiter.Wait(ctx); err != nil {
-			return nil, err
-		}
-
 		input := &glue.ListRegistriesInput{
 			NextToken: nextToken,
 		}
@@ -220,10 +618,6 @@ func (e *GlueExtractor) listAllRegistries(ctx context.Context) ([]*models.GlueRe
 }
 
 func (e *GlueExtractor) getRegistry(ctx context.Context, name string) (*models.GlueRegistry, error) {
-	if err := e.rateLimiter.Wait(ctx); err != nil {
-		return nil, err
-	}
-
 	input := &glue.GetRegistryInput{
 		RegistryId: &types.RegistryId{
 			RegistryName: aws.String(name),
@@ -252,15 +646,26 @@ func (e *GlueExtractor) getRegistry(ctx context.Context, name string) (*models.G
 }
 
 func (e *GlueExtractor) extractRegistrySchemas(ctx context.Context, registryName string) ([]*models.GlueSchema, error) {
-	// First, collect all schema names
+	schemaNames, err := e.listRegistrySchemas(ctx, registryName, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Now fetch all schemas in parallel using worker pool
+	progress := e.newFetchProgress(len(schemaNames))
+	schemas, err := e.fetchSchemasParallel(ctx, registryName, schemaNames, nil, progress)
+	progress.Finish()
+	return schemas, err
+}
+
+// listRegistrySchemas returns the names of a registry's schemas that pass the
+// schema selection. Schemas that only fail migration.since are returned too
+// and recorded in unchanged, when it is given.
+func (e *GlueExtractor) listRegistrySchemas(ctx context.Context, registryName string, unchanged map[string]bool) ([]string, error) {
 	var schemaNames []string
 	var nextToken *string
 
 	for {
-		if err := e.rateLimiter.Wait(ctx); err != nil {
-			return nil, err
-		}
-
 		input := &glue.ListSchemasInput{
 			RegistryId: &types.RegistryId{
 				RegistryName: aws.String(registryName),
@@ -273,22 +678,11 @@ func (e *GlueExtractor) extractRegistrySchemas(ctx context.Context, registryName
 			return nil, fmt.Errorf("failed to list schemas: %w", err)
 		}
 
-		for _, s := range resp.Schemas {
-			schemaName := aws.ToString(s.SchemaName)
-
-			// Apply schema filter if specified
-			if e.config.AWS.SchemaFilter != "" {
-				matched, err := filepath.Match(e.config.AWS.SchemaFilter, schemaName)
-				if err != nil {
-					return nil, fmt.Errorf("invalid schema filter pattern: %w", err)
-				}
-				if !matched {
-					continue
-				}
-			}
-
-			schemaNames = append(schemaNames, schemaName)
+		names, err := e.selectSchemas(ctx, resp.Schemas, unchanged)
+		if err != nil {
+			return nil, err
 		}
+		schemaNames = append(schemaNames, names...)
 
 		if resp.NextToken == nil {
 			break
@@ -296,9 +690,12 @@ func (e *GlueExtractor) extractRegistrySchemas(ctx context.Context, registryName
 		nextToken = resp.NextToken
 	}
 
-	// Create progress bar for schema extraction
-	bar := progressbar.NewOptions(len(schemaNames),
-		progressbar.OptionSetDescription("      Fetching schemas"),
+	return schemaNames, nil
+}
+
+// newFetchProgress creates the progress bar for fetching total schemas
+func (e *GlueExtractor) newFetchProgress(total int) *worker.Progress {
+	return worker.NewProgress(e.config.Output, "Fetching schemas", total,
 		progressbar.OptionSetWidth(50),
 		progressbar.OptionShowCount(),
 		progressbar.OptionSetTheme(progressbar.Theme{
@@ -309,24 +706,127 @@ func (e *GlueExtractor) extractRegistrySchemas(ctx context.Context, registryName
 			BarEnd:        "]",
 		}),
 	)
-
-	// Now fetch all schemas in parallel using worker pool
-	schemas, err := e.fetchSchemasParallel(ctx, registryName, schemaNames, bar)
-	bar.Finish()
-	fmt.Println()
-	return schemas, err
 }
 
-func (e *GlueExtractor) getSchemaVersions(ctx context.Context, registryName, schemaName string) ([]models.GlueSchemaVersion, error) {
-	// First, collect all version numbers
-	var versionNumbers []int64
-	var nextToken *string
+// streamRegistrySchemas lists a registry's schemas page by page and fetches
+// them in batches of concurrency.batch_size, handing each schema to fn before
+// the next batch is fetched
+func (e *GlueExtractor) streamRegistrySchemas(ctx context.Context, registryName string, fn func(*models.GlueSchema) error) error {
+	batchSize := e.config.Concurrency.BatchSize
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	// The total is unknown until the last page, so show a spinner
+	progress := worker.NewProgress(e.config.Output, "Fetching schemas", -1, progressbar.OptionShowCount())
+	defer progress.Finish()
 
+	var batch []string
+	flush := func() error {
+		schemas, err := e.fetchSchemasParallel(ctx, registryName, batch, nil, progress)
+		if err != nil {
+			return err
+		}
+		batch = batch[:0]
+		for _, schema := range schemas {
+			if err := fn(schema); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var nextToken *string
 	for {
-		if err := e.rateLimiter.Wait(ctx); err != nil {
-			return nil, err
+		resp, err := e.client.ListSchemas(ctx, &glue.ListSchemasInput{
+			RegistryId: &types.RegistryId{
+				RegistryName: aws.String(registryName),
+			},
+			NextToken: nextToken,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list schemas: %w", err)
+		}
+
+		names, err := e.selectSchemas(ctx, resp.Schemas, nil)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			batch = append(batch, name)
+			if len(batch) >= batchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+
+		if resp.NextToken == nil {
+			break
+		}
+		nextToken = resp.NextToken
+	}
+
+	if len(batch) > 0 {
+		return flush()
+	}
+	return nil
+}
+
+// selectSchemas returns the names of the listed schemas that pass
+// migration.since, aws.schema_filter, the schema include and exclude lists
+// and aws.tag_filter. When unchanged is given, schemas that only fail
+// migration.since are kept and recorded in it.
+func (e *GlueExtractor) selectSchemas(ctx context.Context, items []types.SchemaListItem, unchanged map[string]bool) ([]string, error) {
+	var names []string
+	for _, s := range items {
+		schemaName := aws.ToString(s.SchemaName)
+
+		// Incremental runs skip schemas not updated since migration.since
+		stale := e.before(parseTimestamp(aws.ToString(s.UpdatedTime)))
+		if stale && unchanged == nil {
+			continue
+		}
+
+		// Apply schema filter if specified
+		if e.config.AWS.SchemaFilter != "" {
+			matched, err := matchPattern(e.config.AWS, e.config.AWS.SchemaFilter, schemaName)
+			if err != nil {
+				return nil, fmt.Errorf("invalid schema filter pattern: %w", err)
+			}
+			if !matched {
+				continue
+			}
+		}
+		if !isSchemaIncluded(e.config.AWS, schemaName) {
+			continue
+		}
+
+		// Apply tag filter if specified
+		if len(e.config.AWS.TagFilter) > 0 {
+			tags, err := e.getTags(ctx, aws.ToString(s.SchemaArn))
+			if err != nil {
+				return nil, fmt.Errorf("failed to get tags for schema %s: %w", schemaName, err)
+			}
+			if !matchesTags(e.config.AWS.TagFilter, tags) {
+				continue
+			}
+		}
+
+		if stale {
+			unchanged[schemaName] = true
 		}
+		names = append(names, schemaName)
+	}
+	return names, nil
+}
 
+func (e *GlueExtractor) getSchemaVersions(ctx context.Context, registryName, schemaName string, listOnly, allVersions bool) ([]models.GlueSchemaVersion, error) {
+	// First, list all versions
+	var listed []models.GlueSchemaVersion
+	var nextToken *string
+
+	for {
 		input := &glue.ListSchemaVersionsInput{
 			SchemaId: &types.SchemaId{
 				RegistryName: aws.String(registryName),
@@ -341,7 +841,16 @@ func (e *GlueExtractor) getSchemaVersions(ctx context.Context, registryName, sch
 		}
 
 		for _, v := range resp.Schemas {
-			versionNumbers = append(versionNumbers, aws.ToInt64(v.VersionNumber))
+			createdTime := parseTimestamp(aws.ToString(v.CreatedTime))
+			if !allVersions && e.before(createdTime) {
+				continue
+			}
+			listed = append(listed, models.GlueSchemaVersion{
+				VersionNumber:   aws.ToInt64(v.VersionNumber),
+				SchemaVersionID: aws.ToString(v.SchemaVersionId),
+				Status:          string(v.Status),
+				CreatedTime:     createdTime,
+			})
 		}
 
 		if resp.NextToken == nil {
@@ -350,7 +859,25 @@ func (e *GlueExtractor) getSchemaVersions(ctx context.Context, registryName, sch
 		nextToken = resp.NextToken
 	}
 
-	// Fetch all versions in parallel
+	sortVersions(listed)
+
+	// The caller fetches the definitions it needs from the listing
+	if listOnly {
+		return listed, nil
+	}
+
+	// Versions older than the latest max_versions_per_schema are never
+	// registered, so they are kept listed without their definitions
+	older := listed[:0]
+	if limit := e.config.Migration.MaxVersionsPerSchema; e.config.Migration.VersionStrategy == "all" && limit > 0 && len(listed) > limit {
+		older = listed[:len(listed)-limit]
+	}
+	var versionNumbers []int64
+	for _, v := range listed[len(older):] {
+		versionNumbers = append(versionNumbers, v.VersionNumber)
+	}
+
+	// Fetch the rest in parallel
 	versions, err := e.fetchVersionsParallel(ctx, registryName, schemaName, versionNumbers)
 	if err != nil {
 		return nil, err
@@ -359,19 +886,96 @@ func (e *GlueExtractor) getSchemaVersions(ctx context.Context, registryName, sch
 	// Sort versions by version number
 	sortVersions(versions)
 
-	return versions, nil
+	return append(slices.Clip(older), versions...), nil
+}
+
+// resolvePending applies migration.on_pending_version to versions whose
+// compatibility check is still PENDING in Glue. "skip" drops them; "wait"
+// polls each until it settles, keeping AVAILABLE versions and dropping
+// FAILURE ones, and fails once pending_wait_timeout passes.
+func (e *GlueExtractor) resolvePending(ctx context.Context, registryName, schemaName string, versions []models.GlueSchemaVersion) ([]models.GlueSchemaVersion, error) {
+	action := e.config.Migration.OnPendingVersion
+	if action != "skip" && action != "wait" {
+		return versions, nil
+	}
+
+	kept := versions[:0:0]
+	for _, v := range versions {
+		if v.Status != string(types.SchemaVersionStatusPending) {
+			kept = append(kept, v)
+			continue
+		}
+		if action == "skip" {
+			slog.Info("skipping pending schema version", "schema", registryName+"."+schemaName, "version", v.VersionNumber)
+			continue
+		}
+
+		settled, err := e.waitForVersion(ctx, registryName, schemaName, v.VersionNumber)
+		if err != nil {
+			return nil, err
+		}
+		if settled.Status != string(types.SchemaVersionStatusAvailable) {
+			slog.Warn("dropping schema version that failed its compatibility check", "schema", registryName+"."+schemaName, "version", v.VersionNumber, "status", settled.Status)
+			continue
+		}
+		v.Status = settled.Status
+		v.Definition = settled.Definition
+		kept = append(kept, v)
+	}
+	return kept, nil
 }
 
-// fetchSchemasParallel fetches multiple schemas in parallel using worker pool
-func (e *GlueExtractor) fetchSchemasParallel(ctx context.Context, registryName string, schemaNames []string, bar *progressbar.ProgressBar) ([]*models.GlueSchema, error) {
+// waitForVersion polls a version until it leaves PENDING or
+// migration.pending_wait_timeout passes
+func (e *GlueExtractor) waitForVersion(ctx context.Context, registryName, schemaName string, versionNumber int64) (models.GlueSchemaVersion, error) {
+	deadline := time.Now().Add(e.config.Migration.PendingWaitTimeout)
+	for {
+		resp, err := e.client.GetSchemaVersion(ctx, &glue.GetSchemaVersionInput{
+			SchemaId: &types.SchemaId{
+				RegistryName: aws.String(registryName),
+				SchemaName:   aws.String(schemaName),
+			},
+			SchemaVersionNumber: &types.SchemaVersionNumber{
+				VersionNumber: aws.Int64(versionNumber),
+			},
+		})
+		if err != nil {
+			return models.GlueSchemaVersion{}, fmt.Errorf("failed to get schema version %d: %w", versionNumber, err)
+		}
+		if resp.Status != types.SchemaVersionStatusPending {
+			return models.GlueSchemaVersion{
+				VersionNumber:   versionNumber,
+				SchemaVersionID: aws.ToString(resp.SchemaVersionId),
+				Definition:      aws.ToString(resp.SchemaDefinition),
+				Status:          string(resp.Status),
+			}, nil
+		}
+
+		if time.Now().Add(e.pendingPollInterval).After(deadline) {
+			return models.GlueSchemaVersion{}, fmt.Errorf("version %d of %s.%s still PENDING after %s", versionNumber, registryName, schemaName, e.config.Migration.PendingWaitTimeout)
+		}
+		select {
+		case <-time.After(e.pendingPollInterval):
+		case <-ctx.Done():
+			return models.GlueSchemaVersion{}, ctx.Err()
+		}
+	}
+}
+
+// fetchSchemasParallel fetches multiple schemas in parallel using worker pool,
+// reading the ones in unchanged as unchanged schemas. Schemas are returned in
+// the order of schemaNames, however the fetches finish.
+func (e *GlueExtractor) fetchSchemasParallel(ctx context.Context, registryName string, schemaNames []string, unchanged map[string]bool, progress *worker.Progress) ([]*models.GlueSchema, error) {
 	numWorkers := e.config.Concurrency.Workers
 	if numWorkers <= 0 {
 		numWorkers = 10
 	}
 
-	// Channels for work distribution
-	jobs := make(chan string, len(schemaNames))
-	results := make(chan *models.GlueSchema, len(schemaNames))
+	// Channels for work distribution. Jobs are indexes into schemaNames and
+	// each result is written to the same slot of schemas.
+	schemas := make([]*models.GlueSchema, len(schemaNames))
+	jobs := make(chan int, len(schemaNames))
+	results := make(chan struct{}, len(schemaNames))
 	errors := make(chan error, len(schemaNames))
 
 	// Start worker pool
@@ -380,20 +984,22 @@ func (e *GlueExtractor) fetchSchemasParallel(ctx context.Context, registryName s
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			for schemaName := range jobs {
-				schema, err := e.GetSchema(ctx, registryName, schemaName)
+			for idx := range jobs {
+				name := schemaNames[idx]
+				schema, err := e.getSchema(ctx, registryName, name, e.config.AWS.LazyDefinitions, unchanged[name])
 				if err != nil {
-					errors <- fmt.Errorf("failed to get schema %s: %w", schemaName, err)
+					errors <- fmt.Errorf("failed to get schema %s: %w", name, err)
 					return
 				}
-				results <- schema
+				schemas[idx] = schema
+				results <- struct{}{}
 			}
 		}()
 	}
 
 	// Send jobs
-	for _, schemaName := range schemaNames {
-		jobs <- schemaName
+	for idx := range schemaNames {
+		jobs <- idx
 	}
 	close(jobs)
 
@@ -404,17 +1010,27 @@ func (e *GlueExtractor) fetchSchemasParallel(ctx context.Context, registryName s
 		close(errors)
 	}()
 
-	// Collect results
-	var schemas []*models.GlueSchema
+	// Wait for every result
 	for i := 0; i < len(schemaNames); i++ {
 		select {
-		case err := <-errors:
-			if err != nil {
-				return nil, err
+		case err, ok := <-errors:
+			if !ok {
+				// All workers exited; stop selecting on the closed channel
+				// so the remaining buffered results are still collected.
+				errors = nil
+				i--
+				continue
+			}
+			return nil, err
+		case _, ok := <-results:
+			if !ok {
+				// Closed without this result; a failed fetch's error
+				// is still buffered
+				results = nil
+				i--
+				continue
 			}
-		case schema := <-results:
-			schemas = append(schemas, schema)
-			bar.Add(1)
+			progress.Add(1)
 		case <-ctx.Done():
 			return nil, ctx.Err()
 		}
@@ -429,6 +1045,11 @@ func (e *GlueExtractor) fetchVersionsParallel(ctx context.Context, registryName,
 	if numWorkers <= 0 {
 		numWorkers = 10
 	}
+	// No point spawning more goroutines than versions; the shared semaphore
+	// caps actual in-flight calls across schemas.
+	if numWorkers > len(versionNumbers) {
+		numWorkers = len(versionNumbers)
+	}
 
 	// Channels for work distribution
 	jobs := make(chan int64, len(versionNumbers))
@@ -442,9 +1063,11 @@ func (e *GlueExtractor) fetchVersionsParallel(ctx context.Context, registryName,
 		go func() {
 			defer wg.Done()
 			for versionNumber := range jobs {
-				// Rate limit
-				if err := e.rateLimiter.Wait(ctx); err != nil {
-					errors <- err
+				// Acquire a slot in the global version-fetch budget
+				select {
+				case e.versionSem <- struct{}{}:
+				case <-ctx.Done():
+					errors <- ctx.Err()
 					return
 				}
 
@@ -459,6 +1082,7 @@ func (e *GlueExtractor) fetchVersionsParallel(ctx context.Context, registryName,
 				}
 
 				versionResp, err := e.client.GetSchemaVersion(ctx, versionInput)
+				<-e.versionSem
 				if err != nil {
 					errors <- fmt.Errorf("failed to get schema version %d: %w", versionNumber, err)
 					return
@@ -497,10 +1121,15 @@ func (e *GlueExtractor) fetchVersionsParallel(ctx context.Context, registryName,
 	var versions []models.GlueSchemaVersion
 	for i := 0; i < len(versionNumbers); i++ {
 		select {
-		case err := <-errors:
-			if err != nil {
-				return nil, err
+		case err, ok := <-errors:
+			if !ok {
+				// All workers exited; stop selecting on the closed channel
+				// so the remaining buffered results are still collected.
+				errors = nil
+				i--
+				continue
 			}
+			return nil, err
 		case version := <-results:
 			versions = append(versions, version)
 		case <-ctx.Done():
@@ -511,8 +1140,23 @@ func (e *GlueExtractor) fetchVersionsParallel(ctx context.Context, registryName,
 	return versions, nil
 }
 
-func (e *GlueExtractor) isExcluded(name string) bool {
-	for _, pattern := range e.config.AWS.RegistryExclude {
+// before reports whether t is earlier than migration.since. Unset since
+// values and unknown timestamps are never filtered.
+func (e *GlueExtractor) before(t time.Time) bool {
+	since := e.config.Migration.Since
+	return !since.IsZero() && !t.IsZero() && t.Before(since)
+}
+
+// isExcluded reports whether a registry matches one of the
+// aws.registry_exclude patterns
+func isExcluded(settings config.AWSConfig, name string) bool {
+	if settings.SchemaFilterCaseInsensitive {
+		name = strings.ToLower(name)
+	}
+	for _, pattern := range settings.RegistryExclude {
+		if settings.SchemaFilterCaseInsensitive {
+			pattern = strings.ToLower(pattern)
+		}
 		// Support glob patterns
 		matched, err := filepath.Match(pattern, name)
 		if err == nil && matched {
@@ -529,6 +1173,36 @@ func (e *GlueExtractor) isExcluded(name string) bool {
 	return false
 }
 
+// isSchemaIncluded reports whether a schema passes aws.schema_include and
+// aws.schema_exclude. An exclude match wins; an empty include list includes
+// every schema.
+func isSchemaIncluded(settings config.AWSConfig, name string) bool {
+	for _, pattern := range settings.SchemaExclude {
+		if matched, err := matchPattern(settings, pattern, name); err == nil && matched {
+			return false
+		}
+	}
+	if len(settings.SchemaInclude) == 0 {
+		return true
+	}
+	for _, pattern := range settings.SchemaInclude {
+		if matched, err := matchPattern(settings, pattern, name); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPattern reports whether name matches the glob pattern, lowercasing
+// both first when aws.schema_filter_case_insensitive is set.
+func matchPattern(settings config.AWSConfig, pattern, name string) (bool, error) {
+	if settings.SchemaFilterCaseInsensitive {
+		pattern = strings.ToLower(pattern)
+		name = strings.ToLower(name)
+	}
+	return filepath.Match(pattern, name)
+}
+
 func sortVersions(versions []models.GlueSchemaVersion) {
 	// Simple bubble sort - versions are typically small arrays
 	for i := 0; i < len(versions); i++ {
@@ -2,13 +2,22 @@ package extractor
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/glue"
 	"github.com/aws/aws-sdk-go-v2/service/glue/types"
+	"github.com/aws/smithy-go"
 
 	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
 	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
@@ -23,6 +32,7 @@ type mockGlueClient struct {
 	GetSchemaFn         func(ctx context.Context, params *glue.GetSchemaInput, optFns ...func(*glue.Options)) (*glue.GetSchemaOutput, error)
 	ListSchemaVersionsFn func(ctx context.Context, params *glue.ListSchemaVersionsInput, optFns ...func(*glue.Options)) (*glue.ListSchemaVersionsOutput, error)
 	GetSchemaVersionFn  func(ctx context.Context, params *glue.GetSchemaVersionInput, optFns ...func(*glue.Options)) (*glue.GetSchemaVersionOutput, error)
+	GetTagsFn           func(ctx context.Context, params *glue.GetTagsInput, optFns ...func(*glue.Options)) (*glue.GetTagsOutput, error)
 }
 
 func (m *mockGlueClient) ListRegistries(ctx context.Context, params *glue.ListRegistriesInput, optFns ...func(*glue.Options)) (*glue.ListRegistriesOutput, error) {
@@ -67,6 +77,13 @@ func (m *mockGlueClient) GetSchemaVersion(ctx context.Context, params *glue.GetS
 	return &glue.GetSchemaVersionOutput{}, nil
 }
 
+func (m *mockGlueClient) GetTags(ctx context.Context, params *glue.GetTagsInput, optFns ...func(*glue.Options)) (*glue.GetTagsOutput, error) {
+	if m.GetTagsFn != nil {
+		return m.GetTagsFn(ctx, params, optFns...)
+	}
+	return &glue.GetTagsOutput{}, nil
+}
+
 // newTestExtractor builds a GlueExtractor wired to the given mock client.
 func newTestExtractor(mock *mockGlueClient) *GlueExtractor {
 	cfg := config.NewDefaultConfig()
@@ -191,10 +208,11 @@ func TestGetSchema_APIError(t *testing.T) {
 
 func TestIsExcluded(t *testing.T) {
 	tests := []struct {
-		name     string
-		patterns []string
-		registry string
-		want     bool
+		name            string
+		patterns        []string
+		registry        string
+		caseInsensitive bool
+		want            bool
 	}{
 		{
 			name:     "exact match",
@@ -244,16 +262,28 @@ func TestIsExcluded(t *testing.T) {
 			registry: "test-payments",
 			want:     false,
 		},
+		{
+			name:     "case differs",
+			patterns: []string{"Test-*"},
+			registry: "test-payments",
+			want:     false,
+		},
+		{
+			name:            "case differs case-insensitive",
+			patterns:        []string{"Test-*"},
+			registry:        "TEST-payments",
+			caseInsensitive: true,
+			want:            true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cfg := config.NewDefaultConfig()
 			cfg.AWS.RegistryExclude = tt.patterns
-			limiter := rate.NewLimiter(rate.Limit(1000), 1)
-			ext := NewWithClient(cfg, &mockGlueClient{}, limiter)
+			cfg.AWS.SchemaFilterCaseInsensitive = tt.caseInsensitive
 
-			got := ext.isExcluded(tt.registry)
+			got := isExcluded(cfg.AWS, tt.registry)
 			if got != tt.want {
 				t.Errorf("isExcluded(%q) with patterns %v = %v, want %v", tt.registry, tt.patterns, got, tt.want)
 			}
@@ -261,6 +291,453 @@ func TestIsExcluded(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// TestIsSchemaIncluded
+// ---------------------------------------------------------------------------
+
+func TestIsSchemaIncluded(t *testing.T) {
+	tests := []struct {
+		name            string
+		include         []string
+		exclude         []string
+		schema          string
+		caseInsensitive bool
+		want            bool
+	}{
+		{
+			name:   "no lists",
+			schema: "order-placed",
+			want:   true,
+		},
+		{
+			name:    "include match",
+			include: []string{"order-*", "payment-*"},
+			schema:  "payment-settled",
+			want:    true,
+		},
+		{
+			name:    "include no match",
+			include: []string{"order-*"},
+			schema:  "user-created",
+			want:    false,
+		},
+		{
+			name:    "exclude match",
+			exclude: []string{"*-test"},
+			schema:  "order-test",
+			want:    false,
+		},
+		{
+			name:    "exclude no match",
+			exclude: []string{"*-test"},
+			schema:  "order-placed",
+			want:    true,
+		},
+		{
+			name:    "exclude wins over include",
+			include: []string{"order-*"},
+			exclude: []string{"order-legacy*"},
+			schema:  "order-legacy-v1",
+			want:    false,
+		},
+		{
+			name:    "included and not excluded",
+			include: []string{"order-*"},
+			exclude: []string{"order-legacy*"},
+			schema:  "order-placed",
+			want:    true,
+		},
+		{
+			name:            "case-insensitive",
+			include:         []string{"Order-*"},
+			exclude:         []string{"*-TEST"},
+			schema:          "ORDER-placed",
+			caseInsensitive: true,
+			want:            true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewDefaultConfig()
+			cfg.AWS.SchemaInclude = tt.include
+			cfg.AWS.SchemaExclude = tt.exclude
+			cfg.AWS.SchemaFilterCaseInsensitive = tt.caseInsensitive
+
+			got := isSchemaIncluded(cfg.AWS, tt.schema)
+			if got != tt.want {
+				t.Errorf("isSchemaIncluded(%q) with include %v, exclude %v = %v, want %v", tt.schema, tt.include, tt.exclude, got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestExtractAll_SchemaIncludeExclude
+// ---------------------------------------------------------------------------
+
+func TestExtractAll_SchemaIncludeExclude(t *testing.T) {
+	mock := &mockGlueClient{
+		ListSchemasFn: func(ctx context.Context, params *glue.ListSchemasInput, optFns ...func(*glue.Options)) (*glue.ListSchemasOutput, error) {
+			return &glue.ListSchemasOutput{Schemas: []types.SchemaListItem{
+				{SchemaName: aws.String("order-placed")},
+				{SchemaName: aws.String("order-test")},
+				{SchemaName: aws.String("user-created")},
+			}}, nil
+		},
+		GetSchemaFn: func(ctx context.Context, params *glue.GetSchemaInput, optFns ...func(*glue.Options)) (*glue.GetSchemaOutput, error) {
+			return &glue.GetSchemaOutput{
+				SchemaName: params.SchemaId.SchemaName,
+				DataFormat: types.DataFormatAvro,
+			}, nil
+		},
+	}
+
+	ext := newTestExtractor(mock)
+	ext.config.AWS.SchemaInclude = []string{"order-*"}
+	ext.config.AWS.SchemaExclude = []string{"*-test"}
+
+	schemas, err := ext.ExtractAll(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(schemas) != 1 || schemas[0].Name != "order-placed" {
+		var got []string
+		for _, s := range schemas {
+			got = append(got, s.Name)
+		}
+		t.Errorf("extracted %v, want [order-placed]", got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestExtractAll_ParallelRegistries
+// ---------------------------------------------------------------------------
+
+func TestExtractAll_ParallelRegistries(t *testing.T) {
+	const ratePerSecond = 200
+	var calls, listing, maxListing int64
+
+	// Every call the extractor makes waits on the rate limiter first
+	count := func() { atomic.AddInt64(&calls, 1) }
+	mock := &mockGlueClient{
+		GetRegistryFn: func(ctx context.Context, params *glue.GetRegistryInput, optFns ...func(*glue.Options)) (*glue.GetRegistryOutput, error) {
+			count()
+			return &glue.GetRegistryOutput{RegistryName: params.RegistryId.RegistryName}, nil
+		},
+		ListSchemasFn: func(ctx context.Context, params *glue.ListSchemasInput, optFns ...func(*glue.Options)) (*glue.ListSchemasOutput, error) {
+			count()
+			cur := atomic.AddInt64(&listing, 1)
+			for {
+				prev := atomic.LoadInt64(&maxListing)
+				if cur <= prev || atomic.CompareAndSwapInt64(&maxListing, prev, cur) {
+					break
+				}
+			}
+			time.Sleep(50 * time.Millisecond)
+			atomic.AddInt64(&listing, -1)
+
+			registry := aws.ToString(params.RegistryId.RegistryName)
+			return &glue.ListSchemasOutput{Schemas: []types.SchemaListItem{
+				{SchemaName: aws.String(registry + "-a")},
+				{SchemaName: aws.String(registry + "-b")},
+			}}, nil
+		},
+		GetSchemaFn: func(ctx context.Context, params *glue.GetSchemaInput, optFns ...func(*glue.Options)) (*glue.GetSchemaOutput, error) {
+			count()
+			return &glue.GetSchemaOutput{
+				SchemaName:   params.SchemaId.SchemaName,
+				RegistryName: params.SchemaId.RegistryName,
+				DataFormat:   types.DataFormatAvro,
+			}, nil
+		},
+		ListSchemaVersionsFn: func(ctx context.Context, params *glue.ListSchemaVersionsInput, optFns ...func(*glue.Options)) (*glue.ListSchemaVersionsOutput, error) {
+			count()
+			return &glue.ListSchemaVersionsOutput{}, nil
+		},
+		GetTagsFn: func(ctx context.Context, params *glue.GetTagsInput, optFns ...func(*glue.Options)) (*glue.GetTagsOutput, error) {
+			count()
+			return &glue.GetTagsOutput{}, nil
+		},
+	}
+
+	cfg := config.NewDefaultConfig()
+	cfg.AWS.RegistryNames = []string{"orders", "payments", "users"}
+	cfg.Concurrency.Workers = 3
+	ext := NewWithClient(cfg, mock, rate.NewLimiter(rate.Limit(ratePerSecond), 1))
+
+	start := time.Now()
+	schemas, err := ext.ExtractAll(context.Background())
+	elapsed := time.Since(start)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []string
+	for _, s := range schemas {
+		got = append(got, s.RegistryName+"/"+s.Name)
+	}
+	sort.Strings(got)
+	want := []string{
+		"orders/orders-a", "orders/orders-b",
+		"payments/payments-a", "payments/payments-b",
+		"users/users-a", "users/users-b",
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("extracted %v, want %v", got, want)
+	}
+
+	if got := atomic.LoadInt64(&maxListing); got < 2 {
+		t.Errorf("max registries listed at once = %d, want them listed concurrently", got)
+	}
+
+	// With a burst of 1, n calls can't finish faster than n-1 limiter intervals
+	n := atomic.LoadInt64(&calls)
+	if floor := time.Duration(n-1) * time.Second / ratePerSecond; elapsed < floor {
+		t.Errorf("%d calls took %v, want at least %v at %d/s", n, elapsed, floor, ratePerSecond)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestExtractAll_RegistryError
+// ---------------------------------------------------------------------------
+
+func TestExtractAll_RegistryError(t *testing.T) {
+	mock := &mockGlueClient{
+		GetRegistryFn: func(ctx context.Context, params *glue.GetRegistryInput, optFns ...func(*glue.Options)) (*glue.GetRegistryOutput, error) {
+			return &glue.GetRegistryOutput{RegistryName: params.RegistryId.RegistryName}, nil
+		},
+		ListSchemasFn: func(ctx context.Context, params *glue.ListSchemasInput, optFns ...func(*glue.Options)) (*glue.ListSchemasOutput, error) {
+			if aws.ToString(params.RegistryId.RegistryName) == "payments" {
+				return nil, &smithy.GenericAPIError{Code: "AccessDeniedException", Message: "denied"}
+			}
+			return &glue.ListSchemasOutput{Schemas: []types.SchemaListItem{
+				{SchemaName: aws.String("schema-a")},
+			}}, nil
+		},
+	}
+
+	ext := newTestExtractor(mock)
+	ext.config.AWS.RegistryNames = []string{"orders", "payments", "users"}
+
+	_, err := ext.ExtractAll(context.Background())
+	if err == nil {
+		t.Fatal("expected an error when a registry can't be listed")
+	}
+	if !strings.Contains(err.Error(), "registry payments") {
+		t.Errorf("error = %q, want it to name registry payments", err)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestExtractAll_SchemaFilterCaseInsensitive
+// ---------------------------------------------------------------------------
+
+func TestExtractAll_SchemaFilterCaseInsensitive(t *testing.T) {
+	mock := &mockGlueClient{
+		ListSchemasFn: func(ctx context.Context, params *glue.ListSchemasInput, optFns ...func(*glue.Options)) (*glue.ListSchemasOutput, error) {
+			return &glue.ListSchemasOutput{Schemas: []types.SchemaListItem{
+				{SchemaName: aws.String("order-placed")},
+				{SchemaName: aws.String("user-created")},
+			}}, nil
+		},
+		GetSchemaFn: func(ctx context.Context, params *glue.GetSchemaInput, optFns ...func(*glue.Options)) (*glue.GetSchemaOutput, error) {
+			return &glue.GetSchemaOutput{
+				SchemaName: params.SchemaId.SchemaName,
+				DataFormat: types.DataFormatAvro,
+			}, nil
+		},
+	}
+
+	for _, tt := range []struct {
+		caseInsensitive bool
+		want            []string
+	}{
+		{caseInsensitive: false, want: nil},
+		{caseInsensitive: true, want: []string{"order-placed"}},
+	} {
+		ext := newTestExtractor(mock)
+		ext.config.AWS.SchemaFilter = "Order-*"
+		ext.config.AWS.SchemaFilterCaseInsensitive = tt.caseInsensitive
+
+		schemas, err := ext.ExtractAll(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var got []string
+		for _, s := range schemas {
+			got = append(got, s.Name)
+		}
+		if len(got) != len(tt.want) || (len(got) == 1 && got[0] != tt.want[0]) {
+			t.Errorf("case_insensitive=%v: extracted %v, want %v", tt.caseInsensitive, got, tt.want)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestExtractAll_Since
+// ---------------------------------------------------------------------------
+
+func TestExtractAll_Since(t *testing.T) {
+	var fetched []int64
+	var mu sync.Mutex
+	mock := &mockGlueClient{
+		ListSchemasFn: func(ctx context.Context, params *glue.ListSchemasInput, optFns ...func(*glue.Options)) (*glue.ListSchemasOutput, error) {
+			return &glue.ListSchemasOutput{Schemas: []types.SchemaListItem{
+				{SchemaName: aws.String("order-placed"), UpdatedTime: aws.String("2024-06-10T08:00:00Z")},
+				{SchemaName: aws.String("user-created"), UpdatedTime: aws.String("2024-01-15T08:00:00Z")},
+				{SchemaName: aws.String("legacy-event")}, // no timestamp: kept
+			}}, nil
+		},
+		GetSchemaFn: func(ctx context.Context, params *glue.GetSchemaInput, optFns ...func(*glue.Options)) (*glue.GetSchemaOutput, error) {
+			return &glue.GetSchemaOutput{
+				SchemaName: params.SchemaId.SchemaName,
+				DataFormat: types.DataFormatAvro,
+			}, nil
+		},
+		ListSchemaVersionsFn: func(ctx context.Context, params *glue.ListSchemaVersionsInput, optFns ...func(*glue.Options)) (*glue.ListSchemaVersionsOutput, error) {
+			return &glue.ListSchemaVersionsOutput{Schemas: []types.SchemaVersionListItem{
+				{VersionNumber: aws.Int64(1), Status: types.SchemaVersionStatusAvailable, CreatedTime: aws.String("2024-01-15T08:00:00Z")},
+				{VersionNumber: aws.Int64(2), Status: types.SchemaVersionStatusAvailable, CreatedTime: aws.String("2024-06-01T00:00:00Z")},
+				{VersionNumber: aws.Int64(3), Status: types.SchemaVersionStatusAvailable, CreatedTime: aws.String("2024-06-10T08:00:00Z")},
+			}}, nil
+		},
+		GetSchemaVersionFn: func(ctx context.Context, params *glue.GetSchemaVersionInput, optFns ...func(*glue.Options)) (*glue.GetSchemaVersionOutput, error) {
+			number := aws.ToInt64(params.SchemaVersionNumber.VersionNumber)
+			mu.Lock()
+			fetched = append(fetched, number)
+			mu.Unlock()
+			return &glue.GetSchemaVersionOutput{
+				SchemaDefinition: aws.String(`{"type":"string"}`),
+				VersionNumber:    aws.Int64(number),
+				Status:           types.SchemaVersionStatusAvailable,
+			}, nil
+		},
+	}
+
+	ext := newTestExtractor(mock)
+	ext.config.Migration.Since = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	schemas, err := ext.ExtractAll(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := make(map[string][]int64)
+	unchanged := make(map[string]bool)
+	for _, s := range schemas {
+		for _, v := range s.Versions {
+			got[s.Name] = append(got[s.Name], v.VersionNumber)
+		}
+		unchanged[s.Name] = s.Unchanged
+	}
+	if len(got) != 3 {
+		t.Fatalf("extracted %v, want order-placed, legacy-event and the unchanged user-created", got)
+	}
+	for _, name := range []string{"order-placed", "legacy-event"} {
+		if v := got[name]; len(v) != 2 || v[0] != 2 || v[1] != 3 || unchanged[name] {
+			t.Errorf("%s versions = %v (unchanged %v), want [2 3] and changed", name, v, unchanged[name])
+		}
+	}
+
+	// The unchanged schema keeps every version listed, so references to its
+	// latest definition resolve
+	if v := got["user-created"]; len(v) != 3 || !unchanged["user-created"] {
+		t.Errorf("user-created versions = %v (unchanged %v), want all 3 and unchanged", v, unchanged["user-created"])
+	}
+	for _, n := range fetched {
+		if n == 1 {
+			t.Error("version 1 was fetched although it predates since")
+		}
+	}
+
+	// Without since nothing is filtered
+	ext = newTestExtractor(mock)
+	schemas, err = ext.ExtractAll(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(schemas) != 3 || len(schemas[0].Versions) != 3 {
+		t.Errorf("expected all 3 schemas with 3 versions each without since, got %d schemas", len(schemas))
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestExtractAll_TagFilter
+// ---------------------------------------------------------------------------
+
+func TestExtractAll_TagFilter(t *testing.T) {
+	tags := map[string]map[string]string{
+		"arn:order-placed": {"team": "payments", "env": "prod"},
+		"arn:order-draft":  {"team": "payments", "env": "dev"},
+		"arn:user-created": {"team": "identity", "env": "prod"},
+		"arn:untagged":     nil,
+	}
+	var mu sync.Mutex
+	tagCalls := make(map[string]int)
+	mock := &mockGlueClient{
+		ListSchemasFn: func(ctx context.Context, params *glue.ListSchemasInput, optFns ...func(*glue.Options)) (*glue.ListSchemasOutput, error) {
+			var items []types.SchemaListItem
+			for _, name := range []string{"order-placed", "order-draft", "user-created", "untagged"} {
+				items = append(items, types.SchemaListItem{SchemaName: aws.String(name), SchemaArn: aws.String("arn:" + name)})
+			}
+			return &glue.ListSchemasOutput{Schemas: items}, nil
+		},
+		GetSchemaFn: func(ctx context.Context, params *glue.GetSchemaInput, optFns ...func(*glue.Options)) (*glue.GetSchemaOutput, error) {
+			return &glue.GetSchemaOutput{
+				SchemaName: params.SchemaId.SchemaName,
+				SchemaArn:  aws.String("arn:" + aws.ToString(params.SchemaId.SchemaName)),
+				DataFormat: types.DataFormatAvro,
+			}, nil
+		},
+		GetTagsFn: func(ctx context.Context, params *glue.GetTagsInput, optFns ...func(*glue.Options)) (*glue.GetTagsOutput, error) {
+			mu.Lock()
+			tagCalls[aws.ToString(params.ResourceArn)]++
+			mu.Unlock()
+			return &glue.GetTagsOutput{Tags: tags[aws.ToString(params.ResourceArn)]}, nil
+		},
+	}
+
+	for _, tt := range []struct {
+		name   string
+		filter map[string]string
+		want   []string
+	}{
+		{"single tag", map[string]string{"team": "payments"}, []string{"order-draft", "order-placed"}},
+		{"all tags must match", map[string]string{"team": "payments", "env": "prod"}, []string{"order-placed"}},
+		{"no match", map[string]string{"team": "billing"}, nil},
+		{"no filter", nil, []string{"order-draft", "order-placed", "untagged", "user-created"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			clear(tagCalls)
+			ext := newTestExtractor(mock)
+			ext.config.AWS.TagFilter = tt.filter
+
+			schemas, err := ext.ExtractAll(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got []string
+			for _, s := range schemas {
+				got = append(got, s.Name)
+				if len(tt.filter) > 0 && s.Tags["team"] != tt.filter["team"] {
+					t.Errorf("%s tags = %v, expected them populated from GetTags", s.Name, s.Tags)
+				}
+			}
+			sort.Strings(got)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("extracted %v, want %v", got, tt.want)
+			}
+
+			// Tags read for the filter are reused for the schema
+			for arn, n := range tagCalls {
+				if n > 1 {
+					t.Errorf("GetTags called %d times for %s, want once", n, arn)
+				}
+			}
+		})
+	}
+}
+
 // ---------------------------------------------------------------------------
 // TestSortVersions
 // ---------------------------------------------------------------------------
@@ -323,3 +800,528 @@ func TestParseTimestamp(t *testing.T) {
 		})
 	}
 }
+
+// ---------------------------------------------------------------------------
+// TestVersionFetch_GlobalConcurrencyCap
+// ---------------------------------------------------------------------------
+
+func TestVersionFetch_GlobalConcurrencyCap(t *testing.T) {
+	const limit = 3
+	var inFlight, maxInFlight int64
+
+	mock := &mockGlueClient{
+		GetSchemaFn: func(ctx context.Context, params *glue.GetSchemaInput, optFns ...func(*glue.Options)) (*glue.GetSchemaOutput, error) {
+			return &glue.GetSchemaOutput{
+				SchemaName: params.SchemaId.SchemaName,
+				DataFormat: types.DataFormatAvro,
+			}, nil
+		},
+		ListSchemaVersionsFn: func(ctx context.Context, params *glue.ListSchemaVersionsInput, optFns ...func(*glue.Options)) (*glue.ListSchemaVersionsOutput, error) {
+			var items []types.SchemaVersionListItem
+			for i := int64(1); i <= 6; i++ {
+				items = append(items, types.SchemaVersionListItem{VersionNumber: aws.Int64(i)})
+			}
+			return &glue.ListSchemaVersionsOutput{Schemas: items}, nil
+		},
+		GetSchemaVersionFn: func(ctx context.Context, params *glue.GetSchemaVersionInput, optFns ...func(*glue.Options)) (*glue.GetSchemaVersionOutput, error) {
+			cur := atomic.AddInt64(&inFlight, 1)
+			for {
+				prev := atomic.LoadInt64(&maxInFlight)
+				if cur <= prev || atomic.CompareAndSwapInt64(&maxInFlight, prev, cur) {
+					break
+				}
+			}
+			time.Sleep(2 * time.Millisecond)
+			atomic.AddInt64(&inFlight, -1)
+			return &glue.GetSchemaVersionOutput{
+				SchemaDefinition: aws.String(`{"type":"record","name":"X","fields":[]}`),
+				VersionNumber:    params.SchemaVersionNumber.VersionNumber,
+			}, nil
+		},
+	}
+
+	cfg := config.NewDefaultConfig()
+	cfg.Concurrency.Workers = 8
+	cfg.Concurrency.VersionFetchConcurrency = limit
+	ext := NewWithClient(cfg, mock, rate.NewLimiter(rate.Inf, 1))
+
+	// Fetch many schemas concurrently, each of which fetches its versions in parallel.
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			schema, err := ext.GetSchema(context.Background(), "test-reg", fmt.Sprintf("schema-%d", i))
+			if err != nil {
+				errs <- err
+				return
+			}
+			if len(schema.Versions) != 6 {
+				errs <- fmt.Errorf("schema-%d: got %d versions, want 6", i, len(schema.Versions))
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	if got := atomic.LoadInt64(&maxInFlight); got > limit {
+		t.Errorf("max in-flight GetSchemaVersion calls = %d, want <= %d", got, limit)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestGetSchema_TagsAsProperties
+// ---------------------------------------------------------------------------
+
+func TestGetSchema_TagsAsProperties(t *testing.T) {
+	var tagCalls int
+	mock := &mockGlueClient{
+		GetSchemaFn: func(ctx context.Context, params *glue.GetSchemaInput, optFns ...func(*glue.Options)) (*glue.GetSchemaOutput, error) {
+			return &glue.GetSchemaOutput{
+				SchemaName: aws.String("user-event"),
+				SchemaArn:  aws.String("arn:aws:glue:us-east-1:123456789012:schema/test-reg/user-event"),
+				DataFormat: types.DataFormatAvro,
+			}, nil
+		},
+		GetTagsFn: func(ctx context.Context, params *glue.GetTagsInput, optFns ...func(*glue.Options)) (*glue.GetTagsOutput, error) {
+			tagCalls++
+			if aws.ToString(params.ResourceArn) != "arn:aws:glue:us-east-1:123456789012:schema/test-reg/user-event" {
+				t.Errorf("GetTags ResourceArn = %q, want schema ARN", aws.ToString(params.ResourceArn))
+			}
+			return &glue.GetTagsOutput{Tags: map[string]string{"team": "payments"}}, nil
+		},
+	}
+
+	// Tags are not fetched unless they are migrated
+	ext := newTestExtractor(mock)
+	ext.config.Metadata.MigrateTags = false
+	if _, err := ext.GetSchema(context.Background(), "test-reg", "user-event"); err != nil {
+		t.Fatalf("GetSchema returned unexpected error: %v", err)
+	}
+	if tagCalls != 0 {
+		t.Errorf("GetTags called %d times with migrate_tags and tags_as_properties disabled, want 0", tagCalls)
+	}
+
+	ext.config.Metadata.TagsAsProperties = true
+	schema, err := ext.GetSchema(context.Background(), "test-reg", "user-event")
+	if err != nil {
+		t.Fatalf("GetSchema returned unexpected error: %v", err)
+	}
+	if schema.Tags["team"] != "payments" {
+		t.Errorf("Tags[team] = %q, want %q", schema.Tags["team"], "payments")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestGetSchema_TagsAccessDenied
+// ---------------------------------------------------------------------------
+
+func TestGetSchema_TagsAccessDenied(t *testing.T) {
+	var tagCalls int
+	mock := &mockGlueClient{
+		ListSchemasFn: func(ctx context.Context, params *glue.ListSchemasInput, optFns ...func(*glue.Options)) (*glue.ListSchemasOutput, error) {
+			return &glue.ListSchemasOutput{Schemas: []types.SchemaListItem{
+				{SchemaName: aws.String("user-event"), SchemaArn: aws.String("arn:schema:user-event")},
+			}}, nil
+		},
+		GetSchemaFn: func(ctx context.Context, params *glue.GetSchemaInput, optFns ...func(*glue.Options)) (*glue.GetSchemaOutput, error) {
+			return &glue.GetSchemaOutput{
+				SchemaName: params.SchemaId.SchemaName,
+				SchemaArn:  aws.String("arn:schema:" + aws.ToString(params.SchemaId.SchemaName)),
+				DataFormat: types.DataFormatAvro,
+			}, nil
+		},
+		GetTagsFn: func(ctx context.Context, params *glue.GetTagsInput, optFns ...func(*glue.Options)) (*glue.GetTagsOutput, error) {
+			tagCalls++
+			return nil, &smithy.GenericAPIError{Code: "AccessDeniedException", Message: "not authorized to perform: glue:GetTags"}
+		},
+	}
+
+	// Tags migrated by default are dropped, and GetTags isn't retried
+	ext := newTestExtractor(mock)
+	for i := 0; i < 2; i++ {
+		schema, err := ext.GetSchema(context.Background(), "test-reg", "user-event")
+		if err != nil {
+			t.Fatalf("GetSchema returned unexpected error: %v", err)
+		}
+		if schema.Tags != nil {
+			t.Errorf("Tags = %v, want none when GetTags is denied", schema.Tags)
+		}
+	}
+	if tagCalls != 1 {
+		t.Errorf("GetTags called %d times, want 1", tagCalls)
+	}
+
+	// A tag filter can't be applied without tags, so it still fails
+	ext = newTestExtractor(mock)
+	ext.config.AWS.TagFilter = map[string]string{"team": "payments"}
+	if _, err := ext.ExtractAll(context.Background()); err == nil {
+		t.Error("expected ExtractAll to fail when GetTags is denied with a tag filter")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestExtractAll_LazyDefinitions
+// ---------------------------------------------------------------------------
+
+func TestExtractAll_LazyDefinitions(t *testing.T) {
+	var versionCalls int64
+	mock := &mockGlueClient{
+		GetRegistryFn: func(ctx context.Context, params *glue.GetRegistryInput, optFns ...func(*glue.Options)) (*glue.GetRegistryOutput, error) {
+			return &glue.GetRegistryOutput{RegistryName: params.RegistryId.RegistryName}, nil
+		},
+		ListSchemasFn: func(ctx context.Context, params *glue.ListSchemasInput, optFns ...func(*glue.Options)) (*glue.ListSchemasOutput, error) {
+			return &glue.ListSchemasOutput{Schemas: []types.SchemaListItem{
+				{SchemaName: aws.String("user-event")},
+			}}, nil
+		},
+		GetSchemaFn: func(ctx context.Context, params *glue.GetSchemaInput, optFns ...func(*glue.Options)) (*glue.GetSchemaOutput, error) {
+			return &glue.GetSchemaOutput{
+				SchemaName:          params.SchemaId.SchemaName,
+				DataFormat:          types.DataFormatAvro,
+				LatestSchemaVersion: aws.Int64(2),
+			}, nil
+		},
+		ListSchemaVersionsFn: func(ctx context.Context, params *glue.ListSchemaVersionsInput, optFns ...func(*glue.Options)) (*glue.ListSchemaVersionsOutput, error) {
+			return &glue.ListSchemaVersionsOutput{Schemas: []types.SchemaVersionListItem{
+				{VersionNumber: aws.Int64(2), SchemaVersionId: aws.String("v2"), Status: types.SchemaVersionStatusAvailable},
+				{VersionNumber: aws.Int64(1), SchemaVersionId: aws.String("v1"), Status: types.SchemaVersionStatusAvailable},
+			}}, nil
+		},
+		GetSchemaVersionFn: func(ctx context.Context, params *glue.GetSchemaVersionInput, optFns ...func(*glue.Options)) (*glue.GetSchemaVersionOutput, error) {
+			atomic.AddInt64(&versionCalls, 1)
+			return &glue.GetSchemaVersionOutput{
+				SchemaDefinition: aws.String(`{"type":"record","name":"User","fields":[]}`),
+				VersionNumber:    params.SchemaVersionNumber.VersionNumber,
+			}, nil
+		},
+	}
+
+	ext := newTestExtractor(mock)
+	ext.config.AWS.LazyDefinitions = true
+
+	schemas, err := ext.ExtractAll(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Only the latest definition is fetched, which is what references and
+	// record names are parsed from
+	if got := atomic.LoadInt64(&versionCalls); got != 1 {
+		t.Errorf("GetSchemaVersion called %d times during planning, want 1", got)
+	}
+	if len(schemas) != 1 || len(schemas[0].Versions) != 2 {
+		t.Fatalf("expected 1 schema with 2 listed versions, got %+v", schemas)
+	}
+	for i, v := range schemas[0].Versions {
+		if v.VersionNumber != int64(i+1) {
+			t.Errorf("Versions[%d].VersionNumber = %d, want %d", i, v.VersionNumber, i+1)
+		}
+	}
+	if def := schemas[0].Versions[0].Definition; def != "" {
+		t.Errorf("Versions[0].Definition = %q, want empty in lazy mode", def)
+	}
+	if schemas[0].Versions[1].Definition == "" {
+		t.Error("expected the latest definition to be fetched in lazy mode")
+	}
+
+	// GetLatestSchema fetches the same single definition
+	if _, err := ext.GetLatestSchema(context.Background(), "test-reg", "user-event"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := atomic.LoadInt64(&versionCalls); got != 2 {
+		t.Errorf("GetSchemaVersion called %d times after GetLatestSchema, want 2", got)
+	}
+
+	// GetSchema still fetches every definition
+	schema, err := ext.GetSchema(context.Background(), "test-reg", "user-event")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := atomic.LoadInt64(&versionCalls); got != 4 {
+		t.Errorf("GetSchemaVersion called %d times after GetSchema, want 4", got)
+	}
+	if schema.Versions[0].Definition == "" {
+		t.Error("expected GetSchema to populate definitions in lazy mode")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestExtractStream_Batches
+// ---------------------------------------------------------------------------
+
+func TestExtractStream_Batches(t *testing.T) {
+	pages := [][]string{{"a", "b", "c"}, {"d"}, {"e"}}
+	var fetched int64
+	mock := &mockGlueClient{
+		GetRegistryFn: func(ctx context.Context, params *glue.GetRegistryInput, optFns ...func(*glue.Options)) (*glue.GetRegistryOutput, error) {
+			return &glue.GetRegistryOutput{RegistryName: params.RegistryId.RegistryName}, nil
+		},
+		ListSchemasFn: func(ctx context.Context, params *glue.ListSchemasInput, optFns ...func(*glue.Options)) (*glue.ListSchemasOutput, error) {
+			page := 0
+			if params.NextToken != nil {
+				page, _ = strconv.Atoi(aws.ToString(params.NextToken))
+			}
+			out := &glue.ListSchemasOutput{}
+			for _, name := range pages[page] {
+				out.Schemas = append(out.Schemas, types.SchemaListItem{SchemaName: aws.String(name)})
+			}
+			if page+1 < len(pages) {
+				out.NextToken = aws.String(strconv.Itoa(page + 1))
+			}
+			return out, nil
+		},
+		GetSchemaFn: func(ctx context.Context, params *glue.GetSchemaInput, optFns ...func(*glue.Options)) (*glue.GetSchemaOutput, error) {
+			atomic.AddInt64(&fetched, 1)
+			return &glue.GetSchemaOutput{
+				SchemaName: params.SchemaId.SchemaName,
+				DataFormat: types.DataFormatAvro,
+			}, nil
+		},
+	}
+
+	ext := newTestExtractor(mock)
+	ext.config.Concurrency.BatchSize = 2
+	ext.config.Output.Quiet = true
+
+	// The callback keeps only names, discarding each schema; if extraction
+	// materialized the registry, every schema would be fetched before the
+	// first callback
+	var consumed []string
+	err := ext.ExtractStream(context.Background(), func(schema *models.GlueSchema) error {
+		if ahead := atomic.LoadInt64(&fetched) - int64(len(consumed)); ahead > 2 {
+			t.Errorf("%d schemas fetched but not yet handed to the callback, want at most a batch of 2", ahead)
+		}
+		consumed = append(consumed, schema.Name)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sort.Strings(consumed)
+	if want := []string{"a", "b", "c", "d", "e"}; !reflect.DeepEqual(consumed, want) {
+		t.Errorf("callback saw %v, want each schema once: %v", consumed, want)
+	}
+
+	// An error from the callback stops extraction
+	stop := errors.New("stop")
+	calls := 0
+	err = ext.ExtractStream(context.Background(), func(schema *models.GlueSchema) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("ExtractStream() = %v after %d calls, want the callback's error after 1", err, calls)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestGetSchema_PendingVersions
+// ---------------------------------------------------------------------------
+
+// pendingVersionMock lists two versions and reports version 2 as PENDING
+// for the first pendingCalls GetSchemaVersion calls, then AVAILABLE.
+func pendingVersionMock(pendingCalls int64, calls *int64) *mockGlueClient {
+	return &mockGlueClient{
+		GetSchemaFn: func(ctx context.Context, params *glue.GetSchemaInput, optFns ...func(*glue.Options)) (*glue.GetSchemaOutput, error) {
+			return &glue.GetSchemaOutput{
+				SchemaName:          params.SchemaId.SchemaName,
+				DataFormat:          types.DataFormatAvro,
+				LatestSchemaVersion: aws.Int64(2),
+			}, nil
+		},
+		ListSchemaVersionsFn: func(ctx context.Context, params *glue.ListSchemaVersionsInput, optFns ...func(*glue.Options)) (*glue.ListSchemaVersionsOutput, error) {
+			return &glue.ListSchemaVersionsOutput{Schemas: []types.SchemaVersionListItem{
+				{VersionNumber: aws.Int64(1), SchemaVersionId: aws.String("v1"), Status: types.SchemaVersionStatusAvailable},
+				{VersionNumber: aws.Int64(2), SchemaVersionId: aws.String("v2"), Status: types.SchemaVersionStatusPending},
+			}}, nil
+		},
+		GetSchemaVersionFn: func(ctx context.Context, params *glue.GetSchemaVersionInput, optFns ...func(*glue.Options)) (*glue.GetSchemaVersionOutput, error) {
+			number := aws.ToInt64(params.SchemaVersionNumber.VersionNumber)
+			status := types.SchemaVersionStatusAvailable
+			if number == 2 && atomic.AddInt64(calls, 1) <= pendingCalls {
+				status = types.SchemaVersionStatusPending
+			}
+			return &glue.GetSchemaVersionOutput{
+				SchemaDefinition: aws.String(fmt.Sprintf(`{"type":"record","name":"V%d","fields":[]}`, number)),
+				VersionNumber:    aws.Int64(number),
+				Status:           status,
+			}, nil
+		},
+	}
+}
+
+func TestGetSchema_PendingVersionWait(t *testing.T) {
+	var calls int64
+	ext := newTestExtractor(pendingVersionMock(2, &calls))
+	ext.config.Migration.OnPendingVersion = "wait"
+	ext.config.Migration.PendingWaitTimeout = time.Second
+	ext.pendingPollInterval = time.Millisecond
+
+	schema, err := ext.GetSchema(context.Background(), "test-reg", "user-event")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(schema.Versions) != 2 {
+		t.Fatalf("expected both versions once version 2 became available, got %d", len(schema.Versions))
+	}
+	latest := schema.Versions[1]
+	if latest.Status != string(types.SchemaVersionStatusAvailable) || !strings.Contains(latest.Definition, "V2") {
+		t.Errorf("latest version = %+v, expected the AVAILABLE definition", latest)
+	}
+	if got := atomic.LoadInt64(&calls); got != 3 {
+		t.Errorf("version 2 fetched %d times, want 3 (initial fetch, one PENDING poll, then AVAILABLE)", got)
+	}
+
+	// A version that never settles fails the schema after the timeout
+	var stuck int64
+	ext = newTestExtractor(pendingVersionMock(1<<30, &stuck))
+	ext.config.Migration.OnPendingVersion = "wait"
+	ext.config.Migration.PendingWaitTimeout = 20 * time.Millisecond
+	ext.pendingPollInterval = time.Millisecond
+	if _, err := ext.GetSchema(context.Background(), "test-reg", "user-event"); err == nil || !strings.Contains(err.Error(), "still PENDING") {
+		t.Errorf("expected a timeout error for a version stuck in PENDING, got %v", err)
+	}
+}
+
+func TestGetSchema_PendingVersionSkip(t *testing.T) {
+	var calls int64
+	ext := newTestExtractor(pendingVersionMock(1<<30, &calls))
+	ext.config.Migration.OnPendingVersion = "skip"
+
+	schema, err := ext.GetSchema(context.Background(), "test-reg", "user-event")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(schema.Versions) != 1 || schema.Versions[0].VersionNumber != 1 {
+		t.Errorf("expected only version 1 with the pending version skipped, got %+v", schema.Versions)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestGetSchema_RetriesThrottling
+// ---------------------------------------------------------------------------
+
+// throttlingMock fails GetSchema with err for the first failures calls
+func throttlingMock(failures int64, err error, calls *int64) *mockGlueClient {
+	return &mockGlueClient{
+		GetSchemaFn: func(ctx context.Context, params *glue.GetSchemaInput, optFns ...func(*glue.Options)) (*glue.GetSchemaOutput, error) {
+			if atomic.AddInt64(calls, 1) <= failures {
+				return nil, err
+			}
+			return &glue.GetSchemaOutput{
+				SchemaName: params.SchemaId.SchemaName,
+				DataFormat: types.DataFormatAvro,
+			}, nil
+		},
+	}
+}
+
+func newRetryTestExtractor(mock *mockGlueClient, attempts int) *GlueExtractor {
+	cfg := config.NewDefaultConfig()
+	cfg.AWS.RegistryNames = []string{"test-reg"}
+	cfg.Concurrency.RetryAttempts = attempts
+	cfg.Concurrency.RetryDelay = time.Millisecond
+	return NewWithClient(cfg, mock, rate.NewLimiter(rate.Limit(1000), 1))
+}
+
+func TestGetSchema_RetriesThrottling(t *testing.T) {
+	throttled := &smithy.GenericAPIError{Code: "ThrottlingException", Message: "Rate exceeded"}
+
+	var calls int64
+	ext := newRetryTestExtractor(throttlingMock(2, throttled, &calls), 3)
+	if _, err := ext.GetSchema(context.Background(), "test-reg", "user-event"); err != nil {
+		t.Fatalf("expected GetSchema to succeed after throttling, got %v", err)
+	}
+	if got := atomic.LoadInt64(&calls); got != 3 {
+		t.Errorf("GetSchema called %d times, want 3 (2 throttled, 1 success)", got)
+	}
+
+	// More throttles than retries: 1 call plus 3 retries, then give up
+	calls = 0
+	ext = newRetryTestExtractor(throttlingMock(10, throttled, &calls), 3)
+	if _, err := ext.GetSchema(context.Background(), "test-reg", "user-event"); err == nil {
+		t.Fatal("expected GetSchema to fail once retries are exhausted")
+	}
+	if got := atomic.LoadInt64(&calls); got != 4 {
+		t.Errorf("GetSchema called %d times, want 4 (retry_attempts: 3)", got)
+	}
+}
+
+func TestGetSchema_NoRetryOnPermanentErrors(t *testing.T) {
+	for _, err := range []error{
+		&types.AccessDeniedException{Message: aws.String("not authorized")},
+		&types.EntityNotFoundException{Message: aws.String("schema not found")},
+	} {
+		var calls int64
+		ext := newRetryTestExtractor(throttlingMock(10, err, &calls), 3)
+		if _, got := ext.GetSchema(context.Background(), "test-reg", "user-event"); got == nil {
+			t.Fatalf("expected %T to fail GetSchema", err)
+		}
+		if got := atomic.LoadInt64(&calls); got != 1 {
+			t.Errorf("%T: GetSchema called %d times, want 1", err, got)
+		}
+	}
+}
+
+func TestGetSchema_RetriesWaitOnRateLimit(t *testing.T) {
+	throttled := &smithy.GenericAPIError{Code: "ThrottlingException", Message: "Rate exceeded"}
+
+	// A limiter that never refills allows exactly its burst of attempts
+	var calls int64
+	cfg := config.NewDefaultConfig()
+	cfg.Concurrency.RetryAttempts = 5
+	cfg.Concurrency.RetryDelay = time.Millisecond
+	ext := NewWithClient(cfg, throttlingMock(10, throttled, &calls), rate.NewLimiter(0, 2))
+	if _, err := ext.GetSchema(context.Background(), "test-reg", "user-event"); err == nil {
+		t.Fatal("expected GetSchema to fail once the rate limit is spent")
+	}
+	if got := atomic.LoadInt64(&calls); got != 2 {
+		t.Errorf("GetSchema called %d times, want 2 (the limiter's burst)", got)
+	}
+}
+
+func TestGetSchema_RetryStopsOnCancel(t *testing.T) {
+	throttled := &smithy.GenericAPIError{Code: "RequestLimitExceeded", Message: "slow down"}
+
+	var calls int64
+	ext := newRetryTestExtractor(throttlingMock(10, throttled, &calls), 5)
+	ext.client.(*retryingClient).delay = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if _, err := ext.GetSchema(ctx, "test-reg", "user-event"); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the backoff to end on cancellation, got %v", err)
+	}
+	if got := atomic.LoadInt64(&calls); got != 1 {
+		t.Errorf("GetSchema called %d times, want 1 before cancellation", got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestAssumeRole
+// ---------------------------------------------------------------------------
+
+func TestAssumeRole(t *testing.T) {
+	base := aws.NewCredentialsCache(aws.AnonymousCredentials{})
+
+	awsCfg := aws.Config{Region: "us-east-1", Credentials: base}
+	assumeRole(&awsCfg, config.AWSConfig{})
+	if awsCfg.Credentials != base {
+		t.Error("expected base credentials to be kept when no role_arn is set")
+	}
+
+	awsCfg = aws.Config{Region: "us-east-1", Credentials: base}
+	assumeRole(&awsCfg, config.AWSConfig{
+		RoleARN:     "arn:aws:iam::123456789012:role/glue-reader",
+		ExternalID:  "ext-1",
+		SessionName: "glue-to-ccsr",
+	})
+	if !aws.IsCredentialsProvider(awsCfg.Credentials, &stscreds.AssumeRoleProvider{}) {
+		t.Errorf("expected an assume-role provider, got %T", awsCfg.Credentials)
+	}
+}
@@ -0,0 +1,164 @@
+// Package gluetest provides an in-memory Glue Schema Registry for tests.
+package gluetest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/glue"
+	gluetypes "github.com/aws/aws-sdk-go-v2/service/glue/types"
+)
+
+// Client implements extractor.GlueAPI over schemas held in memory
+type Client struct {
+	Schemas map[string]map[string]*Schema // registry -> schema name -> data
+}
+
+// Schema is one Glue schema served by Client
+type Schema struct {
+	Definition    string
+	Format        gluetypes.DataFormat
+	Compatibility gluetypes.Compatibility
+	Versions      []string // definitions of versions 1..n; overrides Definition
+	VersionIDs    []string // Glue version IDs of versions 1..n; default ver-NNN
+	Description   string
+	Tags          map[string]string
+	UpdatedTime   string // RFC3339 UpdatedTime reported by ListSchemas
+}
+
+// definitions returns the schema's version definitions, oldest first
+func (s *Schema) definitions() []string {
+	if len(s.Versions) > 0 {
+		return s.Versions
+	}
+	return []string{s.Definition}
+}
+
+// versionID returns the Glue version ID of version n
+func (s *Schema) versionID(n int64) string {
+	if s != nil && n >= 1 && int(n) <= len(s.VersionIDs) {
+		return s.VersionIDs[n-1]
+	}
+	return fmt.Sprintf("ver-%03d", n)
+}
+
+func (m *Client) ListRegistries(ctx context.Context, params *glue.ListRegistriesInput, optFns ...func(*glue.Options)) (*glue.ListRegistriesOutput, error) {
+	var items []gluetypes.RegistryListItem
+	seen := make(map[string]bool)
+	for reg := range m.Schemas {
+		if !seen[reg] {
+			seen[reg] = true
+			items = append(items, gluetypes.RegistryListItem{
+				RegistryName: aws.String(reg),
+				RegistryArn:  aws.String("arn:aws:glue:us-east-1:123456789:" + reg),
+			})
+		}
+	}
+	// Glue lists in a stable order; map iteration is not
+	sort.Slice(items, func(i, j int) bool {
+		return aws.ToString(items[i].RegistryName) < aws.ToString(items[j].RegistryName)
+	})
+	return &glue.ListRegistriesOutput{Registries: items}, nil
+}
+
+func (m *Client) GetRegistry(ctx context.Context, params *glue.GetRegistryInput, optFns ...func(*glue.Options)) (*glue.GetRegistryOutput, error) {
+	return &glue.GetRegistryOutput{
+		RegistryName: params.RegistryId.RegistryName,
+		RegistryArn:  aws.String("arn:aws:glue:us-east-1:123456789:" + aws.ToString(params.RegistryId.RegistryName)),
+	}, nil
+}
+
+func (m *Client) ListSchemas(ctx context.Context, params *glue.ListSchemasInput, optFns ...func(*glue.Options)) (*glue.ListSchemasOutput, error) {
+	regName := aws.ToString(params.RegistryId.RegistryName)
+	var items []gluetypes.SchemaListItem
+	if schemas, ok := m.Schemas[regName]; ok {
+		for name := range schemas {
+			items = append(items, gluetypes.SchemaListItem{
+				SchemaName:   aws.String(name),
+				RegistryName: aws.String(regName),
+				SchemaArn:    aws.String("arn:schema:" + name),
+				UpdatedTime:  aws.String(schemas[name].UpdatedTime),
+			})
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return aws.ToString(items[i].SchemaName) < aws.ToString(items[j].SchemaName)
+	})
+	return &glue.ListSchemasOutput{Schemas: items}, nil
+}
+
+func (m *Client) GetSchema(ctx context.Context, params *glue.GetSchemaInput, optFns ...func(*glue.Options)) (*glue.GetSchemaOutput, error) {
+	regName := aws.ToString(params.SchemaId.RegistryName)
+	schemaName := aws.ToString(params.SchemaId.SchemaName)
+	if schemas, ok := m.Schemas[regName]; ok {
+		if s, ok := schemas[schemaName]; ok {
+			return &glue.GetSchemaOutput{
+				SchemaName:          aws.String(schemaName),
+				RegistryName:        aws.String(regName),
+				DataFormat:          s.Format,
+				Compatibility:       s.Compatibility,
+				LatestSchemaVersion: aws.Int64(int64(len(s.definitions()))),
+				SchemaArn:           aws.String("arn:schema:" + schemaName),
+				Description:         aws.String(s.Description),
+			}, nil
+		}
+	}
+	return nil, &gluetypes.EntityNotFoundException{Message: aws.String("schema not found")}
+}
+
+func (m *Client) ListSchemaVersions(ctx context.Context, params *glue.ListSchemaVersionsInput, optFns ...func(*glue.Options)) (*glue.ListSchemaVersionsOutput, error) {
+	count := 1
+	var schema *Schema
+	if schemas, ok := m.Schemas[aws.ToString(params.SchemaId.RegistryName)]; ok {
+		if s, ok := schemas[aws.ToString(params.SchemaId.SchemaName)]; ok {
+			count = len(s.definitions())
+			schema = s
+		}
+	}
+
+	var items []gluetypes.SchemaVersionListItem
+	for i := 1; i <= count; i++ {
+		items = append(items, gluetypes.SchemaVersionListItem{
+			SchemaVersionId: aws.String(schema.versionID(int64(i))),
+			VersionNumber:   aws.Int64(int64(i)),
+			Status:          gluetypes.SchemaVersionStatusAvailable,
+		})
+	}
+	return &glue.ListSchemaVersionsOutput{Schemas: items}, nil
+}
+
+func (m *Client) GetSchemaVersion(ctx context.Context, params *glue.GetSchemaVersionInput, optFns ...func(*glue.Options)) (*glue.GetSchemaVersionOutput, error) {
+	// Look up the schema definition from the schema ID
+	schemaName := aws.ToString(params.SchemaId.SchemaName)
+	regName := aws.ToString(params.SchemaId.RegistryName)
+	versionNumber := aws.ToInt64(params.SchemaVersionNumber.VersionNumber)
+	definition := `{"type":"record","name":"Unknown","fields":[{"name":"id","type":"string"}]}`
+	var schema *Schema
+	if schemas, ok := m.Schemas[regName]; ok {
+		if s, ok := schemas[schemaName]; ok {
+			if defs := s.definitions(); versionNumber >= 1 && int(versionNumber) <= len(defs) {
+				definition = defs[versionNumber-1]
+			}
+			schema = s
+		}
+	}
+	return &glue.GetSchemaVersionOutput{
+		SchemaDefinition: aws.String(definition),
+		VersionNumber:    aws.Int64(versionNumber),
+		SchemaVersionId:  aws.String(schema.versionID(versionNumber)),
+		Status:           gluetypes.SchemaVersionStatusAvailable,
+	}, nil
+}
+
+func (m *Client) GetTags(ctx context.Context, params *glue.GetTagsInput, optFns ...func(*glue.Options)) (*glue.GetTagsOutput, error) {
+	schemaName := strings.TrimPrefix(aws.ToString(params.ResourceArn), "arn:schema:")
+	for _, schemas := range m.Schemas {
+		if s, ok := schemas[schemaName]; ok {
+			return &glue.GetTagsOutput{Tags: s.Tags}, nil
+		}
+	}
+	return &glue.GetTagsOutput{}, nil
+}
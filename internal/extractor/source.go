@@ -0,0 +1,36 @@
+package extractor
+
+import (
+	"context"
+
+	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
+	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
+)
+
+// Source supplies the schemas to migrate
+type Source interface {
+	// ExtractAll returns every schema selected by the aws settings
+	ExtractAll(ctx context.Context) ([]*models.GlueSchema, error)
+	// ExtractStream hands the same schemas to fn one at a time instead of
+	// collecting them
+	ExtractStream(ctx context.Context, fn func(*models.GlueSchema) error) error
+	// GetSchema returns a single schema with all its versions
+	GetSchema(ctx context.Context, registryName, schemaName string) (*models.GlueSchema, error)
+	// GetLatestSchema returns a single schema with all its versions, of
+	// which only the latest is guaranteed to carry its definition
+	GetLatestSchema(ctx context.Context, registryName, schemaName string) (*models.GlueSchema, error)
+}
+
+var (
+	_ Source = (*GlueExtractor)(nil)
+	_ Source = (*FileSource)(nil)
+)
+
+// NewSource returns a FileSource when aws.source_dir is set and a
+// GlueExtractor otherwise
+func NewSource(cfg *config.Config) (Source, error) {
+	if cfg.AWS.SourceDir != "" {
+		return NewFileSource(cfg), nil
+	}
+	return New(cfg)
+}
@@ -3,6 +3,7 @@ package graph
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
@@ -25,16 +26,50 @@ type DependencyGraph struct {
 	// reverseEdges maps schema key to schemas that depend on it
 	reverseEdges map[string][]string
 	
+	// aliases maps Avro aliases to the keys of the schemas declaring them
+	aliases map[string][]string
+	
+	// records maps record names (Avro short and full names, JSON Schema
+	// titles) to the keys of the schemas defining them
+	records map[string][]string
+	
+	// referenceNames maps schema key to the names its definition uses for
+	// its dependencies, where they differ from the schema name
+	referenceNames map[string]map[string]string
+	
 	// levels stores the topologically sorted levels
 	levels []Level
 }
 
 // Build builds a dependency graph from the given schemas
 func Build(schemas []*models.GlueSchema) (*DependencyGraph, error) {
+	g, err := newGraph(schemas)
+	if err != nil {
+		return nil, err
+	}
+
+	// Detect cycles
+	if err := g.detectCycles(); err != nil {
+		return nil, err
+	}
+
+	// Perform topological sort to get levels
+	g.levels = g.topologicalSort()
+
+	return g, nil
+}
+
+// newGraph parses schemas and resolves their references into edges, without
+// checking for cycles or sorting them into levels
+func newGraph(schemas []*models.GlueSchema) (*DependencyGraph, error) {
 	g := &DependencyGraph{
 		nodes:        make(map[string]*models.ParsedSchema),
 		edges:        make(map[string][]string),
 		reverseEdges: make(map[string][]string),
+		aliases:      make(map[string][]string),
+		records:      make(map[string][]string),
+
+		referenceNames: make(map[string]map[string]string),
 	}
 
 	// First pass: add all nodes
@@ -45,27 +80,42 @@ func Build(schemas []*models.GlueSchema) (*DependencyGraph, error) {
 			return nil, fmt.Errorf("failed to parse schema %s: %w", key, err)
 		}
 		g.nodes[key] = parsed
+		g.indexAliases(key, parsed)
+		g.indexRecordName(key, parsed)
 	}
 
 	// Second pass: build edges based on references
 	for key, parsed := range g.nodes {
 		for _, ref := range parsed.References {
 			// Try to resolve the reference to an existing schema
-			refKey := g.resolveReference(ref, parsed.GlueSchema.RegistryName)
-			if refKey != "" {
-				g.edges[key] = append(g.edges[key], refKey)
-				g.reverseEdges[refKey] = append(g.reverseEdges[refKey], key)
+			var refKey string
+			if parsed.GlueSchema.DataFormat == models.SchemaTypeProtobuf {
+				refKey = g.resolveProtoImport(ref, parsed.GlueSchema.RegistryName)
+			} else {
+				refKey = g.resolveReference(ref, parsed.GlueSchema.RegistryName)
+			}
+			// A schema naming its own type is not a dependency
+			if refKey != "" && refKey != key {
+				g.edges[key] = appendUnique(g.edges[key], refKey)
+				g.reverseEdges[refKey] = appendUnique(g.reverseEdges[refKey], key)
+				if name, ok := parsed.ReferenceNames[ref]; ok {
+					if g.referenceNames[key] == nil {
+						g.referenceNames[key] = make(map[string]string)
+					}
+					g.referenceNames[key][refKey] = name
+				}
 			}
 		}
 	}
 
-	// Detect cycles
-	if err := g.detectCycles(); err != nil {
-		return nil, err
+	// Nodes are visited in map order, so sort the edges to keep reference
+	// lists the same from run to run
+	for _, deps := range g.edges {
+		sort.Strings(deps)
+	}
+	for _, dependents := range g.reverseEdges {
+		sort.Strings(dependents)
 	}
-
-	// Perform topological sort to get levels
-	g.levels = g.topologicalSort()
 
 	return g, nil
 }
@@ -87,6 +137,36 @@ func (g *DependencyGraph) GetDependents(registryName, schemaName string) []strin
 	return g.reverseEdges[key]
 }
 
+// OrderLevels sorts the schemas within each level. Order is one of
+// "alpha" (by registry:schema key), "versions-desc" (most versions first)
+// or "source" (the order of schemas as extracted). Ties fall back to
+// alphabetical so every order is stable across runs for the same input.
+func OrderLevels(levels []Level, order string, schemas []*models.GlueSchema) {
+	sourceIndex := make(map[string]int, len(schemas))
+	for i, s := range schemas {
+		sourceIndex[schemaKey(s.RegistryName, s.Name)] = i
+	}
+
+	for _, level := range levels {
+		items := level.Schemas
+		sort.SliceStable(items, func(i, j int) bool {
+			ki := schemaKey(items[i].SourceRegistry, items[i].SourceSchemaName)
+			kj := schemaKey(items[j].SourceRegistry, items[j].SourceSchemaName)
+			switch order {
+			case "versions-desc":
+				if items[i].SourceVersions != items[j].SourceVersions {
+					return items[i].SourceVersions > items[j].SourceVersions
+				}
+			case "source":
+				if si, sj := sourceIndex[ki], sourceIndex[kj]; si != sj {
+					return si < sj
+				}
+			}
+			return ki < kj
+		})
+	}
+}
+
 func schemaKey(registryName, schemaName string) string {
 	return fmt.Sprintf("%s:%s", registryName, schemaName)
 }
@@ -111,9 +191,81 @@ func (g *DependencyGraph) resolveReference(ref string, currentRegistry string) s
 		}
 	}
 
+	// Try the names schemas define for themselves, then Avro aliases,
+	// preferring a schema in the current registry
+	if key := preferRegistry(g.records[ref], currentRegistry); key != "" {
+		return key
+	}
+	return preferRegistry(g.aliases[ref], currentRegistry)
+}
+
+// resolveProtoImport resolves a protobuf import path, without its .proto
+// suffix, to a schema. A schema matches the whole path when its name with
+// dots read as directories, or its registry and name, spell it out:
+// com/shop/address matches com.shop.address, and shop/address matches
+// address in registry shop. Failing that, the last element of the path is
+// matched against schema names, but only when a single schema has that name,
+// so common/address and billing/address don't resolve to the same schema.
+func (g *DependencyGraph) resolveProtoImport(path string, currentRegistry string) string {
+	base := path[strings.LastIndex(path, "/")+1:]
+	var whole, named []string
+	for key, node := range g.nodes {
+		schema := node.GlueSchema
+		switch {
+		case strings.ReplaceAll(schema.Name, ".", "/") == path || schema.RegistryName+"/"+schema.Name == path:
+			whole = append(whole, key)
+		case schema.Name == base:
+			named = append(named, key)
+		}
+	}
+	if len(whole) > 0 {
+		sort.Strings(whole)
+		return preferRegistry(whole, currentRegistry)
+	}
+	if len(named) == 1 {
+		return named[0]
+	}
+	return ""
+}
+
+// preferRegistry returns the key in registry, or else the first key
+func preferRegistry(keys []string, registry string) string {
+	for _, key := range keys {
+		if strings.HasPrefix(key, registry+":") {
+			return key
+		}
+	}
+	if len(keys) > 0 {
+		return keys[0]
+	}
 	return ""
 }
 
+// indexRecordName records the name a schema's definition gives its type: a
+// JSON Schema title, or an Avro name under both its short and full forms
+func (g *DependencyGraph) indexRecordName(key string, parsed *models.ParsedSchema) {
+	if parsed.RecordName == "" {
+		return
+	}
+	g.records[parsed.RecordName] = appendUnique(g.records[parsed.RecordName], key)
+	if parsed.Namespace != "" && !strings.Contains(parsed.RecordName, ".") {
+		full := parsed.Namespace + "." + parsed.RecordName
+		g.records[full] = appendUnique(g.records[full], key)
+	}
+}
+
+// indexAliases records the Avro aliases of a schema as written and, for
+// short aliases, qualified with the schema's namespace as Avro resolves them
+func (g *DependencyGraph) indexAliases(key string, parsed *models.ParsedSchema) {
+	for _, alias := range parsed.Aliases {
+		g.aliases[alias] = appendUnique(g.aliases[alias], key)
+		if parsed.Namespace != "" && !strings.Contains(alias, ".") {
+			full := parsed.Namespace + "." + alias
+			g.aliases[full] = appendUnique(g.aliases[full], key)
+		}
+	}
+}
+
 func (g *DependencyGraph) detectCycles() error {
 	// Use DFS with coloring to detect cycles
 	// 0 = white (unvisited), 1 = gray (in progress), 2 = black (done)
@@ -140,7 +292,8 @@ func (g *DependencyGraph) detectCycles() error {
 		return nil
 	}
 
-	for node := range g.nodes {
+	// Visit nodes in key order so the same cycle is reported every run
+	for _, node := range g.sortedKeys() {
 		if color[node] == 0 {
 			if err := dfs(node, nil); err != nil {
 				return err
@@ -152,15 +305,10 @@ func (g *DependencyGraph) detectCycles() error {
 }
 
 func (g *DependencyGraph) topologicalSort() []Level {
-	// Calculate in-degree for each node
+	// Calculate in-degree for each node (number of unmigrated dependencies)
 	inDegree := make(map[string]int)
 	for key := range g.nodes {
-		inDegree[key] = 0
-	}
-	for _, deps := range g.edges {
-		for _, dep := range deps {
-			inDegree[dep]++
-		}
+		inDegree[key] = len(g.edges[key])
 	}
 
 	// Start with nodes that have no dependencies
@@ -184,20 +332,12 @@ func (g *DependencyGraph) topologicalSort() []Level {
 			// This shouldn't happen if cycle detection worked
 			break
 		}
+		sort.Strings(currentLevel)
 
 		// Create level with schema mappings
 		levelSchemas := make([]models.SchemaMapping, 0, len(currentLevel))
 		for _, key := range currentLevel {
-			parsed := g.nodes[key]
-			mapping := models.SchemaMapping{
-				SourceRegistry:   parsed.GlueSchema.RegistryName,
-				SourceSchemaName: parsed.GlueSchema.Name,
-				SourceVersions:   len(parsed.GlueSchema.Versions),
-				References:       g.edges[key],
-				DependencyLevel:  level,
-				Status:           models.MappingStatusReady,
-			}
-			levelSchemas = append(levelSchemas, mapping)
+			levelSchemas = append(levelSchemas, g.levelMapping(key, level))
 			
 			// Remove from remaining and update in-degrees
 			delete(remaining, key)
@@ -216,6 +356,62 @@ func (g *DependencyGraph) topologicalSort() []Level {
 	return levels
 }
 
+// sortedKeys returns the keys of every node, sorted
+func (g *DependencyGraph) sortedKeys() []string {
+	keys := make([]string, 0, len(g.nodes))
+	for key := range g.nodes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// SingleLevel places every schema in one level, sorted by registry:schema
+// key. References are resolved as in Build so they are still registered,
+// but they are neither ordered nor checked for cycles. It stands in for
+// Build when ordering is not needed (migration.skip_dependency_graph).
+func SingleLevel(schemas []*models.GlueSchema) ([]Level, error) {
+	if len(schemas) == 0 {
+		return nil, nil
+	}
+	g, err := newGraph(schemas)
+	if err != nil {
+		return nil, err
+	}
+	keys := g.sortedKeys()
+	mappings := make([]models.SchemaMapping, 0, len(keys))
+	for _, key := range keys {
+		mappings = append(mappings, g.levelMapping(key, 0))
+	}
+	return []Level{{Level: 0, Schemas: mappings}}, nil
+}
+
+// levelMapping is the mapping a level starts from for the schema at key,
+// with its resolved references, before the mapper fills in the target
+func (g *DependencyGraph) levelMapping(key string, level int) models.SchemaMapping {
+	schema := g.nodes[key].GlueSchema
+	mapping := models.SchemaMapping{
+		SourceRegistry:   schema.RegistryName,
+		SourceSchemaName: schema.Name,
+		SourceVersions:   len(schema.Versions),
+		SourceFormat:     schema.DataFormat,
+		DependencyLevel:  level,
+		Status:           models.MappingStatusReady,
+
+		SourceCompatibility: schema.Compatibility,
+	}
+
+	mapping.References = g.edges[key]
+	if len(g.edges[key]) > 0 {
+		mapping.ReferenceFormats = make(map[string]models.SchemaType, len(g.edges[key]))
+		for _, refKey := range g.edges[key] {
+			mapping.ReferenceFormats[refKey] = g.nodes[refKey].GlueSchema.DataFormat
+		}
+	}
+	mapping.ReferenceNames = g.referenceNames[key]
+	return mapping
+}
+
 func parseSchema(schema *models.GlueSchema) (*models.ParsedSchema, error) {
 	parsed := &models.ParsedSchema{
 		GlueSchema: schema,
@@ -227,6 +423,10 @@ func parseSchema(schema *models.GlueSchema) (*models.ParsedSchema, error) {
 
 	// Parse the latest version to extract metadata
 	latestVersion := schema.Versions[len(schema.Versions)-1]
+	if latestVersion.Definition == "" {
+		// Definitions are deferred (aws.lazy_definitions); nothing to parse yet
+		return parsed, nil
+	}
 	
 	switch schema.DataFormat {
 	case models.SchemaTypeAvro:
@@ -247,12 +447,29 @@ func parseSchema(schema *models.GlueSchema) (*models.ParsedSchema, error) {
 }
 
 func parseAvroSchema(definition string, parsed *models.ParsedSchema) error {
-	var avro map[string]interface{}
-	if err := json.Unmarshal([]byte(definition), &avro); err != nil {
+	// Avro allows any type at the top level, so don't assume a record object
+	var raw interface{}
+	if err := json.Unmarshal([]byte(definition), &raw); err != nil {
 		return fmt.Errorf("failed to parse Avro schema: %w", err)
 	}
 
-	// Extract record name
+	avro, ok := raw.(map[string]interface{})
+	if !ok {
+		// Top-level union or bare type name
+		parseAvroUnnamed(raw, parsed)
+		return nil
+	}
+
+	switch avro["type"] {
+	case "array":
+		parseAvroUnnamed(avro["items"], parsed)
+		return nil
+	case "map":
+		parseAvroUnnamed(avro["values"], parsed)
+		return nil
+	}
+
+	// Extract record name (enum and fixed are named the same way)
 	if name, ok := avro["name"].(string); ok {
 		parsed.RecordName = name
 	}
@@ -267,6 +484,21 @@ func parseAvroSchema(definition string, parsed *models.ParsedSchema) error {
 		parsed.Documentation = doc
 	}
 
+	// Extract aliases, which references may use instead of the name
+	if aliases, ok := avro["aliases"].([]interface{}); ok {
+		for _, a := range aliases {
+			if alias, ok := a.(string); ok && alias != "" {
+				parsed.Aliases = appendUnique(parsed.Aliases, alias)
+			}
+		}
+	}
+
+	// The schema's own name and the types it defines inline are not
+	// references, even when a field (recursively) uses them
+	defined := make(map[string]bool)
+	namespace := defineAvroName(avro, "", defined)
+	var refs []string
+
 	// Extract fields
 	if fields, ok := avro["fields"].([]interface{}); ok {
 		for _, f := range fields {
@@ -279,11 +511,8 @@ func parseAvroSchema(definition string, parsed *models.ParsedSchema) error {
 				fieldType := field["type"]
 				fieldModel.Type = extractAvroType(fieldType)
 				
-				// Check if it's a reference to another schema
-				refType := extractAvroReference(fieldType)
-				if refType != "" {
-					parsed.References = appendUnique(parsed.References, refType)
-				}
+				// Collect the other schemas it refers to
+				refs = append(refs, extractAvroReferences(fieldType, namespace, defined)...)
 				
 				// Extract doc
 				if doc, ok := field["doc"].(string); ok {
@@ -295,9 +524,31 @@ func parseAvroSchema(definition string, parsed *models.ParsedSchema) error {
 		}
 	}
 
+	for _, ref := range refs {
+		if !defined[ref] {
+			parsed.References = appendUnique(parsed.References, ref)
+		}
+	}
+
 	return nil
 }
 
+// parseAvroUnnamed handles top-level Avro types that carry no name of their
+// own (unions, arrays, maps, primitives). Named types they contain are recorded
+// as references and the Glue schema name stands in for the record name.
+func parseAvroUnnamed(t interface{}, parsed *models.ParsedSchema) {
+	if parsed.GlueSchema != nil {
+		parsed.RecordName = parsed.GlueSchema.Name
+	}
+
+	defined := make(map[string]bool)
+	for _, ref := range extractAvroReferences(t, "", defined) {
+		if !defined[ref] {
+			parsed.References = appendUnique(parsed.References, ref)
+		}
+	}
+}
+
 func parseJSONSchema(definition string, parsed *models.ParsedSchema) error {
 	var jsonSchema map[string]interface{}
 	if err := json.Unmarshal([]byte(definition), &jsonSchema); err != nil {
@@ -325,17 +576,29 @@ func parseJSONSchema(definition string, parsed *models.ParsedSchema) error {
 				if t, ok := propMap["type"].(string); ok {
 					fieldModel.Type = t
 				}
-				
-				// Check for $ref
-				if ref, ok := propMap["$ref"].(string); ok {
-					parsed.References = appendUnique(parsed.References, ref)
-				}
 			}
 			
 			parsed.Fields = append(parsed.Fields, fieldModel)
 		}
 	}
 
+	// Definitions the schema carries itself satisfy its local refs
+	local := make(map[string]bool)
+	for _, section := range []string{"definitions", "$defs"} {
+		if defs, ok := jsonSchema[section].(map[string]interface{}); ok {
+			for name := range defs {
+				local[name] = true
+			}
+		}
+	}
+	for _, ref := range collectJSONRefs(jsonSchema) {
+		name, isLocal := jsonRefName(ref)
+		if name == "" || (isLocal && local[name]) {
+			continue
+		}
+		parsed.References = appendUnique(parsed.References, name)
+	}
+
 	// Check required fields
 	if required, ok := jsonSchema["required"].([]interface{}); ok {
 		requiredMap := make(map[string]bool)
@@ -354,6 +617,59 @@ func parseJSONSchema(definition string, parsed *models.ParsedSchema) error {
 	return nil
 }
 
+// collectJSONRefs returns every $ref in a JSON Schema value, however deeply
+// nested, in a stable order
+func collectJSONRefs(v interface{}) []string {
+	var refs []string
+	switch node := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := node["$ref"].(string); ok {
+			refs = append(refs, ref)
+		}
+		keys := make([]string, 0, len(node))
+		for k := range node {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			// const and enum values are data, not schemas
+			if k == "const" || k == "enum" {
+				continue
+			}
+			refs = append(refs, collectJSONRefs(node[k])...)
+		}
+	case []interface{}:
+		for _, item := range node {
+			refs = append(refs, collectJSONRefs(item)...)
+		}
+	}
+	return refs
+}
+
+// jsonRefName reduces a $ref to the bare name of the schema it points to:
+// #/definitions/Address and #/$defs/Address give Address (local, resolved
+// within the document when it defines Address), and
+// https://example.com/schemas/Address.json#/definitions/Street gives Address.
+// Other pointers into the same document give "".
+func jsonRefName(ref string) (name string, local bool) {
+	if strings.HasPrefix(ref, "#") {
+		for _, prefix := range []string{"#/definitions/", "#/$defs/"} {
+			if rest := strings.TrimPrefix(ref, prefix); rest != ref && !strings.Contains(rest, "/") {
+				return rest, true
+			}
+		}
+		return "", true
+	}
+
+	if i := strings.Index(ref, "#"); i >= 0 {
+		ref = ref[:i]
+	}
+	if i := strings.LastIndex(ref, "/"); i >= 0 {
+		ref = ref[i+1:]
+	}
+	return strings.TrimSuffix(ref, ".json"), false
+}
+
 func parseProtobufSchema(definition string, parsed *models.ParsedSchema) error {
 	// Simple protobuf parsing for message name and imports
 	lines := strings.Split(definition, "\n")
@@ -374,16 +690,51 @@ func parseProtobufSchema(definition string, parsed *models.ParsedSchema) error {
 			}
 		}
 		
-		// Extract imports (references)
+		// Extract imports (references), which Schema Registry resolves by
+		// the path as written
 		if strings.HasPrefix(line, "import ") {
-			importPath := strings.Trim(strings.TrimSuffix(strings.TrimPrefix(line, "import "), ";"), "\"")
-			parsed.References = appendUnique(parsed.References, importPath)
+			importPath := protoImportPath(line)
+			name := protoImportName(importPath)
+			if name == "" {
+				continue
+			}
+			parsed.References = appendUnique(parsed.References, name)
+			if _, seen := parsed.ReferenceNames[name]; !seen {
+				if parsed.ReferenceNames == nil {
+					parsed.ReferenceNames = make(map[string]string)
+				}
+				parsed.ReferenceNames[name] = importPath
+			}
 		}
 	}
 
 	return nil
 }
 
+// protoImportPath returns the file path of an import statement:
+// import public "common/address.proto"; gives common/address.proto
+func protoImportPath(line string) string {
+	rest := strings.TrimSpace(strings.TrimPrefix(line, "import "))
+	for _, modifier := range []string{"public ", "weak "} {
+		rest = strings.TrimSpace(strings.TrimPrefix(rest, modifier))
+	}
+	if i := strings.Index(rest, ";"); i >= 0 {
+		rest = rest[:i]
+	}
+	return strings.Trim(strings.TrimSpace(rest), "\"'")
+}
+
+// protoImportName gives the reference an import path is resolved by, the
+// path without its suffix: common/address.proto gives common/address. The
+// well-known google/protobuf types are built into Schema Registry, so they
+// give "".
+func protoImportName(importPath string) string {
+	if strings.HasPrefix(importPath, "google/protobuf/") {
+		return ""
+	}
+	return strings.TrimSuffix(importPath, ".proto")
+}
+
 func extractAvroType(t interface{}) string {
 	switch v := t.(type) {
 	case string:
@@ -409,33 +760,78 @@ func extractAvroType(t interface{}) string {
 	return "unknown"
 }
 
-func extractAvroReference(t interface{}) string {
+// avroPrimitives are the Avro type names that never refer to another schema
+var avroPrimitives = map[string]bool{
+	"null": true, "boolean": true, "int": true, "long": true,
+	"float": true, "double": true, "bytes": true, "string": true,
+}
+
+// extractAvroReferences walks an Avro type - through unions, array items,
+// map values and the fields of inline records - and returns every named type
+// it uses, in order. Records, enums and fixed types defined inline are added
+// to defined instead, under their short and full names, so the caller can
+// drop uses of them once the whole schema has been walked. namespace is the
+// enclosing namespace that inline definitions without one inherit.
+func extractAvroReferences(t interface{}, namespace string, defined map[string]bool) []string {
 	switch v := t.(type) {
 	case string:
-		// If it's not a primitive type, it might be a reference
-		primitives := map[string]bool{
-			"null": true, "boolean": true, "int": true, "long": true,
-			"float": true, "double": true, "bytes": true, "string": true,
+		if !avroPrimitives[v] {
+			return []string{v}
 		}
-		if !primitives[v] {
-			return v
+	case []interface{}:
+		var refs []string
+		for _, member := range v {
+			refs = append(refs, extractAvroReferences(member, namespace, defined)...)
 		}
+		return refs
 	case map[string]interface{}:
-		// Check for named type
-		if name, ok := v["name"].(string); ok {
-			if _, isPrimitive := v["type"]; !isPrimitive {
-				return name
-			}
-		}
-	case []interface{}:
-		// Union type - check each element
-		for _, item := range v {
-			if ref := extractAvroReference(item); ref != "" {
-				return ref
+		switch v["type"] {
+		case "array":
+			return extractAvroReferences(v["items"], namespace, defined)
+		case "map":
+			return extractAvroReferences(v["values"], namespace, defined)
+		case "record", "error":
+			namespace = defineAvroName(v, namespace, defined)
+			var refs []string
+			fields, _ := v["fields"].([]interface{})
+			for _, f := range fields {
+				if field, ok := f.(map[string]interface{}); ok {
+					refs = append(refs, extractAvroReferences(field["type"], namespace, defined)...)
+				}
 			}
+			return refs
+		case "enum", "fixed":
+			defineAvroName(v, namespace, defined)
+			return nil
 		}
+		// A primitive with attributes such as a logicalType, or a
+		// reference wrapped as {"type": "Name"}
+		return extractAvroReferences(v["type"], namespace, defined)
 	}
-	return ""
+	return nil
+}
+
+// defineAvroName adds the short and full name of the named type t to
+// defined and returns the namespace its own nested definitions inherit
+func defineAvroName(t map[string]interface{}, namespace string, defined map[string]bool) string {
+	name, _ := t["name"].(string)
+	if ns, ok := t["namespace"].(string); ok {
+		namespace = ns
+	}
+	if name == "" {
+		return namespace
+	}
+	defined[name] = true
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		// A full name sets the namespace and also defines the short name
+		namespace = name[:i]
+		defined[name[i+1:]] = true
+		return namespace
+	}
+	if namespace != "" {
+		defined[namespace+"."+name] = true
+	}
+	return namespace
 }
 
 func appendUnique(slice []string, item string) []string {
@@ -1,6 +1,9 @@
 package graph
 
 import (
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
@@ -102,3 +105,616 @@ func TestAppendUnique(t *testing.T) {
 		t.Errorf("Expected 3 items (no duplicate), got %d", len(result))
 	}
 }
+
+func TestParseSchema_TopLevelEnum(t *testing.T) {
+	schema := &models.GlueSchema{
+		Name:         "order-status",
+		RegistryName: "payments",
+		DataFormat:   models.SchemaTypeAvro,
+		Versions: []models.GlueSchemaVersion{
+			{VersionNumber: 1, Definition: `{"type":"enum","name":"OrderStatus","namespace":"com.example","doc":"Order lifecycle","symbols":["NEW","PAID"]}`},
+		},
+	}
+
+	parsed, err := parseSchema(schema)
+	if err != nil {
+		t.Fatalf("parseSchema failed: %v", err)
+	}
+	if parsed.RecordName != "OrderStatus" {
+		t.Errorf("RecordName = %q, expected 'OrderStatus'", parsed.RecordName)
+	}
+	if parsed.Namespace != "com.example" {
+		t.Errorf("Namespace = %q, expected 'com.example'", parsed.Namespace)
+	}
+	if parsed.Documentation != "Order lifecycle" {
+		t.Errorf("Documentation = %q, expected 'Order lifecycle'", parsed.Documentation)
+	}
+}
+
+func TestBuild_TopLevelUnion(t *testing.T) {
+	schemas := []*models.GlueSchema{
+		{
+			Name:         "Address",
+			RegistryName: "shared",
+			DataFormat:   models.SchemaTypeAvro,
+			Versions: []models.GlueSchemaVersion{
+				{VersionNumber: 1, Definition: `{"type":"record","name":"Address","fields":[]}`},
+			},
+		},
+		{
+			Name:         "maybe-address",
+			RegistryName: "shared",
+			DataFormat:   models.SchemaTypeAvro,
+			Versions: []models.GlueSchemaVersion{
+				{VersionNumber: 1, Definition: `["null","Address"]`},
+			},
+		},
+	}
+
+	graph, err := Build(schemas)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	parsed := graph.nodes["shared:maybe-address"]
+	if parsed.RecordName != "maybe-address" {
+		t.Errorf("RecordName = %q, expected Glue schema name 'maybe-address'", parsed.RecordName)
+	}
+	deps := graph.GetDependencies("shared", "maybe-address")
+	if len(deps) != 1 || deps[0] != "shared:Address" {
+		t.Errorf("dependencies = %v, expected [shared:Address]", deps)
+	}
+
+	levels := graph.GetLevels()
+	if len(levels) != 2 {
+		t.Fatalf("Expected 2 levels, got %d", len(levels))
+	}
+	// The referenced schema must be migrated before the union that uses it
+	if levels[0].Schemas[0].SourceSchemaName != "Address" {
+		t.Errorf("Expected Address in level 0, got %s", levels[0].Schemas[0].SourceSchemaName)
+	}
+	// The referenced schema's format travels with the mapping
+	if got := levels[1].Schemas[0].ReferenceFormats["shared:Address"]; got != models.SchemaTypeAvro {
+		t.Errorf("ReferenceFormats[shared:Address] = %q, expected AVRO", got)
+	}
+}
+
+func TestBuild_ReferenceByAlias(t *testing.T) {
+	schemas := []*models.GlueSchema{
+		{
+			Name:         "postal-address",
+			RegistryName: "shared",
+			DataFormat:   models.SchemaTypeAvro,
+			Versions: []models.GlueSchemaVersion{
+				{VersionNumber: 1, Definition: `{"type":"record","name":"PostalAddress","namespace":"com.example","aliases":["Address","com.legacy.Location"],"fields":[]}`},
+			},
+		},
+		{
+			Name:         "customer",
+			RegistryName: "payments",
+			DataFormat:   models.SchemaTypeAvro,
+			Versions: []models.GlueSchemaVersion{
+				{VersionNumber: 1, Definition: `{"type":"record","name":"Customer","fields":[{"name":"home","type":"com.example.Address"},{"name":"work","type":["null","com.legacy.Location"]}]}`},
+			},
+		},
+	}
+
+	graph, err := Build(schemas)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	parsed := graph.nodes["shared:postal-address"]
+	if len(parsed.Aliases) != 2 || parsed.Aliases[0] != "Address" || parsed.Aliases[1] != "com.legacy.Location" {
+		t.Errorf("Aliases = %v, expected [Address com.legacy.Location]", parsed.Aliases)
+	}
+
+	// Both the namespace-qualified short alias and the full alias resolve,
+	// to a single edge
+	deps := graph.GetDependencies("payments", "customer")
+	if len(deps) != 1 || deps[0] != "shared:postal-address" {
+		t.Errorf("dependencies = %v, expected [shared:postal-address]", deps)
+	}
+	if levels := graph.GetLevels(); len(levels) != 2 || levels[0].Schemas[0].SourceSchemaName != "postal-address" {
+		t.Errorf("expected postal-address in level 0 ahead of customer, got %+v", levels)
+	}
+}
+
+func TestParseSchema_NestedAvroReferences(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition string
+		want       []string
+	}{
+		{
+			name:       "array items",
+			definition: `{"type":"record","name":"Order","fields":[{"name":"lines","type":{"type":"array","items":"OrderLine"}}]}`,
+			want:       []string{"OrderLine"},
+		},
+		{
+			name:       "map values",
+			definition: `{"type":"record","name":"Order","fields":[{"name":"totals","type":{"type":"map","values":["null","Money"]}}]}`,
+			want:       []string{"Money"},
+		},
+		{
+			name:       "union of records",
+			definition: `{"type":"record","name":"Payment","fields":[{"name":"method","type":["null","Card","BankTransfer"]}]}`,
+			want:       []string{"Card", "BankTransfer"},
+		},
+		{
+			name:       "enum, fixed and wrapped references",
+			definition: `{"type":"record","name":"Order","fields":[{"name":"status","type":"OrderStatus"},{"name":"hash","type":{"type":"Digest"}},{"name":"id","type":{"type":"string","logicalType":"uuid"}}]}`,
+			want:       []string{"OrderStatus", "Digest"},
+		},
+		{
+			name: "inside an inline record",
+			definition: `{"type":"record","name":"Order","namespace":"com.example","fields":[
+				{"name":"shipping","type":{"type":"record","name":"Shipping","fields":[
+					{"name":"to","type":"Address"},
+					{"name":"parcels","type":{"type":"array","items":{"type":"map","values":"Parcel"}}}
+				]}},
+				{"name":"billing","type":"com.example.Shipping"}
+			]}`,
+			want: []string{"Address", "Parcel"},
+		},
+		{
+			name: "inline enum and fixed definitions",
+			definition: `{"type":"record","name":"Order","fields":[
+				{"name":"status","type":{"type":"enum","name":"Status","symbols":["NEW"]}},
+				{"name":"previous","type":["null","Status"]},
+				{"name":"hash","type":{"type":"fixed","name":"com.example.Hash","size":16}},
+				{"name":"parent","type":["null","Hash"]}
+			]}`,
+		},
+		{
+			name:       "recursive record",
+			definition: `{"type":"record","name":"TreeNode","namespace":"com.example","fields":[{"name":"children","type":{"type":"array","items":"com.example.TreeNode"}},{"name":"parent","type":["null","TreeNode"]}]}`,
+		},
+		{
+			name:       "top-level array",
+			definition: `{"type":"array","items":{"type":"map","values":"LineItem"}}`,
+			want:       []string{"LineItem"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := parseSchema(&models.GlueSchema{
+				Name:         "schema",
+				RegistryName: "payments",
+				DataFormat:   models.SchemaTypeAvro,
+				Versions:     []models.GlueSchemaVersion{{VersionNumber: 1, Definition: tt.definition}},
+			})
+			if err != nil {
+				t.Fatalf("parseSchema failed: %v", err)
+			}
+			if strings.Join(parsed.References, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("References = %v, expected %v", parsed.References, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuild_ArrayReference(t *testing.T) {
+	schemas := []*models.GlueSchema{
+		{
+			Name:         "Order",
+			RegistryName: "payments",
+			DataFormat:   models.SchemaTypeAvro,
+			Versions: []models.GlueSchemaVersion{
+				{VersionNumber: 1, Definition: `{"type":"record","name":"Order","fields":[{"name":"lines","type":{"type":"array","items":"OrderLine"}}]}`},
+			},
+		},
+		{
+			Name:         "OrderLine",
+			RegistryName: "payments",
+			DataFormat:   models.SchemaTypeAvro,
+			Versions: []models.GlueSchemaVersion{
+				{VersionNumber: 1, Definition: `{"type":"record","name":"OrderLine","fields":[{"name":"sku","type":"string"}]}`},
+			},
+		},
+	}
+
+	graph, err := Build(schemas)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	levels := graph.GetLevels()
+	if len(levels) != 2 || levels[0].Schemas[0].SourceSchemaName != "OrderLine" || levels[1].Schemas[0].SourceSchemaName != "Order" {
+		t.Errorf("expected OrderLine in level 0 ahead of Order, got %+v", levels)
+	}
+}
+
+func TestParseSchema_JSONRefs(t *testing.T) {
+	definition := `{
+		"title": "Order",
+		"type": "object",
+		"properties": {
+			"billing": {"$ref": "#/definitions/Address"},
+			"lines": {"type": "array", "items": {"$ref": "https://example.com/schemas/OrderLine.json"}},
+			"customer": {"type": "object", "properties": {"id": {"$ref": "customer-id.json#/definitions/Id"}}},
+			"status": {"$ref": "#/$defs/Status"},
+			"total": {"$ref": "#/properties/lines"}
+		},
+		"$defs": {
+			"Status": {"type": "string", "enum": [{"$ref": "not-a-schema"}]},
+			"Money": {"anyOf": [{"$ref": "#/definitions/Currency"}, {"type": "number"}]}
+		}
+	}`
+
+	parsed, err := parseSchema(&models.GlueSchema{
+		Name:         "order",
+		RegistryName: "payments",
+		DataFormat:   models.SchemaTypeJSON,
+		Versions:     []models.GlueSchemaVersion{{VersionNumber: 1, Definition: definition}},
+	})
+	if err != nil {
+		t.Fatalf("parseSchema failed: %v", err)
+	}
+
+	// Status is defined in the document; the other refs name other schemas
+	want := []string{"Currency", "Address", "customer-id", "OrderLine"}
+	if strings.Join(parsed.References, ",") != strings.Join(want, ",") {
+		t.Errorf("References = %v, expected %v", parsed.References, want)
+	}
+}
+
+func TestBuild_JSONRefs(t *testing.T) {
+	schemas := []*models.GlueSchema{
+		{
+			Name:         "customer-address",
+			RegistryName: "shared",
+			DataFormat:   models.SchemaTypeJSON,
+			Versions: []models.GlueSchemaVersion{
+				{VersionNumber: 1, Definition: `{"title":"Address","type":"object","properties":{"street":{"type":"string"}}}`},
+			},
+		},
+		{
+			Name:         "OrderLine",
+			RegistryName: "payments",
+			DataFormat:   models.SchemaTypeJSON,
+			Versions: []models.GlueSchemaVersion{
+				{VersionNumber: 1, Definition: `{"type":"object","properties":{"sku":{"type":"string"}}}`},
+			},
+		},
+		{
+			Name:         "Order",
+			RegistryName: "payments",
+			DataFormat:   models.SchemaTypeJSON,
+			Versions: []models.GlueSchemaVersion{
+				{VersionNumber: 1, Definition: `{"title":"Order","type":"object","properties":{
+					"billing":{"$ref":"#/definitions/Address"},
+					"lines":{"type":"array","items":{"$ref":"#/definitions/OrderLine"}}
+				}}`},
+			},
+		},
+	}
+
+	graph, err := Build(schemas)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	// Address resolves by the title of customer-address, OrderLine by name
+	deps := graph.GetDependencies("payments", "Order")
+	if strings.Join(deps, ",") != "payments:OrderLine,shared:customer-address" {
+		t.Errorf("dependencies = %v, expected [payments:OrderLine shared:customer-address]", deps)
+	}
+	levels := graph.GetLevels()
+	if len(levels) != 2 || len(levels[1].Schemas) != 1 || levels[1].Schemas[0].SourceSchemaName != "Order" {
+		t.Errorf("expected Order alone in level 1, got %+v", levels)
+	}
+}
+
+func TestParseSchema_ProtobufImports(t *testing.T) {
+	definition := `syntax = "proto3";
+package shop;
+
+import "common/address.proto";
+import public "money.proto";
+import weak 'legacy/v1/note.proto'; // unused
+import "google/protobuf/timestamp.proto";
+
+message Customer {
+  Address billing = 1;
+}
+`
+
+	parsed, err := parseSchema(&models.GlueSchema{
+		Name:         "customer",
+		RegistryName: "shop",
+		DataFormat:   models.SchemaTypeProtobuf,
+		Versions:     []models.GlueSchemaVersion{{VersionNumber: 1, Definition: definition}},
+	})
+	if err != nil {
+		t.Fatalf("parseSchema failed: %v", err)
+	}
+
+	// Well-known types are built into Schema Registry
+	want := []string{"common/address", "money", "legacy/v1/note"}
+	if strings.Join(parsed.References, ",") != strings.Join(want, ",") {
+		t.Errorf("References = %v, expected %v", parsed.References, want)
+	}
+	wantNames := map[string]string{
+		"common/address": "common/address.proto",
+		"money":          "money.proto",
+		"legacy/v1/note": "legacy/v1/note.proto",
+	}
+	if !reflect.DeepEqual(parsed.ReferenceNames, wantNames) {
+		t.Errorf("ReferenceNames = %v, expected %v", parsed.ReferenceNames, wantNames)
+	}
+}
+
+func TestBuild_ProtobufImports(t *testing.T) {
+	schemas := []*models.GlueSchema{
+		{
+			Name:         "address",
+			RegistryName: "shop",
+			DataFormat:   models.SchemaTypeProtobuf,
+			Versions: []models.GlueSchemaVersion{
+				{VersionNumber: 1, Definition: "syntax = \"proto3\";\npackage common;\n\nmessage Address {\n  string street = 1;\n}\n"},
+			},
+		},
+		{
+			Name:         "customer",
+			RegistryName: "shop",
+			DataFormat:   models.SchemaTypeProtobuf,
+			Versions: []models.GlueSchemaVersion{
+				{VersionNumber: 1, Definition: "syntax = \"proto3\";\nimport \"common/address.proto\";\n\nmessage Customer {\n  common.Address billing = 1;\n}\n"},
+			},
+		},
+	}
+
+	graph, err := Build(schemas)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	deps := graph.GetDependencies("shop", "customer")
+	if strings.Join(deps, ",") != "shop:address" {
+		t.Fatalf("dependencies = %v, expected [shop:address]", deps)
+	}
+	levels := graph.GetLevels()
+	if len(levels) != 2 || levels[1].Schemas[0].SourceSchemaName != "customer" {
+		t.Fatalf("expected customer alone in level 1, got %+v", levels)
+	}
+	if got := levels[1].Schemas[0].ReferenceNames["shop:address"]; got != "common/address.proto" {
+		t.Errorf("reference name for shop:address = %q, expected the import path common/address.proto", got)
+	}
+
+	// A single level still carries the import, unordered
+	single, err := SingleLevel(schemas)
+	if err != nil {
+		t.Fatalf("SingleLevel failed: %v", err)
+	}
+	customer := single[0].Schemas[1]
+	if customer.SourceSchemaName != "customer" || strings.Join(customer.References, ",") != "shop:address" {
+		t.Fatalf("single-level customer = %+v, expected a reference to shop:address", customer)
+	}
+	if customer.ReferenceFormats["shop:address"] != models.SchemaTypeProtobuf || customer.ReferenceNames["shop:address"] != "common/address.proto" {
+		t.Errorf("single-level reference format %q and name %q, expected PROTOBUF and common/address.proto",
+			customer.ReferenceFormats["shop:address"], customer.ReferenceNames["shop:address"])
+	}
+}
+
+func TestBuild_ProtobufImportPaths(t *testing.T) {
+	proto := func(registry, name, definition string) *models.GlueSchema {
+		return &models.GlueSchema{
+			Name:         name,
+			RegistryName: registry,
+			DataFormat:   models.SchemaTypeProtobuf,
+			Versions:     []models.GlueSchemaVersion{{VersionNumber: 1, Definition: definition}},
+		}
+	}
+	schemas := []*models.GlueSchema{
+		proto("billing", "common", "syntax = \"proto3\";\nmessage Common {}\n"),
+		proto("shipping", "common", "syntax = \"proto3\";\nmessage Common {}\n"),
+		proto("shop", "com.shop.money", "syntax = \"proto3\";\nmessage Money {}\n"),
+		proto("shop", "order", "syntax = \"proto3\";\n"+
+			"import \"billing/common.proto\";\n"+
+			"import \"shipping/common.proto\";\n"+
+			"import \"com/shop/money.proto\";\n"+
+			"import \"lib/common.proto\";\n"+
+			"message Order {}\n"),
+	}
+
+	graph, err := Build(schemas)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	// The registry and dotted-name matches resolve to distinct schemas; the
+	// lib/ import shares only its file name with two schemas, so it is left
+	// unresolved rather than guessed
+	deps := graph.GetDependencies("shop", "order")
+	want := "billing:common,shipping:common,shop:com.shop.money"
+	if strings.Join(deps, ",") != want {
+		t.Errorf("dependencies = %v, expected [%s]", deps, strings.ReplaceAll(want, ",", " "))
+	}
+}
+
+func TestBuild_DeterministicOrder(t *testing.T) {
+	avro := func(registry, name, definition string) *models.GlueSchema {
+		return &models.GlueSchema{
+			Name:         name,
+			RegistryName: registry,
+			DataFormat:   models.SchemaTypeAvro,
+			Versions:     []models.GlueSchemaVersion{{VersionNumber: 1, Definition: definition}},
+		}
+	}
+	schemas := []*models.GlueSchema{
+		avro("payments", "Refund", `{"type":"record","name":"Refund","fields":[{"name":"a","type":"Money"},{"name":"b","type":"Currency"},{"name":"c","type":"Address"}]}`),
+		avro("shared", "Money", `{"type":"record","name":"Money","fields":[]}`),
+		avro("shared", "Currency", `{"type":"enum","name":"Currency","symbols":["USD"]}`),
+		avro("shared", "Address", `{"type":"record","name":"Address","fields":[]}`),
+		avro("payments", "Order", `{"type":"record","name":"Order","fields":[{"name":"a","type":"Address"},{"name":"b","type":"Money"}]}`),
+		avro("orders", "Shipment", `{"type":"record","name":"Shipment","fields":[]}`),
+		avro("orders", "Invoice", `{"type":"record","name":"Invoice","fields":[]}`),
+	}
+
+	// order renders the levels with each schema's references
+	order := func(levels []Level) string {
+		var b strings.Builder
+		for _, level := range levels {
+			for _, schema := range level.Schemas {
+				b.WriteString(fmt.Sprintf("%d %s:%s %v\n", level.Level, schema.SourceRegistry, schema.SourceSchemaName, schema.References))
+			}
+		}
+		return b.String()
+	}
+
+	graph, err := Build(schemas)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	want := `0 orders:Invoice []
+0 orders:Shipment []
+0 shared:Address []
+0 shared:Currency []
+0 shared:Money []
+1 payments:Order [shared:Address shared:Money]
+1 payments:Refund [shared:Address shared:Currency shared:Money]
+`
+	if got := order(graph.GetLevels()); got != want {
+		t.Fatalf("levels:\n%s\nexpected:\n%s", got, want)
+	}
+	if got := graph.GetDependents("shared", "Money"); strings.Join(got, ",") != "payments:Order,payments:Refund" {
+		t.Errorf("dependents of Money = %v, expected them sorted", got)
+	}
+
+	for i := 0; i < 20; i++ {
+		graph, err := Build(schemas)
+		if err != nil {
+			t.Fatalf("Build failed: %v", err)
+		}
+		if got := order(graph.GetLevels()); got != want {
+			t.Fatalf("build %d ordered the levels differently:\n%s", i, got)
+		}
+	}
+}
+
+func TestBuild_DeferredDefinitions(t *testing.T) {
+	// With lazy definitions only version numbers are known while planning
+	schemas := []*models.GlueSchema{
+		{
+			Name:         "UserEvent",
+			RegistryName: "test-reg",
+			DataFormat:   models.SchemaTypeAvro,
+			Versions:     []models.GlueSchemaVersion{{VersionNumber: 1}, {VersionNumber: 2}},
+		},
+	}
+
+	graph, err := Build(schemas)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if levels := graph.GetLevels(); len(levels) != 1 || len(levels[0].Schemas) != 1 {
+		t.Errorf("expected one level with one schema, got %+v", levels)
+	}
+}
+
+func TestSingleLevel(t *testing.T) {
+	// A reference cycle would fail Build; SingleLevel resolves the references
+	// without ordering them
+	schemas := []*models.GlueSchema{
+		{
+			Name:         "b",
+			RegistryName: "reg",
+			DataFormat:   models.SchemaTypeAvro,
+			Versions: []models.GlueSchemaVersion{
+				{VersionNumber: 1, Definition: `{"type":"record","name":"B","fields":[{"name":"a","type":"a"}]}`},
+			},
+		},
+		{
+			Name:         "a",
+			RegistryName: "reg",
+			DataFormat:   models.SchemaTypeAvro,
+			Versions: []models.GlueSchemaVersion{
+				{VersionNumber: 1, Definition: `{"type":"record","name":"A","fields":[{"name":"b","type":"b"}]}`},
+				{VersionNumber: 2, Definition: `{"type":"record","name":"A","fields":[{"name":"b","type":["null","b"]}]}`},
+			},
+		},
+	}
+	if _, err := Build(schemas); err == nil {
+		t.Fatal("expected Build to reject the reference cycle")
+	}
+
+	levels, err := SingleLevel(schemas)
+	if err != nil {
+		t.Fatalf("SingleLevel failed: %v", err)
+	}
+	if len(levels) != 1 || len(levels[0].Schemas) != 2 {
+		t.Fatalf("expected one level with both schemas, got %+v", levels)
+	}
+	first, second := levels[0].Schemas[0], levels[0].Schemas[1]
+	if first.SourceSchemaName != "a" || second.SourceSchemaName != "b" {
+		t.Errorf("order = [%s %s], expected [a b]", first.SourceSchemaName, second.SourceSchemaName)
+	}
+	if first.SourceVersions != 2 || first.DependencyLevel != 0 {
+		t.Errorf("mapping = %+v, expected 2 versions at level 0", first)
+	}
+	if len(first.References) != 1 || first.References[0] != "reg:b" || first.ReferenceFormats["reg:b"] != models.SchemaTypeAvro {
+		t.Errorf("a references = %v (formats %v), expected reg:b as Avro", first.References, first.ReferenceFormats)
+	}
+	if len(second.References) != 1 || second.References[0] != "reg:a" {
+		t.Errorf("b references = %v, expected reg:a", second.References)
+	}
+
+	if levels, err := SingleLevel(nil); err != nil || levels != nil {
+		t.Errorf("expected no levels for no schemas, got %+v", levels)
+	}
+}
+
+func TestOrderLevels_Alpha(t *testing.T) {
+	names := []string{"orders", "accounts", "shipments", "customers", "invoices"}
+	var schemas []*models.GlueSchema
+	for _, name := range names {
+		schemas = append(schemas, &models.GlueSchema{
+			Name:         name,
+			RegistryName: "default",
+			DataFormat:   models.SchemaTypeAvro,
+			Versions: []models.GlueSchemaVersion{
+				{VersionNumber: 1, Definition: `{"type":"record","name":"X","fields":[]}`},
+			},
+		})
+	}
+
+	expected := []string{"accounts", "customers", "invoices", "orders", "shipments"}
+
+	// Level contents come from map iteration, so repeat to catch instability
+	for run := 0; run < 10; run++ {
+		graph, err := Build(schemas)
+		if err != nil {
+			t.Fatalf("Build failed: %v", err)
+		}
+		levels := graph.GetLevels()
+		OrderLevels(levels, "alpha", schemas)
+
+		if len(levels) != 1 || len(levels[0].Schemas) != len(expected) {
+			t.Fatalf("Expected 1 level with %d schemas, got %v", len(expected), levels)
+		}
+		for i, mapping := range levels[0].Schemas {
+			if mapping.SourceSchemaName != expected[i] {
+				t.Fatalf("run %d: position %d = %q, expected %q", run, i, mapping.SourceSchemaName, expected[i])
+			}
+		}
+	}
+}
+
+func TestOrderLevels_VersionsDesc(t *testing.T) {
+	levels := []Level{{
+		Level: 0,
+		Schemas: []models.SchemaMapping{
+			{SourceRegistry: "default", SourceSchemaName: "a", SourceVersions: 1},
+			{SourceRegistry: "default", SourceSchemaName: "b", SourceVersions: 5},
+			{SourceRegistry: "default", SourceSchemaName: "c", SourceVersions: 3},
+		},
+	}}
+
+	OrderLevels(levels, "versions-desc", nil)
+
+	got := []string{levels[0].Schemas[0].SourceSchemaName, levels[0].Schemas[1].SourceSchemaName, levels[0].Schemas[2].SourceSchemaName}
+	if got[0] != "b" || got[1] != "c" || got[2] != "a" {
+		t.Errorf("order = %v, expected [b c a]", got)
+	}
+}
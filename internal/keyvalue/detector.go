@@ -1,6 +1,7 @@
 package keyvalue
 
 import (
+	"io"
 	"os"
 	"regexp"
 	"strings"
@@ -276,3 +277,38 @@ func GetSuffix(role models.SchemaRole) string {
 	}
 	return "-value"
 }
+
+// OverrideSeedEntry is one detected role written to an override seed file
+type OverrideSeedEntry struct {
+	Schema string // registry.schema
+	Role   models.SchemaRole
+	Reason string
+}
+
+// WriteOverrideSeed writes entries to w as a role override file, with each
+// detection reason as a comment, so users can edit the roles they disagree
+// with and feed the file back through key_value.role_override_file
+func WriteOverrideSeed(w io.Writer, entries []OverrideSeedEntry) error {
+	overrides := &yaml.Node{Kind: yaml.MappingNode}
+	for _, e := range entries {
+		overrides.Content = append(overrides.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: e.Schema},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: string(e.Role), LineComment: e.Reason},
+		)
+	}
+	doc := &yaml.Node{
+		Kind:        yaml.MappingNode,
+		HeadComment: "Detected key/value roles. Edit any role that is wrong and set\nkey_value.role_override_file to this file.",
+		Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Value: "overrides"},
+			overrides,
+		},
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	return enc.Close()
+}
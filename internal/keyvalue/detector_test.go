@@ -1,6 +1,10 @@
 package keyvalue
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
@@ -158,3 +162,41 @@ func TestGetSuffix(t *testing.T) {
 		t.Errorf("GetSuffix(value) = %q, expected -value", suffix)
 	}
 }
+
+func TestWriteOverrideSeed_RoundTrip(t *testing.T) {
+	entries := []OverrideSeedEntry{
+		{Schema: "orders.OrderId", Role: models.SchemaRoleKey, Reason: "Built-in pattern: Id$"},
+		{Schema: "orders.OrderPlaced", Role: models.SchemaRoleValue, Reason: "Default role"},
+		{Schema: "users.Account", Role: models.SchemaRoleKey, Reason: "Structure: few fields with ID-like names"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteOverrideSeed(&buf, entries); err != nil {
+		t.Fatalf("WriteOverrideSeed() unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "# Default role") {
+		t.Errorf("expected detection reasons as comments, got:\n%s", buf.String())
+	}
+
+	path := filepath.Join(t.TempDir(), "roles.yaml")
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := New(config.NewDefaultConfig())
+	if err != nil {
+		t.Fatalf("Failed to create detector: %v", err)
+	}
+	if err := d.loadOverrideFile(path); err != nil {
+		t.Fatalf("loadOverrideFile() unexpected error: %v\n%s", err, buf.String())
+	}
+
+	for _, e := range entries {
+		registry, schema, _ := strings.Cut(e.Schema, ".")
+		result := d.Detect(registry, schema, nil)
+		if result.Role != e.Role || result.Reason != "Override file" {
+			t.Errorf("Detect(%s) = %s (reason: %s), expected %s from the override file",
+				e.Schema, result.Role, result.Reason, e.Role)
+		}
+	}
+}
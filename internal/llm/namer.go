@@ -2,11 +2,18 @@ package llm
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
+	"text/template"
 
 	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
 	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
 )
 
 // Namer uses LLM to suggest schema names
@@ -15,32 +22,65 @@ type Namer struct {
 	provider     Provider
 	preprocessor *Preprocessor
 	cache        *Cache
+	prompt       *template.Template // llm.prompt_template_file, nil for the built-in prompt
+	promptHash   string             // hash of the prompt template, "" for the built-in prompt
 	callCount    int
 	totalCost    float64
+
+	// Dry runs with llm.estimate_in_dry_run tally what the calls would cost
+	// instead of making them
+	estimate       bool
+	estimatedCalls int
+	estimatedCost  float64
 }
 
+// ErrCostEstimated is returned by SuggestName when the namer only estimates
+// LLM cost and did not call the provider
+var ErrCostEstimated = errors.New("LLM call estimated, not made")
+
+// estimatedCompletionTokens approximates the JSON object the prompt asks for
+const estimatedCompletionTokens = 50
+
 // NameSuggestion represents an LLM naming suggestion
 type NameSuggestion struct {
 	OriginalName   string `json:"original_name"`
 	SuggestedName  string `json:"suggested_name"`
 	IsKeySchema    bool   `json:"is_key_schema"`
 	Reasoning      string `json:"reasoning"`
+	RoleSuggested  bool   `json:"role_suggested,omitempty"` // IsKeySchema came from the LLM and may override the detected role
 }
 
-// NewNamer creates a new LLM Namer
-func NewNamer(cfg *config.Config) (*Namer, error) {
+// NewNamer creates a new LLM Namer. awsCfg is only used by the bedrock
+// provider.
+func NewNamer(cfg *config.Config, awsCfg aws.Config) (*Namer, error) {
 	// Create provider based on configuration
-	provider, err := NewProvider(cfg)
+	provider, err := NewProvider(cfg, awsCfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create LLM provider: %w", err)
 	}
 
+	return NewNamerWithProvider(cfg, provider)
+}
+
+// NewNamerWithProvider creates a Namer with an injected provider (for testing).
+func NewNamerWithProvider(cfg *config.Config, provider Provider) (*Namer, error) {
+	var prompt *template.Template
+	var promptHash string
+	if cfg.LLM.PromptTemplateFile != "" {
+		var err error
+		prompt, promptHash, err = LoadPromptTemplate(cfg.LLM.PromptTemplateFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Create preprocessor
 	preprocessor := NewPreprocessor()
 
 	// Create cache
 	var cache *Cache
 	if cfg.LLM.CacheFile != "" {
+		var err error
 		cache, err = NewCache(cfg.LLM.CacheFile)
 		if err != nil {
 			// Non-fatal - just log and continue without cache
@@ -56,27 +96,44 @@ func NewNamer(cfg *config.Config) (*Namer, error) {
 		provider:     provider,
 		preprocessor: preprocessor,
 		cache:        cache,
+		prompt:       prompt,
+		promptHash:   promptHash,
+		estimate:     cfg.Output.DryRun && cfg.LLM.EstimateInDryRun,
 	}, nil
 }
 
 // SuggestName uses the LLM to suggest a subject name
 func (n *Namer) SuggestName(ctx context.Context, schema *models.GlueSchema, parsed *models.ParsedSchema, role models.SchemaRole) (*NameSuggestion, error) {
 	// Check cache first
-	cacheKey := fmt.Sprintf("%s:%s", schema.RegistryName, schema.Name)
+	cacheKey := n.cacheKey(schema.RegistryName, schema.Name)
 	if cached, ok := n.cache.Get(cacheKey); ok {
 		return cached, nil
 	}
 
+	// Preprocess schema to extract context
+	schemaContext := n.preprocessor.ExtractContext(schema, parsed)
+
+	// Estimate instead of calling the provider; the cost limit doesn't apply
+	if n.estimate {
+		cost, err := n.EstimateCost(schemaContext, role)
+		if err != nil {
+			return nil, err
+		}
+		n.estimatedCalls++
+		n.estimatedCost += cost
+		return nil, ErrCostEstimated
+	}
+
 	// Check cost limit
 	if n.config.LLM.MaxCost > 0 && n.totalCost >= n.config.LLM.MaxCost {
 		return nil, fmt.Errorf("LLM cost limit reached ($%.2f)", n.config.LLM.MaxCost)
 	}
 
-	// Preprocess schema to extract context
-	schemaContext := n.preprocessor.ExtractContext(schema, parsed)
-
 	// Build prompt
-	prompt := n.buildPrompt(schemaContext, role)
+	prompt, err := n.buildPrompt(schemaContext, role)
+	if err != nil {
+		return nil, err
+	}
 
 	// Call LLM
 	response, cost, err := n.provider.Complete(ctx, prompt)
@@ -104,6 +161,89 @@ func (n *Namer) SuggestName(ctx context.Context, schema *models.GlueSchema, pars
 	return suggestion, nil
 }
 
+// cacheKey identifies a schema's suggestion in the cache. Suggestions made
+// with a prompt template are kept apart per template, while the built-in
+// prompt keeps the plain registry:schema key earlier caches were written with.
+func (n *Namer) cacheKey(registry, schema string) string {
+	if n.promptHash == "" {
+		return fmt.Sprintf("%s:%s", registry, schema)
+	}
+	return fmt.Sprintf("%s:%s@%s", registry, schema, n.promptHash)
+}
+
+// SeedCache pre-populates the cache with the names a prior run's LLM
+// suggested, taken from its report. Entries are only seeded for schemas whose
+// latest definition still hashes to the value recorded in the report, so
+// changed schemas are named afresh. It returns the number of entries seeded.
+func (n *Namer) SeedCache(report *models.MigrationReport, schemas []*models.GlueSchema) int {
+	current := make(map[string]*models.GlueSchema, len(schemas))
+	for _, schema := range schemas {
+		current[fmt.Sprintf("%s:%s", schema.RegistryName, schema.Name)] = schema
+	}
+
+	seeded := 0
+	for _, s := range report.Schemas {
+		if s.NamingStrategy != "llm" || s.SuggestedName == "" || s.ContentHash == "" {
+			continue
+		}
+		schema, ok := current[fmt.Sprintf("%s:%s", s.SourceRegistry, s.SourceSchema)]
+		if !ok || ContentHash(schema) != s.ContentHash {
+			continue
+		}
+		key := n.cacheKey(s.SourceRegistry, s.SourceSchema)
+		if _, ok := n.cache.Get(key); ok {
+			continue
+		}
+		n.cache.Set(key, &NameSuggestion{
+			OriginalName:  s.SourceSchema,
+			SuggestedName: s.SuggestedName,
+			IsKeySchema:   s.DetectedRole == models.SchemaRoleKey,
+			Reasoning:     "seeded from a prior report",
+			RoleSuggested: true,
+		})
+		seeded++
+	}
+
+	return seeded
+}
+
+// ContentHash returns the SHA-256 of the schema's latest definition, or ""
+// when no definition has been fetched
+func ContentHash(schema *models.GlueSchema) string {
+	if len(schema.Versions) == 0 {
+		return ""
+	}
+	definition := schema.Versions[len(schema.Versions)-1].Definition
+	if definition == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(definition))
+	return hex.EncodeToString(sum[:])
+}
+
+// EstimateCost approximates what naming one schema costs without calling the
+// provider: about four characters per prompt token, priced at
+// llm.input_token_cost, plus a short completion at llm.output_token_cost
+func (n *Namer) EstimateCost(schemaContext *models.SchemaContext, role models.SchemaRole) (float64, error) {
+	prompt, err := n.buildPrompt(schemaContext, role)
+	if err != nil {
+		return 0, err
+	}
+	promptTokens := len(prompt) / 4
+	return float64(promptTokens)*n.config.LLM.InputTokenCost +
+		float64(estimatedCompletionTokens)*n.config.LLM.OutputTokenCost, nil
+}
+
+// GetEstimatedCalls returns the number of LLM calls estimated instead of made
+func (n *Namer) GetEstimatedCalls() int {
+	return n.estimatedCalls
+}
+
+// GetEstimatedCost returns the estimated cost of the calls not made
+func (n *Namer) GetEstimatedCost() float64 {
+	return n.estimatedCost
+}
+
 // GetCallCount returns the number of LLM calls made
 func (n *Namer) GetCallCount() int {
 	return n.callCount
@@ -122,7 +262,15 @@ func (n *Namer) Close() error {
 	return nil
 }
 
-func (n *Namer) buildPrompt(schemaContext *SchemaContext, role models.SchemaRole) string {
+func (n *Namer) buildPrompt(schemaContext *models.SchemaContext, role models.SchemaRole) (string, error) {
+	if n.prompt != nil {
+		var b strings.Builder
+		if err := n.prompt.Execute(&b, models.PromptData{SchemaContext: *schemaContext, Role: role}); err != nil {
+			return "", fmt.Errorf("failed to render prompt template: %w", err)
+		}
+		return b.String(), nil
+	}
+
 	return fmt.Sprintf(`You are a Confluent Cloud Schema Registry naming expert.
 
 Given information about an AWS Glue schema, suggest an appropriate Confluent Cloud subject name.
@@ -150,10 +298,12 @@ Detected Role: %s
 1. Analyze the schema name, record name, namespace, and field names
 2. The schema has been detected as a %s schema
 3. Suggest a clean, descriptive subject name following Confluent conventions
-4. Include the appropriate suffix (-%s)
+4. Include the appropriate suffix (-%s), unless the fields show the detected
+   role is wrong; then set is_key_schema to match and use that role's suffix
 
-Respond with ONLY the suggested subject name, nothing else.
-Example response: payment-transactions-value`,
+Respond with ONLY a JSON object with these fields, nothing else:
+{"suggested_name": "<subject name>", "is_key_schema": <true or false>, "reasoning": "<one sentence>"}
+Example response: {"suggested_name": "payment-transactions-value", "is_key_schema": false, "reasoning": "Payment event payload"}`,
 		schemaContext.GlueSchemaName,
 		schemaContext.GlueRegistry,
 		schemaContext.SchemaType,
@@ -165,11 +315,16 @@ Example response: payment-transactions-value`,
 		role,
 		role,
 		role,
-	)
+	), nil
 }
 
 func (n *Namer) parseResponse(response string, originalName string) (*NameSuggestion, error) {
-	// Clean up the response
+	// Prefer the JSON object the prompt asks for
+	if suggestion, ok := parseJSONResponse(response, originalName); ok {
+		return suggestion, nil
+	}
+
+	// Otherwise treat the response as a bare subject name
 	suggested := response
 	suggested = cleanResponse(suggested)
 
@@ -184,8 +339,47 @@ func (n *Namer) parseResponse(response string, originalName string) (*NameSugges
 	}, nil
 }
 
+// parseJSONResponse extracts the suggestion object from a response, allowing
+// for markdown fences or text around it. ok is false when there is no object
+// with a suggested_name.
+func parseJSONResponse(response string, originalName string) (*NameSuggestion, bool) {
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+	if start < 0 || end < start {
+		return nil, false
+	}
+
+	var raw struct {
+		SuggestedName string `json:"suggested_name"`
+		IsKeySchema   *bool  `json:"is_key_schema"`
+		Reasoning     string `json:"reasoning"`
+	}
+	if err := json.Unmarshal([]byte(response[start:end+1]), &raw); err != nil {
+		return nil, false
+	}
+	suggested := cleanResponse(raw.SuggestedName)
+	if suggested == "" {
+		return nil, false
+	}
+
+	suggestion := &NameSuggestion{
+		OriginalName:  originalName,
+		SuggestedName: suggested,
+		Reasoning:     raw.Reasoning,
+	}
+	if suggestion.Reasoning == "" {
+		suggestion.Reasoning = "LLM suggestion"
+	}
+	if raw.IsKeySchema != nil {
+		suggestion.IsKeySchema = *raw.IsKeySchema
+		suggestion.RoleSuggested = true
+	}
+	return suggestion, true
+}
+
 func cleanResponse(s string) string {
-	// Remove common markdown artifacts
+	// Remove common markdown artifacts, which may be followed by a newline
+	s = trim(s)
 	s = trimPrefix(s, "```")
 	s = trimSuffix(s, "```")
 	s = trimPrefix(s, "`")
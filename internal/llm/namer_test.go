@@ -0,0 +1,294 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
+	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
+)
+
+// countingProvider records how many completions were requested
+type countingProvider struct {
+	calls int
+}
+
+func (p *countingProvider) Complete(ctx context.Context, prompt string) (string, float64, error) {
+	p.calls++
+	return `{"suggested_name": "fresh-name-value", "is_key_schema": false, "reasoning": "test"}`, 0.01, nil
+}
+
+func newTestSchema(registry, name, definition string) *models.GlueSchema {
+	return &models.GlueSchema{
+		RegistryName: registry,
+		Name:         name,
+		DataFormat:   models.SchemaTypeAvro,
+		Versions: []models.GlueSchemaVersion{
+			{VersionNumber: 1, Definition: definition},
+		},
+	}
+}
+
+func TestNamer_SeedCache(t *testing.T) {
+	unchanged := newTestSchema("payments", "MSK_PaymentEvent", `{"type":"record","name":"PaymentEvent","fields":[]}`)
+	changed := newTestSchema("payments", "MSK_RefundEvent", `{"type":"record","name":"RefundEvent","fields":[{"name":"id","type":"string"}]}`)
+
+	prior := &models.MigrationReport{
+		Schemas: []models.SchemaReport{
+			{
+				SourceRegistry: "payments",
+				SourceSchema:   "MSK_PaymentEvent",
+				NamingStrategy: "llm",
+				SuggestedName:  "payment-event-value",
+				ContentHash:    ContentHash(unchanged),
+			},
+			{
+				SourceRegistry: "payments",
+				SourceSchema:   "MSK_RefundEvent",
+				NamingStrategy: "llm",
+				SuggestedName:  "refund-event-value",
+				ContentHash:    ContentHash(newTestSchema("payments", "MSK_RefundEvent", `{"type":"record","name":"RefundEvent","fields":[]}`)),
+			},
+		},
+	}
+
+	provider := &countingProvider{}
+	n := &Namer{
+		config:       config.NewDefaultConfig(),
+		provider:     provider,
+		preprocessor: NewPreprocessor(),
+		cache:        NewEmptyCache(),
+	}
+
+	if seeded := n.SeedCache(prior, []*models.GlueSchema{unchanged, changed}); seeded != 1 {
+		t.Fatalf("SeedCache() = %d, expected 1 entry seeded", seeded)
+	}
+
+	suggestion, err := n.SuggestName(context.Background(), unchanged, nil, models.SchemaRoleValue)
+	if err != nil {
+		t.Fatalf("SuggestName() unexpected error: %v", err)
+	}
+	if suggestion.SuggestedName != "payment-event-value" {
+		t.Errorf("SuggestedName = %q, expected the seeded payment-event-value", suggestion.SuggestedName)
+	}
+	if provider.calls != 0 {
+		t.Errorf("expected no provider calls for a seeded schema, got %d", provider.calls)
+	}
+
+	// The refund schema changed since the report, so it must be named afresh
+	if _, err := n.SuggestName(context.Background(), changed, nil, models.SchemaRoleValue); err != nil {
+		t.Fatalf("SuggestName() unexpected error: %v", err)
+	}
+	if provider.calls != 1 {
+		t.Errorf("expected 1 provider call for the changed schema, got %d", provider.calls)
+	}
+}
+
+func TestParseResponse(t *testing.T) {
+	n := &Namer{}
+	tests := []struct {
+		name          string
+		response      string
+		wantName      string
+		wantKey       bool
+		wantSuggested bool
+		wantReasoning string
+	}{
+		{
+			name:          "json object",
+			response:      `{"suggested_name": "order-key", "is_key_schema": true, "reasoning": "Only carries the order ID"}`,
+			wantName:      "order-key",
+			wantKey:       true,
+			wantSuggested: true,
+			wantReasoning: "Only carries the order ID",
+		},
+		{
+			name:          "json in a markdown fence",
+			response:      "```json\n{\"suggested_name\": \"order-placed-value\", \"is_key_schema\": false, \"reasoning\": \"Event payload\"}\n```",
+			wantName:      "order-placed-value",
+			wantSuggested: true,
+			wantReasoning: "Event payload",
+		},
+		{
+			name:          "json without a role",
+			response:      `{"suggested_name": "order-placed-value"}`,
+			wantName:      "order-placed-value",
+			wantReasoning: "LLM suggestion",
+		},
+		{
+			name:          "legacy plain text",
+			response:      "`payment-transactions-value`\n",
+			wantName:      "payment-transactions-value",
+			wantReasoning: "LLM suggestion",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := n.parseResponse(tt.response, "MSK_OrderEvent")
+			if err != nil {
+				t.Fatalf("parseResponse() unexpected error: %v", err)
+			}
+			if got.SuggestedName != tt.wantName || got.IsKeySchema != tt.wantKey || got.RoleSuggested != tt.wantSuggested || got.Reasoning != tt.wantReasoning {
+				t.Errorf("parseResponse() = %+v, expected name %q, key %v, role suggested %v, reasoning %q",
+					got, tt.wantName, tt.wantKey, tt.wantSuggested, tt.wantReasoning)
+			}
+			if got.OriginalName != "MSK_OrderEvent" {
+				t.Errorf("OriginalName = %q, expected MSK_OrderEvent", got.OriginalName)
+			}
+		})
+	}
+
+	if _, err := n.parseResponse("  \n", "MSK_OrderEvent"); err == nil {
+		t.Error("expected an error for an empty response")
+	}
+}
+
+// recordingProvider keeps the last prompt it was sent
+type recordingProvider struct {
+	prompt string
+}
+
+func (p *recordingProvider) Complete(ctx context.Context, prompt string) (string, float64, error) {
+	p.prompt = prompt
+	return `{"suggested_name": "payments.payment-event-value", "is_key_schema": false, "reasoning": "test"}`, 0, nil
+}
+
+func TestNamer_PromptTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prompt.tmpl")
+	tmpl := "Prefix the subject with the domain {{.GlueRegistry}}.\nSchema: {{.GlueSchemaName}} ({{.SchemaType}}, {{.FieldCount}} fields)\nRole: {{.Role}}\n"
+	if err := os.WriteFile(path, []byte(tmpl), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.NewDefaultConfig()
+	cfg.LLM.CacheFile = ""
+	cfg.LLM.PromptTemplateFile = path
+	provider := &recordingProvider{}
+	n, err := NewNamerWithProvider(cfg, provider)
+	if err != nil {
+		t.Fatalf("NewNamerWithProvider() unexpected error: %v", err)
+	}
+
+	schema := newTestSchema("payments", "MSK_PaymentEvent", `{"type":"record","name":"PaymentEvent","fields":[{"name":"id","type":"string"}]}`)
+	if _, err := n.SuggestName(context.Background(), schema, nil, models.SchemaRoleValue); err != nil {
+		t.Fatalf("SuggestName() unexpected error: %v", err)
+	}
+
+	want := "Prefix the subject with the domain payments.\nSchema: MSK_PaymentEvent (AVRO, 1 fields)\nRole: value\n"
+	if provider.prompt != want {
+		t.Errorf("prompt = %q, expected %q", provider.prompt, want)
+	}
+}
+
+func TestNamer_PromptTemplateCacheKey(t *testing.T) {
+	dir := t.TempDir()
+	cacheFile := filepath.Join(dir, "llm-cache.json")
+	schema := newTestSchema("payments", "MSK_PaymentEvent", `{"type":"record","name":"PaymentEvent","fields":[]}`)
+
+	// suggest names the schema with the template text, saving to the shared
+	// cache, and returns how many provider calls it took
+	suggest := func(text string) int {
+		t.Helper()
+		path := filepath.Join(dir, "prompt.tmpl")
+		if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cfg := config.NewDefaultConfig()
+		cfg.LLM.CacheFile = cacheFile
+		cfg.LLM.PromptTemplateFile = path
+		provider := &countingProvider{}
+		n, err := NewNamerWithProvider(cfg, provider)
+		if err != nil {
+			t.Fatalf("NewNamerWithProvider() unexpected error: %v", err)
+		}
+		if _, err := n.SuggestName(context.Background(), schema, nil, models.SchemaRoleValue); err != nil {
+			t.Fatalf("SuggestName() unexpected error: %v", err)
+		}
+		if err := n.Close(); err != nil {
+			t.Fatalf("Close() unexpected error: %v", err)
+		}
+		return provider.calls
+	}
+
+	if calls := suggest("Schema: {{.GlueSchemaName}}"); calls != 1 {
+		t.Errorf("first run made %d provider calls, expected 1", calls)
+	}
+	if calls := suggest("Schema: {{.GlueSchemaName}}"); calls != 0 {
+		t.Errorf("same template made %d provider calls, expected the cached name", calls)
+	}
+	if calls := suggest("Name the subject for {{.GlueSchemaName}} in kebab case"); calls != 1 {
+		t.Errorf("edited template made %d provider calls, expected a fresh suggestion", calls)
+	}
+}
+
+func TestNamer_PromptTemplateUnknownField(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prompt.tmpl")
+	if err := os.WriteFile(path, []byte("Schema: {{.SchemaName}}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.NewDefaultConfig()
+	cfg.LLM.CacheFile = ""
+	cfg.LLM.PromptTemplateFile = path
+	if _, err := NewNamerWithProvider(cfg, &recordingProvider{}); err == nil {
+		t.Error("expected an error for a template referring to an unknown field")
+	}
+}
+
+func TestNamer_PromptTemplateUnknownFieldInBranch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prompt.tmpl")
+	tmpl := "Schema: {{.GlueSchemaName}}\n{{if .Namespace}}Namespace: {{.Namespce}}{{end}}"
+	if err := os.WriteFile(path, []byte(tmpl), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.NewDefaultConfig()
+	cfg.LLM.CacheFile = ""
+	cfg.LLM.PromptTemplateFile = path
+	if _, err := NewNamerWithProvider(cfg, &recordingProvider{}); err == nil || !strings.Contains(err.Error(), "Namespce") {
+		t.Errorf("expected an error naming the unknown field inside the if branch, got %v", err)
+	}
+}
+
+func TestNamer_EstimateInDryRun(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.LLM.CacheFile = ""
+	cfg.Output.DryRun = true
+	cfg.LLM.EstimateInDryRun = true
+	cfg.LLM.InputTokenCost = 0.000001
+	cfg.LLM.OutputTokenCost = 0.000002
+	provider := &countingProvider{}
+	n, err := NewNamerWithProvider(cfg, provider)
+	if err != nil {
+		t.Fatalf("NewNamerWithProvider() unexpected error: %v", err)
+	}
+
+	schema := newTestSchema("payments", "MSK_PaymentEvent", `{"type":"record","name":"PaymentEvent","fields":[{"name":"id","type":"string"}]}`)
+	for i := 0; i < 2; i++ {
+		if _, err := n.SuggestName(context.Background(), schema, nil, models.SchemaRoleValue); !errors.Is(err, ErrCostEstimated) {
+			t.Fatalf("SuggestName() error = %v, expected ErrCostEstimated", err)
+		}
+	}
+
+	if provider.calls != 0 {
+		t.Errorf("expected no provider calls when estimating, got %d", provider.calls)
+	}
+	if n.GetCallCount() != 0 || n.GetTotalCost() != 0 {
+		t.Errorf("real calls = %d (cost %f), expected none", n.GetCallCount(), n.GetTotalCost())
+	}
+	perCall, err := n.EstimateCost(n.preprocessor.ExtractContext(schema, nil), models.SchemaRoleValue)
+	if err != nil {
+		t.Fatalf("EstimateCost() unexpected error: %v", err)
+	}
+	if perCall <= float64(estimatedCompletionTokens)*cfg.LLM.OutputTokenCost {
+		t.Errorf("EstimateCost() = %f, expected prompt tokens on top of the completion", perCall)
+	}
+	if n.GetEstimatedCalls() != 2 || n.GetEstimatedCost() != 2*perCall {
+		t.Errorf("estimated %d calls costing %f, expected 2 costing %f", n.GetEstimatedCalls(), n.GetEstimatedCost(), 2*perCall)
+	}
+}
@@ -10,19 +10,6 @@ import (
 // Preprocessor extracts key context from schemas for LLM processing
 type Preprocessor struct{}
 
-// SchemaContext represents the preprocessed context sent to the LLM
-type SchemaContext struct {
-	GlueSchemaName  string   `json:"glue_schema_name"`
-	GlueRegistry    string   `json:"glue_registry"`
-	SchemaType      string   `json:"schema_type"`
-	RecordName      string   `json:"record_name,omitempty"`
-	Namespace       string   `json:"namespace,omitempty"`
-	Documentation   string   `json:"documentation,omitempty"`
-	KeyFields       []string `json:"key_fields,omitempty"`
-	FieldCount      int      `json:"field_count"`
-	References      []string `json:"references,omitempty"`
-}
-
 // FieldSummary represents a summarized field
 type FieldSummary struct {
 	Name     string `json:"name"`
@@ -36,8 +23,8 @@ func NewPreprocessor() *Preprocessor {
 }
 
 // ExtractContext extracts key context from a schema for LLM processing
-func (p *Preprocessor) ExtractContext(schema *models.GlueSchema, parsed *models.ParsedSchema) *SchemaContext {
-	ctx := &SchemaContext{
+func (p *Preprocessor) ExtractContext(schema *models.GlueSchema, parsed *models.ParsedSchema) *models.SchemaContext {
+	ctx := &models.SchemaContext{
 		GlueSchemaName: schema.Name,
 		GlueRegistry:   schema.RegistryName,
 		SchemaType:     string(schema.DataFormat),
@@ -73,7 +60,7 @@ func (p *Preprocessor) ExtractContext(schema *models.GlueSchema, parsed *models.
 	return ctx
 }
 
-func (p *Preprocessor) extractFromDefinition(definition string, schemaType string, ctx *SchemaContext) {
+func (p *Preprocessor) extractFromDefinition(definition string, schemaType string, ctx *models.SchemaContext) {
 	switch schemaType {
 	case "AVRO":
 		p.extractFromAvro(definition, ctx)
@@ -84,7 +71,7 @@ func (p *Preprocessor) extractFromDefinition(definition string, schemaType strin
 	}
 }
 
-func (p *Preprocessor) extractFromAvro(definition string, ctx *SchemaContext) {
+func (p *Preprocessor) extractFromAvro(definition string, ctx *models.SchemaContext) {
 	var avro map[string]interface{}
 	if err := json.Unmarshal([]byte(definition), &avro); err != nil {
 		return
@@ -126,7 +113,7 @@ func (p *Preprocessor) extractFromAvro(definition string, ctx *SchemaContext) {
 	}
 }
 
-func (p *Preprocessor) extractFromJSON(definition string, ctx *SchemaContext) {
+func (p *Preprocessor) extractFromJSON(definition string, ctx *models.SchemaContext) {
 	var jsonSchema map[string]interface{}
 	if err := json.Unmarshal([]byte(definition), &jsonSchema); err != nil {
 		return
@@ -160,7 +147,7 @@ func (p *Preprocessor) extractFromJSON(definition string, ctx *SchemaContext) {
 	}
 }
 
-func (p *Preprocessor) extractFromProtobuf(definition string, ctx *SchemaContext) {
+func (p *Preprocessor) extractFromProtobuf(definition string, ctx *models.SchemaContext) {
 	lines := strings.Split(definition, "\n")
 	fieldCount := 0
 
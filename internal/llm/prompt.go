@@ -0,0 +1,35 @@
+package llm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"text/template"
+
+	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/tmplcheck"
+)
+
+// LoadPromptTemplate parses the prompt template at path and checks every
+// field it refers to against models.PromptData, so a template that refers to
+// an unknown field fails at startup rather than on the first schema that
+// reaches it. It also returns a short hash of
+// the template text, which keys the LLM cache so names suggested under an
+// edited template aren't reused.
+func LoadPromptTemplate(path string) (*template.Template, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read prompt template: %w", err)
+	}
+	tmpl, err := template.New("prompt").Parse(string(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid prompt template: %w", err)
+	}
+
+	if err := tmplcheck.Fields(tmpl, models.PromptData{}); err != nil {
+		return nil, "", fmt.Errorf("invalid prompt template: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return tmpl, hex.EncodeToString(sum[:])[:12], nil
+}
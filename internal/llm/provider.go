@@ -5,11 +5,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
 	"golang.org/x/time/rate"
 )
 
@@ -18,24 +21,41 @@ type Provider interface {
 	Complete(ctx context.Context, prompt string) (string, float64, error)
 }
 
-// NewProvider creates a new LLM provider based on configuration
-func NewProvider(cfg *config.Config) (Provider, error) {
+// NewProvider creates a new LLM provider based on configuration. awsCfg
+// carries the AWS region and credentials the bedrock provider calls with;
+// other providers ignore it.
+func NewProvider(cfg *config.Config, awsCfg aws.Config) (Provider, error) {
 	// Create rate limiter
 	limiter := rate.NewLimiter(rate.Limit(cfg.LLM.RateLimit), 1)
 	inputCost := cfg.LLM.InputTokenCost
 	outputCost := cfg.LLM.OutputTokenCost
 
+	// HTTP providers retry 429 and 5xx responses
+	retry := newRetryPolicy(cfg)
+
 	switch cfg.LLM.Provider {
 	case "openai":
-		return NewOpenAIProvider(cfg.LLM.APIKey, cfg.LLM.Model, limiter, inputCost, outputCost), nil
+		p := NewOpenAIProvider(cfg.LLM.APIKey, cfg.LLM.Model, limiter, inputCost, outputCost)
+		p.retry = retry
+		return p, nil
 	case "anthropic":
-		return NewAnthropicProvider(cfg.LLM.APIKey, cfg.LLM.Model, limiter, inputCost, outputCost), nil
+		p := NewAnthropicProvider(cfg.LLM.APIKey, cfg.LLM.Model, limiter, inputCost, outputCost)
+		p.retry = retry
+		return p, nil
+	case "azure":
+		p := NewAzureOpenAIProvider(cfg.LLM.BaseURL, cfg.LLM.APIKey, cfg.LLM.Model, cfg.LLM.APIVersion, limiter, inputCost, outputCost)
+		p.retry = retry
+		return p, nil
 	case "ollama":
-		return NewOllamaProvider(cfg.LLM.BaseURL, cfg.LLM.Model, limiter), nil
+		p := NewOllamaProvider(cfg.LLM.BaseURL, cfg.LLM.Model, limiter)
+		p.retry = retry
+		return p, nil
 	case "local":
-		return NewGenericProvider(cfg.LLM.BaseURL, cfg.LLM.Model, cfg.LLM.APIKey, limiter), nil
+		p := NewGenericProvider(cfg.LLM.BaseURL, cfg.LLM.Model, cfg.LLM.APIKey, limiter)
+		p.retry = retry
+		return p, nil
 	case "bedrock":
-		return NewBedrockProvider(cfg.LLM.Model, limiter)
+		return NewBedrockProvider(awsCfg, cfg.LLM.Model, limiter, inputCost, outputCost)
 	default:
 		return nil, fmt.Errorf("unknown LLM provider: %s", cfg.LLM.Provider)
 	}
@@ -47,6 +67,7 @@ type OpenAIProvider struct {
 	model           string
 	limiter         *rate.Limiter
 	client          *http.Client
+	retry           retryPolicy
 	inputTokenCost  float64
 	outputTokenCost float64
 }
@@ -82,27 +103,113 @@ func (p *OpenAIProvider) Complete(ctx context.Context, prompt string) (string, f
 		return "", 0, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/chat/completions", bytes.NewReader(body))
+	resp, respBody, err := p.retry.do(ctx, p.client, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/chat/completions", bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+		return req, nil
+	})
 	if err != nil {
 		return "", 0, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf("OpenAI API error: %s", string(respBody))
+	}
+
+	var result struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+		Usage struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+			TotalTokens      int `json:"total_tokens"`
+		} `json:"usage"`
+	}
+
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return "", 0, err
+	}
+
+	if len(result.Choices) == 0 {
+		return "", 0, fmt.Errorf("no response from OpenAI")
+	}
 
-	resp, err := p.client.Do(req)
+	cost := float64(result.Usage.PromptTokens)*p.inputTokenCost + float64(result.Usage.CompletionTokens)*p.outputTokenCost
+
+	return result.Choices[0].Message.Content, cost, nil
+}
+
+// AzureOpenAIProvider implements the Provider interface for Azure OpenAI,
+// where the model is the name of a deployment on the resource at baseURL
+type AzureOpenAIProvider struct {
+	baseURL         string
+	apiKey          string
+	deployment      string
+	apiVersion      string
+	limiter         *rate.Limiter
+	client          *http.Client
+	retry           retryPolicy
+	inputTokenCost  float64
+	outputTokenCost float64
+}
+
+// NewAzureOpenAIProvider creates a new Azure OpenAI provider
+func NewAzureOpenAIProvider(baseURL, apiKey, deployment, apiVersion string, limiter *rate.Limiter, inputCost, outputCost float64) *AzureOpenAIProvider {
+	return &AzureOpenAIProvider{
+		baseURL:         strings.TrimSuffix(baseURL, "/"),
+		apiKey:          apiKey,
+		deployment:      deployment,
+		apiVersion:      apiVersion,
+		limiter:         limiter,
+		client:          &http.Client{Timeout: 60 * time.Second},
+		inputTokenCost:  inputCost,
+		outputTokenCost: outputCost,
+	}
+}
+
+func (p *AzureOpenAIProvider) Complete(ctx context.Context, prompt string) (string, float64, error) {
+	if err := p.limiter.Wait(ctx); err != nil {
+		return "", 0, err
+	}
+
+	// The deployment selects the model, so none is sent in the body
+	reqBody := map[string]interface{}{
+		"messages": []map[string]string{
+			{"role": "user", "content": prompt},
+		},
+		"max_tokens":  500,
+		"temperature": 0.3,
+	}
+
+	body, err := json.Marshal(reqBody)
 	if err != nil {
 		return "", 0, err
 	}
-	defer resp.Body.Close()
 
-	respBody, err := io.ReadAll(resp.Body)
+	endpoint := fmt.Sprintf("%s/openai/deployments/%s/chat/completions?api-version=%s",
+		p.baseURL, url.PathEscape(p.deployment), url.QueryEscape(p.apiVersion))
+	resp, respBody, err := p.retry.do(ctx, p.client, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("api-key", p.apiKey)
+		return req, nil
+	})
 	if err != nil {
 		return "", 0, err
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return "", 0, fmt.Errorf("OpenAI API error: %s", string(respBody))
+		return "", 0, fmt.Errorf("Azure OpenAI API error: %s", string(respBody))
 	}
 
 	var result struct {
@@ -123,7 +230,7 @@ func (p *OpenAIProvider) Complete(ctx context.Context, prompt string) (string, f
 	}
 
 	if len(result.Choices) == 0 {
-		return "", 0, fmt.Errorf("no response from OpenAI")
+		return "", 0, fmt.Errorf("no response from Azure OpenAI")
 	}
 
 	cost := float64(result.Usage.PromptTokens)*p.inputTokenCost + float64(result.Usage.CompletionTokens)*p.outputTokenCost
@@ -137,6 +244,7 @@ type AnthropicProvider struct {
 	model           string
 	limiter         *rate.Limiter
 	client          *http.Client
+	retry           retryPolicy
 	inputTokenCost  float64
 	outputTokenCost float64
 }
@@ -171,22 +279,16 @@ func (p *AnthropicProvider) Complete(ctx context.Context, prompt string) (string
 		return "", 0, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.anthropic.com/v1/messages", bytes.NewReader(body))
-	if err != nil {
-		return "", 0, err
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("x-api-key", p.apiKey)
-	req.Header.Set("anthropic-version", "2023-06-01")
-
-	resp, err := p.client.Do(req)
-	if err != nil {
-		return "", 0, err
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
+	resp, respBody, err := p.retry.do(ctx, p.client, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", "https://api.anthropic.com/v1/messages", bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("x-api-key", p.apiKey)
+		req.Header.Set("anthropic-version", "2023-06-01")
+		return req, nil
+	})
 	if err != nil {
 		return "", 0, err
 	}
@@ -224,6 +326,7 @@ type OllamaProvider struct {
 	model   string
 	limiter *rate.Limiter
 	client  *http.Client
+	retry   retryPolicy
 }
 
 // NewOllamaProvider creates a new Ollama provider
@@ -255,20 +358,14 @@ func (p *OllamaProvider) Complete(ctx context.Context, prompt string) (string, f
 		return "", 0, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/api/generate", bytes.NewReader(body))
-	if err != nil {
-		return "", 0, err
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := p.client.Do(req)
-	if err != nil {
-		return "", 0, err
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
+	resp, respBody, err := p.retry.do(ctx, p.client, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/api/generate", bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
 	if err != nil {
 		return "", 0, err
 	}
@@ -296,6 +393,7 @@ type GenericProvider struct {
 	apiKey  string
 	limiter *rate.Limiter
 	client  *http.Client
+	retry   retryPolicy
 }
 
 // NewGenericProvider creates a new generic provider
@@ -328,23 +426,17 @@ func (p *GenericProvider) Complete(ctx context.Context, prompt string) (string,
 		return "", 0, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/chat/completions", bytes.NewReader(body))
-	if err != nil {
-		return "", 0, err
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	if p.apiKey != "" {
-		req.Header.Set("Authorization", "Bearer "+p.apiKey)
-	}
-
-	resp, err := p.client.Do(req)
-	if err != nil {
-		return "", 0, err
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
+	resp, respBody, err := p.retry.do(ctx, p.client, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/chat/completions", bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if p.apiKey != "" {
+			req.Header.Set("Authorization", "Bearer "+p.apiKey)
+		}
+		return req, nil
+	})
 	if err != nil {
 		return "", 0, err
 	}
@@ -373,26 +465,132 @@ func (p *GenericProvider) Complete(ctx context.Context, prompt string) (string,
 	return result.Choices[0].Message.Content, 0, nil
 }
 
-// BedrockProvider implements the Provider interface for AWS Bedrock
+// BedrockProvider implements the Provider interface for AWS Bedrock, for the
+// Anthropic Claude and Amazon Titan text model families
 type BedrockProvider struct {
-	model   string
-	limiter *rate.Limiter
+	model           string
+	limiter         *rate.Limiter
+	invoke          bedrockInvokeFunc
+	inputTokenCost  float64
+	outputTokenCost float64
 }
 
-// NewBedrockProvider creates a new Bedrock provider
-func NewBedrockProvider(model string, limiter *rate.Limiter) (*BedrockProvider, error) {
+// bedrockInvokeFunc matches bedrockruntime.Client.InvokeModel
+type bedrockInvokeFunc func(ctx context.Context, params *bedrockruntime.InvokeModelInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.InvokeModelOutput, error)
+
+// NewBedrockProvider creates a new Bedrock provider calling with awsCfg's
+// region and credentials
+func NewBedrockProvider(awsCfg aws.Config, model string, limiter *rate.Limiter, inputCost, outputCost float64) (*BedrockProvider, error) {
+	if bedrockFamily(model) == "" {
+		return nil, fmt.Errorf("unsupported Bedrock model %q: expected an anthropic.claude or amazon.titan-text model", model)
+	}
+
 	return &BedrockProvider{
-		model:   model,
-		limiter: limiter,
+		model:           model,
+		limiter:         limiter,
+		invoke:          bedrockruntime.NewFromConfig(awsCfg).InvokeModel,
+		inputTokenCost:  inputCost,
+		outputTokenCost: outputCost,
 	}, nil
 }
 
+// bedrockFamily returns the request format for a Bedrock model ID, allowing
+// cross-region inference profile prefixes such as "us.", or "" if unsupported
+func bedrockFamily(model string) string {
+	switch {
+	case strings.Contains(model, "anthropic.claude"):
+		return "claude"
+	case strings.Contains(model, "amazon.titan-text"):
+		return "titan"
+	default:
+		return ""
+	}
+}
+
 func (p *BedrockProvider) Complete(ctx context.Context, prompt string) (string, float64, error) {
 	if err := p.limiter.Wait(ctx); err != nil {
 		return "", 0, err
 	}
 
-	// Note: Full Bedrock implementation would require AWS SDK
-	// This is a placeholder that shows the structure
-	return "", 0, fmt.Errorf("Bedrock provider requires AWS SDK implementation")
+	family := bedrockFamily(p.model)
+
+	var reqBody map[string]interface{}
+	switch family {
+	case "claude":
+		reqBody = map[string]interface{}{
+			"anthropic_version": "bedrock-2023-05-31",
+			"max_tokens":        500,
+			"messages": []map[string]string{
+				{"role": "user", "content": prompt},
+			},
+		}
+	case "titan":
+		reqBody = map[string]interface{}{
+			"inputText": prompt,
+			"textGenerationConfig": map[string]interface{}{
+				"maxTokenCount": 500,
+				"temperature":   0.3,
+			},
+		}
+	default:
+		return "", 0, fmt.Errorf("unsupported Bedrock model %q", p.model)
+	}
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", 0, err
+	}
+
+	resp, err := p.invoke(ctx, &bedrockruntime.InvokeModelInput{
+		ModelId:     aws.String(p.model),
+		Body:        body,
+		ContentType: aws.String("application/json"),
+		Accept:      aws.String("application/json"),
+	})
+	if err != nil {
+		return "", 0, fmt.Errorf("Bedrock API error: %w", err)
+	}
+
+	var text string
+	var inputTokens, outputTokens int
+	switch family {
+	case "claude":
+		var result struct {
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+			Usage struct {
+				InputTokens  int `json:"input_tokens"`
+				OutputTokens int `json:"output_tokens"`
+			} `json:"usage"`
+		}
+		if err := json.Unmarshal(resp.Body, &result); err != nil {
+			return "", 0, err
+		}
+		if len(result.Content) == 0 {
+			return "", 0, fmt.Errorf("no response from Bedrock")
+		}
+		text = result.Content[0].Text
+		inputTokens, outputTokens = result.Usage.InputTokens, result.Usage.OutputTokens
+	case "titan":
+		var result struct {
+			InputTextTokenCount int `json:"inputTextTokenCount"`
+			Results             []struct {
+				TokenCount int    `json:"tokenCount"`
+				OutputText string `json:"outputText"`
+			} `json:"results"`
+		}
+		if err := json.Unmarshal(resp.Body, &result); err != nil {
+			return "", 0, err
+		}
+		if len(result.Results) == 0 {
+			return "", 0, fmt.Errorf("no response from Bedrock")
+		}
+		text = result.Results[0].OutputText
+		inputTokens, outputTokens = result.InputTextTokenCount, result.Results[0].TokenCount
+	}
+
+	cost := float64(inputTokens)*p.inputTokenCost + float64(outputTokens)*p.outputTokenCost
+
+	return text, cost, nil
 }
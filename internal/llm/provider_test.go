@@ -0,0 +1,101 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"golang.org/x/time/rate"
+)
+
+func TestBedrockProvider_Claude(t *testing.T) {
+	var got *bedrockruntime.InvokeModelInput
+	p := &BedrockProvider{
+		model:   "anthropic.claude-3-haiku-20240307-v1:0",
+		limiter: rate.NewLimiter(rate.Inf, 1),
+		invoke: func(ctx context.Context, params *bedrockruntime.InvokeModelInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.InvokeModelOutput, error) {
+			got = params
+			return &bedrockruntime.InvokeModelOutput{
+				Body: []byte(`{"content":[{"type":"text","text":"order-events-value"}],"usage":{"input_tokens":100,"output_tokens":20}}`),
+			}, nil
+		},
+		inputTokenCost:  0.001,
+		outputTokenCost: 0.005,
+	}
+
+	text, cost, err := p.Complete(context.Background(), "name this schema")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text != "order-events-value" {
+		t.Errorf("text = %q, want order-events-value", text)
+	}
+	if want := 100*0.001 + 20*0.005; cost != want {
+		t.Errorf("cost = %v, want %v", cost, want)
+	}
+
+	if aws.ToString(got.ModelId) != p.model {
+		t.Errorf("ModelId = %q, want %q", aws.ToString(got.ModelId), p.model)
+	}
+	if aws.ToString(got.ContentType) != "application/json" {
+		t.Errorf("ContentType = %q, want application/json", aws.ToString(got.ContentType))
+	}
+
+	var body struct {
+		AnthropicVersion string `json:"anthropic_version"`
+		MaxTokens        int    `json:"max_tokens"`
+		Messages         []struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"messages"`
+	}
+	if err := json.Unmarshal(got.Body, &body); err != nil {
+		t.Fatalf("request body is not JSON: %v", err)
+	}
+	if body.AnthropicVersion != "bedrock-2023-05-31" || body.MaxTokens == 0 {
+		t.Errorf("body = %s, want anthropic_version bedrock-2023-05-31 and max_tokens", got.Body)
+	}
+	if len(body.Messages) != 1 || body.Messages[0].Role != "user" || body.Messages[0].Content != "name this schema" {
+		t.Errorf("messages = %+v, want a single user message with the prompt", body.Messages)
+	}
+}
+
+func TestAzureOpenAIProvider(t *testing.T) {
+	var gotPath, gotVersion, gotKey, gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotVersion = r.URL.Query().Get("api-version")
+		gotKey = r.Header.Get("api-key")
+		gotAuth = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"order-events-value"}}],"usage":{"prompt_tokens":100,"completion_tokens":20,"total_tokens":120}}`))
+	}))
+	defer server.Close()
+
+	p := NewAzureOpenAIProvider(server.URL+"/", "azure-key", "gpt-4o-naming", "2024-02-01", rate.NewLimiter(rate.Inf, 1), 0.001, 0.005)
+
+	text, cost, err := p.Complete(context.Background(), "name this schema")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text != "order-events-value" {
+		t.Errorf("text = %q, want order-events-value", text)
+	}
+	if want := 100*0.001 + 20*0.005; cost != want {
+		t.Errorf("cost = %v, want %v", cost, want)
+	}
+
+	if gotPath != "/openai/deployments/gpt-4o-naming/chat/completions" {
+		t.Errorf("path = %q, want the deployment's chat completions path", gotPath)
+	}
+	if gotVersion != "2024-02-01" {
+		t.Errorf("api-version = %q, want 2024-02-01", gotVersion)
+	}
+	if gotKey != "azure-key" || gotAuth != "" {
+		t.Errorf("api-key = %q, Authorization = %q; want the key in api-key only", gotKey, gotAuth)
+	}
+}
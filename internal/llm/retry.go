@@ -0,0 +1,101 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/akrishnanDG/glue-to-ccsr/internal/backoff"
+	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
+)
+
+// ErrRetriesExhausted is returned when an LLM API kept answering 429 or 5xx
+// until concurrency.retry_attempts ran out
+var ErrRetriesExhausted = errors.New("LLM API retries exhausted")
+
+// retryPolicy retries LLM HTTP calls on 429 and 5xx responses. It honors a
+// Retry-After header and otherwise backs off exponentially with jitter from
+// delay; either way it waits no longer than backoff.Cap(delay). The zero
+// value makes a single attempt.
+type retryPolicy struct {
+	attempts int
+	delay    time.Duration
+}
+
+func newRetryPolicy(cfg *config.Config) retryPolicy {
+	return retryPolicy{
+		attempts: cfg.Concurrency.RetryAttempts,
+		delay:    cfg.Concurrency.RetryDelay,
+	}
+}
+
+// do sends the request built by newRequest until it gets a response that is
+// not retryable, and returns that response with its body read. Backoff sleeps
+// end early when ctx is cancelled.
+func (r retryPolicy) do(ctx context.Context, client *http.Client, newRequest func() (*http.Request, error)) (*http.Response, []byte, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := newRequest()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, nil, err
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if !retryableStatus(resp.StatusCode) {
+			return resp, body, nil
+		}
+		if attempt >= r.attempts {
+			return nil, nil, fmt.Errorf("%w after %d attempts: status %d: %s", ErrRetriesExhausted, attempt+1, resp.StatusCode, string(body))
+		}
+
+		delay := r.wait(resp, attempt)
+		slog.Debug("retrying LLM call", "status", resp.StatusCode, "attempt", attempt+1, "delay", delay)
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// wait returns how long to sleep before retrying after resp. A Retry-After
+// header is capped like the backoff, so a server can't hold a call for ever.
+func (r retryPolicy) wait(resp *http.Response, attempt int) time.Duration {
+	if delay, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+		return min(delay, backoff.Cap(r.delay))
+	}
+	return backoff.WithJitter(r.delay, attempt)
+}
+
+// retryableStatus reports whether an LLM API status is throttling or a
+// server error
+func retryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date
+func retryAfter(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return max(time.Until(at), 0), true
+	}
+	return 0, false
+}
@@ -0,0 +1,93 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/akrishnanDG/glue-to-ccsr/internal/backoff"
+	"golang.org/x/time/rate"
+)
+
+// throttlingServer answers 429 for the first throttled requests, the first
+// with Retry-After and the rest without, then a chat completion
+func throttlingServer(t *testing.T, throttled int32) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		if n <= throttled {
+			if n == 1 {
+				w.Header().Set("Retry-After", "0")
+			}
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error":{"message":"rate limited"}}`))
+			return
+		}
+		w.Write([]byte(`{"choices":[{"message":{"content":"order-events-value"}}]}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestRetryPolicy_RetriesThrottledCalls(t *testing.T) {
+	server, calls := throttlingServer(t, 2)
+
+	p := NewGenericProvider(server.URL, "local-model", "", rate.NewLimiter(rate.Inf, 1))
+	p.retry = retryPolicy{attempts: 3, delay: time.Millisecond}
+
+	text, _, err := p.Complete(context.Background(), "name this schema")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text != "order-events-value" {
+		t.Errorf("text = %q, want order-events-value", text)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("server saw %d calls, want 2 throttled and 1 successful", got)
+	}
+}
+
+func TestRetryPolicy_Exhausted(t *testing.T) {
+	server, calls := throttlingServer(t, 2)
+
+	p := NewGenericProvider(server.URL, "local-model", "", rate.NewLimiter(rate.Inf, 1))
+	p.retry = retryPolicy{attempts: 1, delay: time.Millisecond}
+
+	_, _, err := p.Complete(context.Background(), "name this schema")
+	if !errors.Is(err, ErrRetriesExhausted) {
+		t.Fatalf("error = %v, want ErrRetriesExhausted", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("server saw %d calls, want the first attempt and 1 retry", got)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	if d, ok := retryAfter("3"); !ok || d != 3*time.Second {
+		t.Errorf("retryAfter(3) = %v, %v; want 3s", d, ok)
+	}
+	if d, ok := retryAfter(time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat)); !ok || d != 0 {
+		t.Errorf("retryAfter(past date) = %v, %v; want 0", d, ok)
+	}
+	if _, ok := retryAfter("soon"); ok {
+		t.Error("expected an unparseable Retry-After to be ignored")
+	}
+}
+
+func TestRetryPolicy_WaitCapsRetryAfter(t *testing.T) {
+	p := retryPolicy{attempts: 3, delay: time.Second}
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"3600"}}}
+	if d := p.wait(resp, 0); d != backoff.Cap(time.Second) {
+		t.Errorf("wait(Retry-After: 3600) = %v, want the %v backoff cap", d, backoff.Cap(time.Second))
+	}
+
+	resp.Header.Set("Retry-After", "2")
+	if d := p.wait(resp, 0); d != 2*time.Second {
+		t.Errorf("wait(Retry-After: 2) = %v, want 2s", d)
+	}
+}
@@ -4,11 +4,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"hash/fnv"
 	"io"
+	"log/slog"
+	"net"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
@@ -21,46 +26,111 @@ type ConfluentLoader struct {
 	config      *config.Config
 	client      *http.Client
 	rateLimiter *rate.Limiter
-	baseURL     string
+	baseURL     string // scheme, host and any path prefix, without a trailing slash
+	baseQuery   string // query string from the configured URL, sent on every request
+
+	metadataWarnOnce sync.Once // warns once when the registry lacks the metadata endpoint
+
+	// refVersionsMu guards refVersions, the latest version of each
+	// referenced subject, looked up once per run
+	refVersionsMu sync.Mutex
+	refVersions   map[string]int
 }
 
 // New creates a new ConfluentLoader
 func New(cfg *config.Config) (*ConfluentLoader, error) {
-	baseURL := strings.TrimSuffix(cfg.ConfluentCloud.URL, "/")
+	baseURL, baseQuery := splitBaseURL(cfg.ConfluentCloud.URL)
 
 	return &ConfluentLoader{
 		config:      cfg,
-		client:      &http.Client{Timeout: 30 * time.Second},
+		client:      &http.Client{Timeout: 30 * time.Second, Transport: newTransport(cfg)},
 		rateLimiter: rate.NewLimiter(rate.Limit(cfg.Concurrency.CCRateLimit), 1),
 		baseURL:     baseURL,
+		baseQuery:   baseQuery,
 	}, nil
 }
 
-// RegisterSchema registers a schema version in Confluent Cloud
+// newTransport builds the HTTP transport from confluent_cloud.transport.
+// Unless max_conns_per_host is set, the per-host pool is sized to the larger
+// of concurrency.workers and followup_concurrency so every in-flight request
+// can hold a connection without redialing
+func newTransport(cfg *config.Config) *http.Transport {
+	tc := cfg.ConfluentCloud.Transport
+
+	maxConns := tc.MaxConnsPerHost
+	if maxConns == 0 {
+		maxConns = max(cfg.Concurrency.Workers, cfg.Concurrency.FollowupConcurrency)
+	}
+	idleTimeout := tc.IdleConnTimeout
+	if idleTimeout == 0 {
+		idleTimeout = 90 * time.Second
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxConnsPerHost = maxConns
+	transport.MaxIdleConnsPerHost = maxConns
+	transport.ForceAttemptHTTP2 = tc.ForceAttemptHTTP2
+	transport.IdleConnTimeout = idleTimeout
+	return transport
+}
+
+// splitBaseURL separates the configured Schema Registry URL into the prefix
+// API paths are appended to, keeping any path prefix such as /sr, and its
+// query string
+func splitBaseURL(raw string) (string, string) {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil {
+		return strings.TrimRight(raw, "/"), ""
+	}
+	query := u.RawQuery
+	u.RawQuery = ""
+	u.ForceQuery = false
+	u.Fragment = ""
+	u.RawFragment = ""
+	return strings.TrimRight(u.String(), "/"), query
+}
+
+// endpoint returns the full URL for an API path such as "/subjects", which
+// may carry its own query string
+func (l *ConfluentLoader) endpoint(path string) string {
+	if l.baseQuery == "" {
+		return l.baseURL + path
+	}
+	if strings.Contains(path, "?") {
+		return l.baseURL + path + "&" + l.baseQuery
+	}
+	return l.baseURL + path + "?" + l.baseQuery
+}
+
+// RegisterSchema registers a schema version in Confluent Cloud. With
+// migration.skip_existing it first looks the schema up under the subject and
+// returns ErrAlreadyRegistered, without registering, when it is already there.
 func (l *ConfluentLoader) RegisterSchema(ctx context.Context, mapping *models.SchemaMapping, version *models.GlueSchemaVersion) error {
 	if err := l.rateLimiter.Wait(ctx); err != nil {
 		return err
 	}
 
-	// Build subject name with context
-	subject := mapping.TargetSubject
-	if mapping.TargetContext != "" {
-		subject = mapping.TargetContext + ":" + subject
+	subject := contextSubject(mapping)
+	reqBody, err := l.registrationRequest(ctx, mapping, version)
+	if err != nil {
+		return err
 	}
-
-	// Prepare the schema registration request
-	reqBody := SchemaRegistrationRequest{
-		Schema:     version.Definition,
-		SchemaType: getSchemaType(mapping),
+	if l.config.Migration.PreserveSchemaIDs || l.config.Migration.PreserveVersions {
+		reqBody.ID = SourceSchemaID(version)
+	}
+	if l.config.Migration.PreserveVersions {
+		reqBody.Version = int(version.VersionNumber)
 	}
 
-	// Add references if needed
-	if len(mapping.References) > 0 && l.config.Migration.ReferenceStrategy == "rewrite" {
-		refs, err := l.buildReferences(mapping.References, mapping.TargetContext)
+	if l.config.Migration.SkipExisting {
+		_, found, err := l.lookupSchema(ctx, subject, lookupRequest(reqBody))
 		if err != nil {
-			return fmt.Errorf("failed to build references: %w", err)
+			return err
+		}
+		if found {
+			return ErrAlreadyRegistered
 		}
-		reqBody.References = refs
 	}
 
 	body, err := json.Marshal(reqBody)
@@ -69,8 +139,7 @@ func (l *ConfluentLoader) RegisterSchema(ctx context.Context, mapping *models.Sc
 	}
 
 	// Make the API call (URL encode subject name)
-	encodedSubject := url.PathEscape(subject)
-	apiURL := fmt.Sprintf("%s/subjects/%s/versions", l.baseURL, encodedSubject)
+	apiURL := l.endpoint("/subjects/" + url.PathEscape(subject) + "/versions")
 	
 	req, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewReader(body))
 	if err != nil {
@@ -79,7 +148,7 @@ func (l *ConfluentLoader) RegisterSchema(ctx context.Context, mapping *models.Sc
 
 	l.setHeaders(req)
 
-	resp, err := l.client.Do(req)
+	resp, err := l.do(req)
 	if err != nil {
 		return fmt.Errorf("failed to register schema: %w", err)
 	}
@@ -91,12 +160,306 @@ func (l *ConfluentLoader) RegisterSchema(ctx context.Context, mapping *models.Sc
 	}
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return fmt.Errorf("schema registration failed for subject '%s': %s (status %d)", subject, string(respBody), resp.StatusCode)
+		regErr := newRegisterError(subject, resp.StatusCode, respBody)
+		regErr.SchemaID = reqBody.ID
+		return regErr
 	}
 
 	return nil
 }
 
+// ErrAlreadyRegistered is returned by RegisterSchema when migration.skip_existing
+// is set and the identical schema is already registered under the subject
+var ErrAlreadyRegistered = errors.New("schema already registered under subject")
+
+// SchemaRegisteredUnderSubject looks a schema version up under the mapping's
+// subject, with the same schema type and references RegisterSchema would
+// send, and returns its version there, or false if the subject doesn't have it
+func (l *ConfluentLoader) SchemaRegisteredUnderSubject(ctx context.Context, mapping *models.SchemaMapping, version *models.GlueSchemaVersion) (int, bool, error) {
+	reqBody, err := l.registrationRequest(ctx, mapping, version)
+	if err != nil {
+		return 0, false, err
+	}
+	return l.lookupSchema(ctx, contextSubject(mapping), lookupRequest(reqBody))
+}
+
+// contextSubject returns the mapping's target subject qualified with its
+// context, if any
+func contextSubject(mapping *models.SchemaMapping) string {
+	if mapping.TargetContext != "" {
+		return mapping.TargetContext + ":" + mapping.TargetSubject
+	}
+	return mapping.TargetSubject
+}
+
+// registrationRequest builds the body registering the version under the
+// mapping's subject, without the preserved ID and version
+func (l *ConfluentLoader) registrationRequest(ctx context.Context, mapping *models.SchemaMapping, version *models.GlueSchemaVersion) (SchemaRegistrationRequest, error) {
+	reqBody := SchemaRegistrationRequest{
+		Schema:     version.Definition,
+		SchemaType: getSchemaType(mapping),
+		Metadata:   l.buildMetadata(mapping),
+	}
+
+	// Add references if needed
+	if len(mapping.References) > 0 && l.config.Migration.ReferenceStrategy == "rewrite" {
+		refs, err := l.buildReferences(ctx, mapping)
+		if err != nil {
+			return reqBody, fmt.Errorf("failed to build references: %w", err)
+		}
+		reqBody.References = refs
+	}
+	return reqBody, nil
+}
+
+// lookupRequest keeps the parts of a registration body that identify the
+// schema: its definition, type and references
+func lookupRequest(reqBody SchemaRegistrationRequest) SchemaRegistrationRequest {
+	return SchemaRegistrationRequest{
+		Schema:     reqBody.Schema,
+		SchemaType: reqBody.SchemaType,
+		References: reqBody.References,
+	}
+}
+
+// lookupSchema posts a schema to the subject lookup endpoint, which finds an
+// identical schema without registering anything. A missing subject or schema
+// is reported as not found.
+func (l *ConfluentLoader) lookupSchema(ctx context.Context, subject string, lookup SchemaRegistrationRequest) (int, bool, error) {
+	if err := l.rateLimiter.Wait(ctx); err != nil {
+		return 0, false, err
+	}
+
+	body, err := json.Marshal(lookup)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	apiURL := l.endpoint("/subjects/" + url.PathEscape(subject))
+	req, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, false, err
+	}
+
+	l.setHeaders(req)
+
+	resp, err := l.do(req)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to look up schema: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return 0, false, nil
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, false, newStatusError(fmt.Sprintf("failed to look up schema under subject '%s'", subject), resp.StatusCode, respBody)
+	}
+
+	var result struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return 0, false, err
+	}
+
+	return result.Version, true, nil
+}
+
+// SourceSchemaID derives the schema ID registered for a Glue version when
+// migration.preserve_schema_ids is set. Glue identifies versions by UUID, so
+// the ID is a stable positive 31-bit hash of the version ID: the same Glue
+// version always maps to the same target ID. Distinct versions can still
+// hash to the same ID, so planning fails on duplicates among the versions it
+// migrates; an ID taken by an earlier run is rejected by the target.
+func SourceSchemaID(version *models.GlueSchemaVersion) int {
+	h := fnv.New32a()
+	h.Write([]byte(version.SchemaVersionID))
+	id := int(h.Sum32() & 0x7fffffff)
+	if id == 0 {
+		id = 1
+	}
+	return id
+}
+
+// RegisterError is returned when Schema Registry rejects a registration
+type RegisterError struct {
+	Subject    string
+	StatusCode int
+	ErrorCode  int    // Schema Registry error_code, if present
+	Body       string // raw response body
+	SchemaID   int    // explicit schema ID sent with preserve_schema_ids, or 0
+}
+
+func (e *RegisterError) Error() string {
+	if e.SchemaID != 0 {
+		return fmt.Sprintf("schema registration with explicit ID %d failed for subject '%s': %s (status %d); the target rejected the preserved ID, check that the subject is in IMPORT mode and the ID is not used by another schema", e.SchemaID, e.Subject, e.Body, e.StatusCode)
+	}
+	return fmt.Sprintf("schema registration failed for subject '%s': %s (status %d)", e.Subject, e.Body, e.StatusCode)
+}
+
+// newRegisterError builds a RegisterError from a Schema Registry response
+func newRegisterError(subject string, status int, body []byte) *RegisterError {
+	regErr := &RegisterError{Subject: subject, StatusCode: status, Body: string(body)}
+	var srErr struct {
+		ErrorCode int `json:"error_code"`
+	}
+	if json.Unmarshal(body, &srErr) == nil {
+		regErr.ErrorCode = srErr.ErrorCode
+	}
+	return regErr
+}
+
+// StatusError is returned when Schema Registry answers a request other than
+// a registration or mode change with an unexpected status
+type StatusError struct {
+	Op         string // what failed, e.g. "failed to get subjects"
+	StatusCode int
+	ErrorCode  int    // Schema Registry error_code, if present
+	Body       string // raw response body
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s: %s (status %d)", e.Op, e.Body, e.StatusCode)
+}
+
+// newStatusError builds a StatusError from a Schema Registry response
+func newStatusError(op string, status int, body []byte) *StatusError {
+	statusErr := &StatusError{Op: op, StatusCode: status, Body: string(body)}
+	var srErr struct {
+		ErrorCode int `json:"error_code"`
+	}
+	if json.Unmarshal(body, &srErr) == nil {
+		statusErr.ErrorCode = srErr.ErrorCode
+	}
+	return statusErr
+}
+
+// defaultRetryableErrorCodes says whether failures with well-known Schema
+// Registry error codes are retried when confluent_cloud.retryable_error_codes
+// does not list them. Rejected schemas fail the same way on every attempt;
+// backend and forwarding errors are usually transient.
+var defaultRetryableErrorCodes = map[int]bool{
+	409:   false, // incompatible schema
+	42201: false, // invalid schema
+	50001: true,  // error in the backend data store
+	50002: true,  // operation timed out
+	50003: true,  // error forwarding the request to the leader
+}
+
+// Retryable reports whether a failed request is worth retrying. Responses
+// carrying a Schema Registry error_code follow
+// confluent_cloud.retryable_error_codes, then defaultRetryableErrorCodes;
+// other responses are retried only on a 5xx or 429 status. Of the errors
+// without a response, only network errors and timeouts are retried, so
+// cancellation and failures outside Schema Registry fail at once.
+func (l *ConfluentLoader) Retryable(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var status, code int
+	var regErr *RegisterError
+	var modeErr *ModeError
+	var statusErr *StatusError
+	switch {
+	case errors.As(err, &regErr):
+		status, code = regErr.StatusCode, regErr.ErrorCode
+	case errors.As(err, &modeErr):
+		status, code = modeErr.StatusCode, modeErr.ErrorCode
+	case errors.As(err, &statusErr):
+		status, code = statusErr.StatusCode, statusErr.ErrorCode
+	default:
+		var netErr net.Error
+		return errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.ErrUnexpectedEOF)
+	}
+
+	if code != 0 {
+		if retryable, ok := l.config.ConfluentCloud.RetryableErrorCodes[code]; ok {
+			return retryable
+		}
+		if retryable, ok := defaultRetryableErrorCodes[code]; ok {
+			return retryable
+		}
+	}
+	return status >= 500 || status == http.StatusTooManyRequests
+}
+
+// CheckCompatibility tests a schema version against the latest version
+// registered under the mapping's subject without registering it. It returns
+// whether the schema is compatible and, if not, the reasons Schema Registry
+// gave. A subject that does not exist yet is reported as compatible.
+func (l *ConfluentLoader) CheckCompatibility(ctx context.Context, mapping *models.SchemaMapping, version *models.GlueSchemaVersion) (bool, []string, error) {
+	if err := l.rateLimiter.Wait(ctx); err != nil {
+		return false, nil, err
+	}
+
+	subject := mapping.TargetSubject
+	if mapping.TargetContext != "" {
+		subject = mapping.TargetContext + ":" + subject
+	}
+
+	reqBody := SchemaRegistrationRequest{
+		Schema:     version.Definition,
+		SchemaType: getSchemaType(mapping),
+	}
+	if len(mapping.References) > 0 && l.config.Migration.ReferenceStrategy == "rewrite" {
+		refs, err := l.buildReferences(ctx, mapping)
+		if err != nil {
+			return false, nil, fmt.Errorf("failed to build references: %w", err)
+		}
+		reqBody.References = refs
+	}
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	apiURL := l.endpoint("/compatibility/subjects/" + url.PathEscape(subject) + "/versions/latest?verbose=true")
+	req, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewReader(body))
+	if err != nil {
+		return false, nil, err
+	}
+
+	l.setHeaders(req)
+
+	resp, err := l.do(req)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to check compatibility: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return true, nil, nil
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return false, nil, newStatusError(fmt.Sprintf("compatibility check failed for subject '%s'", subject), resp.StatusCode, respBody)
+	}
+
+	var result struct {
+		IsCompatible bool     `json:"is_compatible"`
+		Messages     []string `json:"messages"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return false, nil, err
+	}
+
+	return result.IsCompatible, result.Messages, nil
+}
+
 // SetCompatibility sets the compatibility level for a subject
 func (l *ConfluentLoader) SetCompatibility(ctx context.Context, subject string, compatibility string) error {
 	if err := l.rateLimiter.Wait(ctx); err != nil {
@@ -112,8 +475,7 @@ func (l *ConfluentLoader) SetCompatibility(ctx context.Context, subject string,
 		return err
 	}
 
-	encodedSubject := url.PathEscape(subject)
-	apiURL := fmt.Sprintf("%s/config/%s", l.baseURL, encodedSubject)
+	apiURL := l.endpoint("/config/" + url.PathEscape(subject))
 	req, err := http.NewRequestWithContext(ctx, "PUT", apiURL, bytes.NewReader(body))
 	if err != nil {
 		return err
@@ -121,7 +483,7 @@ func (l *ConfluentLoader) SetCompatibility(ctx context.Context, subject string,
 
 	l.setHeaders(req)
 
-	resp, err := l.client.Do(req)
+	resp, err := l.do(req)
 	if err != nil {
 		return err
 	}
@@ -129,7 +491,7 @@ func (l *ConfluentLoader) SetCompatibility(ctx context.Context, subject string,
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to set compatibility: %s", string(respBody))
+		return newStatusError("failed to set compatibility", resp.StatusCode, respBody)
 	}
 
 	return nil
@@ -137,19 +499,29 @@ func (l *ConfluentLoader) SetCompatibility(ctx context.Context, subject string,
 
 // GetSubjects returns all existing subjects
 func (l *ConfluentLoader) GetSubjects(ctx context.Context) ([]string, error) {
+	return l.listSubjects(ctx, "/subjects")
+}
+
+// GetSubjectsInAllContexts returns the existing subjects of every context.
+// Subjects outside the default context are qualified, as in ":.payments:orders-value".
+func (l *ConfluentLoader) GetSubjectsInAllContexts(ctx context.Context) ([]string, error) {
+	return l.listSubjects(ctx, "/subjects?subjectPrefix="+url.QueryEscape(":*:"))
+}
+
+func (l *ConfluentLoader) listSubjects(ctx context.Context, path string) ([]string, error) {
 	if err := l.rateLimiter.Wait(ctx); err != nil {
 		return nil, err
 	}
 
-	url := fmt.Sprintf("%s/subjects", l.baseURL)
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	apiURL := l.endpoint(path)
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	l.setHeaders(req)
 
-	resp, err := l.client.Do(req)
+	resp, err := l.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -161,7 +533,7 @@ func (l *ConfluentLoader) GetSubjects(ctx context.Context) ([]string, error) {
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get subjects: %s", string(respBody))
+		return nil, newStatusError("failed to get subjects", resp.StatusCode, respBody)
 	}
 
 	var subjects []string
@@ -178,8 +550,7 @@ func (l *ConfluentLoader) SubjectExists(ctx context.Context, subject string) (bo
 		return false, err
 	}
 
-	encodedSubject := url.PathEscape(subject)
-	apiURL := fmt.Sprintf("%s/subjects/%s/versions", l.baseURL, encodedSubject)
+	apiURL := l.endpoint("/subjects/" + url.PathEscape(subject) + "/versions")
 	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
 	if err != nil {
 		return false, err
@@ -187,13 +558,246 @@ func (l *ConfluentLoader) SubjectExists(ctx context.Context, subject string) (bo
 
 	l.setHeaders(req)
 
-	resp, err := l.client.Do(req)
+	resp, err := l.do(req)
 	if err != nil {
 		return false, err
 	}
 	defer resp.Body.Close()
 
-	return resp.StatusCode == http.StatusOK, nil
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		respBody, _ := io.ReadAll(resp.Body)
+		return false, newStatusError(fmt.Sprintf("failed to check subject '%s'", subject), resp.StatusCode, respBody)
+	}
+}
+
+// GetLatestVersion returns the latest version number registered under a
+// subject, or 0 if the subject does not exist
+func (l *ConfluentLoader) GetLatestVersion(ctx context.Context, subject string) (int, error) {
+	if err := l.rateLimiter.Wait(ctx); err != nil {
+		return 0, err
+	}
+
+	apiURL := l.endpoint("/subjects/" + url.PathEscape(subject) + "/versions/latest")
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	l.setHeaders(req)
+
+	resp, err := l.do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return 0, nil
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, newStatusError(fmt.Sprintf("failed to get latest version of subject '%s'", subject), resp.StatusCode, respBody)
+	}
+
+	var latest struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(respBody, &latest); err != nil {
+		return 0, err
+	}
+
+	return latest.Version, nil
+}
+
+// ErrSubjectNotFound is returned by DeleteSubject when there is nothing left
+// to delete: the subject doesn't exist or, for a soft delete, is already
+// soft-deleted
+var ErrSubjectNotFound = errors.New("subject not found")
+
+// Schema Registry error codes for a missing and a soft-deleted subject
+const (
+	errorCodeSubjectNotFound    = 40401
+	errorCodeSubjectSoftDeleted = 40404
+)
+
+// DeleteSubject deletes a subject and returns the versions removed. A soft
+// delete hides the subject but keeps its schemas recoverable; a permanent
+// delete first soft-deletes it, as Schema Registry requires, and then removes
+// it for good.
+func (l *ConfluentLoader) DeleteSubject(ctx context.Context, subject string, permanent bool) ([]int, error) {
+	versions, err := l.deleteSubject(ctx, subject, false)
+	if !permanent || (err != nil && !errors.Is(err, errSubjectSoftDeleted)) {
+		return versions, err
+	}
+	return l.deleteSubject(ctx, subject, true)
+}
+
+// errSubjectSoftDeleted reports a soft delete of a subject that already is
+var errSubjectSoftDeleted = fmt.Errorf("%w: already soft-deleted", ErrSubjectNotFound)
+
+func (l *ConfluentLoader) deleteSubject(ctx context.Context, subject string, permanent bool) ([]int, error) {
+	if err := l.rateLimiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	path := "/subjects/" + url.PathEscape(subject)
+	if permanent {
+		path += "?permanent=true"
+	}
+	req, err := http.NewRequestWithContext(ctx, "DELETE", l.endpoint(path), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	l.setHeaders(req)
+
+	resp, err := l.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete subject: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var srErr struct {
+			ErrorCode int `json:"error_code"`
+		}
+		json.Unmarshal(respBody, &srErr)
+		switch srErr.ErrorCode {
+		case errorCodeSubjectNotFound:
+			return nil, fmt.Errorf("%w: %s", ErrSubjectNotFound, subject)
+		case errorCodeSubjectSoftDeleted:
+			return nil, errSubjectSoftDeleted
+		}
+		return nil, newStatusError(fmt.Sprintf("failed to delete subject '%s'", subject), resp.StatusCode, respBody)
+	}
+
+	var versions []int
+	if err := json.Unmarshal(respBody, &versions); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// ErrVersionNotFound is returned by DeleteVersion when the version doesn't
+// exist or, for a soft delete, is already soft-deleted
+var ErrVersionNotFound = errors.New("version not found")
+
+// Schema Registry error codes for a missing and a soft-deleted version
+const (
+	errorCodeVersionNotFound    = 40402
+	errorCodeVersionSoftDeleted = 40406
+)
+
+// ListVersions returns the version numbers registered under a subject, or
+// ErrSubjectNotFound if it doesn't exist
+func (l *ConfluentLoader) ListVersions(ctx context.Context, subject string) ([]int, error) {
+	if err := l.rateLimiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	apiURL := l.endpoint("/subjects/" + url.PathEscape(subject) + "/versions")
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	l.setHeaders(req)
+
+	resp, err := l.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrSubjectNotFound, subject)
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError(fmt.Sprintf("failed to list versions of subject '%s'", subject), resp.StatusCode, respBody)
+	}
+
+	var versions []int
+	if err := json.Unmarshal(respBody, &versions); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// DeleteVersion deletes one version of a subject. As with DeleteSubject, a
+// permanent delete soft-deletes the version first.
+func (l *ConfluentLoader) DeleteVersion(ctx context.Context, subject string, version int, permanent bool) error {
+	err := l.deleteVersion(ctx, subject, version, false)
+	if !permanent || (err != nil && !errors.Is(err, errVersionSoftDeleted)) {
+		return err
+	}
+	return l.deleteVersion(ctx, subject, version, true)
+}
+
+// errVersionSoftDeleted reports a soft delete of a version that already is
+var errVersionSoftDeleted = fmt.Errorf("%w: already soft-deleted", ErrVersionNotFound)
+
+func (l *ConfluentLoader) deleteVersion(ctx context.Context, subject string, version int, permanent bool) error {
+	if err := l.rateLimiter.Wait(ctx); err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/subjects/%s/versions/%d", url.PathEscape(subject), version)
+	if permanent {
+		path += "?permanent=true"
+	}
+	req, err := http.NewRequestWithContext(ctx, "DELETE", l.endpoint(path), nil)
+	if err != nil {
+		return err
+	}
+
+	l.setHeaders(req)
+
+	resp, err := l.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete version: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var srErr struct {
+			ErrorCode int `json:"error_code"`
+		}
+		json.Unmarshal(respBody, &srErr)
+		switch srErr.ErrorCode {
+		case errorCodeSubjectNotFound, errorCodeVersionNotFound:
+			return fmt.Errorf("%w: %s version %d", ErrVersionNotFound, subject, version)
+		case errorCodeSubjectSoftDeleted, errorCodeVersionSoftDeleted:
+			return errVersionSoftDeleted
+		}
+		return newStatusError(fmt.Sprintf("failed to delete version %d of subject '%s'", version, subject), resp.StatusCode, respBody)
+	}
+	return nil
 }
 
 // SetMetadata sets metadata for a subject
@@ -207,8 +811,7 @@ func (l *ConfluentLoader) SetMetadata(ctx context.Context, subject string, metad
 		return err
 	}
 
-	encodedSubject := url.PathEscape(subject)
-	apiURL := fmt.Sprintf("%s/subjects/%s/metadata", l.baseURL, encodedSubject)
+	apiURL := l.endpoint("/subjects/" + url.PathEscape(subject) + "/metadata")
 	req, err := http.NewRequestWithContext(ctx, "PUT", apiURL, bytes.NewReader(body))
 	if err != nil {
 		return err
@@ -216,30 +819,210 @@ func (l *ConfluentLoader) SetMetadata(ctx context.Context, subject string, metad
 
 	l.setHeaders(req)
 
-	resp, err := l.client.Do(req)
+	resp, err := l.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	// Metadata endpoint might not exist in all versions
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+	if metadataUnsupported(resp.StatusCode) {
+		l.metadataWarnOnce.Do(func() {
+			slog.Warn("schema registry does not support subject metadata, skipping", "status", resp.StatusCode)
+		})
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return newStatusError("failed to set metadata", resp.StatusCode, respBody)
+	}
+
+	return nil
+}
+
+// metadataUnsupported reports whether a SetMetadata status means the registry
+// has no metadata endpoint rather than that the request failed
+func metadataUnsupported(status int) bool {
+	switch status {
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return true
+	}
+	return false
+}
+
+// Subject modes accepted by SetMode
+const (
+	ModeReadWrite = "READWRITE"
+	ModeReadOnly  = "READONLY"
+	ModeImport    = "IMPORT"
+)
+
+// ErrInvalidMode is returned by SetMode for modes other than READWRITE,
+// READONLY and IMPORT
+var ErrInvalidMode = errors.New("invalid subject mode")
+
+// ModeError is returned when Schema Registry rejects a mode request
+type ModeError struct {
+	Subject    string
+	StatusCode int
+	ErrorCode  int    // Schema Registry error_code, if present
+	Message    string // Schema Registry message, or the raw response body
+}
+
+func (e *ModeError) Error() string {
+	return fmt.Sprintf("mode request for subject '%s' failed: %s (status %d)", e.Subject, e.Message, e.StatusCode)
+}
+
+// SetMode sets the mode of a subject. IMPORT allows registering schemas with
+// explicit IDs and versions; READONLY rejects new registrations.
+func (l *ConfluentLoader) SetMode(ctx context.Context, subject, mode string) error {
+	return l.setMode(ctx, subject, mode, false)
+}
+
+// ForceImportMode switches a subject that already holds schemas to IMPORT,
+// which Schema Registry refuses unless forced
+func (l *ConfluentLoader) ForceImportMode(ctx context.Context, subject string) error {
+	return l.setMode(ctx, subject, ModeImport, true)
+}
+
+func (l *ConfluentLoader) setMode(ctx context.Context, subject, mode string, force bool) error {
+	switch mode {
+	case ModeReadWrite, ModeReadOnly, ModeImport:
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidMode, mode)
+	}
+
+	if err := l.rateLimiter.Wait(ctx); err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(map[string]string{"mode": mode})
+	if err != nil {
+		return err
+	}
+
+	path := "/mode/" + url.PathEscape(subject)
+	if force {
+		path += "?force=true"
+	}
+	req, err := http.NewRequestWithContext(ctx, "PUT", l.endpoint(path), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	l.setHeaders(req)
+
+	resp, err := l.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to set mode: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to set metadata: %s", string(respBody))
+		return newModeError(subject, resp.StatusCode, respBody)
 	}
 
 	return nil
 }
 
+// GetMode returns the mode set on a subject, or "" if the subject has no
+// mode of its own and inherits the registry default
+func (l *ConfluentLoader) GetMode(ctx context.Context, subject string) (string, error) {
+	if err := l.rateLimiter.Wait(ctx); err != nil {
+		return "", err
+	}
+
+	apiURL := l.endpoint("/mode/" + url.PathEscape(subject))
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return "", err
+	}
+
+	l.setHeaders(req)
+
+	resp, err := l.do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get mode: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", newModeError(subject, resp.StatusCode, respBody)
+	}
+
+	var result struct {
+		Mode string `json:"mode"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return "", err
+	}
+
+	return result.Mode, nil
+}
+
+// newModeError builds a ModeError from a Schema Registry error response
+func newModeError(subject string, status int, body []byte) *ModeError {
+	modeErr := &ModeError{Subject: subject, StatusCode: status, Message: string(body)}
+	var srErr struct {
+		ErrorCode int    `json:"error_code"`
+		Message   string `json:"message"`
+	}
+	if json.Unmarshal(body, &srErr) == nil && srErr.Message != "" {
+		modeErr.ErrorCode = srErr.ErrorCode
+		modeErr.Message = srErr.Message
+	}
+	return modeErr
+}
+
+// do sends req, logging its status code at debug level
+func (l *ConfluentLoader) do(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := l.client.Do(req)
+	if err != nil {
+		slog.Debug("schema registry request failed", "method", req.Method, "path", req.URL.Path, "error", err)
+		return nil, err
+	}
+	slog.Debug("schema registry request", "method", req.Method, "path", req.URL.Path, "status", resp.StatusCode, "duration_ms", time.Since(start).Milliseconds())
+	return resp, nil
+}
+
 func (l *ConfluentLoader) setHeaders(req *http.Request) {
 	req.Header.Set("Content-Type", "application/vnd.schemaregistry.v1+json")
-	req.SetBasicAuth(l.config.ConfluentCloud.APIKey, l.config.ConfluentCloud.APISecret)
+	if l.config.ConfluentCloud.UsesBearer() {
+		req.Header.Set("Authorization", "Bearer "+l.config.ConfluentCloud.BearerToken)
+	} else {
+		req.SetBasicAuth(l.config.ConfluentCloud.APIKey, l.config.ConfluentCloud.APISecret)
+	}
+	// Shared and private endpoints route to a Schema Registry cluster by ID
+	if id := l.config.ConfluentCloud.ClusterID; id != "" {
+		req.Header.Set("target-sr-cluster", id)
+	}
 }
 
-func (l *ConfluentLoader) buildReferences(refs []string, context string) ([]models.SchemaReference, error) {
+// ErrReferenceTypeMismatch is returned when a schema references a schema of
+// a different type, which Schema Registry cannot resolve
+var ErrReferenceTypeMismatch = errors.New("reference to a schema of a different type")
+
+func (l *ConfluentLoader) buildReferences(ctx context.Context, mapping *models.SchemaMapping) ([]models.SchemaReference, error) {
 	var result []models.SchemaReference
+	targetContext := mapping.TargetContext
+
+	for _, ref := range mapping.References {
+		refFormat := mapping.ReferenceFormats[ref]
+		if mapping.SourceFormat != "" && refFormat != "" && refFormat != mapping.SourceFormat {
+			return nil, fmt.Errorf("%w: %s schema references %s (%s)", ErrReferenceTypeMismatch, mapping.SourceFormat, ref, refFormat)
+		}
 
-	for _, ref := range refs {
 		// Parse the reference (format: "registry:schema" or just "schema")
 		parts := strings.SplitN(ref, ":", 2)
 		var schemaName string
@@ -249,31 +1032,110 @@ func (l *ConfluentLoader) buildReferences(refs []string, context string) ([]mode
 			refContext = "." + parts[0]
 			schemaName = parts[1]
 		} else {
-			refContext = context
+			refContext = targetContext
 			schemaName = parts[0]
 		}
 
-		// Build the subject name for the reference
-		// Assuming value schema for now - could be enhanced to detect key schemas
-		subject := schemaName + "-value"
-		if refContext != "" {
-			subject = refContext + ":" + subject
+		// Use the subject the referenced schema was mapped to, which carries
+		// its detected role. Without one, assume a value schema.
+		subject, found := mapping.ReferenceSubjects[ref]
+		if !found {
+			subject = schemaName + "-value"
+			if refContext != "" {
+				subject = refContext + ":" + subject
+			}
+		}
+
+		// Referenced schemas are migrated in earlier dependency levels, so
+		// point at the latest version registered in the target
+		version, err := l.referenceVersion(ctx, subject)
+		if err != nil {
+			return nil, err
+		}
+		if version == 0 {
+			version = 1
+		}
+
+		// Protobuf imports are referenced by the path the definition uses
+		name := schemaName
+		if importPath, ok := mapping.ReferenceNames[ref]; ok {
+			name = importPath
 		}
 
 		result = append(result, models.SchemaReference{
-			Name:    schemaName,
+			Name:    name,
 			Subject: subject,
-			Version: 1, // Reference latest version
+			Version: version,
 		})
 	}
 
 	return result, nil
 }
 
+// referenceVersion returns the latest version of a referenced subject. A
+// referenced schema is fully migrated before the schemas that reference it,
+// so its latest version is cached for the rest of the run once it exists.
+func (l *ConfluentLoader) referenceVersion(ctx context.Context, subject string) (int, error) {
+	l.refVersionsMu.Lock()
+	version, ok := l.refVersions[subject]
+	l.refVersionsMu.Unlock()
+	if ok {
+		return version, nil
+	}
+
+	version, err := l.GetLatestVersion(ctx, subject)
+	if err != nil || version == 0 {
+		return version, err
+	}
+
+	l.refVersionsMu.Lock()
+	defer l.refVersionsMu.Unlock()
+	if l.refVersions == nil {
+		l.refVersions = make(map[string]int)
+	}
+	l.refVersions[subject] = version
+	return version, nil
+}
+
+// buildMetadata returns the metadata to send with a registration, or nil when
+// there are no properties to attach. Glue tags override static properties,
+// and the original Glue names override both.
+func (l *ConfluentLoader) buildMetadata(mapping *models.SchemaMapping) *models.SubjectMetadata {
+	cfg := l.config.Metadata
+	if cfg.Strategy == "skip" {
+		return nil
+	}
+
+	properties := make(map[string]string)
+	for k, v := range cfg.Properties {
+		properties[k] = v
+	}
+	if cfg.TagsAsProperties {
+		for k, v := range mapping.SourceTags {
+			properties[k] = v
+		}
+	}
+	if cfg.RecordOriginalName {
+		properties["glue_schema_name"] = mapping.SourceSchemaName
+		properties["glue_registry"] = mapping.SourceRegistry
+	}
+
+	if len(properties) == 0 {
+		return nil
+	}
+	return &models.SubjectMetadata{Properties: properties}
+}
+
+// getSchemaType returns the schemaType to register the mapping's schema with,
+// taken from its source format. Schema Registry treats a missing schemaType
+// as AVRO, so an unknown format falls back to it.
 func getSchemaType(mapping *models.SchemaMapping) string {
-	// Get the schema type from the source schema
-	// Default to AVRO if not specified
-	return "AVRO"
+	switch mapping.SourceFormat {
+	case models.SchemaTypeJSON, models.SchemaTypeProtobuf:
+		return string(mapping.SourceFormat)
+	default:
+		return string(models.SchemaTypeAvro)
+	}
 }
 
 // SchemaRegistrationRequest represents a schema registration request
@@ -281,4 +1143,7 @@ type SchemaRegistrationRequest struct {
 	Schema     string                   `json:"schema"`
 	SchemaType string                   `json:"schemaType,omitempty"`
 	References []models.SchemaReference `json:"references,omitempty"`
+	Metadata   *models.SubjectMetadata  `json:"metadata,omitempty"`
+	ID         int                      `json:"id,omitempty"`      // explicit schema ID, honored in IMPORT mode
+	Version    int                      `json:"version,omitempty"` // explicit version number, honored in IMPORT mode
 }
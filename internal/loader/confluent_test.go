@@ -2,12 +2,18 @@ package loader
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
 	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
@@ -106,6 +112,72 @@ func TestRegisterSchema_Success(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// TestRegisterSchema_SchemaType
+// ---------------------------------------------------------------------------
+
+func TestRegisterSchema_SchemaType(t *testing.T) {
+	tests := []struct {
+		name       string
+		format     models.SchemaType
+		definition string
+		want       string
+	}{
+		{
+			name:       "json",
+			format:     models.SchemaTypeJSON,
+			definition: `{"type":"object","properties":{"id":{"type":"string"}}}`,
+			want:       "JSON",
+		},
+		{
+			name:       "protobuf",
+			format:     models.SchemaTypeProtobuf,
+			definition: "syntax = \"proto3\";\nmessage Order { string id = 1; }\n",
+			want:       "PROTOBUF",
+		},
+		{
+			name:       "avro",
+			format:     models.SchemaTypeAvro,
+			definition: `{"type":"record","name":"Order","fields":[]}`,
+			want:       "AVRO",
+		},
+		{
+			name:       "unknown defaults to avro",
+			definition: `{"type":"record","name":"Order","fields":[]}`,
+			want:       "AVRO",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reqBody SchemaRegistrationRequest
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				json.NewDecoder(r.Body).Decode(&reqBody)
+				w.Write([]byte(`{"id":1}`))
+			}))
+			defer server.Close()
+
+			loader := newTestLoader(t, server.URL)
+
+			mapping := &models.SchemaMapping{
+				TargetSubject: "order-value",
+				SourceFormat:  tt.format,
+			}
+			version := &models.GlueSchemaVersion{Definition: tt.definition}
+
+			if err := loader.RegisterSchema(context.Background(), mapping, version); err != nil {
+				t.Fatalf("RegisterSchema returned unexpected error: %v", err)
+			}
+			if reqBody.SchemaType != tt.want {
+				t.Errorf("body schemaType = %q, want %q", reqBody.SchemaType, tt.want)
+			}
+			if reqBody.Schema != tt.definition {
+				t.Errorf("body schema = %q, want %q", reqBody.Schema, tt.definition)
+			}
+		})
+	}
+}
+
 // ---------------------------------------------------------------------------
 // TestRegisterSchema_WithContext
 // ---------------------------------------------------------------------------
@@ -253,3 +325,1160 @@ func TestSubjectExists_False(t *testing.T) {
 		t.Error("SubjectExists = true, want false")
 	}
 }
+
+// ---------------------------------------------------------------------------
+// TestSubjectExists_Unauthorized
+// ---------------------------------------------------------------------------
+
+func TestSubjectExists_Unauthorized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error_code":401,"message":"Unauthorized"}`))
+	}))
+	defer server.Close()
+
+	loader := newTestLoader(t, server.URL)
+
+	exists, err := loader.SubjectExists(context.Background(), "user-event-value")
+	if err == nil {
+		t.Fatal("SubjectExists expected error for 401, got nil")
+	}
+	if exists {
+		t.Error("SubjectExists = true, want false on error")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestRegisterSchema_ReferenceLatestVersion
+// ---------------------------------------------------------------------------
+
+func TestRegisterSchema_ReferenceLatestVersion(t *testing.T) {
+	var registered SchemaRegistrationRequest
+	var lookups int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/subjects/address-value/versions/latest":
+			// The referenced schema was migrated with 3 versions
+			lookups++
+			w.Write([]byte(`{"subject":"address-value","version":3,"id":12}`))
+		case r.Method == "POST":
+			json.NewDecoder(r.Body).Decode(&registered)
+			w.Write([]byte(`{"id":13}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	loader := newTestLoader(t, server.URL)
+
+	mapping := &models.SchemaMapping{
+		TargetSubject: "customer-value",
+		References:    []string{"address"},
+	}
+	version := &models.GlueSchemaVersion{
+		Definition: `{"type":"record","name":"Customer","fields":[{"name":"addr","type":"address"}]}`,
+	}
+
+	if err := loader.RegisterSchema(context.Background(), mapping, version); err != nil {
+		t.Fatalf("RegisterSchema returned unexpected error: %v", err)
+	}
+
+	if len(registered.References) != 1 {
+		t.Fatalf("len(References) = %d, want 1", len(registered.References))
+	}
+	if registered.References[0].Version != 3 {
+		t.Errorf("References[0].Version = %d, want 3", registered.References[0].Version)
+	}
+
+	// Another version referencing the same subject reuses the lookup
+	if err := loader.RegisterSchema(context.Background(), mapping, version); err != nil {
+		t.Fatalf("RegisterSchema returned unexpected error: %v", err)
+	}
+	if registered.References[0].Version != 3 {
+		t.Errorf("References[0].Version = %d on the second registration, want 3", registered.References[0].Version)
+	}
+	if lookups != 1 {
+		t.Errorf("latest version of address-value looked up %d times, want 1", lookups)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestRegisterSchema_ReferenceSubjects
+// ---------------------------------------------------------------------------
+
+func TestRegisterSchema_ReferenceSubjects(t *testing.T) {
+	var registered SchemaRegistrationRequest
+	var looked []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "POST" {
+			json.NewDecoder(r.Body).Decode(&registered)
+			w.Write([]byte(`{"id":13}`))
+			return
+		}
+		looked = append(looked, r.URL.Path)
+		w.Write([]byte(`{"version":2}`))
+	}))
+	defer server.Close()
+
+	loader := newTestLoader(t, server.URL)
+
+	mapping := &models.SchemaMapping{
+		TargetContext: ".orders",
+		TargetSubject: "order-event-value",
+		References:    []string{"orders:OrderKey", "shared:Address"},
+		// Address is outside the plan, so it falls back to a value subject
+		ReferenceSubjects: map[string]string{"orders:OrderKey": ".orders:order-key"},
+	}
+	version := &models.GlueSchemaVersion{
+		Definition: `{"type":"record","name":"OrderEvent","fields":[{"name":"key","type":"OrderKey"},{"name":"ship_to","type":"Address"}]}`,
+	}
+
+	if err := loader.RegisterSchema(context.Background(), mapping, version); err != nil {
+		t.Fatalf("RegisterSchema returned unexpected error: %v", err)
+	}
+
+	want := []models.SchemaReference{
+		{Name: "OrderKey", Subject: ".orders:order-key", Version: 2},
+		{Name: "Address", Subject: ".shared:Address-value", Version: 2},
+	}
+	if len(registered.References) != len(want) {
+		t.Fatalf("References = %+v, want %+v", registered.References, want)
+	}
+	for i := range want {
+		if registered.References[i] != want[i] {
+			t.Errorf("References[%d] = %+v, want %+v", i, registered.References[i], want[i])
+		}
+	}
+	if len(looked) != 2 || looked[0] != "/subjects/.orders:order-key/versions/latest" {
+		t.Errorf("looked up %v, expected the key subject's latest version first", looked)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestRegisterSchema_ProtobufImport
+// ---------------------------------------------------------------------------
+
+func TestRegisterSchema_ProtobufImport(t *testing.T) {
+	var registered SchemaRegistrationRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/subjects/.shop:address-value/versions/latest":
+			w.Write([]byte(`{"subject":".shop:address-value","version":2,"id":12}`))
+		case r.Method == "POST":
+			json.NewDecoder(r.Body).Decode(&registered)
+			w.Write([]byte(`{"id":13}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	loader := newTestLoader(t, server.URL)
+
+	mapping := &models.SchemaMapping{
+		TargetContext:     ".shop",
+		TargetSubject:     "customer-value",
+		SourceFormat:      models.SchemaTypeProtobuf,
+		References:        []string{"shop:address"},
+		ReferenceFormats:  map[string]models.SchemaType{"shop:address": models.SchemaTypeProtobuf},
+		ReferenceSubjects: map[string]string{"shop:address": ".shop:address-value"},
+		ReferenceNames:    map[string]string{"shop:address": "common/address.proto"},
+	}
+	version := &models.GlueSchemaVersion{
+		Definition: "syntax = \"proto3\";\nimport \"common/address.proto\";\n\nmessage Customer {\n  common.Address billing = 1;\n}\n",
+	}
+
+	if err := loader.RegisterSchema(context.Background(), mapping, version); err != nil {
+		t.Fatalf("RegisterSchema returned unexpected error: %v", err)
+	}
+
+	if registered.SchemaType != "PROTOBUF" {
+		t.Errorf("schemaType = %q, want PROTOBUF", registered.SchemaType)
+	}
+	want := models.SchemaReference{Name: "common/address.proto", Subject: ".shop:address-value", Version: 2}
+	if len(registered.References) != 1 || registered.References[0] != want {
+		t.Errorf("References = %+v, want [%+v]", registered.References, want)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestRegisterSchema_ReferenceTypeMismatch
+// ---------------------------------------------------------------------------
+
+func TestRegisterSchema_ReferenceTypeMismatch(t *testing.T) {
+	var posts int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			posts++
+			w.Write([]byte(`{"id":1}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	loader := newTestLoader(t, server.URL)
+
+	mapping := &models.SchemaMapping{
+		TargetSubject:    "customer-value",
+		SourceFormat:     models.SchemaTypeJSON,
+		References:       []string{"shared:Address"},
+		ReferenceFormats: map[string]models.SchemaType{"shared:Address": models.SchemaTypeAvro},
+	}
+	version := &models.GlueSchemaVersion{
+		Definition: `{"type":"object","properties":{"address":{"$ref":"Address"}}}`,
+	}
+
+	err := loader.RegisterSchema(context.Background(), mapping, version)
+	if !errors.Is(err, ErrReferenceTypeMismatch) {
+		t.Fatalf("expected ErrReferenceTypeMismatch, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "shared:Address") {
+		t.Errorf("error = %q, expected it to name the reference", err.Error())
+	}
+	if posts != 0 {
+		t.Errorf("expected no registration request, got %d", posts)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestRegisterSchema_ReferenceUnknownVersion
+// ---------------------------------------------------------------------------
+
+func TestRegisterSchema_ReferenceUnknownVersion(t *testing.T) {
+	var registered SchemaRegistrationRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			json.NewDecoder(r.Body).Decode(&registered)
+			w.Write([]byte(`{"id":13}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	loader := newTestLoader(t, server.URL)
+
+	mapping := &models.SchemaMapping{
+		TargetSubject: "customer-value",
+		References:    []string{"address"},
+	}
+	version := &models.GlueSchemaVersion{Definition: `{"type":"record","name":"Customer","fields":[]}`}
+
+	if err := loader.RegisterSchema(context.Background(), mapping, version); err != nil {
+		t.Fatalf("RegisterSchema returned unexpected error: %v", err)
+	}
+
+	if len(registered.References) != 1 || registered.References[0].Version != 1 {
+		t.Errorf("References = %+v, want a single reference at version 1", registered.References)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestRegisterSchema_Metadata
+// ---------------------------------------------------------------------------
+
+func TestRegisterSchema_Metadata(t *testing.T) {
+	var body map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"id":1}`))
+	}))
+	defer server.Close()
+
+	loader := newTestLoader(t, server.URL)
+	loader.config.Metadata.Properties = map[string]string{"source": "aws-glue", "team": "platform"}
+	loader.config.Metadata.TagsAsProperties = true
+
+	mapping := &models.SchemaMapping{
+		TargetSubject: "user-value",
+		SourceTags:    map[string]string{"team": "payments"},
+	}
+	version := &models.GlueSchemaVersion{Definition: `{"type":"record","name":"User","fields":[]}`}
+
+	if err := loader.RegisterSchema(context.Background(), mapping, version); err != nil {
+		t.Fatalf("RegisterSchema returned unexpected error: %v", err)
+	}
+
+	metadata, ok := body["metadata"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("registration body has no metadata: %v", body)
+	}
+	props, _ := metadata["properties"].(map[string]interface{})
+	if props["source"] != "aws-glue" {
+		t.Errorf("properties[source] = %v, want %q", props["source"], "aws-glue")
+	}
+	// Glue tags take precedence over static properties
+	if props["team"] != "payments" {
+		t.Errorf("properties[team] = %v, want %q", props["team"], "payments")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestRegisterSchema_RecordOriginalName
+// ---------------------------------------------------------------------------
+
+func TestRegisterSchema_RecordOriginalName(t *testing.T) {
+	var body map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"id":1}`))
+	}))
+	defer server.Close()
+
+	loader := newTestLoader(t, server.URL)
+	loader.config.Metadata.RecordOriginalName = true
+
+	mapping := &models.SchemaMapping{
+		SourceRegistry:   "payments",
+		SourceSchemaName: "User.Event",
+		TargetSubject:    "user-event-value",
+	}
+	version := &models.GlueSchemaVersion{Definition: `{"type":"record","name":"User","fields":[]}`}
+
+	if err := loader.RegisterSchema(context.Background(), mapping, version); err != nil {
+		t.Fatalf("RegisterSchema returned unexpected error: %v", err)
+	}
+
+	metadata, ok := body["metadata"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("registration body has no metadata: %v", body)
+	}
+	props, _ := metadata["properties"].(map[string]interface{})
+	if props["glue_schema_name"] != "User.Event" {
+		t.Errorf("properties[glue_schema_name] = %v, want %q", props["glue_schema_name"], "User.Event")
+	}
+	if props["glue_registry"] != "payments" {
+		t.Errorf("properties[glue_registry] = %v, want %q", props["glue_registry"], "payments")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestRegisterSchema_NoMetadataByDefault
+// ---------------------------------------------------------------------------
+
+func TestRegisterSchema_NoMetadataByDefault(t *testing.T) {
+	var body map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"id":1}`))
+	}))
+	defer server.Close()
+
+	loader := newTestLoader(t, server.URL)
+
+	mapping := &models.SchemaMapping{TargetSubject: "user-value"}
+	version := &models.GlueSchemaVersion{Definition: `{"type":"record","name":"User","fields":[]}`}
+
+	if err := loader.RegisterSchema(context.Background(), mapping, version); err != nil {
+		t.Fatalf("RegisterSchema returned unexpected error: %v", err)
+	}
+	if _, ok := body["metadata"]; ok {
+		t.Errorf("expected no metadata in registration body, got %v", body["metadata"])
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestSetMode_Import
+// ---------------------------------------------------------------------------
+
+func TestSetMode_Import(t *testing.T) {
+	var method, path string
+	var body map[string]string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		path = r.URL.EscapedPath()
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"mode":"IMPORT"}`))
+	}))
+	defer server.Close()
+
+	loader := newTestLoader(t, server.URL)
+	if err := loader.SetMode(context.Background(), ":.payments:user-value", ModeImport); err != nil {
+		t.Fatalf("SetMode returned unexpected error: %v", err)
+	}
+
+	if method != http.MethodPut {
+		t.Errorf("method = %q, want PUT", method)
+	}
+	if path != "/mode/:.payments:user-value" {
+		t.Errorf("path = %q, want %q", path, "/mode/:.payments:user-value")
+	}
+	if body["mode"] != "IMPORT" {
+		t.Errorf("body mode = %q, want IMPORT", body["mode"])
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestForceImportMode
+// ---------------------------------------------------------------------------
+
+func TestForceImportMode(t *testing.T) {
+	var uri string
+	var body map[string]string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uri = r.URL.RequestURI()
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"mode":"IMPORT"}`))
+	}))
+	defer server.Close()
+
+	loader := newTestLoader(t, server.URL)
+	if err := loader.ForceImportMode(context.Background(), "user-value"); err != nil {
+		t.Fatalf("ForceImportMode returned unexpected error: %v", err)
+	}
+
+	if uri != "/mode/user-value?force=true" {
+		t.Errorf("request = %q, want %q", uri, "/mode/user-value?force=true")
+	}
+	if body["mode"] != "IMPORT" {
+		t.Errorf("body mode = %q, want IMPORT", body["mode"])
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestSetMode_Errors
+// ---------------------------------------------------------------------------
+
+func TestSetMode_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"error_code":42205,"message":"Cannot import since found existing subjects"}`))
+	}))
+	defer server.Close()
+
+	loader := newTestLoader(t, server.URL)
+
+	err := loader.SetMode(context.Background(), "user-value", "WRITEONLY")
+	if !errors.Is(err, ErrInvalidMode) {
+		t.Errorf("expected ErrInvalidMode for unknown mode, got %v", err)
+	}
+
+	err = loader.SetMode(context.Background(), "user-value", ModeImport)
+	var modeErr *ModeError
+	if !errors.As(err, &modeErr) {
+		t.Fatalf("expected *ModeError, got %v", err)
+	}
+	if modeErr.StatusCode != http.StatusUnprocessableEntity || modeErr.ErrorCode != 42205 {
+		t.Errorf("ModeError = %+v, want status 422 and error code 42205", modeErr)
+	}
+	if modeErr.Subject != "user-value" || !strings.Contains(modeErr.Message, "existing subjects") {
+		t.Errorf("ModeError = %+v, expected subject and registry message", modeErr)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestGetMode
+// ---------------------------------------------------------------------------
+
+func TestGetMode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/mode/locked-value" {
+			w.Write([]byte(`{"mode":"READONLY"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error_code":40409,"message":"Subject mode not found"}`))
+	}))
+	defer server.Close()
+
+	loader := newTestLoader(t, server.URL)
+
+	mode, err := loader.GetMode(context.Background(), "locked-value")
+	if err != nil || mode != ModeReadOnly {
+		t.Errorf("GetMode(locked-value) = %q, %v; want READONLY, nil", mode, err)
+	}
+
+	mode, err = loader.GetMode(context.Background(), "user-value")
+	if err != nil || mode != "" {
+		t.Errorf("GetMode(user-value) = %q, %v; want empty mode for inherited default", mode, err)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestRetryable_ErrorCodes
+// ---------------------------------------------------------------------------
+
+func TestRetryable_ErrorCodes(t *testing.T) {
+	var errorCode int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"error_code":` + strconv.Itoa(errorCode) + `,"message":"failed"}`))
+	}))
+	defer server.Close()
+
+	loader := newTestLoader(t, server.URL)
+	loader.config.ConfluentCloud.RetryableErrorCodes = map[int]bool{
+		50001: false,
+		409:   true,
+	}
+
+	tests := []struct {
+		code      int
+		retryable bool
+	}{
+		{50001, false}, // configured codes override the defaults
+		{409, true},
+		{42201, false}, // defaults apply to codes the config leaves out
+		{50003, true},
+		{50005, true}, // unknown codes fall back to the 500 status
+	}
+
+	for _, tt := range tests {
+		errorCode = tt.code
+		err := loader.RegisterSchema(context.Background(), &models.SchemaMapping{TargetSubject: "user-event-value"}, &models.GlueSchemaVersion{
+			Definition: `{"type":"record","name":"UserEvent","fields":[]}`,
+		})
+		var regErr *RegisterError
+		if !errors.As(err, &regErr) || regErr.ErrorCode != tt.code {
+			t.Fatalf("code %d: expected a RegisterError carrying the error code, got %v", tt.code, err)
+		}
+		if got := loader.Retryable(err); got != tt.retryable {
+			t.Errorf("Retryable() for error code %d = %v, expected %v", tt.code, got, tt.retryable)
+		}
+	}
+
+}
+
+// ---------------------------------------------------------------------------
+// TestRetryable_Status
+// ---------------------------------------------------------------------------
+
+func TestRetryable_Status(t *testing.T) {
+	loader := newTestLoader(t, "http://localhost")
+
+	tests := []struct {
+		name      string
+		err       error
+		retryable bool
+	}{
+		{"server error", newStatusError("failed to get subjects", http.StatusBadGateway, []byte("bad gateway")), true},
+		{"rate limited", newStatusError("failed to get subjects", http.StatusTooManyRequests, nil), true},
+		{"unauthorized", newStatusError("failed to get subjects", http.StatusUnauthorized, []byte(`{"error_code":401,"message":"Unauthorized"}`)), false},
+		{"unparseable 400", &RegisterError{StatusCode: http.StatusBadRequest, Body: "<html>bad request</html>"}, false},
+		{"unknown 4xx code", &RegisterError{StatusCode: http.StatusUnprocessableEntity, ErrorCode: 42299}, false},
+		{"network error", fmt.Errorf("register: %w", &url.Error{Op: "Post", URL: "http://localhost", Err: errors.New("connection reset")}), true},
+		{"deadline", context.DeadlineExceeded, true},
+		{"canceled", context.Canceled, false},
+		{"other error", errors.New("failed to marshal request"), false},
+	}
+
+	for _, tt := range tests {
+		if got := loader.Retryable(tt.err); got != tt.retryable {
+			t.Errorf("Retryable() for %s = %v, expected %v", tt.name, got, tt.retryable)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestRetryable_DefaultErrorCodes
+// ---------------------------------------------------------------------------
+
+func TestRetryable_DefaultErrorCodes(t *testing.T) {
+	loader := newTestLoader(t, "http://localhost")
+
+	tests := []struct {
+		code      int
+		retryable bool
+	}{
+		{409, false},
+		{42201, false},
+		{50001, true},
+		{50002, true},
+		{50003, true},
+	}
+
+	for _, tt := range tests {
+		err := &RegisterError{StatusCode: http.StatusInternalServerError, ErrorCode: tt.code}
+		if got := loader.Retryable(err); got != tt.retryable {
+			t.Errorf("Retryable() for error code %d = %v, expected %v", tt.code, got, tt.retryable)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestEndpoints_PathPrefix
+// ---------------------------------------------------------------------------
+
+func TestEndpoints_PathPrefix(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.EscapedPath()+"?"+r.URL.RawQuery)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/sr/subjects":
+			w.Write([]byte(`[]`))
+		case strings.HasSuffix(r.URL.Path, "/versions/latest"):
+			w.Write([]byte(`{"version": 1}`))
+		case strings.HasPrefix(r.URL.Path, "/sr/mode/") && r.Method == "GET":
+			w.Write([]byte(`{"mode": "READWRITE"}`))
+		default:
+			w.Write([]byte(`{"id": 1}`))
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		baseURL string
+		query   string
+	}{
+		{"path prefix", server.URL + "/sr", ""},
+		{"trailing slash", server.URL + "/sr/", ""},
+		{"query component", server.URL + "/sr/?tenant=lsrc-123", "tenant=lsrc-123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests = nil
+			loader := newTestLoader(t, tt.baseURL)
+			ctx := context.Background()
+			subject := ".payments:user-event-value"
+			mapping := &models.SchemaMapping{TargetContext: ".payments", TargetSubject: "user-event-value"}
+
+			if err := loader.RegisterSchema(ctx, mapping, &models.GlueSchemaVersion{Definition: `{"type":"string"}`}); err != nil {
+				t.Fatalf("RegisterSchema: %v", err)
+			}
+			if err := loader.SetCompatibility(ctx, subject, "BACKWARD"); err != nil {
+				t.Fatalf("SetCompatibility: %v", err)
+			}
+			if _, err := loader.GetSubjects(ctx); err != nil {
+				t.Fatalf("GetSubjects: %v", err)
+			}
+			if _, err := loader.SubjectExists(ctx, subject); err != nil {
+				t.Fatalf("SubjectExists: %v", err)
+			}
+			if _, err := loader.GetLatestVersion(ctx, subject); err != nil {
+				t.Fatalf("GetLatestVersion: %v", err)
+			}
+			if err := loader.SetMetadata(ctx, subject, &models.SubjectMetadata{}); err != nil {
+				t.Fatalf("SetMetadata: %v", err)
+			}
+			if err := loader.SetMode(ctx, subject, ModeImport); err != nil {
+				t.Fatalf("SetMode: %v", err)
+			}
+			if _, err := loader.GetMode(ctx, subject); err != nil {
+				t.Fatalf("GetMode: %v", err)
+			}
+
+			want := []string{
+				"POST /sr/subjects/.payments:user-event-value/versions",
+				"PUT /sr/config/.payments:user-event-value",
+				"GET /sr/subjects",
+				"GET /sr/subjects/.payments:user-event-value/versions",
+				"GET /sr/subjects/.payments:user-event-value/versions/latest",
+				"PUT /sr/subjects/.payments:user-event-value/metadata",
+				"PUT /sr/mode/.payments:user-event-value",
+				"GET /sr/mode/.payments:user-event-value",
+			}
+			if len(requests) != len(want) {
+				t.Fatalf("got %d requests, expected %d: %v", len(requests), len(want), requests)
+			}
+			for i, w := range want {
+				if requests[i] != w+"?"+tt.query {
+					t.Errorf("request %d = %q, expected %q", i, requests[i], w+"?"+tt.query)
+				}
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestCheckCompatibility
+// ---------------------------------------------------------------------------
+
+func TestCheckCompatibility(t *testing.T) {
+	var path, query string
+	var body SchemaRegistrationRequest
+	compatible := true
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.EscapedPath()
+		query = r.URL.RawQuery
+		json.NewDecoder(r.Body).Decode(&body)
+		if compatible {
+			w.Write([]byte(`{"is_compatible": true}`))
+			return
+		}
+		w.Write([]byte(`{"is_compatible": false, "messages": ["READER_FIELD_MISSING_DEFAULT_VALUE: id"]}`))
+	}))
+	defer server.Close()
+
+	loader := newTestLoader(t, server.URL)
+	mapping := &models.SchemaMapping{
+		TargetContext: ".payments",
+		TargetSubject: "user-event-value",
+	}
+	version := &models.GlueSchemaVersion{Definition: `{"type":"record","name":"UserEvent","fields":[]}`}
+
+	ok, messages, err := loader.CheckCompatibility(context.Background(), mapping, version)
+	if err != nil {
+		t.Fatalf("CheckCompatibility returned unexpected error: %v", err)
+	}
+	if !ok || len(messages) != 0 {
+		t.Errorf("expected compatible with no messages, got %v %v", ok, messages)
+	}
+	if path != "/compatibility/subjects/.payments:user-event-value/versions/latest" {
+		t.Errorf("path = %q", path)
+	}
+	if query != "verbose=true" {
+		t.Errorf("query = %q, want verbose=true", query)
+	}
+	if body.Schema != version.Definition {
+		t.Errorf("request schema = %q, expected the version's definition", body.Schema)
+	}
+
+	compatible = false
+	ok, messages, err = loader.CheckCompatibility(context.Background(), mapping, version)
+	if err != nil {
+		t.Fatalf("CheckCompatibility returned unexpected error: %v", err)
+	}
+	if ok {
+		t.Error("expected incompatible result")
+	}
+	if len(messages) != 1 || !strings.Contains(messages[0], "READER_FIELD_MISSING_DEFAULT_VALUE") {
+		t.Errorf("messages = %v, expected the server's reason", messages)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestSetHeaders_ClusterID
+// ---------------------------------------------------------------------------
+
+func TestSetHeaders_ClusterID(t *testing.T) {
+	var clusters []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clusters = append(clusters, r.Header.Get("target-sr-cluster"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	loader := newTestLoader(t, server.URL)
+	if _, err := loader.GetSubjects(context.Background()); err != nil {
+		t.Fatalf("GetSubjects: %v", err)
+	}
+
+	loader.config.ConfluentCloud.ClusterID = "lsrc-abc123"
+	if _, err := loader.GetSubjects(context.Background()); err != nil {
+		t.Fatalf("GetSubjects: %v", err)
+	}
+
+	if len(clusters) != 2 || clusters[0] != "" || clusters[1] != "lsrc-abc123" {
+		t.Errorf("target-sr-cluster headers = %q, expected none without cluster_id and lsrc-abc123 with it", clusters)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestSetHeaders_AuthType
+// ---------------------------------------------------------------------------
+
+func TestSetHeaders_AuthType(t *testing.T) {
+	basic := "Basic " + base64.StdEncoding.EncodeToString([]byte("test-key:test-secret"))
+	tests := []struct {
+		authType string
+		want     string
+	}{
+		{"", basic},
+		{"basic", basic},
+		{"bearer", "Bearer oidc-access-token"},
+	}
+
+	for _, tt := range tests {
+		t.Run("auth_type="+tt.authType, func(t *testing.T) {
+			var got string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Get("Authorization")
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`[]`))
+			}))
+			defer server.Close()
+
+			loader := newTestLoader(t, server.URL)
+			loader.config.ConfluentCloud.AuthType = tt.authType
+			loader.config.ConfluentCloud.BearerToken = "oidc-access-token"
+			if _, err := loader.GetSubjects(context.Background()); err != nil {
+				t.Fatalf("GetSubjects: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Authorization = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestSetMetadata_Unsupported
+// ---------------------------------------------------------------------------
+
+func TestSetMetadata_Unsupported(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{"not found", http.StatusNotFound, false},
+		{"method not allowed", http.StatusMethodNotAllowed, false},
+		{"not implemented", http.StatusNotImplemented, false},
+		{"bad request", http.StatusBadRequest, true},
+		{"unauthorized", http.StatusUnauthorized, true},
+		{"forbidden", http.StatusForbidden, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				w.WriteHeader(tt.status)
+				w.Write([]byte(`{"error_code":` + strconv.Itoa(tt.status) + `,"message":"metadata"}`))
+			}))
+			defer server.Close()
+
+			loader := newTestLoader(t, server.URL)
+			ctx := context.Background()
+
+			// Unsupported statuses must keep being tolerated after the first warning.
+			for i := 0; i < 2; i++ {
+				err := loader.SetMetadata(ctx, "user-event-value", &models.SubjectMetadata{})
+				if tt.wantErr && err == nil {
+					t.Fatalf("expected error for status %d", tt.status)
+				}
+				if !tt.wantErr && err != nil {
+					t.Fatalf("expected status %d to be treated as unsupported, got %v", tt.status, err)
+				}
+			}
+			if calls != 2 {
+				t.Errorf("server received %d requests, expected 2", calls)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestRegisterSchema_PreserveSchemaIDs
+// ---------------------------------------------------------------------------
+
+func TestRegisterSchema_PreserveSchemaIDs(t *testing.T) {
+	var body map[string]interface{}
+	reject := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body = nil
+		json.NewDecoder(r.Body).Decode(&body)
+		if reject {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"error_code":42205,"message":"Overwrite new schema with id 1234 is not permitted."}`))
+			return
+		}
+		w.Write([]byte(`{"id":1}`))
+	}))
+	defer server.Close()
+
+	mapping := &models.SchemaMapping{TargetSubject: "user-event-value"}
+	version := &models.GlueSchemaVersion{
+		SchemaVersionID: "6b7e5f8a-1c2d-4e3f-9a0b-1c2d3e4f5a6b",
+		Definition:      `{"type":"record","name":"UserEvent","fields":[]}`,
+	}
+
+	loader := newTestLoader(t, server.URL)
+	if err := loader.RegisterSchema(context.Background(), mapping, version); err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+	if _, ok := body["id"]; ok {
+		t.Errorf("id sent without preserve_schema_ids: %v", body)
+	}
+
+	loader.config.Migration.ImportMode = true
+	loader.config.Migration.PreserveSchemaIDs = true
+	if err := loader.RegisterSchema(context.Background(), mapping, version); err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+	want := SourceSchemaID(version)
+	if id, _ := body["id"].(float64); int(id) != want || want <= 0 {
+		t.Errorf("id = %v, want %d", body["id"], want)
+	}
+	if again := SourceSchemaID(&models.GlueSchemaVersion{SchemaVersionID: version.SchemaVersionID}); again != want {
+		t.Errorf("SourceSchemaID is not stable: %d then %d", want, again)
+	}
+
+	reject = true
+	err := loader.RegisterSchema(context.Background(), mapping, version)
+	var regErr *RegisterError
+	if !errors.As(err, &regErr) || regErr.SchemaID != want {
+		t.Fatalf("expected a RegisterError carrying ID %d, got %v", want, err)
+	}
+	if !strings.Contains(err.Error(), "explicit ID "+strconv.Itoa(want)) || !strings.Contains(err.Error(), "rejected the preserved ID") {
+		t.Errorf("error = %q, expected it to explain the rejected ID", err.Error())
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestRegisterSchema_PreserveVersions
+// ---------------------------------------------------------------------------
+
+func TestRegisterSchema_PreserveVersions(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body = nil
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"id":1}`))
+	}))
+	defer server.Close()
+
+	mapping := &models.SchemaMapping{TargetSubject: "user-event-value"}
+	version := &models.GlueSchemaVersion{
+		VersionNumber:   3,
+		SchemaVersionID: "6b7e5f8a-1c2d-4e3f-9a0b-1c2d3e4f5a6b",
+		Definition:      `{"type":"record","name":"UserEvent","fields":[]}`,
+	}
+
+	loader := newTestLoader(t, server.URL)
+	if err := loader.RegisterSchema(context.Background(), mapping, version); err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+	if _, ok := body["version"]; ok {
+		t.Errorf("version sent without preserve_versions: %v", body)
+	}
+
+	loader.config.Migration.ImportMode = true
+	loader.config.Migration.PreserveVersions = true
+	if err := loader.RegisterSchema(context.Background(), mapping, version); err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+	if v, _ := body["version"].(float64); v != 3 {
+		t.Errorf("version = %v, want the Glue version number 3", body["version"])
+	}
+	if id, _ := body["id"].(float64); int(id) != SourceSchemaID(version) {
+		t.Errorf("id = %v, want %d alongside the version", body["id"], SourceSchemaID(version))
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestNew_TransportConfig
+// ---------------------------------------------------------------------------
+
+func TestNew_TransportConfig(t *testing.T) {
+	loader := newTestLoader(t, "https://sr.example.com")
+	transport, ok := loader.client.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("client transport = %T, want *http.Transport", loader.client.Transport)
+	}
+	workers := loader.config.Concurrency.Workers
+	if transport.MaxConnsPerHost != workers || transport.MaxIdleConnsPerHost != workers {
+		t.Errorf("default pool = %d conns, %d idle, want %d (concurrency.workers)",
+			transport.MaxConnsPerHost, transport.MaxIdleConnsPerHost, workers)
+	}
+	if !transport.ForceAttemptHTTP2 || transport.IdleConnTimeout != 90*time.Second {
+		t.Errorf("default http2 = %v, idle timeout = %v, want true and 90s",
+			transport.ForceAttemptHTTP2, transport.IdleConnTimeout)
+	}
+
+	cfg := config.NewDefaultConfig()
+	cfg.ConfluentCloud.URL = "https://sr.example.com"
+	cfg.ConfluentCloud.Transport = config.TransportConfig{
+		MaxConnsPerHost:   64,
+		ForceAttemptHTTP2: false,
+		IdleConnTimeout:   15 * time.Second,
+	}
+	loader, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() returned unexpected error: %v", err)
+	}
+	transport = loader.client.Transport.(*http.Transport)
+	if transport.MaxConnsPerHost != 64 || transport.MaxIdleConnsPerHost != 64 {
+		t.Errorf("pool = %d conns, %d idle, want 64", transport.MaxConnsPerHost, transport.MaxIdleConnsPerHost)
+	}
+	if transport.ForceAttemptHTTP2 {
+		t.Error("ForceAttemptHTTP2 = true, want false as configured")
+	}
+	if transport.IdleConnTimeout != 15*time.Second {
+		t.Errorf("IdleConnTimeout = %v, want 15s", transport.IdleConnTimeout)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestRegisterSchema_SkipExisting
+// ---------------------------------------------------------------------------
+
+func TestRegisterSchema_SkipExisting(t *testing.T) {
+	existing := `{"type":"record","name":"UserEvent","fields":[]}`
+	var registrations int
+	var lookups []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/subjects/user-event-value":
+			var body SchemaRegistrationRequest
+			json.NewDecoder(r.Body).Decode(&body)
+			lookups = append(lookups, body.Schema)
+			if body.Schema != existing {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"error_code":40403,"message":"Schema not found"}`))
+				return
+			}
+			w.Write([]byte(`{"subject":"user-event-value","id":7,"version":3,"schema":` + strconv.Quote(existing) + `}`))
+		case r.Method == "POST" && r.URL.Path == "/subjects/user-event-value/versions":
+			registrations++
+			w.Write([]byte(`{"id":8}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	loader := newTestLoader(t, server.URL)
+	loader.config.Migration.SkipExisting = true
+	mapping := &models.SchemaMapping{TargetSubject: "user-event-value"}
+
+	// Lookup hit: the identical schema is already there, so nothing is registered
+	err := loader.RegisterSchema(context.Background(), mapping, &models.GlueSchemaVersion{Definition: existing})
+	if !errors.Is(err, ErrAlreadyRegistered) {
+		t.Fatalf("expected ErrAlreadyRegistered for an existing schema, got %v", err)
+	}
+	if registrations != 0 {
+		t.Errorf("expected no registration for an existing schema, got %d", registrations)
+	}
+
+	// Lookup miss: the new schema is registered
+	changed := `{"type":"record","name":"UserEvent","fields":[{"name":"id","type":"string"}]}`
+	if err := loader.RegisterSchema(context.Background(), mapping, &models.GlueSchemaVersion{Definition: changed}); err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+	if registrations != 1 {
+		t.Errorf("expected 1 registration for a new schema, got %d", registrations)
+	}
+	if len(lookups) != 2 {
+		t.Errorf("expected a lookup before each registration, got %d", len(lookups))
+	}
+
+	version, found, err := loader.SchemaRegisteredUnderSubject(context.Background(), mapping, &models.GlueSchemaVersion{Definition: existing})
+	if err != nil || !found || version != 3 {
+		t.Errorf("SchemaRegisteredUnderSubject = %d, %v, %v, want version 3 found", version, found, err)
+	}
+	if _, found, err := loader.SchemaRegisteredUnderSubject(context.Background(), mapping, &models.GlueSchemaVersion{Definition: changed}); err != nil || found {
+		t.Errorf("SchemaRegisteredUnderSubject for a missing schema = %v, %v, want not found", found, err)
+	}
+
+	// Without skip_existing no lookup is made
+	lookups = nil
+	loader.config.Migration.SkipExisting = false
+	if err := loader.RegisterSchema(context.Background(), mapping, &models.GlueSchemaVersion{Definition: existing}); err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+	if len(lookups) != 0 || registrations != 2 {
+		t.Errorf("expected a plain registration without skip_existing, got %d lookups and %d registrations", len(lookups), registrations)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestSchemaRegisteredUnderSubject_TypeAndReferences
+// ---------------------------------------------------------------------------
+
+func TestSchemaRegisteredUnderSubject_TypeAndReferences(t *testing.T) {
+	var lookup SchemaRegistrationRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/subjects/common-value/versions/latest":
+			w.Write([]byte(`{"version":2}`))
+		case r.Method == "POST" && r.URL.Path == "/subjects/orders-value":
+			json.NewDecoder(r.Body).Decode(&lookup)
+			w.Write([]byte(`{"subject":"orders-value","id":9,"version":4}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	loader := newTestLoader(t, server.URL)
+	loader.config.Migration.ReferenceStrategy = "rewrite"
+	mapping := &models.SchemaMapping{
+		TargetSubject:     "orders-value",
+		SourceFormat:      models.SchemaTypeProtobuf,
+		References:        []string{"common"},
+		ReferenceSubjects: map[string]string{"common": "common-value"},
+	}
+
+	version, found, err := loader.SchemaRegisteredUnderSubject(context.Background(), mapping, &models.GlueSchemaVersion{Definition: `syntax = "proto3";`})
+	if err != nil || !found || version != 4 {
+		t.Fatalf("SchemaRegisteredUnderSubject = %d, %v, %v, want version 4 found", version, found, err)
+	}
+	if lookup.SchemaType != "PROTOBUF" {
+		t.Errorf("lookup schemaType = %q, want PROTOBUF", lookup.SchemaType)
+	}
+	if len(lookup.References) != 1 || lookup.References[0].Subject != "common-value" || lookup.References[0].Version != 2 {
+		t.Errorf("lookup references = %+v, want common-value version 2", lookup.References)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestDeleteSubject
+// ---------------------------------------------------------------------------
+
+func TestDeleteSubject(t *testing.T) {
+	var calls []string
+	softDeleted := map[string]bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		calls = append(calls, r.URL.RequestURI())
+		subject := strings.TrimPrefix(r.URL.Path, "/subjects/")
+		switch {
+		case subject == "missing-value":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error_code":40401,"message":"Subject 'missing-value' not found."}`))
+		case r.URL.Query().Get("permanent") == "true":
+			w.Write([]byte(`[1,2]`))
+		case softDeleted[subject]:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error_code":40404,"message":"Subject 'user-value' was soft deleted."}`))
+		default:
+			softDeleted[subject] = true
+			w.Write([]byte(`[1,2]`))
+		}
+	}))
+	defer server.Close()
+
+	loader := newTestLoader(t, server.URL)
+	ctx := context.Background()
+
+	versions, err := loader.DeleteSubject(ctx, "user-value", false)
+	if err != nil || len(versions) != 2 {
+		t.Fatalf("soft DeleteSubject = %v, %v; want versions [1 2]", versions, err)
+	}
+	if _, err := loader.DeleteSubject(ctx, "user-value", false); !errors.Is(err, ErrSubjectNotFound) {
+		t.Errorf("soft-deleting twice = %v, want ErrSubjectNotFound", err)
+	}
+
+	// A permanent delete of an already soft-deleted subject goes straight on
+	calls = nil
+	if _, err := loader.DeleteSubject(ctx, "user-value", true); err != nil {
+		t.Fatalf("permanent DeleteSubject: %v", err)
+	}
+	want := []string{"/subjects/user-value", "/subjects/user-value?permanent=true"}
+	if strings.Join(calls, " ") != strings.Join(want, " ") {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+
+	if _, err := loader.DeleteSubject(ctx, "missing-value", true); !errors.Is(err, ErrSubjectNotFound) {
+		t.Errorf("DeleteSubject(missing-value) = %v, want ErrSubjectNotFound", err)
+	}
+}
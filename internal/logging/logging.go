@@ -1,13 +1,17 @@
 package logging
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"io"
 	"log/slog"
 	"os"
 )
 
 // Setup configures the global slog logger based on the log level and optional log file.
-func Setup(level string, logFile string) {
+// With jsonLogs, every record is a JSON object carrying a run_id shared by the
+// whole run, so log aggregators can correlate one migration's events.
+func Setup(level string, logFile string, jsonLogs bool) {
 	var logLevel slog.Level
 	switch level {
 	case "debug":
@@ -30,8 +34,24 @@ func Setup(level string, logFile string) {
 		}
 	}
 
-	handler := slog.NewTextHandler(writer, &slog.HandlerOptions{
-		Level: logLevel,
-	})
-	slog.SetDefault(slog.New(handler))
+	slog.SetDefault(NewLogger(writer, logLevel, jsonLogs))
+}
+
+// NewLogger returns a text logger writing to w, or with jsonLogs a JSON
+// logger tagged with a new run_id
+func NewLogger(w io.Writer, level slog.Level, jsonLogs bool) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level}
+	if !jsonLogs {
+		return slog.New(slog.NewTextHandler(w, opts))
+	}
+	return slog.New(slog.NewJSONHandler(w, opts)).With("run_id", NewRunID())
+}
+
+// NewRunID returns a random correlation ID for one run
+func NewRunID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
 }
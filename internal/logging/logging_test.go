@@ -0,0 +1,51 @@
+package logging
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetup_Level(t *testing.T) {
+	prev := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	tests := []struct {
+		level     string
+		wantDebug bool
+		wantInfo  bool
+	}{
+		{level: "error"},
+		{level: "info", wantInfo: true},
+		{level: "debug", wantDebug: true, wantInfo: true},
+		{level: "", wantInfo: true},
+	}
+
+	for _, tt := range tests {
+		t.Run("level="+tt.level, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "run.log")
+			Setup(tt.level, path, false)
+
+			slog.Debug("schema registry request", "status", 200)
+			slog.Info("extracting schemas from AWS Glue Schema Registry")
+			slog.Error("schema migration failed", "schema", "payments.OrderEvent")
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("expected output.log_file to be written: %v", err)
+			}
+			got := string(data)
+			if !strings.Contains(got, `level=ERROR msg="schema migration failed" schema=payments.OrderEvent`) {
+				t.Errorf("expected the error line, got:\n%s", got)
+			}
+			if strings.Contains(got, "extracting schemas") != tt.wantInfo {
+				t.Errorf("info line logged = %v, want %v:\n%s", !tt.wantInfo, tt.wantInfo, got)
+			}
+			if strings.Contains(got, "schema registry request") != tt.wantDebug {
+				t.Errorf("debug line logged = %v, want %v:\n%s", !tt.wantDebug, tt.wantDebug, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package mapper
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/akrishnanDG/glue-to-ccsr/internal/keyvalue"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/normalizer"
+	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
+)
+
+// NormalizationCollision is a group of schemas with distinct subjects under
+// the current normalization settings that would share a subject under the
+// alternate settings
+type NormalizationCollision struct {
+	Subject string                 `json:"subject"` // shared subject under the alternate settings
+	Schemas []AuditedSchemaSubject `json:"schemas"`
+}
+
+// AuditedSchemaSubject is one schema in a NormalizationCollision
+type AuditedSchemaSubject struct {
+	Schema  string `json:"schema"`  // registry:schema
+	Current string `json:"current"` // subject under the current settings
+}
+
+// AuditNormalization maps schemas under cfg's normalization settings and
+// under alternate, and reports the subjects that would newly collide if
+// alternate were used. Subjects that already collide under the current
+// settings are not reported. LLM naming falls back to the topic strategy so
+// the audit makes no network calls.
+func AuditNormalization(ctx context.Context, cfg *config.Config, alternate config.NormalizationConfig, schemas []*models.GlueSchema) ([]NormalizationCollision, error) {
+	altCfg := *cfg
+	altCfg.Normalization = alternate
+
+	current, err := auditSubjects(ctx, cfg, schemas)
+	if err != nil {
+		return nil, err
+	}
+	proposed, err := auditSubjects(ctx, &altCfg, schemas)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string][]int)
+	for i, subject := range proposed {
+		groups[subject] = append(groups[subject], i)
+	}
+
+	var collisions []NormalizationCollision
+	for subject, indexes := range groups {
+		if len(indexes) < 2 {
+			continue
+		}
+		distinct := make(map[string]bool)
+		for _, i := range indexes {
+			distinct[current[i]] = true
+		}
+		if len(distinct) < 2 {
+			continue
+		}
+
+		collision := NormalizationCollision{Subject: subject}
+		for _, i := range indexes {
+			collision.Schemas = append(collision.Schemas, AuditedSchemaSubject{
+				Schema:  fmt.Sprintf("%s:%s", schemas[i].RegistryName, schemas[i].Name),
+				Current: current[i],
+			})
+		}
+		sort.Slice(collision.Schemas, func(a, b int) bool {
+			return collision.Schemas[a].Schema < collision.Schemas[b].Schema
+		})
+		collisions = append(collisions, collision)
+	}
+
+	sort.Slice(collisions, func(a, b int) bool {
+		return collisions[a].Subject < collisions[b].Subject
+	})
+	return collisions, nil
+}
+
+// SubjectChange is a schema whose subject differs between two configs
+type SubjectChange struct {
+	Schema string `json:"schema"` // registry:schema
+	Old    string `json:"old_subject"`
+	New    string `json:"new_subject"`
+}
+
+// DiffNaming maps schemas under oldCfg and newCfg and returns every schema
+// whose full subject changes, sorted by schema. Like AuditNormalization it
+// makes no network calls, and it compares subjects before collision
+// resolution.
+func DiffNaming(ctx context.Context, oldCfg, newCfg *config.Config, schemas []*models.GlueSchema) ([]SubjectChange, error) {
+	before, err := auditSubjects(ctx, oldCfg, schemas)
+	if err != nil {
+		return nil, err
+	}
+	after, err := auditSubjects(ctx, newCfg, schemas)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []SubjectChange
+	for i, schema := range schemas {
+		if before[i] == after[i] {
+			continue
+		}
+		changes = append(changes, SubjectChange{
+			Schema: fmt.Sprintf("%s:%s", schema.RegistryName, schema.Name),
+			Old:    before[i],
+			New:    after[i],
+		})
+	}
+	sort.Slice(changes, func(a, b int) bool {
+		return changes[a].Schema < changes[b].Schema
+	})
+	return changes, nil
+}
+
+// auditSubjects returns the full subject each schema maps to under cfg,
+// before collision resolution
+func auditSubjects(ctx context.Context, cfg *config.Config, schemas []*models.GlueSchema) ([]string, error) {
+	kvDet, err := keyvalue.New(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key/value detector: %w", err)
+	}
+	m, err := New(cfg, normalizer.New(cfg), kvDet, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	subjects := make([]string, len(schemas))
+	for i, schema := range schemas {
+		mapping, err := m.MapSchema(ctx, schema)
+		if err != nil {
+			return nil, fmt.Errorf("failed to map schema %s: %w", schema.Name, err)
+		}
+		subjects[i] = mapping.TargetSubject
+		if mapping.TargetContext != "" {
+			subjects[i] = mapping.TargetContext + ":" + mapping.TargetSubject
+		}
+	}
+	return subjects, nil
+}
+
+// DetectRoles runs key/value detection over schemas under cfg and returns the
+// detected roles, sorted by schema, ready to seed a role override file
+func DetectRoles(cfg *config.Config, schemas []*models.GlueSchema) ([]keyvalue.OverrideSeedEntry, error) {
+	kvDet, err := keyvalue.New(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key/value detector: %w", err)
+	}
+	m, err := New(cfg, normalizer.New(cfg), kvDet, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]keyvalue.OverrideSeedEntry, 0, len(schemas))
+	for _, schema := range schemas {
+		detection := kvDet.Detect(schema.RegistryName, schema.Name, m.parseSchemaMetadata(schema))
+		entries = append(entries, keyvalue.OverrideSeedEntry{
+			Schema: schema.RegistryName + "." + schema.Name,
+			Role:   detection.Role,
+			Reason: detection.Reason,
+		})
+	}
+	sort.Slice(entries, func(a, b int) bool {
+		return entries[a].Schema < entries[b].Schema
+	})
+	return entries, nil
+}
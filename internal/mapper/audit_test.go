@@ -0,0 +1,65 @@
+package mapper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
+	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
+)
+
+func TestAuditNormalization_LowerCollidesKebabDoesNot(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Normalization.NormalizeCase = "kebab"
+
+	alternate := cfg.Normalization
+	alternate.NormalizeCase = "lower"
+
+	schemas := []*models.GlueSchema{
+		{Name: "UserEvent", RegistryName: "payments", DataFormat: models.SchemaTypeAvro},
+		{Name: "Userevent", RegistryName: "payments", DataFormat: models.SchemaTypeAvro},
+		{Name: "OrderEvent", RegistryName: "payments", DataFormat: models.SchemaTypeAvro},
+	}
+
+	collisions, err := AuditNormalization(context.Background(), cfg, alternate, schemas)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(collisions) != 1 {
+		t.Fatalf("expected 1 new collision, got %d: %+v", len(collisions), collisions)
+	}
+
+	c := collisions[0]
+	if c.Subject != "userevent-value" {
+		t.Errorf("subject = %q, expected 'userevent-value'", c.Subject)
+	}
+	if len(c.Schemas) != 2 || c.Schemas[0].Schema != "payments:UserEvent" || c.Schemas[1].Schema != "payments:Userevent" {
+		t.Fatalf("schemas = %+v, expected UserEvent and Userevent", c.Schemas)
+	}
+	if c.Schemas[0].Current != "user-event-value" || c.Schemas[1].Current != "userevent-value" {
+		t.Errorf("current subjects = %q, %q, expected distinct kebab-case subjects", c.Schemas[0].Current, c.Schemas[1].Current)
+	}
+}
+
+func TestAuditNormalization_ExistingCollisionNotReported(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Normalization.NormalizeCase = "lower"
+
+	alternate := cfg.Normalization
+	alternate.NormalizeCase = "lower"
+	alternate.InvalidCharReplacement = "_"
+
+	// Both already map to the same subject, so the alternate adds nothing new
+	schemas := []*models.GlueSchema{
+		{Name: "UserEvent", RegistryName: "payments", DataFormat: models.SchemaTypeAvro},
+		{Name: "Userevent", RegistryName: "payments", DataFormat: models.SchemaTypeAvro},
+	}
+
+	collisions, err := AuditNormalization(context.Background(), cfg, alternate, schemas)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(collisions) != 0 {
+		t.Errorf("expected no new collisions, got %+v", collisions)
+	}
+}
@@ -2,37 +2,45 @@ package mapper
 
 import (
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"sort"
 	"strings"
 
+	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
 	"gopkg.in/yaml.v3"
 )
 
 // CustomNameMappingFile represents the structure of a custom name mapping YAML file.
 type CustomNameMappingFile struct {
 	// Simple mappings: schema name -> subject (matches any registry)
-	Mappings map[string]string `yaml:"mappings"`
+	Mappings map[string]string `yaml:"mappings,omitempty"`
 
 	// Qualified mappings: registry:schema -> subject (specific registry)
-	QualifiedMappings map[string]string `yaml:"qualified_mappings"`
+	QualifiedMappings map[string]string `yaml:"qualified_mappings,omitempty"`
 
 	// Extended mappings with optional role and context overrides
-	ExtendedMappings []ExtendedMapping `yaml:"extended_mappings"`
+	ExtendedMappings []ExtendedMapping `yaml:"extended_mappings,omitempty"`
 }
 
 // ExtendedMapping represents a mapping with optional role and context overrides.
+// Setting both KeySubject and ValueSubject registers the schema under both.
 type ExtendedMapping struct {
-	Source  string `yaml:"source"`  // schema name or registry:schema
-	Subject string `yaml:"subject"` // target subject name
-	Role    string `yaml:"role"`    // optional: key or value
-	Context string `yaml:"context"` // optional: target context
+	Source       string `yaml:"source"`                  // schema name or registry:schema
+	Subject      string `yaml:"subject,omitempty"`       // target subject name
+	Role         string `yaml:"role,omitempty"`          // optional: key or value
+	Context      string `yaml:"context,omitempty"`       // optional: target context
+	KeySubject   string `yaml:"key_subject,omitempty"`   // optional: key subject, instead of subject
+	ValueSubject string `yaml:"value_subject,omitempty"` // optional: value subject, instead of subject
 }
 
 // ResolvedCustomMapping is the internal representation after loading.
 type ResolvedCustomMapping struct {
-	Subject string
-	Role    string // empty means use auto-detection
-	Context string // empty means use default generation
+	Subject    string
+	Role       string // empty means use auto-detection
+	Context    string // empty means use default generation
+	KeySubject string // additional key subject; Subject is then the value subject
 }
 
 // loadedCustomMappings holds all resolved custom mappings for fast lookup.
@@ -43,6 +51,47 @@ type loadedCustomMappings struct {
 	simple map[string]*ResolvedCustomMapping
 }
 
+// ScaffoldMappingFile builds a name mapping file that pins every mapping to
+// its current subject, role and context, as a starting point for hand-tuning.
+// Mappings in error are left out; entries are sorted by source.
+func ScaffoldMappingFile(mappings []*models.SchemaMapping) *CustomNameMappingFile {
+	file := &CustomNameMappingFile{}
+	for _, mapping := range mappings {
+		if mapping.Status == models.MappingStatusError || mapping.TargetSubject == "" {
+			continue
+		}
+		ext := ExtendedMapping{
+			Source:  mapping.SourceRegistry + ":" + mapping.SourceSchemaName,
+			Subject: mapping.TargetSubject,
+			Role:    string(mapping.DetectedRole),
+			Context: mapping.TargetContext,
+		}
+		if mapping.KeySubject != "" {
+			ext.Subject = ""
+			ext.Role = ""
+			ext.KeySubject = mapping.KeySubject
+			ext.ValueSubject = mapping.TargetSubject
+		}
+		file.ExtendedMappings = append(file.ExtendedMappings, ext)
+	}
+	sort.Slice(file.ExtendedMappings, func(i, j int) bool {
+		return file.ExtendedMappings[i].Source < file.ExtendedMappings[j].Source
+	})
+	return file
+}
+
+// WriteMappingFile writes file to w as YAML that name_mapping_file accepts
+func WriteMappingFile(w io.Writer, file *CustomNameMappingFile) error {
+	fmt.Fprintln(w, "# Generated from the current migration plan. Edit the subjects as needed")
+	fmt.Fprintln(w, "# and pass this file back with naming.name_mapping_file or --name-mapping-file.")
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(file); err != nil {
+		return fmt.Errorf("failed to write mapping file: %w", err)
+	}
+	return enc.Close()
+}
+
 func loadCustomMappings(path string) (*loadedCustomMappings, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -76,6 +125,21 @@ func loadCustomMappings(path string) (*loadedCustomMappings, error) {
 			Role:    ext.Role,
 			Context: ext.Context,
 		}
+		switch {
+		case ext.KeySubject != "" && ext.ValueSubject != "":
+			resolved.Subject = ext.ValueSubject
+			resolved.Role = "value"
+			resolved.KeySubject = ext.KeySubject
+		case ext.KeySubject != "":
+			resolved.Subject = ext.KeySubject
+			resolved.Role = "key"
+		case ext.ValueSubject != "":
+			resolved.Subject = ext.ValueSubject
+			resolved.Role = "value"
+		}
+		if resolved.Subject == "" {
+			return nil, fmt.Errorf("extended mapping for %q has no subject", ext.Source)
+		}
 		if strings.Contains(ext.Source, ":") {
 			loaded.qualified[ext.Source] = resolved
 		} else {
@@ -86,6 +150,41 @@ func loadCustomMappings(path string) (*loadedCustomMappings, error) {
 	return loaded, nil
 }
 
+// loadMergedCustomMappings loads each mapping file in order and merges them,
+// later files overriding earlier ones. A source defined in more than one file
+// is logged so an unintended override across domains is visible.
+func loadMergedCustomMappings(paths []string) (*loadedCustomMappings, error) {
+	merged := &loadedCustomMappings{
+		qualified: make(map[string]*ResolvedCustomMapping),
+		simple:    make(map[string]*ResolvedCustomMapping),
+	}
+	definedIn := make(map[string]string) // source -> file that last defined it
+
+	for _, path := range paths {
+		loaded, err := loadCustomMappings(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		for _, part := range []struct {
+			from, into map[string]*ResolvedCustomMapping
+		}{
+			{loaded.qualified, merged.qualified},
+			{loaded.simple, merged.simple},
+		} {
+			for source, mapping := range part.from {
+				if prev, ok := definedIn[source]; ok && prev != path {
+					slog.Warn("name mapping source defined in multiple files; using the later one",
+						"source", source, "previous", prev, "file", path)
+				}
+				definedIn[source] = path
+				part.into[source] = mapping
+			}
+		}
+	}
+
+	return merged, nil
+}
+
 func loadContextMappings(path string) (map[string]string, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
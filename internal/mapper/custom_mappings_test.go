@@ -1,6 +1,7 @@
 package mapper
 
 import (
+	"bytes"
 	"context"
 	"os"
 	"path/filepath"
@@ -101,6 +102,45 @@ extended_mappings:
 	}
 }
 
+func TestLoadCustomMappings_KeyAndValueSubjects(t *testing.T) {
+	path := writeTempFile(t, `
+extended_mappings:
+  - source: "OrderId"
+    key_subject: "orders-key"
+    value_subject: "orders-value"
+  - source: "CustomerId"
+    key_subject: "customers-key"
+`)
+
+	loaded, err := loadCustomMappings(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	both := loaded.simple["OrderId"]
+	if both.Subject != "orders-value" || both.Role != "value" || both.KeySubject != "orders-key" {
+		t.Errorf("expected value subject 'orders-value' with key subject 'orders-key', got %+v", both)
+	}
+
+	// A key subject on its own is just a key-role subject
+	keyOnly := loaded.simple["CustomerId"]
+	if keyOnly.Subject != "customers-key" || keyOnly.Role != "key" || keyOnly.KeySubject != "" {
+		t.Errorf("expected key subject 'customers-key' only, got %+v", keyOnly)
+	}
+}
+
+func TestLoadCustomMappings_ExtendedMappingWithoutSubject(t *testing.T) {
+	path := writeTempFile(t, `
+extended_mappings:
+  - source: "OrderId"
+    role: "key"
+`)
+
+	if _, err := loadCustomMappings(path); err == nil {
+		t.Error("expected error for extended mapping without a subject")
+	}
+}
+
 func TestLoadCustomMappings_EmptyFile(t *testing.T) {
 	path := writeTempFile(t, "")
 
@@ -419,3 +459,100 @@ extended_mappings:
 		t.Errorf("unexpected extended context: %q", loaded.simple["ExtendedSchema"].Context)
 	}
 }
+
+func TestScaffoldMappingFile_ReproducesAutoSubjects(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Output.DryRun = true
+	cfg.AWS.RegistryAll = true
+	cfg.Naming.ContextMapping = "registry"
+	// A dual-subject mapping must survive the round trip as well
+	cfg.Naming.NameMappingFile = writeTempFile(t, `
+extended_mappings:
+  - source: "orders:OrderId"
+    key_subject: "orders-key"
+    value_subject: "orders-value"
+`)
+	m := newTestMapper(t, cfg)
+
+	schemas := []*models.GlueSchema{
+		{Name: "UserEvent", RegistryName: "users", DataFormat: models.SchemaTypeAvro},
+		{Name: "OrderKey", RegistryName: "orders", DataFormat: models.SchemaTypeAvro},
+		{Name: "payment.refund", RegistryName: "payments", DataFormat: models.SchemaTypeAvro},
+		{Name: "OrderId", RegistryName: "orders", DataFormat: models.SchemaTypeAvro},
+	}
+
+	auto, err := m.MapAll(context.Background(), schemas)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteMappingFile(&buf, ScaffoldMappingFile(auto)); err != nil {
+		t.Fatalf("WriteMappingFile() unexpected error: %v", err)
+	}
+	path := writeTempFile(t, buf.String())
+
+	if _, err := loadCustomMappings(path); err != nil {
+		t.Fatalf("scaffolded file does not load: %v\n%s", err, buf.String())
+	}
+
+	cfg.Naming.NameMappingFile = path
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("scaffolded file does not validate: %v\n%s", err, buf.String())
+	}
+	pinned, err := newTestMapper(t, cfg).MapAll(context.Background(), schemas)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i := range auto {
+		if pinned[i].NamingStrategy != "custom-mapping" {
+			t.Errorf("%s: naming strategy = %q, expected custom-mapping", auto[i].SourceSchemaName, pinned[i].NamingStrategy)
+		}
+		if pinned[i].KeySubject != auto[i].KeySubject {
+			t.Errorf("%s: pinned key subject %q, expected auto %q", auto[i].SourceSchemaName, pinned[i].KeySubject, auto[i].KeySubject)
+		}
+		if pinned[i].TargetSubject != auto[i].TargetSubject || pinned[i].TargetContext != auto[i].TargetContext || pinned[i].DetectedRole != auto[i].DetectedRole {
+			t.Errorf("%s: pinned %q %q %q, expected auto %q %q %q", auto[i].SourceSchemaName,
+				pinned[i].TargetContext, pinned[i].TargetSubject, pinned[i].DetectedRole,
+				auto[i].TargetContext, auto[i].TargetSubject, auto[i].DetectedRole)
+		}
+	}
+}
+
+func TestNew_MergesNameMappingFiles(t *testing.T) {
+	first := writeTempFile(t, `
+mappings:
+  "OrderEvent": "orders-value"
+  "UserEvent": "users-value"
+`)
+	second := writeTempFile(t, `
+qualified_mappings:
+  "payments:PaymentEvent": "payments-value"
+extended_mappings:
+  - source: "OrderEvent"
+    subject: "orders-v2-value"
+    context: ".orders"
+`)
+
+	cfg := config.NewDefaultConfig()
+	cfg.Naming.NameMappingFiles = []string{first, second}
+	m := newTestMapper(t, cfg)
+
+	// The second file overrides the source it shares with the first
+	result, found := m.lookupCustomMapping("orders", "OrderEvent")
+	if !found {
+		t.Fatal("expected match for OrderEvent")
+	}
+	if result.Subject != "orders-v2-value" || result.Context != ".orders" {
+		t.Errorf("OrderEvent = %q in %q, expected the second file's orders-v2-value in .orders", result.Subject, result.Context)
+	}
+
+	// Sources defined in only one file are kept
+	if result, found := m.lookupCustomMapping("users", "UserEvent"); !found || result.Subject != "users-value" {
+		t.Errorf("UserEvent = %+v, expected users-value from the first file", result)
+	}
+	if result, found := m.lookupCustomMapping("payments", "PaymentEvent"); !found || result.Subject != "payments-value" {
+		t.Errorf("payments:PaymentEvent = %+v, expected payments-value from the second file", result)
+	}
+}
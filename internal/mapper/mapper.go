@@ -2,7 +2,11 @@ package mapper
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
 	"strings"
 	"text/template"
 
@@ -13,6 +17,11 @@ import (
 	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
 )
 
+// registryContextPattern matches one dot-separated segment of a registry name
+// that can be used verbatim in a context name: at least one alphanumeric
+// character, plus underscores and hyphens.
+var registryContextPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]*[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
 // NomenclatureMapper maps AWS Glue schema names to Confluent Cloud subject names
 type NomenclatureMapper struct {
 	config          *config.Config
@@ -34,8 +43,13 @@ func New(cfg *config.Config, norm *normalizer.Normalizer, kvDet *keyvalue.Detect
 	}
 
 	// Load custom name mappings if specified
+	var mappingFiles []string
 	if cfg.Naming.NameMappingFile != "" {
-		mappings, err := loadCustomMappings(cfg.Naming.NameMappingFile)
+		mappingFiles = append(mappingFiles, cfg.Naming.NameMappingFile)
+	}
+	mappingFiles = append(mappingFiles, cfg.Naming.NameMappingFiles...)
+	if len(mappingFiles) > 0 {
+		mappings, err := loadMergedCustomMappings(mappingFiles)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load custom name mappings: %w", err)
 		}
@@ -71,27 +85,106 @@ func (m *NomenclatureMapper) MapAll(ctx context.Context, schemas []*models.GlueS
 		if err != nil {
 			return nil, fmt.Errorf("failed to map schema %s: %w", schema.Name, err)
 		}
+		slog.Debug("mapped schema",
+			"schema", schema.RegistryName+"."+schema.Name,
+			"context", mapping.TargetContext,
+			"subject", mapping.TargetSubject,
+			"role", mapping.DetectedRole,
+			"strategy", mapping.NamingStrategy,
+			"reason", mapping.NamingReason,
+			"status", mapping.Status)
 		mappings = append(mappings, mapping)
 	}
 
+	if m.config.Naming.ContextMapping == "custom" {
+		m.flagSharedFallbackContexts(mappings)
+	}
+
 	return mappings, nil
 }
 
+// flagSharedFallbackContexts warns about registries missing from the context
+// mapping file whose fallback context (the registry name) is also the
+// explicit target of another registry, since their schemas would silently
+// share one context.
+func (m *NomenclatureMapper) flagSharedFallbackContexts(mappings []*models.SchemaMapping) {
+	owners := make(map[string][]string)
+	for registry, ctx := range m.contextMappings {
+		owners["."+ctx] = append(owners["."+ctx], registry)
+	}
+	for _, registries := range owners {
+		sort.Strings(registries)
+	}
+
+	for _, mapping := range mappings {
+		if mapping.Status == models.MappingStatusError {
+			continue
+		}
+		if _, mapped := m.contextMappings[mapping.SourceRegistry]; mapped {
+			continue
+		}
+		fallback, err := registryContext(mapping.SourceRegistry)
+		if err != nil || mapping.TargetContext != fallback {
+			continue
+		}
+		registries, shared := owners[fallback]
+		if !shared {
+			continue
+		}
+		warning := fmt.Sprintf("registry %q is not in the context mapping file and falls back to context %q, which is also mapped for registry %s",
+			mapping.SourceRegistry, fallback, strings.Join(registries, ", "))
+		if mapping.Warning != "" {
+			warning = mapping.Warning + "; " + warning
+		}
+		mapping.Status = models.MappingStatusWarning
+		mapping.Warning = warning
+	}
+}
+
+// SplitUnsupported separates schemas whose data format is not Avro, JSON or
+// Protobuf. Their definitions can't be parsed, so naming them would rely on
+// guesses; they are returned as dead letters with the reason instead.
+func SplitUnsupported(schemas []*models.GlueSchema) ([]*models.GlueSchema, []models.DeadLetter) {
+	var supported []*models.GlueSchema
+	var dead []models.DeadLetter
+	for _, schema := range schemas {
+		if schema.DataFormat.Supported() {
+			supported = append(supported, schema)
+			continue
+		}
+		dead = append(dead, models.DeadLetter{
+			SourceRegistry: schema.RegistryName,
+			SourceSchema:   schema.Name,
+			DataFormat:     schema.DataFormat,
+			Reason:         fmt.Sprintf("unsupported data format %q (expected AVRO, JSON or PROTOBUF)", schema.DataFormat),
+		})
+	}
+	return supported, dead
+}
+
 // MapSchema maps a single schema to a Confluent Cloud subject
 func (m *NomenclatureMapper) MapSchema(ctx context.Context, schema *models.GlueSchema) (*models.SchemaMapping, error) {
 	mapping := &models.SchemaMapping{
 		SourceRegistry:   schema.RegistryName,
 		SourceSchemaName: schema.Name,
 		SourceVersions:   len(schema.Versions),
+		SourceFormat:     schema.DataFormat,
+		ContentHash:      llm.ContentHash(schema),
+
+		SourceCompatibility: schema.Compatibility,
 		Status:           models.MappingStatusReady,
 	}
 
 	// Check custom name mappings first (highest priority)
 	if customMapping, found := m.lookupCustomMapping(schema.RegistryName, schema.Name); found {
 		mapping.TargetSubject = customMapping.Subject
+		mapping.KeySubject = customMapping.KeySubject
 		mapping.NamingStrategy = "custom-mapping"
 		mapping.NamingReason = "Custom name mapping file"
 		mapping.Transformations = []string{fmt.Sprintf("custom-mapping: %s -> %s", schema.Name, customMapping.Subject)}
+		if customMapping.KeySubject != "" {
+			mapping.Transformations = append(mapping.Transformations, fmt.Sprintf("custom-mapping: %s -> %s", schema.Name, customMapping.KeySubject))
+		}
 
 		// Use overridden role if provided, otherwise detect normally
 		if customMapping.Role != "" {
@@ -106,7 +199,11 @@ func (m *NomenclatureMapper) MapSchema(ctx context.Context, schema *models.GlueS
 		if customMapping.Context != "" {
 			mapping.TargetContext = customMapping.Context
 		} else {
-			mapping.TargetContext = m.generateContext(schema.RegistryName)
+			targetContext, err := m.generateContext(schema.RegistryName)
+			if err != nil {
+				return nil, err
+			}
+			mapping.TargetContext = targetContext
 		}
 
 		return mapping, nil
@@ -121,41 +218,69 @@ func (m *NomenclatureMapper) MapSchema(ctx context.Context, schema *models.GlueS
 	mapping.NamingReason = detection.Reason
 
 	// Generate context
-	mapping.TargetContext = m.generateContext(schema.RegistryName)
+	targetContext, err := m.generateContext(schema.RegistryName)
+	if err != nil {
+		return nil, err
+	}
+	mapping.TargetContext = targetContext
 
 	// Generate subject name based on strategy
-	var err error
-	mapping.TargetSubject, mapping.NamingStrategy, mapping.Transformations, err = m.generateSubjectName(ctx, schema, parsed, detection.Role)
+	var role models.SchemaRole
+	mapping.TargetSubject, mapping.NamingStrategy, role, mapping.Transformations, err = m.generateSubjectName(ctx, schema, parsed, detection.Role)
 	if err != nil {
 		mapping.Status = models.MappingStatusError
 		mapping.Error = err.Error()
 		return mapping, nil
 	}
+	if role != detection.Role {
+		mapping.DetectedRole = role
+		mapping.NamingReason = fmt.Sprintf("LLM classified the schema as %s (detected %s)", role, detection.Role)
+	}
+	if mapping.NamingStrategy == "llm" {
+		mapping.SuggestedName = mapping.TargetSubject
+	}
 
 	return mapping, nil
 }
 
-func (m *NomenclatureMapper) generateContext(registryName string) string {
+func (m *NomenclatureMapper) generateContext(registryName string) (string, error) {
 	switch m.config.Naming.ContextMapping {
 	case "registry":
 		// Map registry to context
-		return "." + registryName
+		return registryContext(registryName)
 	case "flat":
 		// All schemas in default context
-		return ""
+		return "", nil
 	case "custom":
 		if m.contextMappings != nil {
 			if ctx, ok := m.contextMappings[registryName]; ok {
-				return "." + ctx
+				return "." + ctx, nil
 			}
 		}
-		return "." + registryName
+		return registryContext(registryName)
 	default:
-		return "." + registryName
+		return registryContext(registryName)
+	}
+}
+
+// registryContext derives a context name from a registry name. Dotted names
+// such as payments.refunds give nested contexts, so each segment is checked
+// on its own. It refuses names that would yield an empty or illegal context
+// rather than letting schemas silently land in the default context.
+func registryContext(registryName string) (string, error) {
+	name := strings.Trim(strings.TrimSpace(registryName), ".")
+	for _, segment := range strings.Split(name, ".") {
+		if !registryContextPattern.MatchString(segment) {
+			return "", fmt.Errorf("registry %q does not produce a valid context name (context_mapping uses the registry name; use context_mapping: custom to map it explicitly)", registryName)
+		}
 	}
+	return "." + name, nil
 }
 
-func (m *NomenclatureMapper) generateSubjectName(ctx context.Context, schema *models.GlueSchema, parsed *models.ParsedSchema, role models.SchemaRole) (string, string, []string, error) {
+// generateSubjectName returns the subject, the strategy that named it and the
+// role it was named for, which is role unless the LLM classified the schema
+// differently
+func (m *NomenclatureMapper) generateSubjectName(ctx context.Context, schema *models.GlueSchema, parsed *models.ParsedSchema, role models.SchemaRole) (string, string, models.SchemaRole, []string, error) {
 	var baseName string
 	var strategy string
 	var transformations []string
@@ -169,13 +294,29 @@ func (m *NomenclatureMapper) generateSubjectName(ctx context.Context, schema *mo
 		strategy = "record"
 		baseName, transformations = m.recordNameStrategy(schema, parsed, role)
 
+	case "topic-record":
+		strategy = "topic-record"
+		baseName, transformations = m.topicRecordNameStrategy(schema, parsed, role)
+
 	case "llm":
 		strategy = "llm"
 		var err error
-		baseName, transformations, err = m.llmNameStrategy(ctx, schema, parsed, role)
+		var llmRole models.SchemaRole
+		baseName, llmRole, transformations, err = m.llmNameStrategy(ctx, schema, parsed, role)
+		if err == nil && llmRole != role {
+			transformations = append(transformations, fmt.Sprintf("LLM role: %s → %s", role, llmRole))
+			role = llmRole
+		}
 		if err != nil {
+			if errors.Is(err, llm.ErrRetriesExhausted) {
+				slog.Warn("LLM retries exhausted; falling back to topic strategy",
+					"schema", schema.RegistryName+"."+schema.Name, "error", err)
+			}
 			// Fall back to topic strategy
 			strategy = "topic (fallback)"
+			if errors.Is(err, llm.ErrCostEstimated) {
+				strategy = "topic (llm estimated)"
+			}
 			baseName, transformations = m.topicNameStrategy(schema, role)
 		}
 
@@ -184,7 +325,7 @@ func (m *NomenclatureMapper) generateSubjectName(ctx context.Context, schema *mo
 		var err error
 		baseName, transformations, err = m.customNameStrategy(schema, parsed, role)
 		if err != nil {
-			return "", "", nil, err
+			return "", "", "", nil, err
 		}
 
 	default:
@@ -192,7 +333,8 @@ func (m *NomenclatureMapper) generateSubjectName(ctx context.Context, schema *mo
 		baseName, transformations = m.topicNameStrategy(schema, role)
 	}
 
-	return baseName, strategy, transformations, nil
+	subject, truncated := m.normalizer.TruncateWithHash(baseName)
+	return subject, strategy, role, append(transformations, truncated...), nil
 }
 
 // topicNameStrategy uses the schema name as the subject base with role suffix
@@ -240,15 +382,43 @@ func (m *NomenclatureMapper) recordNameStrategy(schema *models.GlueSchema, parse
 	return result, transforms
 }
 
-// llmNameStrategy uses an LLM to suggest the subject name
-func (m *NomenclatureMapper) llmNameStrategy(ctx context.Context, schema *models.GlueSchema, parsed *models.ParsedSchema, role models.SchemaRole) (string, []string, error) {
+// topicRecordNameStrategy follows Confluent's TopicRecordNameStrategy: the
+// normalized schema name, then the record's fully-qualified name as written
+// in the definition, then the role suffix. A schema with no record name is
+// named by the topic strategy.
+func (m *NomenclatureMapper) topicRecordNameStrategy(schema *models.GlueSchema, parsed *models.ParsedSchema, role models.SchemaRole) (string, []string) {
+	if parsed == nil || parsed.RecordName == "" {
+		topic, transforms := m.topicNameStrategy(schema, role)
+		return topic, append(transforms, "topic-record: no record name, using the topic name")
+	}
+
+	topic, transforms := m.normalizer.Normalize(schema.Name)
+	topic = normalizer.StripKeySuffix(topic)
+	topic = normalizer.StripValueSuffix(topic)
+
+	// An Avro name may already be qualified
+	record := parsed.RecordName
+	if parsed.Namespace != "" && !strings.Contains(record, ".") {
+		record = parsed.Namespace + "." + record
+	}
+	if cleaned := normalizer.CleanForSubject(record); cleaned != record {
+		transforms = append(transforms, fmt.Sprintf("topic-record: %s → %s", record, cleaned))
+		record = cleaned
+	}
+
+	return topic + "-" + record + keyvalue.GetSuffix(role), transforms
+}
+
+// llmNameStrategy uses an LLM to suggest the subject name. The returned role
+// is the one the LLM classified the schema as, or role if it gave none.
+func (m *NomenclatureMapper) llmNameStrategy(ctx context.Context, schema *models.GlueSchema, parsed *models.ParsedSchema, role models.SchemaRole) (string, models.SchemaRole, []string, error) {
 	if m.llmNamer == nil {
-		return "", nil, fmt.Errorf("LLM namer not configured")
+		return "", "", nil, fmt.Errorf("LLM namer not configured")
 	}
 
 	suggestion, err := m.llmNamer.SuggestName(ctx, schema, parsed, role)
 	if err != nil {
-		return "", nil, err
+		return "", "", nil, err
 	}
 
 	var transforms []string
@@ -256,7 +426,14 @@ func (m *NomenclatureMapper) llmNameStrategy(ctx context.Context, schema *models
 		transforms = append(transforms, fmt.Sprintf("LLM: %s → %s", suggestion.OriginalName, suggestion.SuggestedName))
 	}
 
-	return suggestion.SuggestedName, transforms, nil
+	if suggestion.RoleSuggested {
+		role = models.SchemaRoleValue
+		if suggestion.IsKeySchema {
+			role = models.SchemaRoleKey
+		}
+	}
+
+	return suggestion.SuggestedName, role, transforms, nil
 }
 
 // customNameStrategy uses a user-defined template
@@ -0,0 +1,330 @@
+package mapper
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/akrishnanDG/glue-to-ccsr/internal/keyvalue"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/llm"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/normalizer"
+	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
+)
+
+func newTestMapper(t *testing.T, cfg *config.Config) *NomenclatureMapper {
+	t.Helper()
+	norm := normalizer.New(cfg)
+	kvDet, err := keyvalue.New(cfg)
+	if err != nil {
+		t.Fatalf("failed to create key/value detector: %v", err)
+	}
+	m, err := New(cfg, norm, kvDet, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return m
+}
+
+func TestMapSchema_RegistryContext(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Naming.ContextMapping = "registry"
+	m := newTestMapper(t, cfg)
+
+	schema := &models.GlueSchema{
+		Name:         "UserEvent",
+		RegistryName: "payments",
+		DataFormat:   models.SchemaTypeAvro,
+	}
+
+	mapping, err := m.MapSchema(context.Background(), schema)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mapping.TargetContext != ".payments" {
+		t.Errorf("expected context '.payments', got %q", mapping.TargetContext)
+	}
+}
+
+// stubProvider answers every completion with the same response
+type stubProvider struct {
+	response string
+}
+
+func (p *stubProvider) Complete(ctx context.Context, prompt string) (string, float64, error) {
+	return p.response, 0, nil
+}
+
+func TestMapSchema_LLMOverridesRole(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Naming.SubjectStrategy = "llm"
+	norm := normalizer.New(cfg)
+	kvDet, err := keyvalue.New(cfg)
+	if err != nil {
+		t.Fatalf("failed to create key/value detector: %v", err)
+	}
+	namer, err := llm.NewNamerWithProvider(cfg, &stubProvider{
+		response: `{"suggested_name": "order-key", "is_key_schema": true, "reasoning": "Only carries the order ID"}`,
+	})
+	if err != nil {
+		t.Fatalf("failed to create namer: %v", err)
+	}
+	m, err := New(cfg, norm, kvDet, namer)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	schema := &models.GlueSchema{
+		Name:         "OrderEvent",
+		RegistryName: "orders",
+		DataFormat:   models.SchemaTypeAvro,
+		Versions: []models.GlueSchemaVersion{
+			{VersionNumber: 1, Definition: `{"type":"record","name":"OrderEvent","fields":[{"name":"order_id","type":"string"}]}`},
+		},
+	}
+
+	mapping, err := m.MapSchema(context.Background(), schema)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mapping.NamingStrategy != "llm" || mapping.TargetSubject != "order-key" {
+		t.Fatalf("mapping = %s via %s, expected order-key via llm", mapping.TargetSubject, mapping.NamingStrategy)
+	}
+	if mapping.DetectedRole != models.SchemaRoleKey {
+		t.Errorf("DetectedRole = %s, expected the LLM to flip it to key", mapping.DetectedRole)
+	}
+	if !strings.Contains(mapping.NamingReason, "LLM classified the schema as key") {
+		t.Errorf("NamingReason = %q, expected the LLM classification", mapping.NamingReason)
+	}
+}
+
+func TestMapSchema_CarriesSourceCompatibility(t *testing.T) {
+	m := newTestMapper(t, config.NewDefaultConfig())
+
+	schema := &models.GlueSchema{
+		Name:          "UserEvent",
+		RegistryName:  "payments",
+		DataFormat:    models.SchemaTypeAvro,
+		Compatibility: "BACKWARD_ALL",
+	}
+
+	mapping, err := m.MapSchema(context.Background(), schema)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mapping.SourceCompatibility != "BACKWARD_ALL" {
+		t.Errorf("expected source compatibility 'BACKWARD_ALL', got %q", mapping.SourceCompatibility)
+	}
+}
+
+func TestMapSchema_TopicRecordStrategy(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Naming.SubjectStrategy = "topic-record"
+	m := newTestMapper(t, cfg)
+
+	tests := []struct {
+		name       string
+		schema     string
+		definition string
+		want       string
+	}{
+		{
+			name:       "namespace and record name",
+			schema:     "OrderEvents",
+			definition: `{"type":"record","name":"OrderCreated","namespace":"com.example.orders","fields":[]}`,
+			want:       "order-events-com.example.orders.OrderCreated-value",
+		},
+		{
+			name:       "qualified record name",
+			schema:     "order_events",
+			definition: `{"type":"record","name":"com.example.orders.OrderShipped","fields":[]}`,
+			want:       "order-events-com.example.orders.OrderShipped-value",
+		},
+		{
+			name:   "no record name",
+			schema: "OrderEvents",
+			want:   "order-events-value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := &models.GlueSchema{
+				Name:         tt.schema,
+				RegistryName: "orders",
+				DataFormat:   models.SchemaTypeAvro,
+			}
+			if tt.definition != "" {
+				schema.Versions = []models.GlueSchemaVersion{{VersionNumber: 1, Definition: tt.definition}}
+			}
+
+			mapping, err := m.MapSchema(context.Background(), schema)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if mapping.TargetSubject != tt.want || mapping.NamingStrategy != "topic-record" {
+				t.Errorf("mapping = %s via %s, expected %s via topic-record", mapping.TargetSubject, mapping.NamingStrategy, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistryContext_Dotted(t *testing.T) {
+	tests := []struct {
+		registry string
+		want     string
+		wantErr  bool
+	}{
+		{registry: "payments.refunds", want: ".payments.refunds"},
+		{registry: ".payments.refunds.", want: ".payments.refunds"},
+		{registry: "team-a.orders_v2", want: ".team-a.orders_v2"},
+		{registry: "payments..refunds", wantErr: true},
+		{registry: "payments.$#", wantErr: true},
+		{registry: "payments.-", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.registry, func(t *testing.T) {
+			got, err := registryContext(tt.registry)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("registryContext(%q) = %q, expected an error", tt.registry, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("registryContext(%q) returned unexpected error: %v", tt.registry, err)
+			}
+			if got != tt.want {
+				t.Errorf("registryContext(%q) = %q, want %q", tt.registry, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapSchema_RegistryContextInvalidName(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Naming.ContextMapping = "registry"
+	m := newTestMapper(t, cfg)
+
+	schema := &models.GlueSchema{
+		Name:         "UserEvent",
+		RegistryName: "$#$#",
+		DataFormat:   models.SchemaTypeAvro,
+	}
+
+	mapping, err := m.MapSchema(context.Background(), schema)
+	if err == nil {
+		t.Fatalf("expected error for registry with only invalid characters, got context %q", mapping.TargetContext)
+	}
+	if !strings.Contains(err.Error(), `registry "$#$#"`) || !strings.Contains(err.Error(), "valid context name") {
+		t.Errorf("error = %q, expected it to name the registry and the invalid context", err.Error())
+	}
+
+	// MapAll must surface the same error rather than producing a mapping.
+	if _, err := m.MapAll(context.Background(), []*models.GlueSchema{schema}); err == nil {
+		t.Error("expected MapAll to fail for registry with only invalid characters")
+	}
+}
+
+func TestMapAll_CustomContextSharedWithFallback(t *testing.T) {
+	path := writeTempFile(t, `
+regA: shared
+`)
+
+	cfg := config.NewDefaultConfig()
+	cfg.Naming.ContextMapping = "custom"
+	cfg.Naming.ContextMappingFile = path
+	m := newTestMapper(t, cfg)
+
+	// regB is named "shared" and is absent from the file, so it falls back
+	// to the same context regA is mapped to explicitly.
+	schemas := []*models.GlueSchema{
+		{Name: "UserEvent", RegistryName: "regA", DataFormat: models.SchemaTypeAvro},
+		{Name: "OrderEvent", RegistryName: "shared", DataFormat: models.SchemaTypeAvro},
+	}
+
+	mappings, err := m.MapAll(context.Background(), schemas)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mapped, fallback := mappings[0], mappings[1]
+	if mapped.TargetContext != ".shared" || fallback.TargetContext != ".shared" {
+		t.Fatalf("expected both schemas in '.shared', got %q and %q", mapped.TargetContext, fallback.TargetContext)
+	}
+	if mapped.Status != models.MappingStatusReady {
+		t.Errorf("explicitly mapped registry status = %q, want ready", mapped.Status)
+	}
+	if fallback.Status != models.MappingStatusWarning {
+		t.Fatalf("fallback registry status = %q, want warning", fallback.Status)
+	}
+	if !strings.Contains(fallback.Warning, `"shared"`) || !strings.Contains(fallback.Warning, "regA") {
+		t.Errorf("warning = %q, expected it to name both registries", fallback.Warning)
+	}
+}
+
+func TestFlagSharedFallbackContexts_KeepsEarlierWarning(t *testing.T) {
+	path := writeTempFile(t, `
+regA: shared
+`)
+
+	cfg := config.NewDefaultConfig()
+	cfg.Naming.ContextMapping = "custom"
+	cfg.Naming.ContextMappingFile = path
+	m := newTestMapper(t, cfg)
+
+	mapping := &models.SchemaMapping{
+		SourceRegistry:   "shared",
+		SourceSchemaName: "OrderEvent",
+		TargetContext:    ".shared",
+		TargetSubject:    "order-event-value",
+		Status:           models.MappingStatusWarning,
+		Warning:          "detected role is a guess",
+	}
+	m.flagSharedFallbackContexts([]*models.SchemaMapping{mapping})
+
+	if !strings.HasPrefix(mapping.Warning, "detected role is a guess; ") || !strings.Contains(mapping.Warning, "regA") {
+		t.Errorf("warning = %q, expected the earlier warning followed by the shared context one", mapping.Warning)
+	}
+}
+
+func TestMapAll_CustomContextDistinctFallbackNoWarning(t *testing.T) {
+	path := writeTempFile(t, `
+regA: shared
+`)
+
+	cfg := config.NewDefaultConfig()
+	cfg.Naming.ContextMapping = "custom"
+	cfg.Naming.ContextMappingFile = path
+	m := newTestMapper(t, cfg)
+
+	mappings, err := m.MapAll(context.Background(), []*models.GlueSchema{
+		{Name: "OrderEvent", RegistryName: "regB", DataFormat: models.SchemaTypeAvro},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mappings[0].Status != models.MappingStatusReady || mappings[0].Warning != "" {
+		t.Errorf("expected no warning for distinct fallback context, got status %q warning %q", mappings[0].Status, mappings[0].Warning)
+	}
+}
+
+func TestSplitUnsupported(t *testing.T) {
+	schemas := []*models.GlueSchema{
+		{Name: "UserEvent", RegistryName: "payments", DataFormat: models.SchemaTypeAvro},
+		{Name: "LegacyEvent", RegistryName: "payments", DataFormat: models.SchemaType("THRIFT")},
+		{Name: "OrderEvent", RegistryName: "payments", DataFormat: models.SchemaTypeProtobuf},
+	}
+
+	supported, dead := SplitUnsupported(schemas)
+	if len(supported) != 2 || supported[0].Name != "UserEvent" || supported[1].Name != "OrderEvent" {
+		t.Errorf("supported = %v, expected UserEvent and OrderEvent", supported)
+	}
+	if len(dead) != 1 || dead[0].SourceSchema != "LegacyEvent" || dead[0].DataFormat != "THRIFT" {
+		t.Fatalf("dead letters = %+v, expected LegacyEvent", dead)
+	}
+	if !strings.Contains(dead[0].Reason, "unsupported data format") {
+		t.Errorf("reason = %q, expected it to explain the unsupported format", dead[0].Reason)
+	}
+}
@@ -2,11 +2,22 @@ package migrator
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/akrishnanDG/glue-to-ccsr/internal/compare"
 	"github.com/akrishnanDG/glue-to-ccsr/internal/extractor"
 	"github.com/akrishnanDG/glue-to-ccsr/internal/graph"
 	"github.com/akrishnanDG/glue-to-ccsr/internal/keyvalue"
@@ -15,10 +26,13 @@ import (
 	"github.com/akrishnanDG/glue-to-ccsr/internal/mapper"
 	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
 	"github.com/akrishnanDG/glue-to-ccsr/internal/normalizer"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/report"
 	"github.com/akrishnanDG/glue-to-ccsr/internal/validator"
 	"github.com/akrishnanDG/glue-to-ccsr/internal/worker"
 	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/schollz/progressbar/v3"
+	"golang.org/x/sync/errgroup"
 )
 
 // Result represents the result of a migration
@@ -29,8 +43,12 @@ type Result struct {
 	Successful          int
 	Failed              int
 	Skipped             int
+	NotStarted          int // left unstarted by an interrupt
+	AlreadyExists       int
 	LLMCalls            int
 	LLMCost             float64
+	EstimatedLLMCalls   int // dry runs with llm.estimate_in_dry_run
+	EstimatedLLMCost    float64
 	Errors              []error
 	Report              *models.MigrationReport
 }
@@ -38,7 +56,7 @@ type Result struct {
 // Migrator orchestrates the migration process
 type Migrator struct {
 	config      *config.Config
-	extractor   *extractor.GlueExtractor
+	extractor   extractor.Source
 	loader      *loader.ConfluentLoader
 	mapper      *mapper.NomenclatureMapper
 	normalizer  *normalizer.Normalizer
@@ -47,12 +65,17 @@ type Migrator struct {
 	validator   *validator.Validator
 	workerPool  *worker.Pool
 	checkpoint  *worker.CheckpointManager
+	stateMu     sync.Mutex // guards the checkpoint state workers update
+
+	// followupSem bounds in-flight follow-up calls made after registration,
+	// such as metadata updates, across all workers
+	followupSem chan struct{}
 }
 
 // New creates a new Migrator
 func New(cfg *config.Config) (*Migrator, error) {
-	// Create extractor
-	ext, err := extractor.New(cfg)
+	// Create extractor, reading from an export directory when configured
+	ext, err := extractor.NewSource(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create extractor: %w", err)
 	}
@@ -75,7 +98,15 @@ func New(cfg *config.Config) (*Migrator, error) {
 	// Create LLM namer if using LLM strategy
 	var llmNmr *llm.Namer
 	if cfg.Naming.SubjectStrategy == "llm" {
-		llmNmr, err = llm.NewNamer(cfg)
+		// Bedrock is called with the same AWS credentials as Glue
+		var awsCfg aws.Config
+		if cfg.LLM.Provider == "bedrock" {
+			awsCfg, err = extractor.LoadAWSConfig(context.Background(), cfg.AWS)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create LLM namer: %w", err)
+			}
+		}
+		llmNmr, err = llm.NewNamer(cfg, awsCfg)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create LLM namer: %w", err)
 		}
@@ -110,13 +141,15 @@ func New(cfg *config.Config) (*Migrator, error) {
 		validator:  val,
 		workerPool: pool,
 		checkpoint: chkpt,
+
+		followupSem: make(chan struct{}, followupLimit(cfg)),
 	}, nil
 }
 
 // NewWithDeps creates a Migrator with pre-built dependencies (for testing).
 func NewWithDeps(
 	cfg *config.Config,
-	ext *extractor.GlueExtractor,
+	ext extractor.Source,
 	ldr *loader.ConfluentLoader,
 	mpr *mapper.NomenclatureMapper,
 	norm *normalizer.Normalizer,
@@ -133,14 +166,39 @@ func NewWithDeps(
 		kvDetector: kvDet,
 		validator:  val,
 		workerPool: pool,
+
+		followupSem: make(chan struct{}, followupLimit(cfg)),
 	}
 }
 
 // Run executes the migration
 func (m *Migrator) Run(ctx context.Context) (*Result, error) {
 	startTime := time.Now()
-	result := &Result{}
 
+	plan, err := m.Plan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Scaffold a name mapping file from the plan instead of migrating
+	if path := m.config.Output.MapFileTemplate; path != "" {
+		if err := writeMappingTemplate(path, plan.Mappings); err != nil {
+			return nil, err
+		}
+		slog.Info("wrote name mapping template, no changes made", "file", path, "mappings", len(plan.Mappings))
+		result := newResult(plan)
+		result.Report = m.generateReport(plan, startTime, true)
+		return result, nil
+	}
+
+	return m.execute(ctx, plan, startTime)
+}
+
+// Plan extracts the schemas, orders them by dependency, maps and validates
+// them, and returns the migration plan without registering anything.
+// Validation errors fail planning unless output.dry_run is set; either way
+// they are recorded in the plan.
+func (m *Migrator) Plan(ctx context.Context) (*models.MigrationPlan, error) {
 	// Step 1: Extract schemas from AWS Glue
 	slog.Info("extracting schemas from AWS Glue Schema Registry", "step", "1/5")
 	schemas, err := m.extractor.ExtractAll(ctx)
@@ -149,21 +207,52 @@ func (m *Migrator) Run(ctx context.Context) (*Result, error) {
 	}
 	slog.Info("extraction complete", "schemas", len(schemas), "registries", m.countRegistries(schemas))
 
+	// Set aside schemas in formats that can't be parsed or registered
+	schemas, deadLetters := mapper.SplitUnsupported(schemas)
+	for _, d := range deadLetters {
+		slog.Warn("skipping schema", "schema", d.SourceRegistry+"."+d.SourceSchema, "reason", d.Reason)
+	}
+
 	// Step 2: Build dependency graph
-	slog.Info("building dependency graph", "step", "2/5")
-	depGraph, err := graph.Build(schemas)
-	if err != nil {
-		return nil, fmt.Errorf("failed to build dependency graph: %w", err)
+	var levels []graph.Level
+	if m.config.Migration.SkipDependencyGraph {
+		slog.Warn("skipping dependency graph; schema references will not be ordered", "step", "2/5")
+		var err error
+		levels, err = graph.SingleLevel(schemas)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve schema references: %w", err)
+		}
+	} else {
+		slog.Info("building dependency graph", "step", "2/5")
+		depGraph, err := graph.Build(schemas)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build dependency graph: %w", err)
+		}
+		levels = depGraph.GetLevels()
+		slog.Info("dependency graph built", "levels", len(levels))
 	}
-	levels := depGraph.GetLevels()
-	slog.Info("dependency graph built", "levels", len(levels))
 
 	// Step 3: Generate mappings
 	slog.Info("generating schema mappings", "step", "3/5")
+	if m.llmNamer != nil && m.config.LLM.SeedCache != "" {
+		prior, err := report.Load(m.config.LLM.SeedCache)
+		if err != nil {
+			return nil, fmt.Errorf("failed to seed LLM cache: %w", err)
+		}
+		seeded := m.llmNamer.SeedCache(prior, schemas)
+		slog.Info("seeded LLM cache from report", "file", m.config.LLM.SeedCache, "entries", seeded)
+	}
 	mappings, err := m.mapper.MapAll(ctx, schemas)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate mappings: %w", err)
 	}
+	if m.config.Migration.CanonicalizeDefinitions {
+		for _, mapping := range mappings {
+			if canonicalizable(mapping.SourceFormat) {
+				mapping.Transformations = append(mapping.Transformations, "canonicalize-definitions")
+			}
+		}
+	}
 	
 	// Create a lookup map for the complete mappings
 	mappingLookup := make(map[string]*models.SchemaMapping)
@@ -180,6 +269,7 @@ func (m *Migrator) Run(ctx context.Context) (*Result, error) {
 				// Copy the target fields from the complete mapping
 				levels[i].Schemas[j].TargetContext = completeMapping.TargetContext
 				levels[i].Schemas[j].TargetSubject = completeMapping.TargetSubject
+				levels[i].Schemas[j].KeySubject = completeMapping.KeySubject
 				levels[i].Schemas[j].DetectedRole = completeMapping.DetectedRole
 				levels[i].Schemas[j].NamingStrategy = completeMapping.NamingStrategy
 				levels[i].Schemas[j].NamingReason = completeMapping.NamingReason
@@ -190,12 +280,24 @@ func (m *Migrator) Run(ctx context.Context) (*Result, error) {
 		}
 	}
 
+	// Keep only the roles selected for this run
+	if roles := m.config.Migration.Roles; len(roles) > 0 {
+		before := len(mappings)
+		schemas, mappings, levels = filterByRole(roles, schemas, mappings, levels)
+		slog.Info("filtered schemas by detected role", "roles", roles, "kept", len(mappings), "excluded", before-len(mappings))
+	}
+
+	// Schemas unchanged since migration.since stay in the plan only when a
+	// changed schema references them
+	schemas, mappings, levels = skipUnchanged(schemas, mappings, levels)
+
 	// Step 3.5: Auto-resolve collisions if enabled
+	var resolvedCollisions []models.Collision
 	if m.config.Normalization.CollisionCheck && m.config.Normalization.CollisionResolution != "" && m.config.Normalization.CollisionResolution != "fail" {
 		collisions := m.normalizer.DetectCollisions(mappings)
 		if len(collisions) > 0 {
 			slog.Warn("naming collisions detected", "count", len(collisions), "strategy", m.config.Normalization.CollisionResolution)
-			mappings = m.normalizer.ResolveCollisions(mappings)
+			mappings, resolvedCollisions = m.normalizer.ResolveCollisionsExplained(mappings)
 			
 			// Update the mappings in the levels after collision resolution
 			for i := range mappings {
@@ -217,16 +319,9 @@ func (m *Migrator) Run(ctx context.Context) (*Result, error) {
 
 	// Step 4: Validate mappings
 	slog.Info("validating mappings", "step", "4/5")
-	validationResult := m.validator.ValidateAll(mappings)
-	if validationResult.HasErrors() {
-		for _, e := range validationResult.Errors {
-			slog.Error("validation error", "schema", e.Schema, "message", e.Message)
-		}
-		if m.config.Output.DryRun {
-			// In dry-run mode, continue to show the report
-		} else {
-			return nil, fmt.Errorf("validation failed with %d errors", len(validationResult.Errors))
-		}
+	validationResult, err := m.validate(mappings)
+	if err != nil {
+		return nil, err
 	}
 
 	// Check for collisions
@@ -239,17 +334,55 @@ func (m *Migrator) Run(ctx context.Context) (*Result, error) {
 		}
 	}
 
+	// Explicit IDs are hashed from Glue version IDs and can collide; catch
+	// that before IMPORT mode lets one version take another's ID
+	if m.config.Migration.PreserveSchemaIDs || m.config.Migration.PreserveVersions {
+		if err := checkSchemaIDs(schemas, mappings); err != nil {
+			return nil, err
+		}
+	}
+
+	// Order schemas within each level before planning
+	graph.OrderLevels(levels, m.config.Migration.LevelOrder, schemas)
+
 	// Step 5: Create migration plan
-	plan := m.createPlan(schemas, mappings, levels)
-	result.RegistriesProcessed = len(plan.SourceRegistries)
-	result.SchemasProcessed = plan.TotalSchemas
-	result.VersionsProcessed = plan.TotalVersions
+	plan := m.createPlan(schemas, mappings, levels, resolvedCollisions)
+	plan.DeadLetters = deadLetters
+	plan.Errors = validationResult.Errors
+	plan.Warnings = validationResult.Warnings
+	return plan, nil
+}
+
+// execute registers the schemas of plan level by level, or reports what
+// would be registered when output.dry_run is set
+func (m *Migrator) execute(ctx context.Context, plan *models.MigrationPlan, startTime time.Time) (*Result, error) {
+	result := newResult(plan)
 
 	// If dry-run, print report and return
 	if m.config.Output.DryRun {
+		if m.hasTargetCredentials() {
+			plan.Summary.AlreadyExists = m.countExistingSubjects(ctx, plan.Mappings)
+			plan.Summary.TargetChecked = true
+			result.AlreadyExists = plan.Summary.AlreadyExists
+		}
+		if m.llmNamer != nil {
+			result.LLMCalls = m.llmNamer.GetCallCount()
+			result.LLMCost = m.llmNamer.GetTotalCost()
+			result.EstimatedLLMCalls = m.llmNamer.GetEstimatedCalls()
+			result.EstimatedLLMCost = m.llmNamer.GetEstimatedCost()
+			plan.Summary.LLMCalls = result.LLMCalls + result.EstimatedLLMCalls
+			plan.Summary.EstimatedLLMCost = result.LLMCost + result.EstimatedLLMCost
+		}
 		slog.Info("dry run complete, no changes made", "step", "5/5")
-		m.printDryRunReport(plan)
+		if m.config.Output.Decorative() {
+			m.printDryRunReport(plan)
+		}
 		result.Report = m.generateReport(plan, startTime, true)
+		result.Report.EstimatedAPICalls = &plan.Summary.APICalls
+		result.Report.Results.LLMCalls = result.LLMCalls
+		result.Report.Results.LLMCost = result.LLMCost
+		result.Report.Results.EstimatedLLMCalls = result.EstimatedLLMCalls
+		result.Report.Results.EstimatedLLMCost = result.EstimatedLLMCost
 		return result, nil
 	}
 
@@ -258,25 +391,86 @@ func (m *Migrator) Run(ctx context.Context) (*Result, error) {
 	
 	// Resume from checkpoint if specified
 	var state *models.MigrationState
+	var err error
+	resolveReferenceSubjects(plan)
+	levels := planLevels(plan)
+	targetHash := hashTargetURL(m.config.ConfluentCloud.URL)
+	configHash, err := hashConfig(m.config)
+	if err != nil {
+		return nil, err
+	}
 	if m.checkpoint != nil && m.config.Checkpoint.Resume {
 		state, err = m.checkpoint.Load()
+		if err != nil && m.config.Checkpoint.RetryFailed {
+			return nil, fmt.Errorf("checkpoint.retry_failed needs a checkpoint to retry: %w", err)
+		}
 		if err != nil {
 			slog.Warn("could not load checkpoint, starting fresh", "error", err)
-			state = models.NewMigrationState("")
+			state = models.NewMigrationState(configHash)
 		} else {
+			if state.TargetURLHash != "" && state.TargetURLHash != targetHash {
+				if !m.config.Checkpoint.Force {
+					return nil, fmt.Errorf("checkpoint %s was written for a different Confluent Cloud Schema Registry than %s; refusing to resume (use --force to resume anyway)",
+						m.config.Checkpoint.File, m.config.ConfluentCloud.URL)
+				}
+				slog.Warn("resuming checkpoint written for a different target URL", "url", m.config.ConfluentCloud.URL)
+			}
+			if state.ConfigHash != "" && state.ConfigHash != configHash {
+				if !m.config.Checkpoint.Force {
+					return nil, fmt.Errorf("checkpoint %s was written with different naming, migration or mapping file settings, so its completed schemas may be stale; refusing to resume (use --force to resume anyway)",
+						m.config.Checkpoint.File)
+				}
+				slog.Warn("resuming checkpoint written with different naming, migration or mapping file settings")
+			}
 			slog.Info("resuming from checkpoint", "completed", state.CompletedCount, "total", state.TotalSchemas)
 		}
 	} else {
-		state = models.NewMigrationState("")
+		state = models.NewMigrationState(configHash)
 	}
-	state.TotalSchemas = len(mappings)
+	state.TargetURLHash = targetHash
+	state.ConfigHash = configHash
+	state.TotalSchemas = len(plan.Mappings)
 	state.MigrationOrder = getMigrationOrder(levels)
 
+	skip, err := skipBefore(state.MigrationOrder, m.config.Checkpoint.ContinueFrom)
+	if err != nil {
+		return nil, err
+	}
+	if len(skip) > 0 {
+		slog.Info("continuing from schema", "schema", m.config.Checkpoint.ContinueFrom, "skipped", len(skip))
+	}
+
+	// Schemas this run won't touch, for reconciliation
+	resumed := make(map[string]bool, len(state.CompletedSchemas)+len(skip))
+	for key := range state.CompletedSchemas {
+		resumed[key] = true
+	}
+	for key := range skip {
+		resumed[key] = true
+	}
+
+	// With retry_failed only the schemas that failed last time are migrated,
+	// even ones before continue_from; every other schema is skipped
+	if m.checkpoint != nil && m.config.Checkpoint.Resume && m.config.Checkpoint.RetryFailed {
+		if skip == nil {
+			skip = make(map[string]bool, len(state.MigrationOrder))
+		}
+		for _, key := range state.MigrationOrder {
+			if _, failed := state.FailedSchemas[key]; failed {
+				delete(skip, key)
+				delete(resumed, key)
+			} else {
+				skip[key] = true
+			}
+		}
+		slog.Info("retrying failed schemas", "failed", len(state.FailedSchemas))
+	}
+
 	// Migrate level by level
 	for _, level := range levels {
 		slog.Info("processing dependency level", "level", level.Level, "schemas", len(level.Schemas))
 		
-		levelResult, err := m.migrateLevel(ctx, level, state)
+		levelResult, err := m.migrateLevel(ctx, level, state, skip)
 		if err != nil {
 			return nil, fmt.Errorf("failed at level %d: %w", level.Level, err)
 		}
@@ -284,6 +478,7 @@ func (m *Migrator) Run(ctx context.Context) (*Result, error) {
 		result.Successful += levelResult.Successful
 		result.Failed += levelResult.Failed
 		result.Skipped += levelResult.Skipped
+		result.NotStarted += levelResult.NotStarted
 		result.Errors = append(result.Errors, levelResult.Errors...)
 
 		// Save checkpoint after each level
@@ -292,6 +487,19 @@ func (m *Migrator) Run(ctx context.Context) (*Result, error) {
 				slog.Warn("failed to save checkpoint", "error", err)
 			}
 		}
+
+		if m.failureThresholdExceeded(result.Failed, len(plan.Mappings)) {
+			msg := fmt.Sprintf("aborting after level %d: %d of %d schemas failed, exceeding migration.abort_after_failures (%s)",
+				level.Level, result.Failed, len(plan.Mappings), m.config.Migration.AbortAfterFailures)
+			if m.checkpoint != nil {
+				msg += fmt.Sprintf("; progress saved to %s, set checkpoint.resume: true to continue", m.config.Checkpoint.File)
+			}
+			return nil, errors.New(msg)
+		}
+	}
+
+	if result.NotStarted > 0 {
+		slog.Warn("migration interrupted", "not_started", result.NotStarted)
 	}
 
 	// Update LLM stats if used
@@ -301,10 +509,606 @@ func (m *Migrator) Run(ctx context.Context) (*Result, error) {
 	}
 
 	result.Report = m.generateReport(plan, startTime, false)
+	result.Report.Results.Successful = result.Successful
+	result.Report.Results.Failed = result.Failed
+	result.Report.Results.Skipped = result.Skipped
+	result.Report.Results.NotStarted = result.NotStarted
+	result.Report.Results.LLMCalls = result.LLMCalls
+	result.Report.Results.LLMCost = result.LLMCost
+	result.Report.Reconciliation = m.reconcile(ctx, plan.Mappings, state, resumed)
 
 	return result, nil
 }
 
+// newResult starts a Result with the counts known from plan
+func newResult(plan *models.MigrationPlan) *Result {
+	return &Result{
+		RegistriesProcessed: len(plan.SourceRegistries),
+		SchemasProcessed:    plan.TotalSchemas,
+		VersionsProcessed:   plan.TotalVersions,
+		Skipped:             len(plan.DeadLetters),
+	}
+}
+
+// validate checks mappings, logging each error. Errors fail the run unless
+// output.dry_run is set, where the report still shows them.
+func (m *Migrator) validate(mappings []*models.SchemaMapping) (*validator.ValidationResult, error) {
+	validationResult := m.validator.ValidateAll(mappings)
+	if validationResult.HasErrors() {
+		for _, e := range validationResult.Errors {
+			slog.Error("validation error", "schema", e.Schema, "message", e.Message)
+		}
+		if !m.config.Output.DryRun {
+			return nil, fmt.Errorf("validation failed with %d errors", len(validationResult.Errors))
+		}
+	}
+	return validationResult, nil
+}
+
+// planLevels returns the dependency levels of plan in migration order
+func planLevels(plan *models.MigrationPlan) []graph.Level {
+	levels := make([]graph.Level, 0, len(plan.Levels))
+	for _, l := range plan.Levels {
+		levels = append(levels, graph.Level{Level: l.Level, Schemas: l.Schemas})
+	}
+	return levels
+}
+
+// resolveReferenceSubjects records, on each level schema, the subject every
+// schema it references is mapped to in plan, so a reference to a key schema
+// points at its key subject. References outside the plan are left for the
+// loader to name.
+func resolveReferenceSubjects(plan *models.MigrationPlan) {
+	targets := make(map[string]*models.SchemaMapping, len(plan.Mappings))
+	for i := range plan.Mappings {
+		key := fmt.Sprintf("%s:%s", plan.Mappings[i].SourceRegistry, plan.Mappings[i].SourceSchemaName)
+		targets[key] = &plan.Mappings[i]
+	}
+
+	for i := range plan.Levels {
+		for j := range plan.Levels[i].Schemas {
+			schema := &plan.Levels[i].Schemas[j]
+			schema.ReferenceSubjects = nil
+			for _, ref := range schema.References {
+				target, found := targets[ref]
+				if !found || target.TargetSubject == "" {
+					continue
+				}
+				if schema.ReferenceSubjects == nil {
+					schema.ReferenceSubjects = make(map[string]string, len(schema.References))
+				}
+				schema.ReferenceSubjects[ref] = fullSubject(target)
+			}
+		}
+	}
+}
+
+// recordFailure records a failed attempt at the schema key in state, counting
+// the attempts recorded by earlier runs of a resumed checkpoint
+func (m *Migrator) recordFailure(state *models.MigrationState, key string, mapping *models.SchemaMapping, err error) {
+	m.stateMu.Lock()
+	defer m.stateMu.Unlock()
+	state.FailedSchemas[key] = models.FailedSchema{
+		SourceRegistry: mapping.SourceRegistry,
+		SourceSchema:   mapping.SourceSchemaName,
+		Error:          err.Error(),
+		Attempts:       state.FailedSchemas[key].Attempts + 1,
+		LastAttempt:    time.Now(),
+	}
+}
+
+// parallelVersionWorkers caps the versions of one subject registered at once
+// with parallel_versions
+const parallelVersionWorkers = 4
+
+// registerVersions registers versions under target's subject and returns the
+// numbers of those already there. Versions are registered in order, stopping
+// at the first failure, unless parallel_versions is set and preserve_versions
+// gives each one an explicit number; then they are registered concurrently
+// and every failed version is reported.
+func (m *Migrator) registerVersions(ctx context.Context, key string, mapping, target *models.SchemaMapping, versions []models.GlueSchemaVersion) ([]int64, error) {
+	subject := fullSubject(target)
+
+	if !m.config.Migration.ParallelVersions || !m.config.Migration.PreserveVersions || len(versions) < 2 {
+		var existing []int64
+		for i := range versions {
+			found, err := m.registerVersion(ctx, key, target, &versions[i])
+			if err != nil {
+				m.dumpFailure(mapping, subject, versions[i:i+1], err)
+				return existing, err
+			}
+			if found {
+				existing = append(existing, versions[i].VersionNumber)
+			}
+		}
+		return existing, nil
+	}
+
+	// Indexed by version so the results come out in order
+	found := make([]bool, len(versions))
+	errs := make([]error, len(versions))
+	var g errgroup.Group
+	g.SetLimit(parallelVersionWorkers)
+	for i := range versions {
+		g.Go(func() error {
+			found[i], errs[i] = m.registerVersion(ctx, key, target, &versions[i])
+			return nil
+		})
+	}
+	g.Wait()
+
+	var existing []int64
+	var failed []models.GlueSchemaVersion
+	for i := range versions {
+		if found[i] {
+			existing = append(existing, versions[i].VersionNumber)
+		}
+		if errs[i] != nil {
+			failed = append(failed, versions[i])
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		m.dumpFailure(mapping, subject, failed, err)
+		return existing, err
+	}
+	return existing, nil
+}
+
+// registerVersion registers one version under target's subject. It reports
+// whether the version was already registered there, which is not an error.
+func (m *Migrator) registerVersion(ctx context.Context, key string, target *models.SchemaMapping, version *models.GlueSchemaVersion) (bool, error) {
+	err := m.loader.RegisterSchema(ctx, target, version)
+	if errors.Is(err, loader.ErrAlreadyRegistered) {
+		slog.Debug("skipping version already in target", "schema", key, "subject", fullSubject(target), "version", version.VersionNumber)
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to register version %d of %s: %w", version.VersionNumber, key, err)
+	}
+	return false, nil
+}
+
+// subjectMetadata returns the subject metadata for schema's Glue description
+// and tags, or nil when metadata isn't migrated or there is none. Tags are
+// written as sorted key=value pairs.
+func (m *Migrator) subjectMetadata(schema *models.GlueSchema) *models.SubjectMetadata {
+	cfg := m.config.Metadata
+	if cfg.Strategy != "migrate" {
+		return nil
+	}
+
+	metadata := &models.SubjectMetadata{}
+	if cfg.MigrateDescription && schema.Description != "" {
+		metadata.Properties = map[string]string{"description": schema.Description}
+	}
+	if cfg.MigrateTags {
+		for k, v := range schema.Tags {
+			metadata.Tags = append(metadata.Tags, k+"="+v)
+		}
+		sort.Strings(metadata.Tags)
+	}
+
+	if len(metadata.Properties) == 0 && len(metadata.Tags) == 0 {
+		return nil
+	}
+	return metadata
+}
+
+// filterByRole drops the schemas whose detected role isn't in roles from the
+// schemas, mappings and dependency levels. Schemas a kept schema references,
+// directly or transitively, are kept whatever their role so its references
+// still resolve in the target.
+func filterByRole(roles []string, schemas []*models.GlueSchema, mappings []*models.SchemaMapping, levels []graph.Level) ([]*models.GlueSchema, []*models.SchemaMapping, []graph.Level) {
+	allowed := make(map[models.SchemaRole]bool, len(roles))
+	for _, role := range roles {
+		allowed[models.SchemaRole(role)] = true
+	}
+
+	keep := make(map[string]bool, len(mappings))
+	for _, mapping := range mappings {
+		if allowed[mapping.DetectedRole] {
+			keep[fmt.Sprintf("%s:%s", mapping.SourceRegistry, mapping.SourceSchemaName)] = true
+		}
+	}
+	return keepReferenced(keep, schemas, mappings, levels)
+}
+
+// skipUnchanged drops the schemas extracted as unchanged since
+// migration.since unless a changed schema references them, directly or
+// transitively. The ones kept are marked skipped, so they are planned and
+// give references their real subjects but are not registered again.
+func skipUnchanged(schemas []*models.GlueSchema, mappings []*models.SchemaMapping, levels []graph.Level) ([]*models.GlueSchema, []*models.SchemaMapping, []graph.Level) {
+	unchanged := make(map[string]bool)
+	keep := make(map[string]bool, len(schemas))
+	for _, s := range schemas {
+		key := fmt.Sprintf("%s:%s", s.RegistryName, s.Name)
+		if s.Unchanged {
+			unchanged[key] = true
+		} else {
+			keep[key] = true
+		}
+	}
+	if len(unchanged) == 0 {
+		return schemas, mappings, levels
+	}
+
+	schemas, mappings, levels = keepReferenced(keep, schemas, mappings, levels)
+	skip := func(mapping *models.SchemaMapping) bool {
+		if !unchanged[fmt.Sprintf("%s:%s", mapping.SourceRegistry, mapping.SourceSchemaName)] {
+			return false
+		}
+		mapping.Status = models.MappingStatusSkipped
+		mapping.Warning = "unchanged since migration.since; planned only so references to it resolve"
+		return true
+	}
+	kept := 0
+	for _, mapping := range mappings {
+		if skip(mapping) {
+			kept++
+		}
+	}
+	for i := range levels {
+		for j := range levels[i].Schemas {
+			skip(&levels[i].Schemas[j])
+		}
+	}
+	slog.Info("kept unchanged schemas that changed schemas reference", "kept", kept, "dropped", len(unchanged)-kept)
+	return schemas, mappings, levels
+}
+
+// keepReferenced keeps the schemas in keep, and every schema they reference
+// directly or transitively, in the schemas, mappings and dependency levels
+func keepReferenced(keep map[string]bool, schemas []*models.GlueSchema, mappings []*models.SchemaMapping, levels []graph.Level) ([]*models.GlueSchema, []*models.SchemaMapping, []graph.Level) {
+	// References are only recorded on the level schemas
+	references := make(map[string][]string)
+	for _, level := range levels {
+		for _, mapping := range level.Schemas {
+			key := fmt.Sprintf("%s:%s", mapping.SourceRegistry, mapping.SourceSchemaName)
+			references[key] = mapping.References
+		}
+	}
+
+	var pending []string
+	for key := range keep {
+		pending = append(pending, key)
+	}
+	for len(pending) > 0 {
+		key := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		for _, ref := range references[key] {
+			if !keep[ref] {
+				keep[ref] = true
+				pending = append(pending, ref)
+			}
+		}
+	}
+
+	var keptMappings []*models.SchemaMapping
+	for _, mapping := range mappings {
+		if keep[fmt.Sprintf("%s:%s", mapping.SourceRegistry, mapping.SourceSchemaName)] {
+			keptMappings = append(keptMappings, mapping)
+		}
+	}
+
+	var keptSchemas []*models.GlueSchema
+	for _, s := range schemas {
+		if keep[fmt.Sprintf("%s:%s", s.RegistryName, s.Name)] {
+			keptSchemas = append(keptSchemas, s)
+		}
+	}
+
+	var keptLevels []graph.Level
+	for _, level := range levels {
+		var kept []models.SchemaMapping
+		for _, mapping := range level.Schemas {
+			if keep[fmt.Sprintf("%s:%s", mapping.SourceRegistry, mapping.SourceSchemaName)] {
+				kept = append(kept, mapping)
+			}
+		}
+		if len(kept) > 0 {
+			keptLevels = append(keptLevels, graph.Level{Level: level.Level, Schemas: kept})
+		}
+	}
+
+	return keptSchemas, keptMappings, keptLevels
+}
+
+// checkSchemaIDs fails when distinct Glue versions derive the same target
+// schema ID, naming every colliding pair with the subject it is registered
+// under. A schema with a key subject registers each version under both it and
+// its value subject with the same ID, which is not a collision.
+func checkSchemaIDs(schemas []*models.GlueSchema, mappings []*models.SchemaMapping) error {
+	sources := make(map[string]*models.GlueSchema, len(schemas))
+	for _, s := range schemas {
+		sources[fmt.Sprintf("%s:%s", s.RegistryName, s.Name)] = s
+	}
+
+	type owner struct {
+		versionID string
+		name      string
+	}
+	owners := make(map[int]owner)
+	var collisions []string
+	for _, mapping := range mappings {
+		schema, ok := sources[fmt.Sprintf("%s:%s", mapping.SourceRegistry, mapping.SourceSchemaName)]
+		if !ok {
+			continue
+		}
+		for _, target := range registrationTargets(mapping) {
+			for i := range schema.Versions {
+				version := &schema.Versions[i]
+				id := loader.SourceSchemaID(version)
+				name := fmt.Sprintf("%s:%s version %d (%s)", schema.RegistryName, schema.Name, version.VersionNumber, fullSubject(target))
+				prev, ok := owners[id]
+				if !ok {
+					owners[id] = owner{versionID: version.SchemaVersionID, name: name}
+					continue
+				}
+				if prev.versionID != version.SchemaVersionID {
+					collisions = append(collisions, fmt.Sprintf("%s and %s both map to schema ID %d", prev.name, name, id))
+				}
+			}
+		}
+	}
+	if len(collisions) > 0 {
+		return fmt.Errorf("preserve_schema_ids derived duplicate schema IDs; turn off preserve_schema_ids and preserve_versions to let Schema Registry assign IDs: %s",
+			strings.Join(collisions, "; "))
+	}
+	return nil
+}
+
+// hashTargetURL identifies a target registry in checkpoints without storing
+// its URL in plain text
+func hashTargetURL(url string) string {
+	sum := sha256.Sum256([]byte(strings.TrimRight(strings.TrimSpace(url), "/")))
+	return hex.EncodeToString(sum[:])
+}
+
+// hashConfig identifies the settings that decide target subjects and what is
+// registered under them, so a checkpoint isn't resumed after they change.
+// Mapping, override and prompt files count by their contents. Settings that
+// only change how the migration runs, such as skip_existing or
+// per_schema_timeout, are left out so they can be adjusted between an
+// interrupted run and its resume.
+func hashConfig(cfg *config.Config) (string, error) {
+	migration := cfg.Migration
+	settings := struct {
+		Naming        config.NamingConfig
+		Normalization config.NormalizationConfig
+		KeyValue      config.KeyValueConfig
+		Migration     any
+		LLM           any
+		Files         map[string]string
+	}{
+		Naming:        cfg.Naming,
+		Normalization: cfg.Normalization,
+		KeyValue:      cfg.KeyValue,
+		Migration: struct {
+			VersionStrategy, ReferenceStrategy           string
+			MaxVersionsPerSchema                         int
+			Since                                        time.Time
+			Roles                                        []string
+			ImportMode, PreserveSchemaIDs                bool
+			PreserveVersions, PreserveCompatibility      bool
+			SkipDocOnlyVersions, CanonicalizeDefinitions bool
+		}{
+			migration.VersionStrategy, migration.ReferenceStrategy,
+			migration.MaxVersionsPerSchema,
+			migration.Since,
+			migration.Roles,
+			migration.ImportMode, migration.PreserveSchemaIDs,
+			migration.PreserveVersions, migration.PreserveCompatibility,
+			migration.SkipDocOnlyVersions, migration.CanonicalizeDefinitions,
+		},
+		Files: make(map[string]string),
+	}
+	if cfg.Naming.SubjectStrategy == "llm" {
+		settings.LLM = struct {
+			Provider, Model, BaseURL, PromptTemplateFile string
+		}{cfg.LLM.Provider, cfg.LLM.Model, cfg.LLM.BaseURL, cfg.LLM.PromptTemplateFile}
+	}
+
+	files := append([]string{cfg.Naming.NameMappingFile, cfg.Naming.ContextMappingFile, cfg.KeyValue.RoleOverrideFile}, cfg.Naming.NameMappingFiles...)
+	if cfg.Naming.SubjectStrategy == "llm" {
+		files = append(files, cfg.LLM.PromptTemplateFile)
+	}
+	for _, path := range files {
+		if path == "" {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to hash config: %w", err)
+		}
+		sum := sha256.Sum256(data)
+		settings.Files[path] = hex.EncodeToString(sum[:])
+	}
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash config: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// writeMappingTemplate writes a name mapping file pinning each mapping to its
+// planned subject
+func writeMappingTemplate(path string, mappings []models.SchemaMapping) error {
+	ptrs := make([]*models.SchemaMapping, len(mappings))
+	for i := range mappings {
+		ptrs[i] = &mappings[i]
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create mapping template: %w", err)
+	}
+	if err := mapper.WriteMappingFile(f, mapper.ScaffoldMappingFile(ptrs)); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// failureThresholdExceeded reports whether failed, out of total schemas,
+// is over migration.abort_after_failures
+func (m *Migrator) failureThresholdExceeded(failed, total int) bool {
+	value, percent, set, err := m.config.Migration.FailureThreshold()
+	if err != nil || !set {
+		return false
+	}
+	if percent {
+		return total > 0 && float64(failed)*100/float64(total) > value
+	}
+	return float64(failed) > value
+}
+
+// hasTargetCredentials reports whether the target registry can be queried
+func (m *Migrator) hasTargetCredentials() bool {
+	return m.config.ConfluentCloud.HasCredentials()
+}
+
+// countExistingSubjects checks, read-only, how many planned subjects already
+// exist in the target. Subjects that cannot be checked are not counted.
+func (m *Migrator) countExistingSubjects(ctx context.Context, mappings []models.SchemaMapping) int {
+	slog.Info("checking target for existing subjects", "subjects", len(mappings))
+
+	var existing int64
+	errors := m.workerPool.Execute(ctx, mappings, func(ctx context.Context, mapping models.SchemaMapping) error {
+		if mapping.Status == models.MappingStatusError {
+			return nil
+		}
+		subject := mapping.TargetSubject
+		if mapping.TargetContext != "" {
+			subject = mapping.TargetContext + ":" + subject
+		}
+		exists, err := m.loader.SubjectExists(ctx, subject)
+		if err != nil && !m.loader.Retryable(err) {
+			// e.g. bad credentials, which no retry will fix
+			return worker.Permanent(err)
+		}
+		if err != nil {
+			return err
+		}
+		if exists {
+			atomic.AddInt64(&existing, 1)
+		}
+		return nil
+	})
+
+	failed := 0
+	for _, err := range errors {
+		if err != nil {
+			failed++
+			slog.Debug("subject existence check failed", "error", err)
+		}
+	}
+	if failed > 0 {
+		slog.Warn("could not check some subjects in target", "failed", failed)
+	}
+
+	return int(existing)
+}
+
+// reconcile checks every planned subject against the target's subject list
+// after a real run. A subject is registered when its schema migrated in this
+// run, skipped when every version it would have registered was already in
+// the target, resumed when a checkpoint or continue_from had already covered
+// it, and failed when its schema failed or the subject is missing from the
+// target. It returns nil when the subject list can't be fetched.
+func (m *Migrator) reconcile(ctx context.Context, mappings []models.SchemaMapping, state *models.MigrationState, resumed map[string]bool) *models.ReconciliationReport {
+	list := m.loader.GetSubjects
+	for _, mapping := range mappings {
+		if mapping.TargetContext != "" {
+			list = m.loader.GetSubjectsInAllContexts
+			break
+		}
+	}
+	subjects, err := list(ctx)
+	if err != nil {
+		slog.Warn("could not list target subjects for reconciliation", "error", err)
+		return nil
+	}
+	existing := make(map[string]bool, len(subjects))
+	for _, subject := range subjects {
+		existing[unqualifiedSubject(subject)] = true
+	}
+
+	rec := &models.ReconciliationReport{}
+	for i := range mappings {
+		mapping := &mappings[i]
+		key := fmt.Sprintf("%s:%s", mapping.SourceRegistry, mapping.SourceSchemaName)
+		done, completed := state.CompletedSchemas[key]
+		failure, failed := state.FailedSchemas[key]
+		preexisting := done.Versions > 0 && len(done.SkippedExistingVersions) == done.Versions
+
+		for _, target := range registrationTargets(mapping) {
+			entry := models.ReconciledSubject{
+				Subject:      fullSubject(target),
+				SourceSchema: mapping.SourceRegistry + "." + mapping.SourceSchemaName,
+			}
+			found := existing[entry.Subject]
+			switch {
+			case resumed[key] && found:
+				entry.Status = "resumed"
+				entry.Reason = "already migrated"
+			case resumed[key]:
+				entry.Status = "failed"
+				entry.Reason = "already migrated but not found in target"
+			case mapping.Status == models.MappingStatusSkipped && found:
+				entry.Status = "skipped"
+				entry.Reason = "unchanged since migration.since"
+			case mapping.Status == models.MappingStatusSkipped:
+				entry.Status = "failed"
+				entry.Reason = "unchanged since migration.since but not found in target"
+			case completed && found && preexisting:
+				entry.Status = "skipped"
+				entry.Reason = "every version already in target"
+			case completed && found:
+				entry.Status = "registered"
+			case completed:
+				entry.Status = "failed"
+				entry.Reason = "registered but not found in target"
+			case failed:
+				entry.Status = "failed"
+				entry.Reason = failure.Error
+			default:
+				entry.Status = "failed"
+				entry.Reason = "not migrated"
+				if mapping.Error != "" {
+					entry.Reason += ": " + mapping.Error
+				}
+			}
+
+			switch entry.Status {
+			case "registered":
+				rec.Registered++
+			case "skipped":
+				rec.Skipped++
+			case "resumed":
+				rec.Resumed++
+			default:
+				rec.Failed++
+			}
+			rec.Subjects = append(rec.Subjects, entry)
+		}
+	}
+
+	slog.Info("reconciled planned subjects against target", "registered", rec.Registered, "skipped", rec.Skipped, "resumed", rec.Resumed, "failed", rec.Failed)
+	return rec
+}
+
+// unqualifiedSubject strips the leading colon Schema Registry puts on
+// context-qualified subjects, so ":.payments:orders-value" compares equal to
+// the ".payments:orders-value" the migrator registers
+func unqualifiedSubject(subject string) string {
+	if strings.HasPrefix(subject, ":.") {
+		return subject[1:]
+	}
+	return subject
+}
+
 func (m *Migrator) countRegistries(schemas []*models.GlueSchema) int {
 	registries := make(map[string]bool)
 	for _, s := range schemas {
@@ -313,10 +1117,11 @@ func (m *Migrator) countRegistries(schemas []*models.GlueSchema) int {
 	return len(registries)
 }
 
-func (m *Migrator) createPlan(schemas []*models.GlueSchema, mappings []*models.SchemaMapping, levels []graph.Level) *models.MigrationPlan {
+func (m *Migrator) createPlan(schemas []*models.GlueSchema, mappings []*models.SchemaMapping, levels []graph.Level, collisions []models.Collision) *models.MigrationPlan {
 	plan := &models.MigrationPlan{
 		SourceRegistries: m.getRegistryNames(schemas),
 		TotalSchemas:     len(schemas),
+		Collisions:       collisions,
 	}
 
 	// Count versions and references
@@ -339,10 +1144,81 @@ func (m *Migrator) createPlan(schemas []*models.GlueSchema, mappings []*models.S
 
 	// Calculate summary
 	plan.Summary = m.calculateSummary(plan)
+	plan.Summary.APICalls = m.estimateAPICalls(schemas, mappings)
 
 	return plan
 }
 
+// estimateAPICalls approximates the calls migrating the mapped schemas will
+// make, following the same version selection and compatibility rules as
+// migrateSchema. Schemas whose mapping failed are not migrated.
+func (m *Migrator) estimateAPICalls(schemas []*models.GlueSchema, mappings []*models.SchemaMapping) models.APICallEstimate {
+	sources := make(map[string]*models.GlueSchema, len(schemas))
+	for _, s := range schemas {
+		sources[fmt.Sprintf("%s:%s", s.RegistryName, s.Name)] = s
+	}
+
+	var est models.APICallEstimate
+	for _, mapping := range mappings {
+		if mapping.Status == models.MappingStatusError || mapping.Status == models.MappingStatusSkipped {
+			continue
+		}
+		schema, ok := sources[fmt.Sprintf("%s:%s", mapping.SourceRegistry, mapping.SourceSchemaName)]
+		if !ok {
+			continue
+		}
+
+		versions := schema.Versions
+		if m.config.Migration.VersionStrategy == "latest" && len(versions) > 0 {
+			versions = versions[len(versions)-1:]
+		}
+		versions, _ = m.capVersions(versions)
+
+		// Planning makes GetSchema and ListSchemaVersions calls, then one
+		// GetSchemaVersion per version within max_versions_per_schema, or
+		// only the latest with lazy_definitions. migrateSchema reads the
+		// schema again as it registers it. An export directory makes no
+		// Glue calls.
+		if m.config.AWS.SourceDir == "" {
+			planned, _ := m.capVersions(schema.Versions)
+			fetched := len(planned)
+			if m.config.AWS.LazyDefinitions {
+				fetched = min(fetched, 1)
+			}
+			est.GlueCalls += 2 + fetched + 2 + len(versions)
+			if extractor.FetchesTags(m.config) && schema.ARN != "" {
+				est.GlueCalls++
+			}
+		}
+		if m.config.Migration.SkipDocOnlyVersions && schema.DataFormat == models.SchemaTypeAvro {
+			versions, _ = dropDocOnlyVersions(versions)
+		}
+
+		targets := len(registrationTargets(mapping))
+		est.Registrations += targets * len(versions)
+		if m.targetCompatibility(mapping.SourceCompatibility) != "" {
+			est.CompatibilityCalls += targets
+		}
+		if m.config.Migration.PrecheckCompatibility && len(versions) > 0 {
+			est.PrecheckCalls += 2 * targets
+		}
+		if m.config.Migration.ImportMode {
+			// Mode and existence lookups, then the switch and the restore
+			est.ModeCalls += 4 * targets
+		}
+		if m.config.Migration.SkipExisting {
+			est.LookupCalls += targets * len(versions)
+		}
+		if m.checkpoint != nil {
+			est.LookupCalls += targets
+		}
+		if m.subjectMetadata(schema) != nil {
+			est.MetadataCalls += targets
+		}
+	}
+	return est
+}
+
 func (m *Migrator) getRegistryNames(schemas []*models.GlueSchema) []string {
 	registryMap := make(map[string]bool)
 	for _, s := range schemas {
@@ -384,21 +1260,22 @@ type levelResult struct {
 	Successful int
 	Failed     int
 	Skipped    int
+	NotStarted int
 	Errors     []error
 }
 
-func (m *Migrator) migrateLevel(ctx context.Context, level graph.Level, state *models.MigrationState) (*levelResult, error) {
+func (m *Migrator) migrateLevel(ctx context.Context, level graph.Level, state *models.MigrationState, skip map[string]bool) (*levelResult, error) {
 	result := &levelResult{}
 
 	// Filter schemas that need to be migrated
 	var toMigrate []models.SchemaMapping
 	for _, mapping := range level.Schemas {
 		key := fmt.Sprintf("%s:%s", mapping.SourceRegistry, mapping.SourceSchemaName)
-		if _, completed := state.CompletedSchemas[key]; completed {
+		if _, completed := state.CompletedSchemas[key]; completed || skip[key] {
 			result.Skipped++
 			continue
 		}
-		if mapping.Status == models.MappingStatusError {
+		if mapping.Status == models.MappingStatusError || mapping.Status == models.MappingStatusSkipped {
 			result.Skipped++
 			continue
 		}
@@ -408,12 +1285,19 @@ func (m *Migrator) migrateLevel(ctx context.Context, level graph.Level, state *m
 	if len(toMigrate) == 0 {
 		return result, nil
 	}
+	if ctx.Err() != nil {
+		result.NotStarted = len(toMigrate)
+		return result, nil
+	}
+
+	// ETA and throughput only make sense on an interactive terminal
+	interactive := m.config.Output.Decorative() && isTerminal(os.Stdout)
 
 	// Create progress bar for schema registration
-	bar := progressbar.NewOptions(len(toMigrate),
-		progressbar.OptionSetDescription("      Registering schemas"),
+	progress := worker.NewProgress(m.config.Output, "Registering schemas", len(toMigrate),
 		progressbar.OptionSetWidth(50),
 		progressbar.OptionShowCount(),
+		progressbar.OptionSetPredictTime(interactive),
 		progressbar.OptionSetTheme(progressbar.Theme{
 			Saucer:        "=",
 			SaucerHead:    ">",
@@ -427,19 +1311,39 @@ func (m *Migrator) migrateLevel(ctx context.Context, level graph.Level, state *m
 	var completed int64
 	progressCallback := func() {
 		atomic.AddInt64(&completed, 1)
-		bar.Add(1)
+		progress.Add(1)
 	}
 
 	// Execute migrations using worker pool with progress
+	levelStart := time.Now()
+	compatApplied := m.applyCompatibility(ctx, toMigrate)
 	errors := m.workerPool.ExecuteWithProgress(ctx, toMigrate, func(ctx context.Context, mapping models.SchemaMapping) error {
-		return m.migrateSchema(ctx, &mapping, state)
+		key := fmt.Sprintf("%s:%s", mapping.SourceRegistry, mapping.SourceSchemaName)
+		start := time.Now()
+		m.logSchemaEvent("schema migration started", &mapping)
+		err := m.migrateSchemaWithTimeout(ctx, &mapping, state, compatApplied[key])
+		if err != nil {
+			m.logSchemaEvent("schema migration finished", &mapping, "status", "failed", "duration_ms", time.Since(start).Milliseconds(), "error", err)
+		} else {
+			m.logSchemaEvent("schema migration finished", &mapping, "status", "migrated", "duration_ms", time.Since(start).Milliseconds())
+		}
+		if err != nil && !m.loader.Retryable(err) {
+			return worker.Permanent(err)
+		}
+		return err
 	}, progressCallback)
 
-	bar.Finish()
-	fmt.Println()
+	progress.Finish()
+	if interactive {
+		fmt.Printf("      Throughput: %.1f schemas/sec\n", throughput(atomic.LoadInt64(&completed), time.Since(levelStart)))
+	}
 
 	// Collect results and print errors immediately
 	for i, err := range errors {
+		if worker.IsNotStarted(err) {
+			result.NotStarted++
+			continue
+		}
 		if err != nil {
 			result.Failed++
 			result.Errors = append(result.Errors, err)
@@ -452,29 +1356,132 @@ func (m *Migrator) migrateLevel(ctx context.Context, level graph.Level, state *m
 			result.Successful++
 		}
 	}
-	if result.Failed > 0 {
+	if result.Failed > 0 && m.config.Output.Decorative() {
 		fmt.Println()
 	}
 
 	return result, nil
 }
 
-func (m *Migrator) migrateSchema(ctx context.Context, mapping *models.SchemaMapping, state *models.MigrationState) error {
-	key := fmt.Sprintf("%s:%s", mapping.SourceRegistry, mapping.SourceSchemaName)
+// logSchemaEvent logs a per-schema event with the schema's registry, name and
+// subject. These are only emitted with output.json_logs, where each schema
+// attempt is one structured record; text logs stay one line per step.
+func (m *Migrator) logSchemaEvent(msg string, mapping *models.SchemaMapping, args ...any) {
+	if !m.config.Output.JSONLogs {
+		return
+	}
+	attrs := append([]any{
+		"registry", mapping.SourceRegistry,
+		"schema", mapping.SourceSchemaName,
+		"subject", fullSubject(mapping),
+	}, args...)
+	slog.Info(msg, attrs...)
+}
 
-	// Get the full schema data
-	schema, err := m.extractor.GetSchema(ctx, mapping.SourceRegistry, mapping.SourceSchemaName)
+// throughput returns the processing rate in items per second
+func throughput(completed int64, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(completed) / elapsed.Seconds()
+}
+
+// isTerminal reports whether f is attached to a character device
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
 	if err != nil {
-		state.FailedSchemas[key] = models.FailedSchema{
-			SourceRegistry: mapping.SourceRegistry,
-			SourceSchema:   mapping.SourceSchemaName,
-			Error:          err.Error(),
-			Attempts:       1,
-			LastAttempt:    time.Now(),
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// followupLimit returns concurrency.followup_concurrency, defaulting to the
+// number of workers
+func followupLimit(cfg *config.Config) int {
+	if cfg.Concurrency.FollowupConcurrency > 0 {
+		return cfg.Concurrency.FollowupConcurrency
+	}
+	return max(cfg.Concurrency.Workers, 1)
+}
+
+// applyCompatibility sets the compatibility level of every subject in a
+// level up front, with up to concurrency.followup_concurrency calls in flight,
+// instead of one call at a time inside each schema's worker. It returns the
+// schemas whose subjects were all configured; the others set compatibility
+// inline in migrateSchema, where failures are retried and recorded.
+func (m *Migrator) applyCompatibility(ctx context.Context, mappings []models.SchemaMapping) map[string]bool {
+	// The compatibility pre-check must see the target as it was, so leave
+	// compatibility to migrateSchema, which sets it after the check
+	if m.config.Migration.PrecheckCompatibility {
+		return nil
+	}
+
+	var mu sync.Mutex
+	applied := make(map[string]bool)
+
+	var g errgroup.Group
+	g.SetLimit(followupLimit(m.config))
+	for i := range mappings {
+		mapping := &mappings[i]
+		key := fmt.Sprintf("%s:%s", mapping.SourceRegistry, mapping.SourceSchemaName)
+		compat := m.targetCompatibility(mapping.SourceCompatibility)
+		if compat == "" {
+			continue
 		}
+
+		g.Go(func() error {
+			for _, target := range registrationTargets(mapping) {
+				if err := m.loader.SetCompatibility(ctx, fullSubject(target), compat); err != nil {
+					slog.Debug("compatibility pre-pass failed, retrying inline", "schema", key, "error", err)
+					return nil
+				}
+			}
+			mu.Lock()
+			applied[key] = true
+			mu.Unlock()
+			return nil
+		})
+	}
+	g.Wait()
+
+	return applied
+}
+
+// migrateSchemaWithTimeout runs migrateSchema under its own deadline when
+// migration.per_schema_timeout is set, so a stuck schema fails on its own
+// without waiting for the run's context
+func (m *Migrator) migrateSchemaWithTimeout(ctx context.Context, mapping *models.SchemaMapping, state *models.MigrationState, compatApplied bool) error {
+	timeout := m.config.Migration.PerSchemaTimeout
+	if timeout <= 0 {
+		return m.migrateSchema(ctx, mapping, state, compatApplied)
+	}
+
+	schemaCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	err := m.migrateSchema(schemaCtx, mapping, state, compatApplied)
+	if err != nil && ctx.Err() == nil && errors.Is(schemaCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("schema timed out after %s: %w", timeout, err)
+	}
+	return err
+}
+
+func (m *Migrator) migrateSchema(ctx context.Context, mapping *models.SchemaMapping, state *models.MigrationState, compatApplied bool) error {
+	key := fmt.Sprintf("%s:%s", mapping.SourceRegistry, mapping.SourceSchemaName)
+
+	// Get the full schema data; only the latest definition is needed when
+	// only the latest version is migrated
+	getSchema := m.extractor.GetSchema
+	if m.config.Migration.VersionStrategy == "latest" {
+		getSchema = m.extractor.GetLatestSchema
+	}
+	schema, err := getSchema(ctx, mapping.SourceRegistry, mapping.SourceSchemaName)
+	if err != nil {
+		m.recordFailure(state, key, mapping, err)
 		return fmt.Errorf("failed to get schema %s: %w", key, err)
 	}
 
+	mapping.SourceTags = schema.Tags
+
 	// Register each version in order
 	versions := schema.Versions
 	if m.config.Migration.VersionStrategy == "latest" {
@@ -483,34 +1490,355 @@ func (m *Migrator) migrateSchema(ctx context.Context, mapping *models.SchemaMapp
 			versions = versions[len(versions)-1:]
 		}
 	}
+	versions, skippedOlder := m.capVersions(versions)
+	if skippedOlder > 0 {
+		slog.Info("skipping older versions beyond max_versions_per_schema", "schema", key, "skipped", skippedOlder)
+	}
+
+	if m.config.Migration.CanonicalizeDefinitions && canonicalizable(schema.DataFormat) {
+		versions = canonicalizeVersions(key, versions)
+	}
+
+	var skippedDocOnly []int64
+	if m.config.Migration.SkipDocOnlyVersions && schema.DataFormat == models.SchemaTypeAvro {
+		versions, skippedDocOnly = dropDocOnlyVersions(versions)
+		if len(skippedDocOnly) > 0 {
+			slog.Info("skipping documentation-only versions", "schema", key, "versions", skippedDocOnly)
+		}
+	}
+
+	if m.config.Migration.PrecheckCompatibility && len(versions) > 0 {
+		for _, target := range registrationTargets(mapping) {
+			if err := m.precheckCompatibility(ctx, target, &versions[len(versions)-1]); err != nil {
+				m.recordFailure(state, key, mapping, err)
+				return err
+			}
+		}
+	}
+
+	var skippedExisting []int64
+	var preexisting map[string]int
+	for _, target := range registrationTargets(mapping) {
+		// Rollback reads the checkpoint, so the subject's latest version is
+		// only needed when there is one to record it in
+		if m.checkpoint != nil {
+			subject := fullSubject(target)
+			latest, err := m.loader.GetLatestVersion(ctx, subject)
+			if err != nil {
+				m.recordFailure(state, key, mapping, err)
+				return fmt.Errorf("failed to get latest version of %s: %w", subject, err)
+			}
+			if latest > 0 {
+				if preexisting == nil {
+					preexisting = make(map[string]int)
+				}
+				preexisting[subject] = latest
+			}
+		}
 
-	for _, version := range versions {
-		err := m.loader.RegisterSchema(ctx, mapping, &version)
+		// Apply compatibility before registering so older versions are accepted
+		// under the same rules they were written with in Glue
+		if compat := m.targetCompatibility(mapping.SourceCompatibility); compat != "" && !compatApplied {
+			subject := fullSubject(target)
+			if err := m.loader.SetCompatibility(ctx, subject, compat); err != nil {
+				m.dumpFailure(mapping, subject, versions, err)
+				m.recordFailure(state, key, mapping, err)
+				return fmt.Errorf("failed to set compatibility for %s: %w", key, err)
+			}
+		}
+
+		// IMPORT mode must be on before the first registration so explicit
+		// IDs are accepted, and the previous mode is restored once the
+		// subject is loaded
+		var previousMode string
+		if m.config.Migration.ImportMode {
+			subject := fullSubject(target)
+			previousMode, err = m.enterImportMode(ctx, subject)
+			if err != nil {
+				m.recordFailure(state, key, mapping, err)
+				return fmt.Errorf("failed to set IMPORT mode for %s: %w", subject, err)
+			}
+		}
+
+		existing, err := m.registerVersions(ctx, key, mapping, target, versions)
 		if err != nil {
-			state.FailedSchemas[key] = models.FailedSchema{
-				SourceRegistry: mapping.SourceRegistry,
-				SourceSchema:   mapping.SourceSchemaName,
-				Error:          err.Error(),
-				Attempts:       1,
-				LastAttempt:    time.Now(),
+			m.recordFailure(state, key, mapping, err)
+			if m.config.Migration.ImportMode {
+				subject := fullSubject(target)
+				if err := m.restoreMode(ctx, subject, previousMode); err != nil {
+					slog.Warn("subject left in IMPORT mode after a failed registration", "subject", subject, "error", err)
+				}
+			}
+			return err
+		}
+		// Recorded once per version, against the primary subject
+		if target == mapping {
+			skippedExisting = existing
+		}
+
+		if m.config.Migration.ImportMode {
+			subject := fullSubject(target)
+			if err := m.restoreMode(ctx, subject, previousMode); err != nil {
+				m.recordFailure(state, key, mapping, err)
+				return fmt.Errorf("failed to restore %s mode for %s: %w", previousMode, subject, err)
 			}
-			return fmt.Errorf("failed to register version %d of %s: %w", version.VersionNumber, key, err)
 		}
 	}
 
-	// Mark as completed
+	if metadata := m.subjectMetadata(schema); metadata != nil {
+		if err := m.applyMetadata(ctx, mapping, metadata); err != nil {
+			m.recordFailure(state, key, mapping, err)
+			return err
+		}
+	}
+
+	// Mark as completed, clearing any failure from an earlier attempt
+	m.stateMu.Lock()
+	defer m.stateMu.Unlock()
+	delete(state.FailedSchemas, key)
 	state.CompletedSchemas[key] = models.CompletedSchema{
 		SourceRegistry: mapping.SourceRegistry,
 		SourceSchema:   mapping.SourceSchemaName,
+		TargetContext:  mapping.TargetContext,
 		TargetSubject:  mapping.TargetSubject,
+		KeySubject:     mapping.KeySubject,
 		Versions:       len(versions),
 		CompletedAt:    time.Now(),
+
+		SkippedDocOnlyVersions:  skippedDocOnly,
+		SkippedOlderVersions:    skippedOlder,
+		SkippedExistingVersions: skippedExisting,
+		PreexistingVersions:     preexisting,
 	}
 	state.CompletedCount++
 
 	return nil
 }
 
+// applyMetadata sets metadata on each of mapping's subjects concurrently,
+// holding a followupSem slot for each call so at most
+// concurrency.followup_concurrency are in flight across all workers
+func (m *Migrator) applyMetadata(ctx context.Context, mapping *models.SchemaMapping, metadata *models.SubjectMetadata) error {
+	var g errgroup.Group
+	for _, target := range registrationTargets(mapping) {
+		subject := fullSubject(target)
+		g.Go(func() error {
+			select {
+			case m.followupSem <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			defer func() { <-m.followupSem }()
+
+			if err := m.loader.SetMetadata(ctx, subject, metadata); err != nil {
+				return fmt.Errorf("failed to set metadata for %s: %w", subject, err)
+			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
+// capVersions keeps only the most recent max_versions_per_schema versions
+// when migrating all versions, returning them in order with the number of
+// older versions dropped
+func (m *Migrator) capVersions(versions []models.GlueSchemaVersion) ([]models.GlueSchemaVersion, int) {
+	limit := m.config.Migration.MaxVersionsPerSchema
+	if m.config.Migration.VersionStrategy != "all" || limit <= 0 || len(versions) <= limit {
+		return versions, 0
+	}
+	return versions[len(versions)-limit:], len(versions) - limit
+}
+
+// modeRestoreTimeout bounds restoring a subject's mode once the migration's
+// context may already be cancelled
+const modeRestoreTimeout = 30 * time.Second
+
+// enterImportMode switches subject to IMPORT and returns the mode to restore
+// afterwards: the subject's own mode, or READWRITE if it inherits the
+// registry's. A subject that already holds schemas, as on a rerun, is only
+// switched when forced.
+func (m *Migrator) enterImportMode(ctx context.Context, subject string) (string, error) {
+	mode, err := m.loader.GetMode(ctx, subject)
+	if err != nil {
+		return "", err
+	}
+	if mode == "" {
+		mode = loader.ModeReadWrite
+	}
+	if mode == loader.ModeImport {
+		return mode, nil
+	}
+
+	exists, err := m.loader.SubjectExists(ctx, subject)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		return mode, m.loader.ForceImportMode(ctx, subject)
+	}
+	return mode, m.loader.SetMode(ctx, subject, loader.ModeImport)
+}
+
+// restoreMode sets a subject back to the mode enterImportMode returned. It
+// runs even after ctx is cancelled, so an interrupted or failed registration
+// does not leave the subject closed to producers.
+func (m *Migrator) restoreMode(ctx context.Context, subject, mode string) error {
+	if mode == loader.ModeImport {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), modeRestoreTimeout)
+	defer cancel()
+	return m.loader.SetMode(ctx, subject, mode)
+}
+
+// precheckCompatibility tests version against the subject the target is
+// registered under, if that subject already exists. An incompatible schema
+// is returned as a permanent error so it is skipped without retries.
+func (m *Migrator) precheckCompatibility(ctx context.Context, target *models.SchemaMapping, version *models.GlueSchemaVersion) error {
+	subject := fullSubject(target)
+	exists, err := m.loader.SubjectExists(ctx, subject)
+	if err != nil {
+		return fmt.Errorf("failed to check subject %s: %w", subject, err)
+	}
+	if !exists {
+		return nil
+	}
+
+	compatible, messages, err := m.loader.CheckCompatibility(ctx, target, version)
+	if err != nil {
+		return err
+	}
+	if !compatible {
+		reason := "incompatible with the latest registered version"
+		if len(messages) > 0 {
+			reason = strings.Join(messages, "; ")
+		}
+		return worker.Permanent(fmt.Errorf("version %d of %s:%s is incompatible with existing subject %s: %s",
+			version.VersionNumber, target.SourceRegistry, target.SourceSchemaName, subject, reason))
+	}
+	return nil
+}
+
+// fullSubject returns the mapping's subject qualified with its context
+func fullSubject(mapping *models.SchemaMapping) string {
+	if mapping.TargetContext != "" {
+		return mapping.TargetContext + ":" + mapping.TargetSubject
+	}
+	return mapping.TargetSubject
+}
+
+// dumpFailure writes the definitions that failed to migrate together with the
+// server's error to output.dump_failures_dir, one file per schema, so they can
+// be inspected or replayed by hand. Write errors are logged, not returned.
+func (m *Migrator) dumpFailure(mapping *models.SchemaMapping, subject string, versions []models.GlueSchemaVersion, cause error) {
+	dir := m.config.Output.DumpFailuresDir
+	if dir == "" {
+		return
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "registry: %s\n", mapping.SourceRegistry)
+	fmt.Fprintf(&b, "schema:   %s\n", mapping.SourceSchemaName)
+	fmt.Fprintf(&b, "subject:  %s\n", subject)
+	fmt.Fprintf(&b, "error:    %s\n", cause)
+	for _, v := range versions {
+		fmt.Fprintf(&b, "\n--- version %d ---\n%s\n", v.VersionNumber, v.Definition)
+	}
+
+	name := dumpFileName.ReplaceAllString(mapping.SourceRegistry+"_"+mapping.SourceSchemaName, "_") + ".txt"
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		slog.Warn("failed to create failure dump directory", "dir", dir, "error", err)
+		return
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		slog.Warn("failed to write failure dump", "file", path, "error", err)
+	}
+}
+
+// dumpFileName matches characters not safe in failure dump file names
+var dumpFileName = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+// dropDocOnlyVersions removes versions that differ from their predecessor
+// only in doc attributes, returning the kept versions and the skipped numbers
+func dropDocOnlyVersions(versions []models.GlueSchemaVersion) ([]models.GlueSchemaVersion, []int64) {
+	if len(versions) < 2 {
+		return versions, nil
+	}
+	kept := []models.GlueSchemaVersion{versions[0]}
+	var skipped []int64
+	for i := 1; i < len(versions); i++ {
+		if compare.DocOnlyChange(versions[i-1].Definition, versions[i].Definition) {
+			skipped = append(skipped, versions[i].VersionNumber)
+			continue
+		}
+		kept = append(kept, versions[i])
+	}
+	return kept, skipped
+}
+
+// canonicalizable reports whether definitions in format are JSON documents
+// that migration.canonicalize_definitions can re-encode
+func canonicalizable(format models.SchemaType) bool {
+	return format == models.SchemaTypeAvro || format == models.SchemaTypeJSON
+}
+
+// canonicalizeVersions returns copies of versions with their definitions
+// re-encoded with sorted keys. A definition that doesn't parse is kept as
+// authored and left for the registry to reject.
+func canonicalizeVersions(key string, versions []models.GlueSchemaVersion) []models.GlueSchemaVersion {
+	out := make([]models.GlueSchemaVersion, len(versions))
+	for i, v := range versions {
+		if canonical, err := compare.CanonicalJSON(v.Definition); err == nil {
+			v.Definition = canonical
+		} else {
+			slog.Warn("could not canonicalize definition", "schema", key, "version", v.VersionNumber, "error", err)
+		}
+		out[i] = v
+	}
+	return out
+}
+
+// registrationTargets returns the mappings a schema is registered under: the
+// mapping itself, plus a key-role copy when it also has a key subject
+func registrationTargets(mapping *models.SchemaMapping) []*models.SchemaMapping {
+	if mapping.KeySubject == "" {
+		return []*models.SchemaMapping{mapping}
+	}
+	keyMapping := *mapping
+	keyMapping.TargetSubject = mapping.KeySubject
+	keyMapping.KeySubject = ""
+	keyMapping.DetectedRole = models.SchemaRoleKey
+	return []*models.SchemaMapping{mapping, &keyMapping}
+}
+
+// targetCompatibility returns the Confluent compatibility level for a Glue
+// compatibility mode. An explicit Glue value (including NONE) is kept; the
+// configured default is used when Glue has none set, or for every subject
+// when migration.preserve_compatibility is off.
+func (m *Migrator) targetCompatibility(compatibility string) string {
+	if !m.config.Migration.PreserveCompatibility {
+		return m.config.Migration.DefaultCompatibility
+	}
+	switch compatibility {
+	case "":
+		return m.config.Migration.DefaultCompatibility
+	case "DISABLED":
+		// Glue's DISABLED blocks new versions; Confluent has no equivalent
+		// level, so leave the target's default in place
+		return ""
+	case "BACKWARD_ALL":
+		return "BACKWARD_TRANSITIVE"
+	case "FORWARD_ALL":
+		return "FORWARD_TRANSITIVE"
+	case "FULL_ALL":
+		return "FULL_TRANSITIVE"
+	default:
+		return compatibility
+	}
+}
+
 func (m *Migrator) printDryRunReport(plan *models.MigrationPlan) {
 	fmt.Println()
 	fmt.Println("╔═══════════════════════════════════════════════════════════════════════════════╗")
@@ -541,6 +1869,8 @@ func (m *Migrator) printDryRunReport(plan *models.MigrationPlan) {
 			status = "[WARN]"
 		} else if mapping.Status == models.MappingStatusError {
 			status = "[ERR]"
+		} else if mapping.Status == models.MappingStatusSkipped {
+			status = "[SKIP]"
 		}
 		
 		// Format target subject with context (only add prefix if context is not empty)
@@ -556,9 +1886,28 @@ func (m *Migrator) printDryRunReport(plan *models.MigrationPlan) {
 			targetSubject,
 			mapping.NamingStrategy,
 		)
+		if mapping.KeySubject != "" {
+			keySubject := mapping.KeySubject
+			if mapping.TargetContext != "" {
+				keySubject = mapping.TargetContext + ":" + keySubject
+			}
+			fmt.Printf("      also as key: %s\n", keySubject)
+		}
+		if mapping.Warning != "" {
+			fmt.Printf("      warning: %s\n", mapping.Warning)
+		}
 	}
 	fmt.Println()
 
+	if len(plan.DeadLetters) > 0 {
+		fmt.Println("DEAD LETTERS (not migrated)")
+		fmt.Println("───────────────────────────")
+		for _, d := range plan.DeadLetters {
+			fmt.Printf("  [SKIP] %s.%s: %s\n", d.SourceRegistry, d.SourceSchema, d.Reason)
+		}
+		fmt.Println()
+	}
+
 	// Summary
 	fmt.Println("SUMMARY")
 	fmt.Println("───────")
@@ -569,10 +1918,75 @@ func (m *Migrator) printDryRunReport(plan *models.MigrationPlan) {
 	fmt.Printf("  Ready:          %d [OK]\n", plan.Summary.Ready)
 	fmt.Printf("  Warnings:       %d [WARN]\n", plan.Summary.Warnings)
 	fmt.Printf("  Errors:         %d [ERR]\n", plan.Summary.Errors)
+	if plan.Summary.TargetChecked {
+		fmt.Printf("  Existing:       %d (already in target)\n", plan.Summary.AlreadyExists)
+	}
 	fmt.Println()
+
+	// API call estimate
+	calls := plan.Summary.APICalls
+	fmt.Println("ESTIMATED API CALLS")
+	fmt.Println("───────────────────")
+	fmt.Printf("  Glue:           %d\n", calls.GlueCalls)
+	fmt.Printf("  Registrations:  %d\n", calls.Registrations)
+	fmt.Printf("  Compatibility:  %d\n", calls.CompatibilityCalls)
+	if m.config.Migration.PrecheckCompatibility {
+		fmt.Printf("  Pre-checks:     %d\n", calls.PrecheckCalls)
+	}
+	if m.config.Migration.ImportMode {
+		fmt.Printf("  Mode changes:   %d\n", calls.ModeCalls)
+	}
+	if calls.LookupCalls > 0 {
+		fmt.Printf("  Lookups:        %d\n", calls.LookupCalls)
+	}
+	if calls.MetadataCalls > 0 {
+		fmt.Printf("  Metadata:       %d\n", calls.MetadataCalls)
+	}
+	fmt.Printf("  Confluent:      %d total\n", calls.ConfluentCalls())
+	fmt.Println()
+
+	if m.llmNamer != nil {
+		fmt.Println("LLM USAGE")
+		fmt.Println("─────────")
+		if m.config.LLM.EstimateInDryRun {
+			fmt.Printf("  Calls:          %d estimated (none made)\n", m.llmNamer.GetEstimatedCalls())
+			fmt.Printf("  Cost:           ~$%.4f estimated\n", m.llmNamer.GetEstimatedCost())
+		} else {
+			fmt.Printf("  Calls:          %d\n", m.llmNamer.GetCallCount())
+			fmt.Printf("  Cost:           $%.4f\n", m.llmNamer.GetTotalCost())
+		}
+		fmt.Println()
+	}
+
+	if m.config.Output.ExplainCollisions {
+		printCollisionExplanations(plan.Collisions)
+	}
+
 	fmt.Println("Run without --dry-run to execute migration.")
 }
 
+// printCollisionExplanations prints how each collision was resolved
+func printCollisionExplanations(collisions []models.Collision) {
+	fmt.Println("COLLISIONS")
+	fmt.Println("──────────")
+	if len(collisions) == 0 {
+		fmt.Println("  No collisions were resolved.")
+		fmt.Println()
+		return
+	}
+	for _, c := range collisions {
+		fmt.Printf("  %s (%d sources, strategy: %s)\n", c.NormalizedName, len(c.SourceSchemas), c.Strategy)
+		for _, o := range c.Outcomes {
+			if o.Skipped {
+				fmt.Printf("    %s → skipped\n", o.SourceSchema)
+			} else {
+				fmt.Printf("    %s → %s\n", o.SourceSchema, o.FinalSubject)
+			}
+		}
+	}
+	fmt.Println()
+}
+
 func (m *Migrator) generateReport(plan *models.MigrationPlan, startTime time.Time, dryRun bool) *models.MigrationReport {
 	endTime := time.Now()
 	
@@ -605,9 +2019,15 @@ func (m *Migrator) generateReport(plan *models.MigrationPlan, startTime time.Tim
 			SchemasProcessed:    plan.Summary.Schemas,
 			VersionsProcessed:   plan.Summary.Versions,
 			Successful:          plan.Summary.Ready,
+			AlreadyExists:       plan.Summary.AlreadyExists,
 		},
 	}
 
+	if m.config.Output.ExplainCollisions {
+		report.Collisions = plan.Collisions
+	}
+	report.DeadLetters = plan.DeadLetters
+
 	// Add schema details
 	for _, mapping := range plan.Mappings {
 		schemaReport := models.SchemaReport{
@@ -615,9 +2035,12 @@ func (m *Migrator) generateReport(plan *models.MigrationPlan, startTime time.Tim
 			SourceSchema:     mapping.SourceSchemaName,
 			TargetContext:    mapping.TargetContext,
 			TargetSubject:    mapping.TargetSubject,
+			SchemaType:       string(mapping.SourceFormat),
 			DetectedRole:     mapping.DetectedRole,
 			RoleReason:       mapping.NamingReason,
 			NamingStrategy:   mapping.NamingStrategy,
+			SuggestedName:    mapping.SuggestedName,
+			ContentHash:      mapping.ContentHash,
 			Transformations:  mapping.Transformations,
 			References:       mapping.References,
 			Status:           string(mapping.Status),
@@ -630,6 +2053,22 @@ func (m *Migrator) generateReport(plan *models.MigrationPlan, startTime time.Tim
 	return report
 }
 
+// skipBefore returns the keys that precede from in order, for
+// checkpoint.continue_from. It fails when from isn't part of the migration.
+func skipBefore(order []string, from string) (map[string]bool, error) {
+	if from == "" {
+		return nil, nil
+	}
+	skip := make(map[string]bool)
+	for _, key := range order {
+		if key == from {
+			return skip, nil
+		}
+		skip[key] = true
+	}
+	return nil, fmt.Errorf("continue-from schema %s is not in the migration order", from)
+}
+
 func getMigrationOrder(levels []graph.Level) []string {
 	var order []string
 	for _, level := range levels {
@@ -5,19 +5,31 @@ package migrator
 import (
 	"context"
 	"encoding/json"
+	"bytes"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/glue"
 	gluetypes "github.com/aws/aws-sdk-go-v2/service/glue/types"
 	"github.com/akrishnanDG/glue-to-ccsr/internal/extractor"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/extractor/gluetest"
 	"github.com/akrishnanDG/glue-to-ccsr/internal/keyvalue"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/llm"
 	"github.com/akrishnanDG/glue-to-ccsr/internal/loader"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/logging"
 	"github.com/akrishnanDG/glue-to-ccsr/internal/mapper"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
 	"github.com/akrishnanDG/glue-to-ccsr/internal/normalizer"
 	"github.com/akrishnanDG/glue-to-ccsr/internal/validator"
 	"github.com/akrishnanDG/glue-to-ccsr/internal/worker"
@@ -25,100 +37,11 @@ import (
 	"golang.org/x/time/rate"
 )
 
-// mockGlueClient implements extractor.GlueAPI for integration tests.
-type mockGlueClient struct {
-	schemas map[string]map[string]*mockSchema // registry -> schema name -> data
-}
-
-type mockSchema struct {
-	definition string
-	format     gluetypes.DataFormat
-}
-
-func (m *mockGlueClient) ListRegistries(ctx context.Context, params *glue.ListRegistriesInput, optFns ...func(*glue.Options)) (*glue.ListRegistriesOutput, error) {
-	var items []gluetypes.RegistryListItem
-	seen := make(map[string]bool)
-	for reg := range m.schemas {
-		if !seen[reg] {
-			seen[reg] = true
-			items = append(items, gluetypes.RegistryListItem{
-				RegistryName: aws.String(reg),
-				RegistryArn:  aws.String("arn:aws:glue:us-east-1:123456789:" + reg),
-			})
-		}
-	}
-	return &glue.ListRegistriesOutput{Registries: items}, nil
-}
-
-func (m *mockGlueClient) GetRegistry(ctx context.Context, params *glue.GetRegistryInput, optFns ...func(*glue.Options)) (*glue.GetRegistryOutput, error) {
-	return &glue.GetRegistryOutput{
-		RegistryName: params.RegistryId.RegistryName,
-		RegistryArn:  aws.String("arn:aws:glue:us-east-1:123456789:" + aws.ToString(params.RegistryId.RegistryName)),
-	}, nil
-}
-
-func (m *mockGlueClient) ListSchemas(ctx context.Context, params *glue.ListSchemasInput, optFns ...func(*glue.Options)) (*glue.ListSchemasOutput, error) {
-	regName := aws.ToString(params.RegistryId.RegistryName)
-	var items []gluetypes.SchemaListItem
-	if schemas, ok := m.schemas[regName]; ok {
-		for name := range schemas {
-			items = append(items, gluetypes.SchemaListItem{
-				SchemaName:   aws.String(name),
-				RegistryName: aws.String(regName),
-				SchemaArn:    aws.String("arn:schema:" + name),
-			})
-		}
-	}
-	return &glue.ListSchemasOutput{Schemas: items}, nil
-}
-
-func (m *mockGlueClient) GetSchema(ctx context.Context, params *glue.GetSchemaInput, optFns ...func(*glue.Options)) (*glue.GetSchemaOutput, error) {
-	regName := aws.ToString(params.SchemaId.RegistryName)
-	schemaName := aws.ToString(params.SchemaId.SchemaName)
-	if schemas, ok := m.schemas[regName]; ok {
-		if s, ok := schemas[schemaName]; ok {
-			return &glue.GetSchemaOutput{
-				SchemaName:          aws.String(schemaName),
-				RegistryName:        aws.String(regName),
-				DataFormat:          s.format,
-				Compatibility:       gluetypes.CompatibilityBackward,
-				LatestSchemaVersion: aws.Int64(1),
-				SchemaArn:           aws.String("arn:schema:" + schemaName),
-			}, nil
-		}
-	}
-	return nil, &gluetypes.EntityNotFoundException{Message: aws.String("schema not found")}
-}
-
-func (m *mockGlueClient) ListSchemaVersions(ctx context.Context, params *glue.ListSchemaVersionsInput, optFns ...func(*glue.Options)) (*glue.ListSchemaVersionsOutput, error) {
-	return &glue.ListSchemaVersionsOutput{
-		Schemas: []gluetypes.SchemaVersionListItem{
-			{
-				SchemaVersionId: aws.String("ver-001"),
-				VersionNumber:   aws.Int64(1),
-				Status:          gluetypes.SchemaVersionStatusAvailable,
-			},
-		},
-	}, nil
-}
-
-func (m *mockGlueClient) GetSchemaVersion(ctx context.Context, params *glue.GetSchemaVersionInput, optFns ...func(*glue.Options)) (*glue.GetSchemaVersionOutput, error) {
-	// Look up the schema definition from the schema ID
-	schemaName := aws.ToString(params.SchemaId.SchemaName)
-	regName := aws.ToString(params.SchemaId.RegistryName)
-	definition := `{"type":"record","name":"Unknown","fields":[{"name":"id","type":"string"}]}`
-	if schemas, ok := m.schemas[regName]; ok {
-		if s, ok := schemas[schemaName]; ok {
-			definition = s.definition
-		}
-	}
-	return &glue.GetSchemaVersionOutput{
-		SchemaDefinition: aws.String(definition),
-		VersionNumber:    aws.Int64(1),
-		SchemaVersionId:  aws.String("ver-001"),
-		Status:           gluetypes.SchemaVersionStatusAvailable,
-	}, nil
-}
+// mockGlueClient and mockSchema serve the Glue side of each test
+type (
+	mockGlueClient = gluetest.Client
+	mockSchema     = gluetest.Schema
+)
 
 type registeredSchema struct {
 	Method  string
@@ -164,15 +87,15 @@ func TestFullMigrationPipeline(t *testing.T) {
 
 	// Set up mock Glue client with 2 schemas
 	mockClient := &mockGlueClient{
-		schemas: map[string]map[string]*mockSchema{
+		Schemas: map[string]map[string]*mockSchema{
 			"test-registry": {
 				"UserEvent": {
-					definition: `{"type":"record","name":"UserEvent","namespace":"com.example","fields":[{"name":"id","type":"string"},{"name":"name","type":"string"},{"name":"email","type":"string"}]}`,
-					format:     gluetypes.DataFormatAvro,
+					Definition: `{"type":"record","name":"UserEvent","namespace":"com.example","fields":[{"name":"id","type":"string"},{"name":"name","type":"string"},{"name":"email","type":"string"}]}`,
+					Format:     gluetypes.DataFormatAvro,
 				},
 				"OrderEvent": {
-					definition: `{"type":"record","name":"OrderEvent","namespace":"com.example","fields":[{"name":"orderId","type":"string"},{"name":"amount","type":"double"},{"name":"status","type":"string"}]}`,
-					format:     gluetypes.DataFormatAvro,
+					Definition: `{"type":"record","name":"OrderEvent","namespace":"com.example","fields":[{"name":"orderId","type":"string"},{"name":"amount","type":"double"},{"name":"status","type":"string"}]}`,
+					Format:     gluetypes.DataFormatAvro,
 				},
 			},
 		},
@@ -227,10 +150,12 @@ func TestFullMigrationPipeline(t *testing.T) {
 }
 
 func TestDryRunProducesReport(t *testing.T) {
-	// Server should NOT receive any requests in dry-run mode
+	// Server should NOT receive any write requests in dry-run mode
 	requestCount := 0
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		requestCount++
+		if r.Method != http.MethodGet {
+			requestCount++
+		}
 		w.WriteHeader(http.StatusOK)
 	}))
 	defer server.Close()
@@ -244,11 +169,11 @@ func TestDryRunProducesReport(t *testing.T) {
 	cfg.Output.DryRun = true
 
 	mockClient := &mockGlueClient{
-		schemas: map[string]map[string]*mockSchema{
+		Schemas: map[string]map[string]*mockSchema{
 			"test-registry": {
 				"TestSchema": {
-					definition: `{"type":"record","name":"TestSchema","fields":[{"name":"id","type":"string"}]}`,
-					format:     gluetypes.DataFormatAvro,
+					Definition: `{"type":"record","name":"TestSchema","fields":[{"name":"id","type":"string"}]}`,
+					Format:     gluetypes.DataFormatAvro,
 				},
 			},
 		},
@@ -275,8 +200,2892 @@ func TestDryRunProducesReport(t *testing.T) {
 		t.Error("expected report to be generated")
 	}
 
-	// No HTTP requests should have been made
+	// No write requests should have been made
 	if requestCount > 0 {
-		t.Errorf("expected 0 HTTP requests in dry-run, got %d", requestCount)
+		t.Errorf("expected 0 write requests in dry-run, got %d", requestCount)
+	}
+}
+
+func TestDryRunCountsExistingSubjects(t *testing.T) {
+	var mu sync.Mutex
+	var writes int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			mu.Lock()
+			writes++
+			mu.Unlock()
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		// Only the order subject already exists in the target
+		if strings.Contains(r.URL.Path, "order") {
+			w.Write([]byte(`[1]`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error_code":40401,"message":"Subject not found"}`))
+	}))
+	defer server.Close()
+
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{
+			"test-registry": {
+				"Order": {
+					Definition: `{"type":"record","name":"Order","fields":[{"name":"id","type":"string"}]}`,
+					Format:     gluetypes.DataFormatAvro,
+				},
+				"Customer": {
+					Definition: `{"type":"record","name":"Customer","fields":[{"name":"id","type":"string"}]}`,
+					Format:     gluetypes.DataFormatAvro,
+				},
+			},
+		},
+	}
+
+	m := newTestMigrator(t, server.URL, mockClient, func(cfg *config.Config) {
+		cfg.Output.DryRun = true
+	})
+
+	result, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("dry-run failed: %v", err)
+	}
+
+	if result.AlreadyExists != 1 {
+		t.Errorf("expected 1 existing subject, got %d", result.AlreadyExists)
+	}
+	if result.Report.Results.AlreadyExists != 1 {
+		t.Errorf("expected report already_exists 1, got %d", result.Report.Results.AlreadyExists)
+	}
+	if writes > 0 {
+		t.Errorf("expected 0 write requests in dry-run, got %d", writes)
+	}
+}
+
+func TestDryRunExistenceCheckDoesNotRetryAuthErrors(t *testing.T) {
+	var mu sync.Mutex
+	checks := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		checks[r.URL.Path]++
+		mu.Unlock()
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error_code":401,"message":"Unauthorized"}`))
+	}))
+	defer server.Close()
+
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{
+			"test-registry": {
+				"Order": {
+					Definition: `{"type":"record","name":"Order","fields":[{"name":"id","type":"string"}]}`,
+					Format:     gluetypes.DataFormatAvro,
+				},
+				"Customer": {
+					Definition: `{"type":"record","name":"Customer","fields":[{"name":"id","type":"string"}]}`,
+					Format:     gluetypes.DataFormatAvro,
+				},
+			},
+		},
+	}
+
+	m := newTestMigrator(t, server.URL, mockClient, func(cfg *config.Config) {
+		cfg.Output.DryRun = true
+		cfg.Concurrency.RetryAttempts = 3
+		cfg.Concurrency.RetryDelay = 10 * time.Millisecond
+	})
+
+	result, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("dry-run failed: %v", err)
+	}
+	if result.AlreadyExists != 0 {
+		t.Errorf("expected no existing subjects when every check fails, got %d", result.AlreadyExists)
+	}
+	if len(checks) != 2 {
+		t.Errorf("expected both subjects to be checked, got %v", checks)
+	}
+	for path, n := range checks {
+		if n != 1 {
+			t.Errorf("%s checked %d times, want once for a 401", path, n)
+		}
+	}
+}
+
+func TestDryRunWithoutCredentialsSkipsExistenceCheck(t *testing.T) {
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{
+			"test-registry": {
+				"Order": {
+					Definition: `{"type":"record","name":"Order","fields":[{"name":"id","type":"string"}]}`,
+					Format:     gluetypes.DataFormatAvro,
+				},
+			},
+		},
+	}
+
+	m := newTestMigrator(t, "", mockClient, func(cfg *config.Config) {
+		cfg.Output.DryRun = true
+	})
+
+	result, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("dry-run failed: %v", err)
+	}
+	if result.AlreadyExists != 0 {
+		t.Errorf("expected no existence count without credentials, got %d", result.AlreadyExists)
+	}
+}
+
+// runCompatibilityMigration migrates a single schema with the given Glue
+// compatibility and returns the compatibility levels PUT to the target.
+// configure, when given, adjusts the config before the run.
+func runCompatibilityMigration(t *testing.T, compat gluetypes.Compatibility, defaultCompat string, configure ...func(*config.Config)) map[string]string {
+	t.Helper()
+
+	var mu sync.Mutex
+	applied := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Method == "PUT" && strings.HasPrefix(r.URL.Path, "/config/") {
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			applied[strings.TrimPrefix(r.URL.Path, "/config/")] = body["compatibility"]
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		if r.Method == "POST" && strings.Contains(r.URL.Path, "/subjects/") {
+			w.Write([]byte(`{"id": 1}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{
+			"test-registry": {
+				"UserEvent": {
+					Definition:    `{"type":"record","name":"UserEvent","fields":[{"name":"id","type":"string"}]}`,
+					Format:        gluetypes.DataFormatAvro,
+					Compatibility: compat,
+				},
+			},
+		},
+	}
+
+	m := newTestMigrator(t, server.URL, mockClient, func(cfg *config.Config) {
+		cfg.Migration.DefaultCompatibility = defaultCompat
+		for _, fn := range configure {
+			fn(cfg)
+		}
+	})
+
+	result, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	if result.Failed != 0 {
+		t.Fatalf("expected 0 failed, got %d: %v", result.Failed, result.Errors)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	return applied
+}
+
+func TestMigrationAppliesDefaultCompatibilityWhenUnset(t *testing.T) {
+	applied := runCompatibilityMigration(t, "", "FULL")
+
+	if got := applied["user-event-value"]; got != "FULL" {
+		t.Errorf("expected default compatibility FULL for unset source, got %q", got)
+	}
+}
+
+func TestMigrationPreservesExplicitNoneCompatibility(t *testing.T) {
+	applied := runCompatibilityMigration(t, gluetypes.CompatibilityNone, "FULL")
+
+	if got := applied["user-event-value"]; got != "NONE" {
+		t.Errorf("expected explicit NONE to be preserved, got %q", got)
+	}
+}
+
+func TestMigrationTranslatesGlueCompatibility(t *testing.T) {
+	tests := []struct {
+		glue gluetypes.Compatibility
+		want string
+	}{
+		{gluetypes.CompatibilityBackward, "BACKWARD"},
+		{gluetypes.CompatibilityBackwardAll, "BACKWARD_TRANSITIVE"},
+		{gluetypes.CompatibilityForward, "FORWARD"},
+		{gluetypes.CompatibilityForwardAll, "FORWARD_TRANSITIVE"},
+		{gluetypes.CompatibilityFull, "FULL"},
+		{gluetypes.CompatibilityFullAll, "FULL_TRANSITIVE"},
+		{gluetypes.CompatibilityNone, "NONE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.glue), func(t *testing.T) {
+			applied := runCompatibilityMigration(t, tt.glue, "")
+			if got := applied["user-event-value"]; got != tt.want {
+				t.Errorf("PUT /config/user-event-value compatibility = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// Glue's DISABLED has no Confluent level, so the target keeps its default
+	if applied := runCompatibilityMigration(t, gluetypes.CompatibilityDisabled, ""); len(applied) != 0 {
+		t.Errorf("expected no compatibility PUT for DISABLED, got %v", applied)
+	}
+}
+
+func TestMigrationPreserveCompatibilityOff(t *testing.T) {
+	off := func(cfg *config.Config) { cfg.Migration.PreserveCompatibility = false }
+
+	if applied := runCompatibilityMigration(t, gluetypes.CompatibilityFullAll, "", off); len(applied) != 0 {
+		t.Errorf("expected no compatibility PUT with preserve_compatibility off, got %v", applied)
+	}
+
+	applied := runCompatibilityMigration(t, gluetypes.CompatibilityFullAll, "BACKWARD", off)
+	if got := applied["user-event-value"]; got != "BACKWARD" {
+		t.Errorf("expected default_compatibility BACKWARD over Glue's FULL_ALL, got %q", got)
+	}
+}
+
+func TestMigrationAbortsAfterFailureThreshold(t *testing.T) {
+	var mu sync.Mutex
+	var registered []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Method == "POST" && strings.Contains(r.URL.Path, "/subjects/") {
+			// Every registration in the first level fails, as with bad credentials
+			if strings.Contains(r.URL.Path, "address") {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"error_code":401,"message":"Unauthorized"}`))
+				return
+			}
+			registered = append(registered, r.URL.Path)
+			w.Write([]byte(`{"id": 1}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	// UserEvent references Address, so it lands in the second level
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{
+			"test-registry": {
+				"Address": {
+					Definition: `{"type":"record","name":"Address","fields":[{"name":"street","type":"string"}]}`,
+					Format:     gluetypes.DataFormatAvro,
+				},
+				"UserEvent": {
+					Definition: `{"type":"record","name":"UserEvent","fields":[{"name":"address","type":"Address"}]}`,
+					Format:     gluetypes.DataFormatAvro,
+				},
+			},
+		},
+	}
+
+	m := newTestMigrator(t, server.URL, mockClient, func(cfg *config.Config) {
+		cfg.Migration.AbortAfterFailures = "10%"
+		cfg.Checkpoint.File = filepath.Join(t.TempDir(), "state.json")
+	})
+
+	_, err := m.Run(context.Background())
+	if err == nil {
+		t.Fatal("expected migration to abort after exceeding the failure threshold")
+	}
+	if !strings.Contains(err.Error(), "abort_after_failures") || !strings.Contains(err.Error(), m.config.Checkpoint.File) {
+		t.Errorf("error = %q, expected it to name the threshold and the checkpoint file", err.Error())
+	}
+
+	mu.Lock()
+	if len(registered) != 0 {
+		t.Errorf("expected no registrations after the abort, got %v", registered)
+	}
+	mu.Unlock()
+
+	state, err := m.checkpoint.Load()
+	if err != nil {
+		t.Fatalf("expected checkpoint to be saved: %v", err)
+	}
+	if _, ok := state.FailedSchemas["test-registry:Address"]; !ok {
+		t.Errorf("expected Address in checkpoint failures, got %v", state.FailedSchemas)
+	}
+}
+
+func TestMigrationRegistersKeyAndValueSubjects(t *testing.T) {
+	var mu sync.Mutex
+	var registered []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Method == "POST" && strings.Contains(r.URL.Path, "/subjects/") {
+			registered = append(registered, r.URL.Path)
+			w.Write([]byte(`{"id": 1}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	mappingFile := filepath.Join(t.TempDir(), "mappings.yaml")
+	if err := os.WriteFile(mappingFile, []byte(`
+extended_mappings:
+  - source: "OrderId"
+    key_subject: "orders-key"
+    value_subject: "orders-value"
+`), 0644); err != nil {
+		t.Fatalf("failed to write mapping file: %v", err)
+	}
+
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{
+			"test-registry": {
+				"OrderId": {
+					Definition: `{"type":"record","name":"OrderId","fields":[{"name":"id","type":"string"}]}`,
+					Format:     gluetypes.DataFormatAvro,
+				},
+			},
+		},
+	}
+
+	m := newTestMigrator(t, server.URL, mockClient, func(cfg *config.Config) {
+		cfg.Naming.ContextMapping = "flat"
+		cfg.Naming.NameMappingFile = mappingFile
+	})
+
+	result, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	if result.Successful != 1 || result.Failed != 0 {
+		t.Errorf("expected 1 successful schema, got %d successful and %d failed: %v", result.Successful, result.Failed, result.Errors)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := map[string]bool{
+		"/subjects/orders-value/versions": true,
+		"/subjects/orders-key/versions":   true,
+	}
+	if len(registered) != len(want) {
+		t.Fatalf("expected registrations under both subjects, got %v", registered)
+	}
+	for _, path := range registered {
+		if !want[path] {
+			t.Errorf("unexpected registration %s", path)
+		}
+	}
+}
+
+// newTestMigrator builds a migrator that extracts from client and registers
+// against the Schema Registry at url, with credentials when url is set.
+// configure, when given, adjusts the config before the components are built;
+// a configured checkpoint file is saved to during the run.
+func newTestMigrator(t *testing.T, url string, client extractor.GlueAPI, configure ...func(*config.Config)) *Migrator {
+	t.Helper()
+
+	cfg := config.NewDefaultConfig()
+	cfg.AWS.Region = "us-east-1"
+	cfg.AWS.RegistryAll = true
+	if url != "" {
+		cfg.ConfluentCloud.URL = url
+		cfg.ConfluentCloud.APIKey = "test-key"
+		cfg.ConfluentCloud.APISecret = "test-secret"
+	}
+	cfg.Concurrency.RetryAttempts = 0
+	for _, fn := range configure {
+		fn(cfg)
+	}
+
+	limiter := rate.NewLimiter(rate.Limit(1000), 1)
+	ext := extractor.NewWithClient(cfg, client, limiter)
+	ldr, err := loader.New(cfg)
+	if err != nil {
+		t.Fatalf("failed to create loader: %v", err)
+	}
+	norm := normalizer.New(cfg)
+	kvDet, err := keyvalue.New(cfg)
+	if err != nil {
+		t.Fatalf("failed to create key/value detector: %v", err)
+	}
+	mpr, err := mapper.New(cfg, norm, kvDet, nil)
+	if err != nil {
+		t.Fatalf("failed to create mapper: %v", err)
+	}
+
+	m := NewWithDeps(cfg, ext, ldr, mpr, norm, kvDet, validator.New(cfg), worker.NewPool(cfg))
+	if cfg.Checkpoint.File != "" {
+		m.checkpoint = worker.NewCheckpointManager(cfg.Checkpoint.File)
+	}
+	return m
+}
+
+// newResumeMigrator builds a migrator for one schema that resumes from the
+// given checkpoint file
+func newResumeMigrator(t *testing.T, url, checkpointFile string, force bool) *Migrator {
+	t.Helper()
+
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{
+			"test-registry": {
+				"UserEvent": {
+					Definition: `{"type":"record","name":"UserEvent","fields":[{"name":"id","type":"string"}]}`,
+					Format:     gluetypes.DataFormatAvro,
+				},
+			},
+		},
+	}
+
+	m := newTestMigrator(t, url, mockClient, func(cfg *config.Config) {
+		cfg.Checkpoint.File = checkpointFile
+		cfg.Checkpoint.Resume = true
+		cfg.Checkpoint.Force = force
+	})
+	return m
+}
+
+func TestResumeRefusesMismatchedTargetURL(t *testing.T) {
+	var mu sync.Mutex
+	var posts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == "POST" {
+			posts++
+			w.Write([]byte(`{"id": 1}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	checkpointFile := filepath.Join(t.TempDir(), "state.json")
+	state := models.NewMigrationState("")
+	state.TargetURLHash = hashTargetURL("https://psrc-other.us-east-2.aws.confluent.cloud")
+	if err := worker.NewCheckpointManager(checkpointFile).Save(state); err != nil {
+		t.Fatalf("failed to write checkpoint: %v", err)
+	}
+
+	_, err := newResumeMigrator(t, server.URL, checkpointFile, false).Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "refusing to resume") {
+		t.Fatalf("expected resume against a different target to be refused, got %v", err)
+	}
+	mu.Lock()
+	if posts != 0 {
+		t.Errorf("expected no registrations after refusing to resume, got %d", posts)
+	}
+	mu.Unlock()
+
+	// --force resumes anyway and records the new target
+	if _, err := newResumeMigrator(t, server.URL, checkpointFile, true).Run(context.Background()); err != nil {
+		t.Fatalf("expected forced resume to succeed, got %v", err)
+	}
+	saved, err := worker.NewCheckpointManager(checkpointFile).Load()
+	if err != nil {
+		t.Fatalf("failed to load checkpoint: %v", err)
+	}
+	if saved.TargetURLHash != hashTargetURL(server.URL) {
+		t.Errorf("expected checkpoint to record the current target URL hash")
+	}
+}
+
+func TestResumeRefusesChangedConfig(t *testing.T) {
+	var mu sync.Mutex
+	var registered []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == "POST" {
+			registered = append(registered, strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/subjects/"), "/versions"))
+			w.Write([]byte(`{"id": 1}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	// A first run records the config hash in the checkpoint
+	checkpointFile := filepath.Join(t.TempDir(), "state.json")
+	first := newResumeMigrator(t, server.URL, checkpointFile, false)
+	first.config.Checkpoint.Resume = false
+	if _, err := first.Run(context.Background()); err != nil {
+		t.Fatalf("first run failed: %v", err)
+	}
+	saved, err := worker.NewCheckpointManager(checkpointFile).Load()
+	if err != nil {
+		t.Fatalf("failed to load checkpoint: %v", err)
+	}
+	want, _ := hashConfig(first.config)
+	if saved.ConfigHash == "" || saved.ConfigHash != want {
+		t.Fatalf("checkpoint config hash = %q, want %q", saved.ConfigHash, want)
+	}
+
+	// The same config resumes, with nothing left to register
+	if _, err := newResumeMigrator(t, server.URL, checkpointFile, false).Run(context.Background()); err != nil {
+		t.Fatalf("expected resume with the same config to succeed, got %v", err)
+	}
+
+	// Settings that only change how the migration runs still resume
+	tuned := newResumeMigrator(t, server.URL, checkpointFile, false)
+	tuned.config.Migration.PerSchemaTimeout = time.Minute
+	tuned.config.Migration.SkipExisting = true
+	if _, err := tuned.Run(context.Background()); err != nil {
+		t.Fatalf("expected resume with changed run settings to succeed, got %v", err)
+	}
+
+	// Migration settings that change what is registered are refused
+	latest := newResumeMigrator(t, server.URL, checkpointFile, false)
+	latest.config.Migration.VersionStrategy = "latest"
+	_, err = latest.Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "refusing to resume") {
+		t.Fatalf("expected resume with a changed version strategy to be refused, got %v", err)
+	}
+
+	// Mapping files count by their contents, not just their paths
+	overrides := filepath.Join(t.TempDir(), "roles.yaml")
+	os.WriteFile(overrides, []byte("overrides: {}\n"), 0644)
+	withOverrides := *first.config
+	withOverrides.KeyValue.RoleOverrideFile = overrides
+	before, err := hashConfig(&withOverrides)
+	if err != nil {
+		t.Fatalf("hashConfig: %v", err)
+	}
+	os.WriteFile(overrides, []byte("overrides:\n  test-registry:UserEvent: key\n"), 0644)
+	if after, _ := hashConfig(&withOverrides); after == before {
+		t.Errorf("expected editing the role override file to change the config hash")
+	}
+
+	// A different naming strategy would change the target subjects
+	changed := newResumeMigrator(t, server.URL, checkpointFile, false)
+	changed.config.Naming.SubjectStrategy = "record"
+	_, err = changed.Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "refusing to resume") {
+		t.Fatalf("expected resume with a changed naming strategy to be refused, got %v", err)
+	}
+
+	// --force resumes anyway and records the new config
+	forced := newResumeMigrator(t, server.URL, checkpointFile, true)
+	forced.config.Naming.SubjectStrategy = "record"
+	if _, err := forced.Run(context.Background()); err != nil {
+		t.Fatalf("expected forced resume to succeed, got %v", err)
+	}
+	if saved, err = worker.NewCheckpointManager(checkpointFile).Load(); err != nil {
+		t.Fatalf("failed to load checkpoint: %v", err)
+	}
+	if want, _ := hashConfig(forced.config); saved.ConfigHash != want {
+		t.Errorf("expected the checkpoint to record the forced run's config hash")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if strings.Join(registered, ",") != "user-event-value" {
+		t.Errorf("registered %v, expected only the first run to register", registered)
+	}
+}
+
+func TestMigrationSkipsDocOnlyVersions(t *testing.T) {
+	var mu sync.Mutex
+	var schemas []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == "POST" && strings.Contains(r.URL.Path, "/subjects/") {
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			schemas = append(schemas, body["schema"].(string))
+			w.Write([]byte(`{"id": 1}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	v1 := `{"type":"record","name":"UserEvent","doc":"A user","fields":[{"name":"id","type":"string"}]}`
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{
+			"test-registry": {
+				"UserEvent": {
+					Format: gluetypes.DataFormatAvro,
+					Versions: []string{
+						v1,
+						`{"type":"record","name":"UserEvent","doc":"A user event","fields":[{"name":"id","type":"string","doc":"User ID"}]}`,
+					},
+				},
+			},
+		},
+	}
+
+	m := newTestMigrator(t, server.URL, mockClient, func(cfg *config.Config) {
+		cfg.Migration.SkipDocOnlyVersions = true
+		cfg.Checkpoint.File = filepath.Join(t.TempDir(), "state.json")
+	})
+
+	if _, err := m.Run(context.Background()); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(schemas) != 1 || schemas[0] != v1 {
+		t.Fatalf("expected only version 1 to be registered, got %v", schemas)
+	}
+
+	state, err := m.checkpoint.Load()
+	if err != nil {
+		t.Fatalf("failed to load checkpoint: %v", err)
+	}
+	skipped := state.CompletedSchemas["test-registry:UserEvent"].SkippedDocOnlyVersions
+	if len(skipped) != 1 || skipped[0] != 2 {
+		t.Errorf("expected version 2 recorded as doc-only skip, got %v", skipped)
+	}
+}
+
+func TestMigrationRecordsPreexistingVersions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/subjects/user-event-value/versions/latest":
+			w.Write([]byte(`{"subject":"user-event-value","version":3,"id":7}`))
+		case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/versions"):
+			w.Write([]byte(`{"id": 1}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error_code":40401,"message":"Subject not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{
+			"test-registry": {
+				"UserEvent": {Format: gluetypes.DataFormatAvro, Versions: []string{`{"type":"record","name":"UserEvent","fields":[]}`}},
+				"Address":   {Format: gluetypes.DataFormatAvro, Versions: []string{`{"type":"record","name":"Address","fields":[]}`}},
+			},
+		},
+	}
+
+	m := newTestMigrator(t, server.URL, mockClient, func(cfg *config.Config) {
+		cfg.Checkpoint.File = filepath.Join(t.TempDir(), "state.json")
+	})
+	if _, err := m.Run(context.Background()); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+
+	// Rollback keeps the versions a subject had before the migration, so
+	// the checkpoint records them for the subject that existed only
+	state, err := m.checkpoint.Load()
+	if err != nil {
+		t.Fatalf("failed to load checkpoint: %v", err)
+	}
+	if got := state.CompletedSchemas["test-registry:UserEvent"].PreexistingVersions; !reflect.DeepEqual(got, map[string]int{"user-event-value": 3}) {
+		t.Errorf("UserEvent preexisting versions = %v, want user-event-value at 3", got)
+	}
+	if got := state.CompletedSchemas["test-registry:Address"].PreexistingVersions; got != nil {
+		t.Errorf("Address preexisting versions = %v, want none", got)
+	}
+}
+
+func TestMigrationCapsVersionsPerSchema(t *testing.T) {
+	var mu sync.Mutex
+	var schemas []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == "POST" && strings.Contains(r.URL.Path, "/subjects/") {
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			schemas = append(schemas, body["schema"].(string))
+			w.Write([]byte(`{"id": 1}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	var versions []string
+	for i := 1; i <= 10; i++ {
+		versions = append(versions, fmt.Sprintf(`{"type":"record","name":"UserEvent","doc":"v%d","fields":[{"name":"id","type":"string"}]}`, i))
+	}
+	client := &versionCountingClient{
+		mockGlueClient: &mockGlueClient{
+			Schemas: map[string]map[string]*mockSchema{
+				"test-registry": {
+					"UserEvent": {Format: gluetypes.DataFormatAvro, Versions: versions},
+				},
+			},
+		},
+		calls: make(map[string]int),
+	}
+
+	m := newTestMigrator(t, server.URL, client, func(cfg *config.Config) {
+		cfg.Migration.MaxVersionsPerSchema = 3
+		cfg.Checkpoint.File = filepath.Join(t.TempDir(), "state.json")
+	})
+
+	if _, err := m.Run(context.Background()); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := versions[7:]
+	if len(schemas) != len(want) {
+		t.Fatalf("expected the latest 3 versions to be registered, got %d: %v", len(schemas), schemas)
+	}
+	for i := range want {
+		if schemas[i] != want[i] {
+			t.Errorf("registration %d = %s, want %s", i, schemas[i], want[i])
+		}
+	}
+
+	state, err := m.checkpoint.Load()
+	if err != nil {
+		t.Fatalf("failed to load checkpoint: %v", err)
+	}
+	completed := state.CompletedSchemas["test-registry:UserEvent"]
+	if completed.Versions != 3 || completed.SkippedOlderVersions != 7 {
+		t.Errorf("checkpoint recorded %d versions and %d skipped, want 3 and 7", completed.Versions, completed.SkippedOlderVersions)
+	}
+
+	// The older definitions are never fetched, while planning or migrating
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if got := client.calls["UserEvent"]; got != 6 {
+		t.Errorf("fetched %d UserEvent definitions, expected the latest 3 twice", got)
+	}
+}
+
+func TestMigrationJSONLogsSchemaEvents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" && strings.Contains(r.URL.Path, "/subjects/") {
+			w.Write([]byte(`{"id": 1}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(logging.NewLogger(&logs, slog.LevelInfo, true))
+	defer slog.SetDefault(prev)
+
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{
+			"test-registry": {
+				"UserEvent":  {Definition: `{"type":"record","name":"UserEvent","fields":[{"name":"id","type":"string"}]}`, Format: gluetypes.DataFormatAvro},
+				"OrderEvent": {Definition: `{"type":"record","name":"OrderEvent","fields":[{"name":"id","type":"string"}]}`, Format: gluetypes.DataFormatAvro},
+			},
+		},
+	}
+
+	m := newTestMigrator(t, server.URL, mockClient, func(cfg *config.Config) {
+		cfg.Output.JSONLogs = true
+	})
+	if _, err := m.Run(context.Background()); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+
+	runIDs := make(map[string]bool)
+	finished := make(map[string]map[string]interface{})
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("log line is not JSON: %q", line)
+		}
+		runID, _ := record["run_id"].(string)
+		runIDs[runID] = true
+		if record["msg"] == "schema migration finished" {
+			finished[record["schema"].(string)] = record
+		}
+	}
+	if len(runIDs) != 1 || runIDs[""] {
+		t.Errorf("expected every record to carry the same run_id, got %v", runIDs)
+	}
+
+	for _, schema := range []string{"UserEvent", "OrderEvent"} {
+		record, ok := finished[schema]
+		if !ok {
+			t.Errorf("no completion event for %s in logs:\n%s", schema, logs.String())
+			continue
+		}
+		if record["registry"] != "test-registry" || record["status"] != "migrated" {
+			t.Errorf("%s completion event = %v, want registry test-registry and status migrated", schema, record)
+		}
+		if subject, _ := record["subject"].(string); !strings.HasSuffix(subject, "-value") {
+			t.Errorf("%s completion event subject = %v", schema, record["subject"])
+		}
+		if _, ok := record["duration_ms"].(float64); !ok {
+			t.Errorf("%s completion event has no duration_ms: %v", schema, record)
+		}
+	}
+}
+
+func TestMigrationPreservesSchemaIDs(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	var ids []int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		switch {
+		case r.Method == "PUT" && strings.HasPrefix(r.URL.Path, "/mode/"):
+			requests = append(requests, "mode "+body["mode"].(string))
+			w.Write([]byte(`{}`))
+		case r.Method == "POST" && strings.Contains(r.URL.Path, "/subjects/"):
+			requests = append(requests, "register")
+			id, _ := body["id"].(float64)
+			ids = append(ids, int(id))
+			w.Write([]byte(`{"id": 1}`))
+		case r.Method == "PUT" && strings.HasPrefix(r.URL.Path, "/config/"):
+			w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{
+			"test-registry": {
+				"UserEvent": {
+					Format: gluetypes.DataFormatAvro,
+					Versions: []string{
+						`{"type":"record","name":"UserEvent","fields":[{"name":"id","type":"string"}]}`,
+						`{"type":"record","name":"UserEvent","fields":[{"name":"id","type":"string"},{"name":"email","type":["null","string"],"default":null}]}`,
+					},
+				},
+			},
+		},
+	}
+
+	m := newTestMigrator(t, server.URL, mockClient, func(cfg *config.Config) {
+		cfg.Migration.ImportMode = true
+		cfg.Migration.PreserveSchemaIDs = true
+	})
+	result, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	if result.Failed != 0 {
+		t.Fatalf("expected no failures, got %v", result.Errors)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"mode IMPORT", "register", "register", "mode READWRITE"}
+	if strings.Join(requests, ",") != strings.Join(want, ",") {
+		t.Fatalf("requests = %v, want %v", requests, want)
+	}
+	for i, n := range []int{1, 2} {
+		wantID := loader.SourceSchemaID(&models.GlueSchemaVersion{SchemaVersionID: fmt.Sprintf("ver-%03d", n)})
+		if ids[i] != wantID {
+			t.Errorf("version %d registered with id %d, want %d", n, ids[i], wantID)
+		}
+	}
+	if ids[0] == ids[1] {
+		t.Errorf("expected distinct IDs per version, got %v", ids)
+	}
+}
+
+func TestMigrationRestoresPreviousMode(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+
+	// On a rerun the subject already holds schemas and has its own mode
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/mode/user-event-value":
+			w.Write([]byte(`{"mode":"READONLY"}`))
+		case r.Method == "GET" && r.URL.Path == "/subjects/user-event-value/versions":
+			w.Write([]byte(`[1]`))
+		case r.Method == "PUT" && strings.HasPrefix(r.URL.Path, "/mode/"):
+			requests = append(requests, "mode "+body["mode"].(string)+" "+r.URL.RawQuery)
+			w.Write([]byte(`{}`))
+		case r.Method == "POST" && strings.Contains(r.URL.Path, "/subjects/"):
+			requests = append(requests, "register")
+			w.Write([]byte(`{"id": 1}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{
+			"test-registry": {
+				"UserEvent": {Format: gluetypes.DataFormatAvro, Versions: []string{`{"type":"record","name":"UserEvent","fields":[]}`}},
+			},
+		},
+	}
+
+	m := newTestMigrator(t, server.URL, mockClient, func(cfg *config.Config) {
+		cfg.Migration.ImportMode = true
+		cfg.Migration.PreserveSchemaIDs = true
+	})
+	result, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	if result.Failed != 0 {
+		t.Fatalf("expected no failures, got %v", result.Errors)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"mode IMPORT force=true", "register", "mode READONLY "}
+	if strings.Join(requests, ",") != strings.Join(want, ",") {
+		t.Errorf("requests = %q, want %q", requests, want)
+	}
+}
+
+func TestMigrationRejectsDuplicateSchemaIDs(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": 1}`))
+	}))
+	defer server.Close()
+
+	// These two version IDs hash to the same 31-bit schema ID
+	first := "00000000-0000-0000-0000-000000062789"
+	second := "00000000-0000-0000-0000-000000279192"
+	if loader.SourceSchemaID(&models.GlueSchemaVersion{SchemaVersionID: first}) != loader.SourceSchemaID(&models.GlueSchemaVersion{SchemaVersionID: second}) {
+		t.Fatal("test version IDs no longer collide")
+	}
+
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{
+			"test-registry": {
+				"UserEvent": {
+					Definition: `{"type":"record","name":"UserEvent","fields":[{"name":"id","type":"string"}]}`,
+					Format:     gluetypes.DataFormatAvro,
+					VersionIDs: []string{first},
+				},
+				"OrderEvent": {
+					Definition: `{"type":"record","name":"OrderEvent","fields":[{"name":"id","type":"string"}]}`,
+					Format:     gluetypes.DataFormatAvro,
+					VersionIDs: []string{second},
+				},
+			},
+		},
+	}
+
+	m := newTestMigrator(t, server.URL, mockClient, func(cfg *config.Config) {
+		cfg.Migration.ImportMode = true
+		cfg.Migration.PreserveSchemaIDs = true
+	})
+	_, err := m.Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "duplicate schema IDs") {
+		t.Fatalf("expected a duplicate schema ID error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "(user-event-value)") || !strings.Contains(err.Error(), "(order-event-value)") {
+		t.Errorf("expected the error to name both subjects, got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(requests) != 0 {
+		t.Errorf("expected no Schema Registry requests before the duplicate is reported, got %v", requests)
+	}
+}
+
+func TestMigrationPreservesVersions(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		switch {
+		case r.Method == "PUT" && strings.HasPrefix(r.URL.Path, "/mode/"):
+			requests = append(requests, "mode "+body["mode"].(string))
+			w.Write([]byte(`{}`))
+		case r.Method == "POST" && strings.Contains(r.URL.Path, "/subjects/"):
+			version, _ := body["version"].(float64)
+			id, _ := body["id"].(float64)
+			requests = append(requests, fmt.Sprintf("register version %d id %d", int(version), int(id)))
+			w.Write([]byte(`{"id": 1}`))
+		case r.Method == "PUT" && strings.HasPrefix(r.URL.Path, "/config/"):
+			w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	// Drops version 1, so versions 2 and 3 must keep their numbers
+
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{
+			"test-registry": {
+				"UserEvent": {
+					Format: gluetypes.DataFormatAvro,
+					Versions: []string{
+						`{"type":"record","name":"UserEvent","fields":[{"name":"id","type":"string"}]}`,
+						`{"type":"record","name":"UserEvent","fields":[{"name":"id","type":"string"},{"name":"email","type":["null","string"],"default":null}]}`,
+						`{"type":"record","name":"UserEvent","fields":[{"name":"id","type":"string"},{"name":"email","type":["null","string"],"default":null},{"name":"name","type":["null","string"],"default":null}]}`,
+					},
+				},
+			},
+		},
+	}
+
+	m := newTestMigrator(t, server.URL, mockClient, func(cfg *config.Config) {
+		cfg.Migration.ImportMode = true
+		cfg.Migration.PreserveVersions = true
+		cfg.Migration.MaxVersionsPerSchema = 2
+	})
+	result, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	if result.Failed != 0 {
+		t.Fatalf("expected no failures, got %v", result.Errors)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"mode IMPORT"}
+	for _, n := range []int{2, 3} {
+		id := loader.SourceSchemaID(&models.GlueSchemaVersion{SchemaVersionID: fmt.Sprintf("ver-%03d", n)})
+		want = append(want, fmt.Sprintf("register version %d id %d", n, id))
+	}
+	want = append(want, "mode READWRITE")
+	if strings.Join(requests, "\n") != strings.Join(want, "\n") {
+		t.Errorf("requests:\n%s\nwant:\n%s", strings.Join(requests, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestMigrationParallelVersions(t *testing.T) {
+	definitions := make([]string, 8)
+	for i := range definitions {
+		definitions[i] = fmt.Sprintf(`{"type":"record","name":"UserEvent","fields":[{"name":"f%d","type":"string"}]}`, i+1)
+	}
+
+	// failing names versions the server rejects
+	run := func(t *testing.T, parallel bool, failing map[int]bool) ([]int, *Result) {
+		var mu sync.Mutex
+		var registered []int
+		inFlight, maxInFlight := 0, 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == "POST" && strings.Contains(r.URL.Path, "/subjects/"):
+				var body loader.SchemaRegistrationRequest
+				json.NewDecoder(r.Body).Decode(&body)
+				mu.Lock()
+				inFlight++
+				if inFlight > maxInFlight {
+					maxInFlight = inFlight
+				}
+				mu.Unlock()
+				time.Sleep(10 * time.Millisecond)
+				mu.Lock()
+				inFlight--
+				registered = append(registered, body.Version)
+				mu.Unlock()
+				if failing[body.Version] {
+					w.WriteHeader(http.StatusUnprocessableEntity)
+					w.Write([]byte(`{"error_code": 42201, "message": "Invalid schema"}`))
+					return
+				}
+				w.Write([]byte(`{"id": 1}`))
+			case r.Method == "PUT":
+				w.Write([]byte(`{}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		mockClient := &mockGlueClient{
+			Schemas: map[string]map[string]*mockSchema{
+				"test-registry": {
+					"UserEvent": {Format: gluetypes.DataFormatAvro, Versions: definitions},
+				},
+			},
+		}
+
+		m := newTestMigrator(t, server.URL, mockClient, func(cfg *config.Config) {
+			cfg.Output.Quiet = true
+			cfg.Migration.ImportMode = true
+			cfg.Migration.PreserveVersions = true
+			cfg.Migration.ParallelVersions = parallel
+			cfg.Concurrency.CCRateLimit = 1000
+		})
+
+		result, err := m.Run(context.Background())
+		if err != nil && len(failing) == 0 {
+			t.Fatalf("migration failed: %v", err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if parallel && maxInFlight < 2 {
+			t.Errorf("expected versions to be registered concurrently, at most %d were in flight", maxInFlight)
+		}
+		if !parallel && maxInFlight != 1 {
+			t.Errorf("expected one registration at a time, %d were in flight", maxInFlight)
+		}
+		return registered, result
+	}
+
+	t.Run("sequential", func(t *testing.T) {
+		registered, result := run(t, false, nil)
+		if result.Failed != 0 {
+			t.Fatalf("expected no failures, got %v", result.Errors)
+		}
+		if fmt.Sprint(registered) != "[1 2 3 4 5 6 7 8]" {
+			t.Errorf("registered versions %v, want 1 to 8 in order", registered)
+		}
+	})
+
+	t.Run("parallel", func(t *testing.T) {
+		registered, result := run(t, true, nil)
+		if result.Failed != 0 {
+			t.Fatalf("expected no failures, got %v", result.Errors)
+		}
+		sort.Ints(registered)
+		if fmt.Sprint(registered) != "[1 2 3 4 5 6 7 8]" {
+			t.Errorf("registered versions %v, want each of 1 to 8 once", registered)
+		}
+	})
+
+	t.Run("parallel failures", func(t *testing.T) {
+		registered, result := run(t, true, map[int]bool{3: true, 6: true})
+		if result.Failed != 1 {
+			t.Fatalf("expected the schema to fail, got %d failures", result.Failed)
+		}
+		if len(registered) != 8 {
+			t.Errorf("expected every version to be attempted, got %v", registered)
+		}
+		msg := result.Errors[0].Error()
+		for _, want := range []string{"failed to register version 3 of", "failed to register version 6 of"} {
+			if !strings.Contains(msg, want) {
+				t.Errorf("error %q does not report %q", msg, want)
+			}
+		}
+		if strings.Contains(msg, "version 4 of") {
+			t.Errorf("error %q reports a version that registered", msg)
+		}
+	})
+}
+
+func TestMigrationRetriesFailedSchemas(t *testing.T) {
+	var mu sync.Mutex
+	var registered []string
+	reject := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/versions") {
+			subject := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/subjects/"), "/versions")
+			registered = append(registered, subject)
+			if reject && subject == "payment-event-value" {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				w.Write([]byte(`{"error_code":42201,"message":"Invalid schema"}`))
+				return
+			}
+			w.Write([]byte(`{"id":1}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	checkpointFile := filepath.Join(t.TempDir(), "checkpoint.json")
+	checkpoint := worker.NewCheckpointManager(checkpointFile)
+	lastAttempt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	state := models.NewMigrationState("")
+	state.CompletedSchemas["test-registry:RefundEvent"] = models.CompletedSchema{
+		SourceRegistry: "test-registry",
+		SourceSchema:   "RefundEvent",
+		TargetSubject:  "refund-event-value",
+		Versions:       1,
+	}
+	state.CompletedCount = 1
+	state.FailedSchemas["test-registry:PaymentEvent"] = models.FailedSchema{
+		SourceRegistry: "test-registry",
+		SourceSchema:   "PaymentEvent",
+		Error:          "connection reset",
+		Attempts:       2,
+		LastAttempt:    lastAttempt,
+	}
+	if err := checkpoint.Save(state); err != nil {
+		t.Fatal(err)
+	}
+
+	schemas := map[string]*mockSchema{}
+	for _, name := range []string{"OrderEvent", "PaymentEvent", "RefundEvent"} {
+		schemas[name] = &mockSchema{
+			Definition: fmt.Sprintf(`{"type":"record","name":%q,"fields":[]}`, name),
+			Format:     gluetypes.DataFormatAvro,
+		}
+	}
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{"test-registry": schemas},
+	}
+
+	run := func() *Result {
+		m := newTestMigrator(t, server.URL, mockClient, func(cfg *config.Config) {
+			cfg.Output.Quiet = true
+			cfg.Checkpoint.File = checkpointFile
+			cfg.Checkpoint.Resume = true
+			cfg.Checkpoint.RetryFailed = true
+		})
+		m.checkpoint = checkpoint
+		result, err := m.Run(context.Background())
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		return result
+	}
+
+	// A failed retry counts the attempt, and OrderEvent, which was never
+	// attempted, is left for a plain resume
+	result := run()
+	if result.Failed != 1 || result.Successful != 0 {
+		t.Fatalf("expected only the retry to run and fail, got %d successful, %d failed", result.Successful, result.Failed)
+	}
+	if strings.Join(registered, ",") != "payment-event-value" {
+		t.Errorf("registered %v, expected only the failed schema to be retried", registered)
+	}
+	saved, err := checkpoint.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	failure := saved.FailedSchemas["test-registry:PaymentEvent"]
+	if failure.Attempts != 3 || !failure.LastAttempt.After(lastAttempt) {
+		t.Errorf("failure = %+v, want attempt 3 after %s", failure, lastAttempt)
+	}
+	if _, ok := saved.CompletedSchemas["test-registry:OrderEvent"]; ok {
+		t.Error("expected the unattempted OrderEvent not to be migrated")
+	}
+
+	// A successful retry clears the failure
+	mu.Lock()
+	registered = nil
+	reject = false
+	mu.Unlock()
+	result = run()
+	if result.Failed != 0 || result.Successful != 1 {
+		t.Fatalf("expected the retry to succeed, got %d successful, %d failed: %v", result.Successful, result.Failed, result.Errors)
+	}
+	if strings.Join(registered, ",") != "payment-event-value" {
+		t.Errorf("registered %v, expected only the failed schema to be retried", registered)
+	}
+	if saved, err = checkpoint.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if len(saved.FailedSchemas) != 0 {
+		t.Errorf("expected the failure to be cleared, got %v", saved.FailedSchemas)
+	}
+	if _, ok := saved.CompletedSchemas["test-registry:PaymentEvent"]; !ok || saved.CompletedCount != 2 {
+		t.Errorf("expected PaymentEvent to be completed, got %v (%d)", saved.CompletedSchemas, saved.CompletedCount)
+	}
+}
+
+func TestMigrationDrainsOnCancel(t *testing.T) {
+	started := make(chan string, 4)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/versions") {
+			started <- strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/subjects/"), "/versions")
+			<-release
+			w.Write([]byte(`{"id": 1}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	defer func() {
+		select {
+		case <-release:
+		default:
+			close(release)
+		}
+	}()
+
+	checkpointFile := filepath.Join(t.TempDir(), "checkpoint.json")
+	schemas := map[string]*mockSchema{}
+	for _, name := range []string{"OrderEvent", "PaymentEvent", "RefundEvent", "UserEvent"} {
+		schemas[name] = &mockSchema{
+			Definition: fmt.Sprintf(`{"type":"record","name":%q,"fields":[]}`, name),
+			Format:     gluetypes.DataFormatAvro,
+		}
+	}
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{"test-registry": schemas},
+	}
+
+	m := newTestMigrator(t, server.URL, mockClient, func(cfg *config.Config) {
+		cfg.Concurrency.Workers = 2
+		cfg.Concurrency.CCRateLimit = 1000
+		cfg.Output.Quiet = true
+		cfg.Checkpoint.File = checkpointFile
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	type runResult struct {
+		result *Result
+		err    error
+	}
+	done := make(chan runResult, 1)
+	go func() {
+		result, err := m.Run(ctx)
+		done <- runResult{result, err}
+	}()
+
+	// Interrupt while both workers are registering, then let them finish
+	inFlight := []string{<-started, <-started}
+	cancel()
+	close(release)
+	run := <-done
+	if run.err != nil {
+		t.Fatalf("Run failed: %v", run.err)
+	}
+
+	result := run.result
+	if result.Successful != 2 || result.Failed != 0 || result.NotStarted != 2 {
+		t.Fatalf("got %d successful, %d failed, %d not started, want 2, 0, 2: %v",
+			result.Successful, result.Failed, result.NotStarted, result.Errors)
+	}
+	if result.Report.Results.NotStarted != 2 {
+		t.Errorf("report not_started = %d, want 2", result.Report.Results.NotStarted)
+	}
+
+	state, err := worker.NewCheckpointManager(checkpointFile).Load()
+	if err != nil {
+		t.Fatalf("failed to load checkpoint: %v", err)
+	}
+	if len(state.CompletedSchemas) != 2 || len(state.FailedSchemas) != 0 {
+		t.Fatalf("checkpoint has %d completed and %d failed schemas, want 2 and 0", len(state.CompletedSchemas), len(state.FailedSchemas))
+	}
+	for _, subject := range inFlight {
+		found := false
+		for _, entry := range state.CompletedSchemas {
+			found = found || entry.TargetSubject == subject
+		}
+		if !found {
+			t.Errorf("expected in-flight subject %s to be checkpointed as completed, got %v", subject, state.CompletedSchemas)
+		}
+	}
+}
+
+func TestMigrationReconcilesPlannedSubjects(t *testing.T) {
+	var mu sync.Mutex
+	// Left by an earlier run: one checkpointed, one registered outside it
+	registered := map[string]bool{"refund-event-value": true, "account-event-value": true}
+	var listed int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.Method == "POST" && strings.HasPrefix(r.URL.Path, "/subjects/") && !strings.HasSuffix(r.URL.Path, "/versions"):
+			// skip_existing lookup
+			if !registered[strings.TrimPrefix(r.URL.Path, "/subjects/")] {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"error_code":40403,"message":"Schema not found"}`))
+				return
+			}
+			w.Write([]byte(`{"id":1,"version":1}`))
+		case r.Method == "POST" && strings.Contains(r.URL.Path, "/subjects/"):
+			subject := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/subjects/"), "/versions")
+			if subject == "payment-event-value" {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				w.Write([]byte(`{"error_code":42201,"message":"Invalid schema"}`))
+				return
+			}
+			// The write to user-event-value is acknowledged but never lands
+			if subject != "user-event-value" {
+				registered[subject] = true
+			}
+			w.Write([]byte(`{"id":1}`))
+		case r.Method == "GET" && r.URL.Path == "/subjects":
+			listed++
+			var subjects []string
+			for subject := range registered {
+				subjects = append(subjects, subject)
+			}
+			json.NewEncoder(w).Encode(subjects)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	checkpointFile := filepath.Join(t.TempDir(), "checkpoint.json")
+	state := models.NewMigrationState("")
+	state.CompletedSchemas["test-registry:RefundEvent"] = models.CompletedSchema{
+		SourceRegistry: "test-registry",
+		SourceSchema:   "RefundEvent",
+		TargetSubject:  "refund-event-value",
+		Versions:       1,
+	}
+	state.CompletedCount = 1
+	if err := worker.NewCheckpointManager(checkpointFile).Save(state); err != nil {
+		t.Fatal(err)
+	}
+
+	schemas := map[string]*mockSchema{}
+	for _, name := range []string{"AccountEvent", "OrderEvent", "PaymentEvent", "RefundEvent", "UserEvent"} {
+		schemas[name] = &mockSchema{
+			Definition: fmt.Sprintf(`{"type":"record","name":%q,"fields":[]}`, name),
+			Format:     gluetypes.DataFormatAvro,
+		}
+	}
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{"test-registry": schemas},
+	}
+
+	m := newTestMigrator(t, server.URL, mockClient, func(cfg *config.Config) {
+		cfg.Checkpoint.File = checkpointFile
+		cfg.Checkpoint.Resume = true
+		cfg.Migration.SkipExisting = true
+	})
+	result, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if listed != 1 {
+		t.Errorf("expected one final subject listing, got %d", listed)
+	}
+
+	rec := result.Report.Reconciliation
+	if rec == nil {
+		t.Fatal("expected a reconciliation in the report")
+	}
+	if rec.Registered != 1 || rec.Skipped != 1 || rec.Resumed != 1 || rec.Failed != 2 || len(rec.Subjects) != 5 {
+		t.Fatalf("reconciliation = %d registered, %d skipped, %d resumed, %d failed of %d, want 1, 1, 1, 2 of 5",
+			rec.Registered, rec.Skipped, rec.Resumed, rec.Failed, len(rec.Subjects))
+	}
+
+	want := map[string]string{
+		"order-event-value":   "registered",
+		"account-event-value": "skipped",
+		"refund-event-value":  "resumed",
+		"payment-event-value": "failed",
+		"user-event-value":    "failed",
+	}
+	for _, s := range rec.Subjects {
+		if s.Status != want[s.Subject] {
+			t.Errorf("%s: status = %q, want %q", s.Subject, s.Status, want[s.Subject])
+		}
+		if s.Subject == "user-event-value" && !strings.Contains(s.Reason, "not found in target") {
+			t.Errorf("user-event-value: reason = %q, want it reported missing from the target", s.Reason)
+		}
+		if s.Subject == "payment-event-value" && !strings.Contains(s.Reason, "Invalid schema") {
+			t.Errorf("payment-event-value: reason = %q, want the registration error", s.Reason)
+		}
+	}
+}
+
+func TestMigrationPlansOnlySelectedRoles(t *testing.T) {
+	schemas := map[string]*mockSchema{}
+	for _, name := range []string{"OrderKey", "OrderValue", "PaymentEvent", "CustomerKey"} {
+		schemas[name] = &mockSchema{
+			Definition: fmt.Sprintf(`{"type":"record","name":%q,"fields":[{"name":"id","type":"string"}]}`, name),
+			Format:     gluetypes.DataFormatAvro,
+		}
+	}
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{"test-registry": schemas},
+	}
+
+	m := newTestMigrator(t, "", mockClient, func(cfg *config.Config) {
+		cfg.Output.DryRun = true
+		cfg.Output.Quiet = true
+		cfg.Migration.Roles = []string{"value"}
+	})
+	result, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("dry-run failed: %v", err)
+	}
+
+	if result.SchemasProcessed != 2 {
+		t.Errorf("expected 2 planned schemas, got %d", result.SchemasProcessed)
+	}
+	planned := map[string]bool{}
+	for _, s := range result.Report.Schemas {
+		if s.DetectedRole != models.SchemaRoleValue {
+			t.Errorf("%s planned with role %s, want only value schemas", s.SourceSchema, s.DetectedRole)
+		}
+		planned[s.SourceSchema] = true
+	}
+	if !planned["OrderValue"] || !planned["PaymentEvent"] || len(planned) != 2 {
+		t.Errorf("planned schemas = %v, want OrderValue and PaymentEvent", planned)
+	}
+}
+
+func TestMigrationPlansReferencesOfSelectedRoles(t *testing.T) {
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{
+			"test-registry": {
+				"OrderKey": {
+					Definition: `{"type":"record","name":"OrderKey","fields":[{"name":"orderId","type":"string"}]}`,
+					Format:     gluetypes.DataFormatAvro,
+				},
+				"OrderEvent": {
+					Definition: `{"type":"record","name":"OrderEvent","fields":[{"name":"key","type":"OrderKey"},{"name":"total","type":"double"}]}`,
+					Format:     gluetypes.DataFormatAvro,
+				},
+				"CustomerKey": {
+					Definition: `{"type":"record","name":"CustomerKey","fields":[{"name":"id","type":"string"}]}`,
+					Format:     gluetypes.DataFormatAvro,
+				},
+			},
+		},
+	}
+
+	m := newTestMigrator(t, "", mockClient, func(cfg *config.Config) {
+		cfg.Output.DryRun = true
+		cfg.Output.Quiet = true
+		cfg.Migration.Roles = []string{"value"}
+	})
+
+	result, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("dry-run failed: %v", err)
+	}
+
+	// The key schema OrderEvent references is kept so its reference resolves
+	planned := map[string]bool{}
+	for _, s := range result.Report.Schemas {
+		planned[s.SourceSchema] = true
+	}
+	if !planned["OrderEvent"] || !planned["OrderKey"] || len(planned) != 2 {
+		t.Errorf("planned schemas = %v, want OrderEvent and the OrderKey it references", planned)
+	}
+}
+
+func TestMigrationSkipsExistingVersions(t *testing.T) {
+	v1 := `{"type":"record","name":"UserEvent","fields":[]}`
+	v2 := `{"type":"record","name":"UserEvent","fields":[{"name":"id","type":"string","default":""}]}`
+
+	var mu sync.Mutex
+	var registered []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/subjects/user-event-value":
+			// Only version 1 is already in the target
+			if body["schema"] != v1 {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"error_code":40403,"message":"Schema not found"}`))
+				return
+			}
+			w.Write([]byte(`{"subject":"user-event-value","id":1,"version":1}`))
+		case r.Method == "POST" && r.URL.Path == "/subjects/user-event-value/versions":
+			registered = append(registered, body["schema"].(string))
+			w.Write([]byte(`{"id":2}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	checkpointFile := filepath.Join(t.TempDir(), "checkpoint.json")
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{
+			"test-registry": {
+				"UserEvent": {Format: gluetypes.DataFormatAvro, Versions: []string{v1, v2}},
+			},
+		},
+	}
+
+	m := newTestMigrator(t, server.URL, mockClient, func(cfg *config.Config) {
+		cfg.Checkpoint.File = checkpointFile
+		cfg.Migration.SkipExisting = true
+	})
+	result, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Successful != 1 || result.Failed != 0 {
+		t.Fatalf("expected 1 successful schema, got %d successful and %d failed: %v", result.Successful, result.Failed, result.Errors)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(registered) != 1 || registered[0] != v2 {
+		t.Errorf("expected only version 2 to be registered, got %v", registered)
+	}
+
+	state, err := worker.NewCheckpointManager(checkpointFile).Load()
+	if err != nil {
+		t.Fatalf("failed to load checkpoint: %v", err)
+	}
+	completed := state.CompletedSchemas["test-registry:UserEvent"]
+	if len(completed.SkippedExistingVersions) != 1 || completed.SkippedExistingVersions[0] != 1 {
+		t.Errorf("skipped existing versions = %v, want [1]", completed.SkippedExistingVersions)
+	}
+}
+
+func TestMigrationSkipsDependencyGraph(t *testing.T) {
+	// The two schemas reference each other, which graph.Build rejects
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{
+			"test-registry": {
+				"OrderEvent": {
+					Definition: `{"type":"record","name":"OrderEvent","fields":[{"name":"payment","type":"PaymentEvent"}]}`,
+					Format:     gluetypes.DataFormatAvro,
+				},
+				"PaymentEvent": {
+					Definition: `{"type":"record","name":"PaymentEvent","fields":[{"name":"order","type":"OrderEvent"}]}`,
+					Format:     gluetypes.DataFormatAvro,
+				},
+			},
+		},
+	}
+
+	run := func(skipGraph bool) (*Result, error) {
+		return newTestMigrator(t, "", mockClient, func(cfg *config.Config) {
+			cfg.Output.DryRun = true
+			cfg.Output.Quiet = true
+			cfg.Migration.SkipDependencyGraph = skipGraph
+		}).Run(context.Background())
+	}
+
+	if _, err := run(false); err == nil || !strings.Contains(err.Error(), "dependency graph") {
+		t.Fatalf("expected the dependency graph to reject the cycle, got %v", err)
+	}
+
+	result, err := run(true)
+	if err != nil {
+		t.Fatalf("dry-run with skip_dependency_graph failed: %v", err)
+	}
+	if result.SchemasProcessed != 2 || len(result.Report.Schemas) != 2 {
+		t.Errorf("expected both schemas planned, got %d processed and %d in the report", result.SchemasProcessed, len(result.Report.Schemas))
+	}
+}
+
+func TestMigrationPerSchemaTimeout(t *testing.T) {
+	// Registration hangs for slow-event until the request is abandoned
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "slow-event") && r.Method == "POST" {
+			select {
+			case <-r.Context().Done():
+			case <-release:
+			}
+			return
+		}
+		switch {
+		case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/versions"):
+			w.Write([]byte(`{"id":1}`))
+		case r.Method == "GET" && r.URL.Path == "/subjects":
+			w.Write([]byte(`["order-event-value","payment-event-value"]`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	schemas := map[string]*mockSchema{}
+	for _, name := range []string{"SlowEvent", "OrderEvent", "PaymentEvent"} {
+		schemas[name] = &mockSchema{
+			Definition: fmt.Sprintf(`{"type":"record","name":%q,"fields":[]}`, name),
+			Format:     gluetypes.DataFormatAvro,
+		}
+	}
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{"test-registry": schemas},
+	}
+
+	m := newTestMigrator(t, server.URL, mockClient, func(cfg *config.Config) {
+		cfg.Concurrency.Workers = 3
+		cfg.Concurrency.CCRateLimit = 1000
+		cfg.Migration.PerSchemaTimeout = 200 * time.Millisecond
+	})
+	start := time.Now()
+	result, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("run took %s, expected the hung schema to time out quickly", elapsed)
+	}
+
+	if result.Successful != 2 || result.Failed != 1 {
+		t.Fatalf("expected 2 successful and 1 failed schema, got %d successful and %d failed: %v", result.Successful, result.Failed, result.Errors)
+	}
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Error(), "timed out") {
+		t.Errorf("errors = %v, expected a per-schema timeout", result.Errors)
+	}
+}
+
+// failingProvider fails the test if the LLM is ever called
+type failingProvider struct {
+	t *testing.T
+}
+
+func (p *failingProvider) Complete(ctx context.Context, prompt string) (string, float64, error) {
+	p.t.Error("LLM provider called during an estimating dry run")
+	return "", 0, fmt.Errorf("unexpected call")
+}
+
+func TestDryRunEstimatesLLMCost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{
+			"payments": {
+				"OrderEvent": {
+					Definition: `{"type":"record","name":"OrderEvent","fields":[{"name":"id","type":"string"}]}`,
+					Format:     gluetypes.DataFormatAvro,
+				},
+				"RefundEvent": {
+					Definition: `{"type":"record","name":"RefundEvent","fields":[{"name":"id","type":"string"}]}`,
+					Format:     gluetypes.DataFormatAvro,
+				},
+			},
+		},
+	}
+
+	m := newTestMigrator(t, server.URL, mockClient, func(cfg *config.Config) {
+		cfg.Output.DryRun = true
+		cfg.Output.Quiet = true
+		cfg.Naming.SubjectStrategy = "llm"
+		cfg.LLM.CacheFile = ""
+		cfg.LLM.EstimateInDryRun = true
+	})
+	namer, err := llm.NewNamerWithProvider(m.config, &failingProvider{t: t})
+	if err != nil {
+		t.Fatalf("failed to create namer: %v", err)
+	}
+	m.mapper, _ = mapper.New(m.config, m.normalizer, m.kvDetector, namer)
+	m.llmNamer = namer
+
+	result, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("dry-run failed: %v", err)
+	}
+
+	if result.LLMCalls != 0 || result.EstimatedLLMCalls != 2 {
+		t.Errorf("LLM calls = %d made, %d estimated; expected 0 made and 2 estimated", result.LLMCalls, result.EstimatedLLMCalls)
+	}
+	if result.EstimatedLLMCost <= 0 {
+		t.Errorf("EstimatedLLMCost = %f, expected a positive estimate", result.EstimatedLLMCost)
+	}
+	if result.Report.Results.EstimatedLLMCalls != 2 || result.Report.Results.EstimatedLLMCost != result.EstimatedLLMCost {
+		t.Errorf("report results = %+v, expected the estimate", result.Report.Results)
+	}
+	for _, s := range result.Report.Schemas {
+		if s.NamingStrategy != "topic (llm estimated)" {
+			t.Errorf("%s named by %q, expected the topic placeholder", s.SourceSchema, s.NamingStrategy)
+		}
+	}
+}
+
+func TestPlanRoundTripsAndApplies(t *testing.T) {
+	var mu sync.Mutex
+	var registered []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/versions") {
+			registered = append(registered, strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/subjects/"), "/versions"))
+			w.Write([]byte(`{"id": 1}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{
+			"test-registry": {
+				"Address": {
+					Definition: `{"type":"record","name":"Address","namespace":"com.example","fields":[{"name":"street","type":"string"}]}`,
+					Format:     gluetypes.DataFormatAvro,
+				},
+				"OrderEvent": {
+					Definition: `{"type":"record","name":"OrderEvent","namespace":"com.example","fields":[{"name":"orderId","type":"string"},{"name":"shipTo","type":"Address"}]}`,
+					Format:     gluetypes.DataFormatAvro,
+				},
+			},
+		},
+	}
+
+	m := newTestMigrator(t, server.URL, mockClient, func(cfg *config.Config) {
+		cfg.Output.Quiet = true
+	})
+
+	plan, err := m.Plan(context.Background())
+	if err != nil {
+		t.Fatalf("planning failed: %v", err)
+	}
+	mu.Lock()
+	if len(registered) != 0 {
+		t.Errorf("planning must not register anything, got %v", registered)
+	}
+	mu.Unlock()
+
+	// Both formats must round-trip every field of the plan
+	var loaded *models.MigrationPlan
+	for _, name := range []string{"plan.json", "plan.yaml"} {
+		path := filepath.Join(t.TempDir(), name)
+		if err := WritePlan(path, plan); err != nil {
+			t.Fatalf("WritePlan(%s): %v", name, err)
+		}
+		loaded, err = LoadPlan(path)
+		if err != nil {
+			t.Fatalf("LoadPlan(%s): %v", name, err)
+		}
+		want, _ := json.Marshal(plan)
+		got, _ := json.Marshal(loaded)
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s round trip changed the plan:\n got %s\nwant %s", name, got, want)
+		}
+	}
+
+	if len(loaded.Levels) != 2 || len(loaded.Levels[1].Schemas) != 1 {
+		t.Fatalf("expected OrderEvent in a second dependency level, got %+v", loaded.Levels)
+	}
+	if order := loaded.Levels[1].Schemas[0]; order.SourceSchemaName != "OrderEvent" || len(order.References) != 1 {
+		t.Fatalf("expected OrderEvent to reference Address, got %+v", order)
+	}
+
+	// A reviewer renames OrderEvent's subject in the mappings
+	var addressSubject string
+	for i := range loaded.Mappings {
+		switch loaded.Mappings[i].SourceSchemaName {
+		case "OrderEvent":
+			loaded.Mappings[i].TargetSubject = "orders-reviewed-value"
+		case "Address":
+			addressSubject = fullSubject(&loaded.Mappings[i])
+		}
+	}
+
+	result, err := m.Apply(context.Background(), loaded)
+	if err != nil {
+		t.Fatalf("apply failed: %v", err)
+	}
+	if result.Successful != 2 || result.Failed != 0 {
+		t.Errorf("expected 2 successful and 0 failed, got %d and %d", result.Successful, result.Failed)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(registered) != 2 || registered[0] != addressSubject || !strings.HasSuffix(registered[1], "orders-reviewed-value") {
+		t.Errorf("expected %s then the edited OrderEvent subject, got %v", addressSubject, registered)
+	}
+}
+
+func TestMigrationReferencesKeySubject(t *testing.T) {
+	var mu sync.Mutex
+	references := make(map[string][]models.SchemaReference)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/versions"):
+			var body loader.SchemaRegistrationRequest
+			json.NewDecoder(r.Body).Decode(&body)
+			subject := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/subjects/"), "/versions")
+			references[subject] = body.References
+			w.Write([]byte(`{"id": 1}`))
+		case r.Method == "GET" && r.URL.Path == "/subjects/order-key/versions/latest":
+			w.Write([]byte(`{"version": 1}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{
+			"test-registry": {
+				"OrderKey": {
+					Definition: `{"type":"record","name":"OrderKey","fields":[{"name":"orderId","type":"string"}]}`,
+					Format:     gluetypes.DataFormatAvro,
+				},
+				"OrderEvent": {
+					Definition: `{"type":"record","name":"OrderEvent","fields":[{"name":"key","type":"OrderKey"},{"name":"total","type":"double"}]}`,
+					Format:     gluetypes.DataFormatAvro,
+				},
+			},
+		},
+	}
+
+	m := newTestMigrator(t, server.URL, mockClient, func(cfg *config.Config) {
+		cfg.Output.Quiet = true
+	})
+
+	result, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	if result.Failed != 0 {
+		t.Fatalf("expected no failures, got %v", result.Errors)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := references["order-key"]; !ok {
+		t.Fatalf("expected OrderKey to be registered as order-key, got %v", references)
+	}
+	refs := references["order-event-value"]
+	if len(refs) != 1 || refs[0].Subject != "order-key" || refs[0].Version != 1 {
+		t.Errorf("order-event-value references = %+v, expected the key subject order-key at version 1", refs)
+	}
+}
+
+func TestMigrationReferencesProtobufImport(t *testing.T) {
+	var mu sync.Mutex
+	registered := make(map[string]loader.SchemaRegistrationRequest)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/versions"):
+			var body loader.SchemaRegistrationRequest
+			json.NewDecoder(r.Body).Decode(&body)
+			subject := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/subjects/"), "/versions")
+			registered[subject] = body
+			w.Write([]byte(`{"id": 1}`))
+		case r.Method == "GET" && r.URL.Path == "/subjects/address-value/versions/latest":
+			w.Write([]byte(`{"version": 1}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{
+			"test-registry": {
+				"address": {
+					Definition: "syntax = \"proto3\";\npackage common;\n\nmessage Address {\n  string street = 1;\n}\n",
+					Format:     gluetypes.DataFormatProtobuf,
+				},
+				"customer": {
+					Definition: "syntax = \"proto3\";\nimport \"common/address.proto\";\n\nmessage Customer {\n  common.Address billing = 1;\n}\n",
+					Format:     gluetypes.DataFormatProtobuf,
+				},
+			},
+		},
+	}
+
+	m := newTestMigrator(t, server.URL, mockClient, func(cfg *config.Config) {
+		cfg.Output.Quiet = true
+	})
+
+	result, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	if result.Failed != 0 {
+		t.Fatalf("expected no failures, got %v", result.Errors)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	body, ok := registered["customer-value"]
+	if !ok {
+		t.Fatalf("expected customer to be registered as customer-value, got %v", registered)
+	}
+	if body.SchemaType != "PROTOBUF" {
+		t.Errorf("customer-value schemaType = %q, expected PROTOBUF", body.SchemaType)
+	}
+	want := models.SchemaReference{Name: "common/address.proto", Subject: "address-value", Version: 1}
+	if len(body.References) != 1 || body.References[0] != want {
+		t.Errorf("customer-value references = %+v, expected [%+v]", body.References, want)
+	}
+}
+
+// versionCountingClient counts the version definitions fetched from Glue
+type versionCountingClient struct {
+	*mockGlueClient
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (c *versionCountingClient) GetSchemaVersion(ctx context.Context, params *glue.GetSchemaVersionInput, optFns ...func(*glue.Options)) (*glue.GetSchemaVersionOutput, error) {
+	c.mu.Lock()
+	c.calls[aws.ToString(params.SchemaId.SchemaName)]++
+	c.mu.Unlock()
+	return c.mockGlueClient.GetSchemaVersion(ctx, params, optFns...)
+}
+
+func TestMigrationSinceKeepsReferencedSchemas(t *testing.T) {
+	var mu sync.Mutex
+	references := make(map[string][]models.SchemaReference)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/versions"):
+			var body loader.SchemaRegistrationRequest
+			json.NewDecoder(r.Body).Decode(&body)
+			subject := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/subjects/"), "/versions")
+			references[subject] = body.References
+			w.Write([]byte(`{"id": 1}`))
+		case r.Method == "GET" && r.URL.Path == "/subjects/address-value/versions/latest":
+			w.Write([]byte(`{"version": 1}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{
+			"test-registry": {
+				"address": {
+					Definition:  `{"type":"record","name":"PostalAddress","fields":[{"name":"street","type":"string"}]}`,
+					Format:      gluetypes.DataFormatAvro,
+					UpdatedTime: "2024-01-01T00:00:00Z",
+				},
+				"legacy": {
+					Definition:  `{"type":"record","name":"Legacy","fields":[{"name":"id","type":"string"}]}`,
+					Format:      gluetypes.DataFormatAvro,
+					UpdatedTime: "2024-01-01T00:00:00Z",
+				},
+				"customer": {
+					Definition:  `{"type":"record","name":"Customer","fields":[{"name":"id","type":"string"},{"name":"home","type":"PostalAddress"}]}`,
+					Format:      gluetypes.DataFormatAvro,
+					UpdatedTime: "2025-06-01T00:00:00Z",
+				},
+			},
+		},
+	}
+
+	m := newTestMigrator(t, server.URL, mockClient, func(cfg *config.Config) {
+		cfg.Migration.Since = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		cfg.Output.Quiet = true
+	})
+
+	result, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	if result.Failed != 0 {
+		t.Fatalf("expected no failures, got %v", result.Errors)
+	}
+
+	// Only the changed schema is registered, referencing the unchanged
+	// address by its planned subject
+	mu.Lock()
+	defer mu.Unlock()
+	if len(references) != 1 {
+		t.Fatalf("registered subjects = %v, expected only customer-value", references)
+	}
+	refs := references["customer-value"]
+	if len(refs) != 1 || refs[0].Subject != "address-value" {
+		t.Errorf("customer-value references = %+v, expected address-value", refs)
+	}
+
+	// The address is planned as skipped; the unreferenced legacy schema is dropped
+	planned := map[string]string{}
+	for _, s := range result.Report.Schemas {
+		planned[s.SourceSchema] = s.Status
+	}
+	if planned["address"] != "skipped" {
+		t.Errorf("address status = %q, expected skipped", planned["address"])
+	}
+	if _, ok := planned["legacy"]; ok {
+		t.Errorf("legacy was planned, expected it to be dropped")
+	}
+}
+
+func TestMigrationLazyDefinitions(t *testing.T) {
+	var mu sync.Mutex
+	references := make(map[string][]models.SchemaReference)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/versions"):
+			var body loader.SchemaRegistrationRequest
+			json.NewDecoder(r.Body).Decode(&body)
+			subject := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/subjects/"), "/versions")
+			references[subject] = body.References
+			w.Write([]byte(`{"id": 1}`))
+		case r.Method == "GET" && r.URL.Path == "/subjects/address-value/versions/latest":
+			w.Write([]byte(`{"version": 1}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &versionCountingClient{
+		mockGlueClient: &mockGlueClient{
+			Schemas: map[string]map[string]*mockSchema{
+				"test-registry": {
+					"address": {
+						Definition: `{"type":"record","name":"PostalAddress","fields":[{"name":"street","type":"string"}]}`,
+						Format:     gluetypes.DataFormatAvro,
+					},
+					"customer": {
+						Versions: []string{
+							`{"type":"record","name":"Customer","fields":[{"name":"id","type":"string"}]}`,
+							`{"type":"record","name":"Customer","fields":[{"name":"id","type":"string"},{"name":"home","type":["null","PostalAddress"],"default":null}]}`,
+							`{"type":"record","name":"Customer","fields":[{"name":"id","type":"string"},{"name":"home","type":"PostalAddress"}]}`,
+						},
+						Format: gluetypes.DataFormatAvro,
+					},
+				},
+			},
+		},
+		calls: make(map[string]int),
+	}
+
+	m := newTestMigrator(t, server.URL, client, func(cfg *config.Config) {
+		cfg.AWS.LazyDefinitions = true
+		cfg.Migration.VersionStrategy = "latest"
+		cfg.Output.Quiet = true
+	})
+
+	result, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	if result.Failed != 0 {
+		t.Fatalf("expected no failures, got %v", result.Errors)
+	}
+
+	// The reference is found by record name in the latest definition
+	mu.Lock()
+	refs := references["customer-value"]
+	mu.Unlock()
+	if len(refs) != 1 || refs[0].Subject != "address-value" {
+		t.Errorf("customer-value references = %+v, expected address-value", refs)
+	}
+
+	// One definition while planning and one while migrating, not all three
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if got := client.calls["customer"]; got != 2 {
+		t.Errorf("fetched %d customer definitions, expected 2", got)
+	}
+}
+
+func TestMigrationSetsSubjectMetadata(t *testing.T) {
+	var mu sync.Mutex
+	metadata := make(map[string]models.SubjectMetadata)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "PUT" && strings.HasSuffix(r.URL.Path, "/metadata"):
+			var body models.SubjectMetadata
+			json.NewDecoder(r.Body).Decode(&body)
+			mu.Lock()
+			metadata[strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/subjects/"), "/metadata")] = body
+			mu.Unlock()
+			w.Write([]byte(`{}`))
+		case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/versions"):
+			w.Write([]byte(`{"id": 1}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{
+			"test-registry": {
+				"UserEvent": {
+					Definition:  `{"type":"record","name":"UserEvent","fields":[{"name":"id","type":"string"}]}`,
+					Format:      gluetypes.DataFormatAvro,
+					Description: "Emitted when a user signs up",
+					Tags:        map[string]string{"team": "identity", "pii": "true"},
+				},
+				"OrderEvent": {
+					Definition: `{"type":"record","name":"OrderEvent","fields":[{"name":"id","type":"string"}]}`,
+					Format:     gluetypes.DataFormatAvro,
+				},
+			},
+		},
+	}
+
+	// run migrates with the metadata settings configure applies
+	run := func(configure func(*config.Config)) {
+		m := newTestMigrator(t, server.URL, mockClient, func(cfg *config.Config) {
+			cfg.Output.Quiet = true
+		}, configure)
+
+		result, err := m.Run(context.Background())
+		if err != nil {
+			t.Fatalf("migration failed: %v", err)
+		}
+		if result.Failed != 0 {
+			t.Fatalf("expected no failures, got %v", result.Errors)
+		}
+	}
+
+	run(func(*config.Config) {})
+	mu.Lock()
+	got, ok := metadata["user-event-value"]
+	if !ok {
+		t.Fatalf("expected metadata to be set on user-event-value, got %v", metadata)
+	}
+	if got.Properties["description"] != "Emitted when a user signs up" {
+		t.Errorf("description property = %q, want the Glue description", got.Properties["description"])
+	}
+	if strings.Join(got.Tags, ",") != "pii=true,team=identity" {
+		t.Errorf("tags = %v, want [pii=true team=identity]", got.Tags)
+	}
+	if _, ok := metadata["order-event-value"]; ok {
+		t.Errorf("expected no metadata for a schema without a description or tags, got %+v", metadata["order-event-value"])
+	}
+	mu.Unlock()
+
+	// Only the enabled parts are migrated, and nothing with the skip strategy
+	metadata = make(map[string]models.SubjectMetadata)
+	run(func(cfg *config.Config) {
+		cfg.Metadata.MigrateDescription = false
+	})
+	if got := metadata["user-event-value"]; got.Properties != nil || len(got.Tags) != 2 {
+		t.Errorf("with migrate_description disabled, metadata = %+v, want the tags only", got)
+	}
+
+	metadata = make(map[string]models.SubjectMetadata)
+	run(func(cfg *config.Config) {
+		cfg.Metadata.MigrateDescription = false
+		cfg.Metadata.Strategy = "skip"
+	})
+	if len(metadata) != 0 {
+		t.Errorf("expected no metadata requests with the skip strategy, got %v", metadata)
+	}
+}
+
+func TestMigrationIgnoresUnsupportedMetadata(t *testing.T) {
+	var mu sync.Mutex
+	registered := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/versions") {
+			mu.Lock()
+			registered++
+			mu.Unlock()
+			w.Write([]byte(`{"id": 1}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{
+			"test-registry": {
+				"UserEvent": {
+					Definition:  `{"type":"record","name":"UserEvent","fields":[{"name":"id","type":"string"}]}`,
+					Format:      gluetypes.DataFormatAvro,
+					Description: "Emitted when a user signs up",
+				},
+			},
+		},
+	}
+
+	m := newTestMigrator(t, server.URL, mockClient, func(cfg *config.Config) {
+		cfg.Output.Quiet = true
+	})
+
+	result, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if result.Failed != 0 || result.Successful != 1 || registered != 1 {
+		t.Errorf("expected the schema to migrate despite the missing metadata endpoint, got %d successful, %d failed: %v", result.Successful, result.Failed, result.Errors)
+	}
+}
+
+func TestMigrationDumpsFailedSchemas(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" && strings.Contains(r.URL.Path, "/subjects/") {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"error_code":42201,"message":"Invalid schema: unknown type Money"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	definition := `{"type":"record","name":"Payment","fields":[{"name":"amount","type":"string"}]}`
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{
+			"test-registry": {
+				"Payment": {
+					Definition: definition,
+					Format:     gluetypes.DataFormatAvro,
+				},
+			},
+		},
+	}
+
+	m := newTestMigrator(t, server.URL, mockClient, func(cfg *config.Config) {
+		cfg.Output.DumpFailuresDir = filepath.Join(t.TempDir(), "failures")
+	})
+	result, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Failed != 1 {
+		t.Fatalf("expected 1 failed schema, got %d", result.Failed)
+	}
+
+	data, err := os.ReadFile(filepath.Join(m.config.Output.DumpFailuresDir, "test-registry_Payment.txt"))
+	if err != nil {
+		t.Fatalf("expected failure dump: %v", err)
+	}
+	dump := string(data)
+	if !strings.Contains(dump, definition) {
+		t.Errorf("dump does not contain the failing definition:\n%s", dump)
+	}
+	if !strings.Contains(dump, "unknown type Money") {
+		t.Errorf("dump does not contain the server error:\n%s", dump)
+	}
+}
+
+func TestMigrationRetriesOnlyRetryableErrorCodes(t *testing.T) {
+	var mu sync.Mutex
+	attempts := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Method == "POST" && strings.Contains(r.URL.Path, "/subjects/") {
+			w.WriteHeader(http.StatusInternalServerError)
+			if strings.Contains(r.URL.Path, "order") {
+				attempts["order"]++
+				w.Write([]byte(`{"error_code":50001,"message":"Error in the backend data store"}`))
+			} else {
+				attempts["payment"]++
+				w.Write([]byte(`{"error_code":42201,"message":"Invalid schema"}`))
+			}
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{
+			"test-registry": {
+				"OrderEvent": {
+					Definition: `{"type":"record","name":"OrderEvent","fields":[]}`,
+					Format:     gluetypes.DataFormatAvro,
+				},
+				"PaymentEvent": {
+					Definition: `{"type":"record","name":"PaymentEvent","fields":[]}`,
+					Format:     gluetypes.DataFormatAvro,
+				},
+			},
+		},
+	}
+
+	m := newTestMigrator(t, server.URL, mockClient, func(cfg *config.Config) {
+		cfg.ConfluentCloud.RetryableErrorCodes = map[int]bool{50001: true, 42201: false}
+		cfg.Concurrency.RetryAttempts = 2
+		cfg.Concurrency.RetryDelay = time.Millisecond
+	})
+	result, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Failed != 2 {
+		t.Errorf("expected 2 failed schemas, got %d", result.Failed)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if attempts["order"] != 3 {
+		t.Errorf("expected the retryable failure to be attempted 3 times, got %d", attempts["order"])
+	}
+	if attempts["payment"] != 1 {
+		t.Errorf("expected the permanent failure to be attempted once, got %d", attempts["payment"])
+	}
+}
+
+func TestMigrationSkipsIncompatibleSchemas(t *testing.T) {
+	var mu sync.Mutex
+	var registered []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		path := r.URL.Path
+		switch {
+		case r.Method == "POST" && strings.HasPrefix(path, "/compatibility/"):
+			if strings.Contains(path, "order") {
+				w.Write([]byte(`{"is_compatible": false, "messages": ["READER_FIELD_MISSING_DEFAULT_VALUE: id"]}`))
+				return
+			}
+			w.Write([]byte(`{"is_compatible": true}`))
+		case r.Method == "GET" && strings.HasSuffix(path, "/versions"):
+			// Both subjects already exist in the target
+			w.Write([]byte(`[1]`))
+		case r.Method == "POST" && strings.HasPrefix(path, "/subjects/"):
+			registered = append(registered, path)
+			w.Write([]byte(`{"id": 1}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{
+			"test-registry": {
+				"OrderEvent": {
+					Definition: `{"type":"record","name":"OrderEvent","fields":[{"name":"id","type":"string"}]}`,
+					Format:     gluetypes.DataFormatAvro,
+				},
+				"PaymentEvent": {
+					Definition: `{"type":"record","name":"PaymentEvent","fields":[]}`,
+					Format:     gluetypes.DataFormatAvro,
+				},
+			},
+		},
+	}
+
+	m := newTestMigrator(t, server.URL, mockClient, func(cfg *config.Config) {
+		cfg.Concurrency.RetryAttempts = 2
+		cfg.Concurrency.RetryDelay = time.Millisecond
+		cfg.Migration.PrecheckCompatibility = true
+	})
+	result, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Failed != 1 || result.Successful != 1 {
+		t.Errorf("expected 1 failed and 1 successful schema, got %d failed, %d successful", result.Failed, result.Successful)
+	}
+	// The failure reports Schema Registry's reasons, not a rejected registration
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Error(), "is incompatible with existing subject order-event-value: READER_FIELD_MISSING_DEFAULT_VALUE: id") {
+		t.Errorf("expected the incompatibility messages in the error, got %v", result.Errors)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(registered) != 1 || !strings.Contains(registered[0], "payment") {
+		t.Errorf("expected only the compatible schema to be registered, got %v", registered)
+	}
+}
+
+func TestMigrationDeadLettersUnsupportedFormats(t *testing.T) {
+	var mu sync.Mutex
+	var registered []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Method == "POST" && strings.Contains(r.URL.Path, "/subjects/") {
+			registered = append(registered, r.URL.Path)
+			w.Write([]byte(`{"id": 1}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{
+			"test-registry": {
+				"UserEvent": {
+					Definition: `{"type":"record","name":"UserEvent","fields":[]}`,
+					Format:     gluetypes.DataFormatAvro,
+				},
+				"LegacyEvent": {
+					Definition: `struct LegacyEvent { 1: string id }`,
+					Format:     gluetypes.DataFormat("THRIFT"),
+				},
+			},
+		},
+	}
+
+	m := newTestMigrator(t, server.URL, mockClient)
+	result, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Successful != 1 || result.Skipped != 1 {
+		t.Errorf("expected 1 successful and 1 skipped schema, got %d successful, %d skipped", result.Successful, result.Skipped)
+	}
+
+	dead := result.Report.DeadLetters
+	if len(dead) != 1 || dead[0].SourceSchema != "LegacyEvent" || !strings.Contains(dead[0].Reason, "THRIFT") {
+		t.Fatalf("expected LegacyEvent dead-lettered for its THRIFT format, got %+v", dead)
+	}
+	for _, s := range result.Report.Schemas {
+		if s.SourceSchema == "LegacyEvent" {
+			t.Errorf("dead-lettered schema should not be mapped, got subject %q", s.TargetSubject)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(registered) != 1 || strings.Contains(strings.ToLower(registered[0]), "legacy") {
+		t.Errorf("expected only UserEvent to be registered, got %v", registered)
+	}
+}
+
+func TestMigrationSetsCompatibilityConcurrently(t *testing.T) {
+	var mu sync.Mutex
+	var inFlight, maxInFlight, compatCalls, registered int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "PUT" && strings.HasPrefix(r.URL.Path, "/config/"):
+			mu.Lock()
+			inFlight++
+			compatCalls++
+			if inFlight > maxInFlight {
+				maxInFlight = inFlight
+			}
+			mu.Unlock()
+
+			time.Sleep(50 * time.Millisecond)
+
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+			w.Write([]byte(`{"compatibility": "BACKWARD"}`))
+		case r.Method == "POST" && strings.Contains(r.URL.Path, "/subjects/"):
+			mu.Lock()
+			registered++
+			mu.Unlock()
+			w.Write([]byte(`{"id": 1}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	// A single worker would set compatibility one schema at a time inline
+
+	schemas := map[string]*mockSchema{}
+	for _, name := range []string{"OrderEvent", "PaymentEvent", "RefundEvent", "UserEvent"} {
+		schemas[name] = &mockSchema{
+			Definition:    fmt.Sprintf(`{"type":"record","name":%q,"fields":[]}`, name),
+			Format:        gluetypes.DataFormatAvro,
+			Compatibility: gluetypes.CompatibilityBackward,
+		}
+	}
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{"test-registry": schemas},
+	}
+
+	m := newTestMigrator(t, server.URL, mockClient, func(cfg *config.Config) {
+		cfg.Concurrency.CCRateLimit = 1000
+		cfg.Concurrency.Workers = 1
+		cfg.Concurrency.FollowupConcurrency = 4
+	})
+	result, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Successful != 4 {
+		t.Errorf("expected 4 successful schemas, got %d", result.Successful)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if compatCalls != 4 {
+		t.Errorf("expected one compatibility call per schema, got %d", compatCalls)
+	}
+	if maxInFlight < 2 {
+		t.Errorf("expected compatibility calls to overlap, max in flight was %d", maxInFlight)
+	}
+	if registered != 4 {
+		t.Errorf("expected 4 registrations, got %d", registered)
+	}
+}
+
+func TestMigrationCanonicalizesDefinitions(t *testing.T) {
+	var mu sync.Mutex
+	bodies := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" && strings.Contains(r.URL.Path, "/versions") {
+			var req struct {
+				Schema string `json:"schema"`
+			}
+			json.NewDecoder(r.Body).Decode(&req)
+			mu.Lock()
+			bodies[r.URL.Path] = req.Schema
+			mu.Unlock()
+			w.Write([]byte(`{"id": 1}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{
+			"test-registry": {
+				"MinifiedEvent": {
+					Definition: `{"type":"record","name":"Event","fields":[{"name":"id","type":"long","default":12345678901234567890}]}`,
+					Format:     gluetypes.DataFormatAvro,
+				},
+				"FormattedEvent": {
+					Definition: "{\n  \"name\": \"Event\",\n  \"type\": \"record\",\n  \"fields\": [\n    {\"type\": \"long\", \"name\": \"id\", \"default\": 12345678901234567890}\n  ]\n}\n",
+					Format:     gluetypes.DataFormatAvro,
+				},
+			},
+		},
+	}
+
+	m := newTestMigrator(t, server.URL, mockClient, func(cfg *config.Config) {
+		cfg.Migration.CanonicalizeDefinitions = true
+	})
+	result, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Successful != 2 {
+		t.Fatalf("expected 2 successful schemas, got %d", result.Successful)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(bodies) != 2 {
+		t.Fatalf("expected 2 registrations, got %v", bodies)
+	}
+	var registered []string
+	for _, schema := range bodies {
+		registered = append(registered, schema)
+	}
+	if registered[0] != registered[1] {
+		t.Errorf("minified and formatted definitions registered differently:\n%s\n%s", registered[0], registered[1])
+	}
+	want := `{"fields":[{"default":12345678901234567890,"name":"id","type":"long"}],"name":"Event","type":"record"}`
+	if registered[0] != want {
+		t.Errorf("registered definition = %s, want %s", registered[0], want)
+	}
+
+	for _, s := range result.Report.Schemas {
+		found := false
+		for _, tr := range s.Transformations {
+			if tr == "canonicalize-definitions" {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected %s to record the canonicalize-definitions transformation, got %v", s.SourceSchema, s.Transformations)
+		}
+	}
+}
+
+func TestDryRunEstimatesAPICalls(t *testing.T) {
+	mockClient := &mockGlueClient{
+		Schemas: map[string]map[string]*mockSchema{
+			"test-registry": {
+				"UserEvent": {
+					Versions: []string{
+						`{"type":"record","name":"UserEvent","fields":[]}`,
+						`{"type":"record","name":"UserEvent","fields":[{"name":"id","type":"string"}]}`,
+						`{"type":"record","name":"UserEvent","fields":[{"name":"id","type":"string"},{"name":"email","type":["null","string"],"default":null}]}`,
+					},
+					Format:        gluetypes.DataFormatAvro,
+					Compatibility: gluetypes.CompatibilityBackward,
+					Description:   "User lifecycle events",
+				},
+				"OrderEvent": {
+					Versions: []string{
+						`{"type":"record","name":"OrderEvent","fields":[]}`,
+						`{"type":"record","name":"OrderEvent","fields":[{"name":"id","type":"string"}]}`,
+					},
+					Format: gluetypes.DataFormatAvro,
+				},
+			},
+		},
+	}
+
+	m := newTestMigrator(t, "", mockClient, func(cfg *config.Config) {
+		cfg.Output.DryRun = true
+		cfg.Migration.VersionStrategy = "all"
+		cfg.Metadata.Strategy = "migrate"
+		cfg.Metadata.MigrateDescription = true
+	})
+	result, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("dry-run failed: %v", err)
+	}
+
+	calls := result.Report.EstimatedAPICalls
+	if calls == nil {
+		t.Fatal("expected the dry-run report to include an API call estimate")
+	}
+	if calls.Registrations != result.VersionsProcessed || calls.Registrations != 5 {
+		t.Errorf("estimated registrations = %d, want total versions %d (5)", calls.Registrations, result.VersionsProcessed)
+	}
+	if calls.CompatibilityCalls != 1 {
+		t.Errorf("estimated compatibility calls = %d, want 1", calls.CompatibilityCalls)
+	}
+	// Two listing calls and a tag lookup per schema, as tags are migrated by
+	// default, plus one call per version, then the listing and version calls
+	// again as each schema is registered
+	if want := (2*2 + 2 + 5) + (2*2 + 5); calls.GlueCalls != want {
+		t.Errorf("estimated Glue calls = %d, want %d", calls.GlueCalls, want)
+	}
+	// Only UserEvent has a description to set
+	if calls.MetadataCalls != 1 {
+		t.Errorf("estimated metadata calls = %d, want 1", calls.MetadataCalls)
+	}
+
+	// Lazy planning and latest-only registration fetch one definition each
+	m = newTestMigrator(t, "", mockClient, func(cfg *config.Config) {
+		cfg.Output.DryRun = true
+		cfg.AWS.LazyDefinitions = true
+		cfg.Migration.VersionStrategy = "latest"
+	})
+	result, err = m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("dry-run failed: %v", err)
+	}
+	if want := (2*2 + 2 + 2) + (2*2 + 2); result.Report.EstimatedAPICalls.GlueCalls != want {
+		t.Errorf("estimated lazy Glue calls = %d, want %d", result.Report.EstimatedAPICalls.GlueCalls, want)
+	}
+}
+
+func TestMigrationContinuesFromSchema(t *testing.T) {
+	var mu sync.Mutex
+	var registered []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/versions") {
+			mu.Lock()
+			registered = append(registered, strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/subjects/"), "/versions"))
+			mu.Unlock()
+			w.Write([]byte(`{"id": 1}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	// With the default level_order the migration order is the listing order,
+	// even though the schemas listed first finish fetching last
+	names := []string{"a-event", "b-event", "c-event", "d-event"}
+	schemas := make(map[string]*mockSchema)
+	delays := make(map[string]time.Duration)
+	for i, name := range names {
+		schemas[name] = &mockSchema{
+			Definition: `{"type":"record","name":"Event","fields":[]}`,
+			Format:     gluetypes.DataFormatAvro,
+		}
+		delays[name] = time.Duration(len(names)-i) * 10 * time.Millisecond
+	}
+	mockClient := &delayedSchemaClient{
+		mockGlueClient: &mockGlueClient{Schemas: map[string]map[string]*mockSchema{"test-registry": schemas}},
+		delays:         delays,
+	}
+
+	m := newTestMigrator(t, server.URL, mockClient, func(cfg *config.Config) {
+		cfg.Concurrency.Workers = len(names)
+		cfg.Checkpoint.ContinueFrom = "test-registry:c-event"
+	})
+	result, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Successful != 2 || result.Skipped != 2 {
+		t.Errorf("expected 2 successful and 2 skipped schemas, got %d successful, %d skipped", result.Successful, result.Skipped)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	got := strings.Join(registered, ",")
+	for _, name := range []string{"a-event", "b-event"} {
+		if strings.Contains(got, name) {
+			t.Errorf("%s is before the continue-from marker but was registered: %v", name, registered)
+		}
+	}
+	for _, name := range []string{"c-event", "d-event"} {
+		if !strings.Contains(got, name) {
+			t.Errorf("%s is at or after the continue-from marker but was not registered: %v", name, registered)
+		}
+	}
+
+	m = newTestMigrator(t, server.URL, mockClient, func(cfg *config.Config) {
+		cfg.Checkpoint.ContinueFrom = "test-registry:missing"
+	})
+	if _, err := m.Run(context.Background()); err == nil || !strings.Contains(err.Error(), "not in the migration order") {
+		t.Errorf("expected an unknown continue-from schema to fail, got %v", err)
+	}
+}
+
+// delayedSchemaClient delays GetSchema per schema name, so fetches can finish
+// in a different order than they were listed
+type delayedSchemaClient struct {
+	*mockGlueClient
+	delays map[string]time.Duration
+}
+
+func (c *delayedSchemaClient) GetSchema(ctx context.Context, params *glue.GetSchemaInput, optFns ...func(*glue.Options)) (*glue.GetSchemaOutput, error) {
+	time.Sleep(c.delays[aws.ToString(params.SchemaId.SchemaName)])
+	return c.mockGlueClient.GetSchema(ctx, params, optFns...)
+}
+
+func TestPlanKeepsSourceOrderAcrossRuns(t *testing.T) {
+	// Schemas listed first finish fetching last
+	names := []string{"a-event", "b-event", "c-event", "d-event", "e-event"}
+	schemas := make(map[string]*mockSchema)
+	delays := make(map[string]time.Duration)
+	for i, name := range names {
+		schemas[name] = &mockSchema{
+			Definition: `{"type":"record","name":"Event","fields":[]}`,
+			Format:     gluetypes.DataFormatAvro,
+		}
+		delays[name] = time.Duration(len(names)-i) * 10 * time.Millisecond
+	}
+	client := &delayedSchemaClient{
+		mockGlueClient: &mockGlueClient{Schemas: map[string]map[string]*mockSchema{"test-registry": schemas}},
+		delays:         delays,
+	}
+
+	// level_order is left at "source", the listing order
+	for run := 0; run < 3; run++ {
+		m := newTestMigrator(t, "", client, func(cfg *config.Config) {
+			cfg.Output.Quiet = true
+			cfg.Concurrency.Workers = len(names)
+		})
+		plan, err := m.Plan(context.Background())
+		if err != nil {
+			t.Fatalf("planning failed: %v", err)
+		}
+
+		var order []string
+		for _, level := range plan.Levels {
+			for _, schema := range level.Schemas {
+				order = append(order, schema.SourceSchemaName)
+			}
+		}
+		if strings.Join(order, ",") != strings.Join(names, ",") {
+			t.Fatalf("run %d planned %v, expected the listing order %v", run, order, names)
+		}
 	}
 }
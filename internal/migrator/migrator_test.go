@@ -0,0 +1,25 @@
+package migrator
+
+import (
+	"testing"
+	"time"
+)
+
+func TestThroughput(t *testing.T) {
+	tests := []struct {
+		completed int64
+		elapsed   time.Duration
+		want      float64
+	}{
+		{50, 10 * time.Second, 5},
+		{3, 1500 * time.Millisecond, 2},
+		{0, time.Second, 0},
+		{10, 0, 0},
+	}
+
+	for _, tt := range tests {
+		if got := throughput(tt.completed, tt.elapsed); got != tt.want {
+			t.Errorf("throughput(%d, %v) = %v, want %v", tt.completed, tt.elapsed, got, tt.want)
+		}
+	}
+}
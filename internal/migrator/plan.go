@@ -0,0 +1,123 @@
+package migrator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// Apply registers the schemas of a plan written by the plan command without
+// extracting or mapping them again. Target names come from the plan's
+// mappings, so hand edits there take effect; the levels fix the order and
+// references. Each schema's versions are still read from the source when it
+// is registered.
+func (m *Migrator) Apply(ctx context.Context, plan *models.MigrationPlan) (*Result, error) {
+	startTime := time.Now()
+
+	mappingLookup := make(map[string]*models.SchemaMapping, len(plan.Mappings))
+	mappings := make([]*models.SchemaMapping, len(plan.Mappings))
+	for i := range plan.Mappings {
+		key := fmt.Sprintf("%s:%s", plan.Mappings[i].SourceRegistry, plan.Mappings[i].SourceSchemaName)
+		mappingLookup[key] = &plan.Mappings[i]
+		mappings[i] = &plan.Mappings[i]
+	}
+
+	for i := range plan.Levels {
+		for j := range plan.Levels[i].Schemas {
+			schema := &plan.Levels[i].Schemas[j]
+			key := fmt.Sprintf("%s:%s", schema.SourceRegistry, schema.SourceSchemaName)
+			mapping, found := mappingLookup[key]
+			if !found {
+				return nil, fmt.Errorf("plan level %d lists %s, which has no mapping", plan.Levels[i].Level, key)
+			}
+			schema.TargetContext = mapping.TargetContext
+			schema.TargetSubject = mapping.TargetSubject
+			schema.KeySubject = mapping.KeySubject
+		}
+	}
+
+	// Hand edits may have broken a subject name
+	slog.Info("validating plan", "mappings", len(mappings))
+	if _, err := m.validate(mappings); err != nil {
+		return nil, err
+	}
+
+	return m.execute(ctx, plan, startTime)
+}
+
+// WritePlan writes plan to path as JSON when the path ends in .json and as
+// YAML otherwise. Both use the JSON field names.
+func WritePlan(path string, plan *models.MigrationPlan) error {
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode plan: %w", err)
+	}
+
+	if !strings.EqualFold(filepath.Ext(path), ".json") {
+		// JSON is YAML, so decoding it keeps the field names and order;
+		// clearing the flow styles re-encodes it in block style
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("failed to encode plan: %w", err)
+		}
+		clearStyle(&doc)
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(&doc); err != nil {
+			return fmt.Errorf("failed to encode plan: %w", err)
+		}
+		if err := enc.Close(); err != nil {
+			return fmt.Errorf("failed to encode plan: %w", err)
+		}
+		data = buf.Bytes()
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write plan %s: %w", path, err)
+	}
+	return nil
+}
+
+// LoadPlan reads a plan written by WritePlan, in either format
+func LoadPlan(path string) (*models.MigrationPlan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan %s: %w", path, err)
+	}
+
+	// The model only has JSON tags, so decode generically and go through JSON
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse plan %s: %w", path, err)
+	}
+	if data, err = json.Marshal(doc); err != nil {
+		return nil, fmt.Errorf("failed to parse plan %s: %w", path, err)
+	}
+	var plan models.MigrationPlan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse plan %s: %w", path, err)
+	}
+	if len(plan.Levels) == 0 && len(plan.Mappings) > 0 {
+		return nil, fmt.Errorf("plan %s has mappings but no dependency levels", path)
+	}
+	return &plan, nil
+}
+
+// clearStyle resets the quoting and flow style of every node under n so the
+// encoder picks plain block style, quoting only where a value needs it
+func clearStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		clearStyle(c)
+	}
+}
@@ -7,8 +7,9 @@ import (
 // MigrationState represents the state of a migration for checkpointing
 type MigrationState struct {
 	// Metadata
-	StartedAt  time.Time `json:"started_at"`
-	ConfigHash string    `json:"config_hash"`
+	StartedAt     time.Time `json:"started_at"`
+	ConfigHash    string    `json:"config_hash"`
+	TargetURLHash string    `json:"target_url_hash,omitempty"` // SHA-256 of the target SR URL
 	
 	// Progress
 	TotalSchemas    int `json:"total_schemas"`
@@ -33,9 +34,25 @@ type MigrationState struct {
 type CompletedSchema struct {
 	SourceRegistry string    `json:"source_registry"`
 	SourceSchema   string    `json:"source_schema"`
+	TargetContext  string    `json:"target_context,omitempty"`
 	TargetSubject  string    `json:"target_subject"`
+	KeySubject     string    `json:"key_subject,omitempty"` // also registered as a key subject
 	Versions       int       `json:"versions"`
 	CompletedAt    time.Time `json:"completed_at"`
+
+	// Versions not registered because they only changed documentation
+	SkippedDocOnlyVersions []int64 `json:"skipped_doc_only_versions,omitempty"`
+
+	// Older versions not registered because of max_versions_per_schema
+	SkippedOlderVersions int `json:"skipped_older_versions,omitempty"`
+
+	// Versions not registered because skip_existing found them in the target
+	SkippedExistingVersions []int64 `json:"skipped_existing_versions,omitempty"`
+
+	// Latest version of each qualified target subject that already existed
+	// before the schema was registered, so rollback deletes only the
+	// versions above it
+	PreexistingVersions map[string]int `json:"preexisting_versions,omitempty"`
 }
 
 // FailedSchema represents a failed schema migration
@@ -76,6 +93,9 @@ type MigrationPlan struct {
 	// Collisions detected
 	Collisions []Collision `json:"collisions,omitempty"`
 	
+	// Schemas set aside without being mapped or registered
+	DeadLetters []DeadLetter `json:"dead_letters,omitempty"`
+	
 	// Warnings
 	Warnings []Warning `json:"warnings,omitempty"`
 	
@@ -86,10 +106,29 @@ type MigrationPlan struct {
 	Summary MigrationSummary `json:"summary"`
 }
 
+// DeadLetter is a schema that was set aside rather than migrated
+type DeadLetter struct {
+	SourceRegistry string     `json:"source_registry"`
+	SourceSchema   string     `json:"source_schema"`
+	DataFormat     SchemaType `json:"data_format"`
+	Reason         string     `json:"reason"`
+}
+
 // Collision represents a naming collision
 type Collision struct {
 	NormalizedName string   `json:"normalized_name"`
 	SourceSchemas  []string `json:"source_schemas"`
+
+	// Resolution details, set when the collision was auto-resolved
+	Strategy string             `json:"strategy,omitempty"`
+	Outcomes []CollisionOutcome `json:"outcomes,omitempty"`
+}
+
+// CollisionOutcome records what happened to one source in a resolved collision
+type CollisionOutcome struct {
+	SourceSchema string `json:"source_schema"`
+	FinalSubject string `json:"final_subject,omitempty"` // empty when skipped
+	Skipped      bool   `json:"skipped,omitempty"`
 }
 
 // Warning represents a migration warning
@@ -116,6 +155,30 @@ type MigrationSummary struct {
 	Collisions       int `json:"collisions"`
 	LLMCalls         int `json:"llm_calls"`
 	EstimatedLLMCost float64 `json:"estimated_llm_cost"`
+
+	// Target existence (dry-run with credentials only)
+	TargetChecked bool `json:"target_checked"`
+	AlreadyExists int  `json:"already_exists"`
+
+	// API calls the real run is expected to make
+	APICalls APICallEstimate `json:"api_calls"`
+}
+
+// APICallEstimate approximates the API calls a migration will make. Glue
+// pagination and retries are not counted.
+type APICallEstimate struct {
+	GlueCalls          int `json:"glue_calls"`               // schema, version listing, version and tag lookups
+	Registrations      int `json:"registrations"`            // one per registered version and subject
+	CompatibilityCalls int `json:"compatibility_calls"`      // per-subject compatibility updates
+	PrecheckCalls      int `json:"precheck_calls"`           // existence and compatibility checks
+	ModeCalls          int `json:"mode_calls,omitempty"`     // mode lookups and switches with import_mode
+	LookupCalls        int `json:"lookup_calls,omitempty"`   // skip_existing version lookups and checkpointed latest versions
+	MetadataCalls      int `json:"metadata_calls,omitempty"` // per-subject metadata updates with metadata.strategy: migrate
+}
+
+// ConfluentCalls returns the estimated total of Schema Registry API calls
+func (e APICallEstimate) ConfluentCalls() int {
+	return e.Registrations + e.CompatibilityCalls + e.PrecheckCalls + e.ModeCalls + e.LookupCalls + e.MetadataCalls
 }
 
 // NewMigrationState creates a new migration state
@@ -0,0 +1,22 @@
+package models
+
+// SchemaContext represents the preprocessed context sent to the LLM
+type SchemaContext struct {
+	GlueSchemaName string   `json:"glue_schema_name"`
+	GlueRegistry   string   `json:"glue_registry"`
+	SchemaType     string   `json:"schema_type"`
+	RecordName     string   `json:"record_name,omitempty"`
+	Namespace      string   `json:"namespace,omitempty"`
+	Documentation  string   `json:"documentation,omitempty"`
+	KeyFields      []string `json:"key_fields,omitempty"`
+	FieldCount     int      `json:"field_count"`
+	References     []string `json:"references,omitempty"`
+}
+
+// PromptData is what an llm.prompt_template_file template is rendered with.
+// The SchemaContext fields are promoted, so templates refer to them
+// directly, e.g. {{.GlueSchemaName}} and {{.Role}}.
+type PromptData struct {
+	SchemaContext
+	Role SchemaRole
+}
@@ -27,6 +27,18 @@ type MigrationReport struct {
 	// Schema details
 	Schemas []SchemaReport `json:"schemas"`
 	
+	// Collision resolutions (with --explain-collisions)
+	Collisions []Collision `json:"collisions,omitempty"`
+	
+	// Schemas set aside, e.g. for an unsupported data format
+	DeadLetters []DeadLetter `json:"dead_letters,omitempty"`
+	
+	// Estimated API calls (dry-run only)
+	EstimatedAPICalls *APICallEstimate `json:"estimated_api_calls,omitempty"`
+	
+	// Planned subjects checked against the target after a real run
+	Reconciliation *ReconciliationReport `json:"reconciliation,omitempty"`
+	
 	// Errors and warnings
 	Errors   []ErrorReport   `json:"errors,omitempty"`
 	Warnings []WarningReport `json:"warnings,omitempty"`
@@ -65,8 +77,30 @@ type ResultsReport struct {
 	Successful          int     `json:"successful"`
 	Failed              int     `json:"failed"`
 	Skipped             int     `json:"skipped"`
+	NotStarted          int     `json:"not_started,omitempty"` // left unstarted by an interrupt
+	AlreadyExists       int     `json:"already_exists,omitempty"`
 	LLMCalls            int     `json:"llm_calls"`
 	LLMCost             float64 `json:"llm_cost"`
+	EstimatedLLMCalls   int     `json:"estimated_llm_calls,omitempty"` // dry-run with llm.estimate_in_dry_run
+	EstimatedLLMCost    float64 `json:"estimated_llm_cost,omitempty"`
+}
+
+// ReconciliationReport compares the planned subjects with the target's
+// subject list after a real run
+type ReconciliationReport struct {
+	Registered int                 `json:"registered"`
+	Skipped    int                 `json:"skipped"` // every version was already in the target
+	Resumed    int                 `json:"resumed"` // already migrated by a checkpoint or continue_from
+	Failed     int                 `json:"failed"`
+	Subjects   []ReconciledSubject `json:"subjects"`
+}
+
+// ReconciledSubject is the end state of one planned subject
+type ReconciledSubject struct {
+	Subject      string `json:"subject"`
+	SourceSchema string `json:"source_schema"` // registry.schema
+	Status       string `json:"status"`        // registered, skipped, resumed, failed
+	Reason       string `json:"reason,omitempty"`
 }
 
 // SchemaReport represents details about a single schema migration
@@ -84,6 +118,8 @@ type SchemaReport struct {
 	DetectedRole     SchemaRole `json:"detected_role"`
 	RoleReason       string     `json:"role_reason"`
 	NamingStrategy   string     `json:"naming_strategy"`
+	SuggestedName    string     `json:"suggested_name,omitempty"`
+	ContentHash      string     `json:"content_hash,omitempty"`
 	Transformations  []string   `json:"transformations,omitempty"`
 	
 	// Versions
@@ -13,6 +13,16 @@ const (
 	SchemaTypeProtobuf SchemaType = "PROTOBUF"
 )
 
+// Supported reports whether schemas of this type can be parsed and registered
+func (t SchemaType) Supported() bool {
+	switch t {
+	case SchemaTypeAvro, SchemaTypeJSON, SchemaTypeProtobuf:
+		return true
+	default:
+		return false
+	}
+}
+
 // SchemaRole represents whether a schema is a key or value schema
 type SchemaRole string
 
@@ -44,6 +54,10 @@ type GlueSchema struct {
 	CreatedTime       time.Time         `json:"created_time"`
 	UpdatedTime       time.Time         `json:"updated_time"`
 	Versions          []GlueSchemaVersion `json:"versions"`
+
+	// Not updated since migration.since; extracted with only its latest
+	// definition so changed schemas can resolve references to it
+	Unchanged bool `json:"unchanged,omitempty"`
 }
 
 // GlueSchemaVersion represents a version of a schema in AWS Glue
@@ -96,9 +110,14 @@ type ParsedSchema struct {
 	RecordName    string   `json:"record_name"`
 	Namespace     string   `json:"namespace"`
 	Documentation string   `json:"documentation"`
+	Aliases       []string `json:"aliases"`
 	Fields        []Field  `json:"fields"`
 	References    []string `json:"references"`
 	
+	// Names references are written as in the definition, where they differ
+	// from References: protobuf imports are referenced by file path
+	ReferenceNames map[string]string `json:"reference_names,omitempty"`
+	
 	// Computed properties
 	DetectedRole   SchemaRole `json:"detected_role"`
 	RoleReason     string     `json:"role_reason"`
@@ -118,18 +137,28 @@ type Field struct {
 // SchemaMapping represents the mapping from a Glue schema to a Confluent subject
 type SchemaMapping struct {
 	// Source
-	SourceRegistry   string `json:"source_registry"`
-	SourceSchemaName string `json:"source_schema_name"`
-	SourceVersions   int    `json:"source_versions"`
+	SourceRegistry   string     `json:"source_registry"`
+	SourceSchemaName string     `json:"source_schema_name"`
+	SourceVersions   int        `json:"source_versions"`
+	SourceFormat     SchemaType `json:"source_format,omitempty"`
+	
+	// Glue compatibility mode, applied to each target subject
+	SourceCompatibility string `json:"source_compatibility,omitempty"`
 	
 	// Target
 	TargetContext    string     `json:"target_context"`
 	TargetSubject    string     `json:"target_subject"`
+	KeySubject       string     `json:"key_subject,omitempty"` // also registered as a key subject
 	DetectedRole     SchemaRole `json:"detected_role"`
 	
+	// Source tags (only fetched when migrated as properties)
+	SourceTags       map[string]string `json:"source_tags,omitempty"`
+	
 	// Naming
 	NamingStrategy   string `json:"naming_strategy"`
 	NamingReason     string `json:"naming_reason,omitempty"`
+	SuggestedName    string `json:"suggested_name,omitempty"` // LLM suggestion before collision resolution
+	ContentHash      string `json:"content_hash,omitempty"`   // hash of the latest definition
 	
 	// Normalization
 	Transformations  []string `json:"transformations,omitempty"`
@@ -138,6 +167,16 @@ type SchemaMapping struct {
 	References       []string `json:"references,omitempty"`
 	DependencyLevel  int      `json:"dependency_level"`
 	
+	// Formats of referenced schemas, keyed like References
+	ReferenceFormats map[string]SchemaType `json:"reference_formats,omitempty"`
+	
+	// Context-qualified subjects of referenced schemas, keyed like References
+	ReferenceSubjects map[string]string `json:"reference_subjects,omitempty"`
+	
+	// Names the definition refers to referenced schemas by, keyed like
+	// References, when they differ from the schema name (protobuf imports)
+	ReferenceNames map[string]string `json:"reference_names,omitempty"`
+	
 	// Status
 	Status           MappingStatus `json:"status"`
 	Warning          string        `json:"warning,omitempty"`
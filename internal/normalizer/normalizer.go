@@ -1,7 +1,11 @@
 package normalizer
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"unicode"
 
@@ -247,64 +251,148 @@ func toSnakeCase(s string) string {
 
 // DetectCollisions detects naming collisions in the mappings
 func (n *Normalizer) DetectCollisions(mappings []*models.SchemaMapping) []models.Collision {
-	// Map normalized names to source schemas
-	normalizedMap := make(map[string][]string)
+	return Collisions(mappings)
+}
 
-	for _, m := range mappings {
-		// Format full subject with context (only add prefix if context is not empty)
-		fullTarget := m.TargetSubject
-		if m.TargetContext != "" {
-			fullTarget = m.TargetContext + ":" + m.TargetSubject
-		}
-		sourceKey := m.SourceRegistry + "." + m.SourceSchemaName
-		normalizedMap[fullTarget] = append(normalizedMap[fullTarget], sourceKey)
-	}
+// Collisions reports every full subject (context plus subject name) that
+// more than one mapping targets, sorted by subject. It is the one collision
+// check shared by the normalizer, validator and migrator, so the same
+// subject name in different contexts is never a collision in any of them.
+// Key subjects count as targets too.
+func Collisions(mappings []*models.SchemaMapping) []models.Collision {
+	targetMap, targets := groupByTarget(mappings, true)
 
-	// Find collisions (multiple sources mapping to same target)
 	var collisions []models.Collision
-	for normalized, sources := range normalizedMap {
-		if len(sources) > 1 {
-			collisions = append(collisions, models.Collision{
-				NormalizedName: normalized,
-				SourceSchemas:  sources,
-			})
+	for _, target := range targets {
+		if len(targetMap[target]) < 2 {
+			continue
+		}
+		var sources []string
+		for _, m := range targetMap[target] {
+			sources = append(sources, m.SourceRegistry+"."+m.SourceSchemaName)
 		}
+		collisions = append(collisions, models.Collision{
+			NormalizedName: target,
+			SourceSchemas:  sources,
+		})
 	}
 
+	sort.Slice(collisions, func(i, j int) bool {
+		return collisions[i].NormalizedName < collisions[j].NormalizedName
+	})
 	return collisions
 }
 
+// groupByTarget groups mappings by their full subject, returning the
+// subjects in the order they were first seen. With withKeys, a mapping is
+// also grouped under its key subject.
+func groupByTarget(mappings []*models.SchemaMapping, withKeys bool) (map[string][]*models.SchemaMapping, []string) {
+	targetMap := make(map[string][]*models.SchemaMapping)
+	var targets []string
+	for _, m := range mappings {
+		subjects := []string{m.TargetSubject}
+		if withKeys && m.KeySubject != "" {
+			subjects = append(subjects, m.KeySubject)
+		}
+		for _, subject := range subjects {
+			// Format full subject with context (only add prefix if context is not empty)
+			fullTarget := subject
+			if m.TargetContext != "" {
+				fullTarget = m.TargetContext + ":" + subject
+			}
+			if _, seen := targetMap[fullTarget]; !seen {
+				targets = append(targets, fullTarget)
+			}
+			targetMap[fullTarget] = append(targetMap[fullTarget], m)
+		}
+	}
+	return targetMap, targets
+}
+
 // ResolveCollisions automatically resolves naming collisions based on configured strategy
 func (n *Normalizer) ResolveCollisions(mappings []*models.SchemaMapping) []*models.SchemaMapping {
+	resolved, _ := n.ResolveCollisionsExplained(mappings)
+	return resolved
+}
+
+// ResolveCollisionsExplained resolves collisions like ResolveCollisions and
+// also returns, per collision, the strategy applied and each source's final
+// subject. Collisions are sorted by the subject they collided on.
+func (n *Normalizer) ResolveCollisionsExplained(mappings []*models.SchemaMapping) ([]*models.SchemaMapping, []models.Collision) {
 	strategy := n.config.Normalization.CollisionResolution
 	if strategy == "" || strategy == "fail" {
-		return mappings // No resolution, let validation fail
+		return mappings, nil // No resolution, let validation fail
 	}
 
-	// Build map of target names to mappings
-	targetMap := make(map[string][]*models.SchemaMapping)
-	for _, m := range mappings {
-		fullTarget := m.TargetSubject
-		if m.TargetContext != "" {
-			fullTarget = m.TargetContext + ":" + m.TargetSubject
-		}
-		targetMap[fullTarget] = append(targetMap[fullTarget], m)
-	}
+	// Build map of target names to mappings. Key subjects come from mapping
+	// files and are never renamed, so a collision on one is left for
+	// validation to report.
+	targetMap, targets := groupByTarget(mappings, false)
 
 	// Resolve collisions
 	resolved := make([]*models.SchemaMapping, 0, len(mappings))
-	for _, mappingList := range targetMap {
+	var explained []models.Collision
+	for _, target := range targets {
+		mappingList := targetMap[target]
 		if len(mappingList) == 1 {
 			// No collision
 			resolved = append(resolved, mappingList[0])
 		} else {
-			// Collision detected, apply resolution strategy
+			// Collision detected, apply resolution strategy in a stable order
+			// so suffixes and kept schemas don't depend on extraction order
+			sortBySource(mappingList)
 			resolvedMappings := n.applyResolutionStrategy(mappingList, strategy)
 			resolved = append(resolved, resolvedMappings...)
+			explained = append(explained, explainCollision(target, strategy, mappingList, resolvedMappings))
 		}
 	}
 
-	return resolved
+	sort.Slice(explained, func(i, j int) bool {
+		return explained[i].NormalizedName < explained[j].NormalizedName
+	})
+
+	return resolved, explained
+}
+
+// explainCollision describes how a collision on target was resolved. Sources
+// missing from kept were dropped by the strategy.
+func explainCollision(target, strategy string, colliding, kept []*models.SchemaMapping) models.Collision {
+	keptSet := make(map[*models.SchemaMapping]bool, len(kept))
+	for _, m := range kept {
+		keptSet[m] = true
+	}
+
+	collision := models.Collision{
+		NormalizedName: target,
+		Strategy:       strategy,
+	}
+	for _, m := range colliding {
+		source := m.SourceRegistry + "." + m.SourceSchemaName
+		collision.SourceSchemas = append(collision.SourceSchemas, source)
+
+		outcome := models.CollisionOutcome{SourceSchema: source}
+		if keptSet[m] {
+			outcome.FinalSubject = m.TargetSubject
+			if m.TargetContext != "" {
+				outcome.FinalSubject = m.TargetContext + ":" + m.TargetSubject
+			}
+		} else {
+			outcome.Skipped = true
+		}
+		collision.Outcomes = append(collision.Outcomes, outcome)
+	}
+
+	return collision
+}
+
+// sortBySource orders mappings by source registry, then schema name
+func sortBySource(mappings []*models.SchemaMapping) {
+	sort.SliceStable(mappings, func(i, j int) bool {
+		if mappings[i].SourceRegistry != mappings[j].SourceRegistry {
+			return mappings[i].SourceRegistry < mappings[j].SourceRegistry
+		}
+		return mappings[i].SourceSchemaName < mappings[j].SourceSchemaName
+	})
 }
 
 func (n *Normalizer) applyResolutionStrategy(colliding []*models.SchemaMapping, strategy string) []*models.SchemaMapping {
@@ -313,8 +401,7 @@ func (n *Normalizer) applyResolutionStrategy(colliding []*models.SchemaMapping,
 		// Add numeric suffix to all but the first
 		for i, m := range colliding {
 			if i > 0 {
-				m.TargetSubject = m.TargetSubject + "-" + string(rune('0'+i))
-				m.Transformations = append(m.Transformations, "collision-suffix")
+				n.rename(m, m.TargetSubject+"-"+strconv.Itoa(i), "collision-suffix")
 			}
 		}
 		return colliding
@@ -322,8 +409,7 @@ func (n *Normalizer) applyResolutionStrategy(colliding []*models.SchemaMapping,
 	case "registry-prefix":
 		// Add registry name as prefix to all
 		for _, m := range colliding {
-			m.TargetSubject = m.SourceRegistry + "-" + m.TargetSubject
-			m.Transformations = append(m.Transformations, "registry-prefix")
+			n.rename(m, m.SourceRegistry+"-"+m.TargetSubject, "registry-prefix")
 		}
 		return colliding
 
@@ -351,6 +437,42 @@ func (n *Normalizer) applyResolutionStrategy(colliding []*models.SchemaMapping,
 	}
 }
 
+// rename gives a colliding mapping its resolved subject. The added suffix or
+// prefix can push a name that was truncated to the limit back over it, so
+// the result is truncated again; hashing the full name keeps it distinct.
+func (n *Normalizer) rename(m *models.SchemaMapping, subject, transformation string) {
+	subject, truncated := n.TruncateWithHash(subject)
+	m.TargetSubject = subject
+	m.Transformations = append(m.Transformations, transformation)
+	m.Transformations = append(m.Transformations, truncated...)
+}
+
+// TruncateWithHash shortens a subject name longer than
+// normalization.max_subject_length. The cut-off tail is replaced with the
+// first 8 hex characters of the SHA-256 of the full name, so long names that
+// share a prefix stay distinct, and a trailing -key or -value is kept.
+func (n *Normalizer) TruncateWithHash(name string) (string, []string) {
+	limit := n.config.Normalization.MaxSubjectLength
+	if limit <= 0 || len(name) <= limit {
+		return name, nil
+	}
+
+	suffix := ""
+	for _, s := range []string{"-key", "-value"} {
+		if strings.HasSuffix(name, s) {
+			suffix = s
+			break
+		}
+	}
+
+	sum := sha256.Sum256([]byte(name))
+	hash := "-" + hex.EncodeToString(sum[:])[:8]
+
+	// max_subject_length is at least 16, which leaves room for both
+	base := strings.TrimRight(name[:limit-len(hash)-len(suffix)], "-_.")
+	return base + hash + suffix, []string{"truncated-with-hash"}
+}
+
 // StripKeySuffix removes key-related suffixes from a name
 func StripKeySuffix(name string) string {
 	suffixes := []string{"-key", "_key", "Key", "-k", "_k"}
@@ -1,8 +1,13 @@
 package normalizer
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
 	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
 )
 
@@ -193,3 +198,213 @@ func TestStripValueSuffix(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveCollisionsExplained_ThreeWaySuffix(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Normalization.CollisionResolution = "suffix"
+	n := New(cfg)
+
+	mappings := []*models.SchemaMapping{
+		{SourceRegistry: "a", SourceSchemaName: "UserEvent", TargetSubject: "user-event-value"},
+		{SourceRegistry: "b", SourceSchemaName: "user.event", TargetSubject: "user-event-value"},
+		{SourceRegistry: "c", SourceSchemaName: "user_event", TargetSubject: "user-event-value"},
+		{SourceRegistry: "a", SourceSchemaName: "Order", TargetSubject: "order-value"},
+	}
+
+	resolved, explained := n.ResolveCollisionsExplained(mappings)
+	if len(resolved) != 4 {
+		t.Fatalf("expected 4 resolved mappings, got %d", len(resolved))
+	}
+	if len(explained) != 1 {
+		t.Fatalf("expected 1 explained collision, got %d", len(explained))
+	}
+
+	c := explained[0]
+	if c.NormalizedName != "user-event-value" || c.Strategy != "suffix" {
+		t.Errorf("collision = %q (strategy %q), expected user-event-value (suffix)", c.NormalizedName, c.Strategy)
+	}
+
+	expected := map[string]string{
+		"a.UserEvent":  "user-event-value",
+		"b.user.event": "user-event-value-1",
+		"c.user_event": "user-event-value-2",
+	}
+	if len(c.Outcomes) != len(expected) {
+		t.Fatalf("expected %d outcomes, got %d", len(expected), len(c.Outcomes))
+	}
+	for _, o := range c.Outcomes {
+		if o.Skipped {
+			t.Errorf("%s unexpectedly skipped", o.SourceSchema)
+		}
+		if want := expected[o.SourceSchema]; o.FinalSubject != want {
+			t.Errorf("%s final subject = %q, expected %q", o.SourceSchema, o.FinalSubject, want)
+		}
+	}
+}
+
+func TestResolveCollisions_ManySuffixesStable(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Normalization.CollisionResolution = "suffix"
+	n := New(cfg)
+
+	// 12 schemas colliding on one subject, handed over in a different order
+	// each time
+	build := func(order []int) []*models.SchemaMapping {
+		var mappings []*models.SchemaMapping
+		for _, i := range order {
+			mappings = append(mappings, &models.SchemaMapping{
+				SourceRegistry:   "reg",
+				SourceSchemaName: fmt.Sprintf("event-%02d", i),
+				TargetSubject:    "event-value",
+			})
+		}
+		return mappings
+	}
+	orders := [][]int{
+		{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11},
+		{11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1, 0},
+		{5, 0, 9, 3, 11, 1, 7, 10, 2, 8, 4, 6},
+	}
+
+	for _, order := range orders {
+		subjects := make(map[string]string)
+		for _, m := range n.ResolveCollisions(build(order)) {
+			subjects[m.SourceSchemaName] = m.TargetSubject
+		}
+		if len(subjects) != 12 {
+			t.Fatalf("order %v: expected 12 resolved mappings, got %d", order, len(subjects))
+		}
+		for i := 0; i < 12; i++ {
+			want := "event-value"
+			if i > 0 {
+				want = fmt.Sprintf("event-value-%d", i)
+			}
+			if got := subjects[fmt.Sprintf("event-%02d", i)]; got != want {
+				t.Errorf("order %v: event-%02d subject = %q, want %q", order, i, got, want)
+			}
+		}
+	}
+}
+
+func TestResolveCollisions_MaxLengthNames(t *testing.T) {
+	long := strings.Repeat("com-example-deeply-nested-", 11) + "order-created-value"
+
+	for _, strategy := range []string{"suffix", "registry-prefix"} {
+		cfg := config.NewDefaultConfig()
+		cfg.Normalization.CollisionResolution = strategy
+		n := New(cfg)
+
+		// A name already truncated to exactly max_subject_length
+		target, _ := n.TruncateWithHash(long)
+		if len(target) != 255 {
+			t.Fatalf("test target is %d chars, want 255", len(target))
+		}
+		mappings := []*models.SchemaMapping{
+			{SourceRegistry: "a", SourceSchemaName: "OrderCreated", TargetSubject: target},
+			{SourceRegistry: "b", SourceSchemaName: "order.created", TargetSubject: target},
+			{SourceRegistry: "c", SourceSchemaName: "order_created", TargetSubject: target},
+		}
+
+		seen := make(map[string]bool)
+		for _, m := range n.ResolveCollisions(mappings) {
+			if len(m.TargetSubject) > 255 {
+				t.Errorf("%s: %s resolved to %d chars, want at most 255", strategy, m.SourceSchemaName, len(m.TargetSubject))
+			}
+			if seen[m.TargetSubject] {
+				t.Errorf("%s: %s resolved to duplicate subject %q", strategy, m.SourceSchemaName, m.TargetSubject)
+			}
+			seen[m.TargetSubject] = true
+		}
+		if len(seen) != 3 {
+			t.Errorf("%s: expected 3 distinct subjects, got %d", strategy, len(seen))
+		}
+	}
+}
+
+func TestResolveCollisionsExplained_SkipMarksDropped(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Normalization.CollisionResolution = "skip"
+	n := New(cfg)
+
+	mappings := []*models.SchemaMapping{
+		{SourceRegistry: "a", SourceSchemaName: "UserEvent", TargetSubject: "user-event-value"},
+		{SourceRegistry: "b", SourceSchemaName: "user.event", TargetSubject: "user-event-value"},
+	}
+
+	_, explained := n.ResolveCollisionsExplained(mappings)
+	if len(explained) != 1 || len(explained[0].Outcomes) != 2 {
+		t.Fatalf("expected 1 collision with 2 outcomes, got %+v", explained)
+	}
+	if explained[0].Outcomes[0].Skipped || !explained[0].Outcomes[1].Skipped {
+		t.Errorf("expected only the second source to be skipped, got %+v", explained[0].Outcomes)
+	}
+}
+
+func TestCollisions_SameSubjectDifferentContexts(t *testing.T) {
+	n := New(config.NewDefaultConfig())
+
+	mappings := []*models.SchemaMapping{
+		{SourceRegistry: "a", SourceSchemaName: "x", TargetContext: ".a", TargetSubject: "x"},
+		{SourceRegistry: "b", SourceSchemaName: "x", TargetContext: ".b", TargetSubject: "x"},
+		{SourceRegistry: "c", SourceSchemaName: "x", TargetSubject: "x"},
+	}
+	if collisions := n.DetectCollisions(mappings); len(collisions) != 0 {
+		t.Errorf("expected no collisions across contexts, got %+v", collisions)
+	}
+
+	mappings = append(mappings, &models.SchemaMapping{SourceRegistry: "d", SourceSchemaName: "x", TargetContext: ".a", TargetSubject: "x"})
+	collisions := Collisions(mappings)
+	if len(collisions) != 1 || collisions[0].NormalizedName != ".a:x" || len(collisions[0].SourceSchemas) != 2 {
+		t.Errorf("expected a single collision on .a:x, got %+v", collisions)
+	}
+}
+
+func TestCollisions_KeySubject(t *testing.T) {
+	mappings := []*models.SchemaMapping{
+		{SourceRegistry: "a", SourceSchemaName: "order", TargetSubject: "order-value", KeySubject: "order-key"},
+		{SourceRegistry: "a", SourceSchemaName: "order-key", TargetSubject: "order-key"},
+	}
+	collisions := Collisions(mappings)
+	if len(collisions) != 1 || collisions[0].NormalizedName != "order-key" || len(collisions[0].SourceSchemas) != 2 {
+		t.Errorf("expected a single collision on the key subject order-key, got %+v", collisions)
+	}
+}
+
+func TestTruncateWithHash(t *testing.T) {
+	n := New(config.NewDefaultConfig())
+
+	long := strings.Repeat("com-example-deeply-nested-", 11) + "order-created-value"
+	if len(long) < 300 {
+		t.Fatalf("test input is %d chars, want at least 300", len(long))
+	}
+
+	got, transforms := n.TruncateWithHash(long)
+	if len(got) > 255 {
+		t.Errorf("truncated name is %d chars, want at most 255", len(got))
+	}
+	if !strings.HasSuffix(got, "-value") {
+		t.Errorf("truncated name %q lost its -value suffix", got)
+	}
+	sum := sha256.Sum256([]byte(long))
+	if hash := hex.EncodeToString(sum[:])[:8]; !strings.HasSuffix(got, "-"+hash+"-value") {
+		t.Errorf("truncated name %q does not end in the hash %s", got, hash)
+	}
+	if len(transforms) != 1 || transforms[0] != "truncated-with-hash" {
+		t.Errorf("transformations = %v, want [truncated-with-hash]", transforms)
+	}
+
+	again, _ := New(config.NewDefaultConfig()).TruncateWithHash(long)
+	if again != got {
+		t.Errorf("truncation is not stable: %q then %q", got, again)
+	}
+
+	// Names sharing the first 255 characters still come out different
+	other, _ := n.TruncateWithHash(strings.TrimSuffix(long, "-value") + "-other-value")
+	if other == got {
+		t.Errorf("distinct long names truncated to the same subject %q", got)
+	}
+
+	if short, transforms := n.TruncateWithHash("order-created-value"); short != "order-created-value" || transforms != nil {
+		t.Errorf("short name changed to %q with %v", short, transforms)
+	}
+}
@@ -0,0 +1,128 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+
+	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
+)
+
+// htmlIssue is one row of the errors and warnings section
+type htmlIssue struct {
+	Level   string // error, warning, skipped
+	Schema  string
+	Message string
+}
+
+// htmlData is what htmlTemplate is rendered with
+type htmlData struct {
+	Report *models.MigrationReport
+	Issues []htmlIssue
+}
+
+// htmlTemplate renders a self-contained page: styles are inline and nothing
+// is loaded from elsewhere, so the file can be attached or shared as is.
+// html/template escapes every schema name, subject and message.
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Glue to Confluent Cloud SR migration report</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #222; }
+h1 { font-size: 1.5em; }
+h2 { font-size: 1.2em; margin-top: 2em; }
+table { border-collapse: collapse; margin-top: 0.5em; }
+th, td { border: 1px solid #ccc; padding: 4px 10px; text-align: left; vertical-align: top; }
+th { background: #f2f2f2; }
+td.count { text-align: right; }
+tr.status-ready, tr.status-success { background: #e8f5e9; }
+tr.status-warning { background: #fff8e1; }
+tr.status-error, tr.status-failed { background: #ffebee; }
+tr.status-skipped { background: #eceff1; }
+tr.issue-error { background: #ffebee; }
+tr.issue-warning { background: #fff8e1; }
+tr.issue-skipped { background: #eceff1; }
+</style>
+</head>
+<body>
+<h1>Glue to Confluent Cloud SR migration report{{if .Report.DryRun}} (dry run){{end}}</h1>
+<p>{{.Report.Source.Region}} &rarr; {{.Report.Target.URL}}<br>
+Started {{.Report.StartTime.Format "2006-01-02 15:04:05 MST"}}, took {{.Report.Duration}}</p>
+
+<h2>Summary</h2>
+<table class="summary">
+<tr><th>Registries</th><td class="count">{{.Report.Results.RegistriesProcessed}}</td></tr>
+<tr><th>Schemas</th><td class="count">{{.Report.Results.SchemasProcessed}}</td></tr>
+<tr><th>Versions</th><td class="count">{{.Report.Results.VersionsProcessed}}</td></tr>
+<tr><th>Successful</th><td class="count">{{.Report.Results.Successful}}</td></tr>
+<tr><th>Failed</th><td class="count">{{.Report.Results.Failed}}</td></tr>
+<tr><th>Skipped</th><td class="count">{{.Report.Results.Skipped}}</td></tr>
+{{- if .Report.Results.NotStarted}}
+<tr><th>Not started</th><td class="count">{{.Report.Results.NotStarted}}</td></tr>
+{{- end}}
+{{- if .Report.Results.AlreadyExists}}
+<tr><th>Already in target</th><td class="count">{{.Report.Results.AlreadyExists}}</td></tr>
+{{- end}}
+{{- if .Report.Results.LLMCalls}}
+<tr><th>LLM calls</th><td class="count">{{.Report.Results.LLMCalls}} (${{printf "%.2f" .Report.Results.LLMCost}})</td></tr>
+{{- end}}
+</table>
+
+<h2>Schemas</h2>
+<table class="schemas">
+<tr><th>Source</th><th>Target subject</th><th>Role</th><th>Strategy</th><th>Versions</th><th>Status</th></tr>
+{{- range .Report.Schemas}}
+<tr class="status-{{.Status}}"><td>{{.SourceRegistry}}.{{.SourceSchema}}</td><td>{{if .TargetContext}}{{.TargetContext}}:{{end}}{{.TargetSubject}}</td><td>{{.DetectedRole}}</td><td>{{.NamingStrategy}}</td><td class="count">{{.VersionsMigrated}}</td><td>{{.Status}}</td></tr>
+{{- end}}
+</table>
+
+<h2>Errors and warnings</h2>
+{{- if .Issues}}
+<table class="issues">
+<tr><th>Level</th><th>Schema</th><th>Message</th></tr>
+{{- range .Issues}}
+<tr class="issue-{{.Level}}"><td>{{.Level}}</td><td>{{.Schema}}</td><td>{{.Message}}</td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p>None.</p>
+{{- end}}
+</body>
+</html>
+`))
+
+// writeHTML renders the report as a self-contained HTML page
+func writeHTML(w io.Writer, report *models.MigrationReport) error {
+	data := htmlData{Report: report, Issues: htmlIssues(report)}
+	if err := htmlTemplate.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to render HTML report: %w", err)
+	}
+	return nil
+}
+
+// htmlIssues collects the report's errors, warnings and dead letters, with
+// per-schema errors and warnings first
+func htmlIssues(report *models.MigrationReport) []htmlIssue {
+	var issues []htmlIssue
+	for _, s := range report.Schemas {
+		schema := s.SourceRegistry + "." + s.SourceSchema
+		if s.Error != "" {
+			issues = append(issues, htmlIssue{Level: "error", Schema: schema, Message: s.Error})
+		}
+		if s.Warning != "" {
+			issues = append(issues, htmlIssue{Level: "warning", Schema: schema, Message: s.Warning})
+		}
+	}
+	for _, e := range report.Errors {
+		issues = append(issues, htmlIssue{Level: "error", Schema: e.Schema, Message: e.Message})
+	}
+	for _, w := range report.Warnings {
+		issues = append(issues, htmlIssue{Level: "warning", Schema: w.Schema, Message: w.Message})
+	}
+	for _, d := range report.DeadLetters {
+		issues = append(issues, htmlIssue{Level: "skipped", Schema: d.SourceRegistry + "." + d.SourceSchema, Message: d.Reason})
+	}
+	return issues
+}
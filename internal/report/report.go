@@ -0,0 +1,144 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
+)
+
+// csvHeader lists the per-schema columns written in CSV format
+var csvHeader = []string{
+	"source_registry",
+	"source_schema",
+	"target_context",
+	"target_subject",
+	"detected_role",
+	"naming_strategy",
+	"versions_migrated",
+	"references",
+	"status",
+	"warning",
+	"error",
+}
+
+// Write renders the migration report to w in the given format.
+// "table" has no machine-readable form and is written as JSON.
+func Write(w io.Writer, report *models.MigrationReport, format string) error {
+	switch format {
+	case "json", "table", "":
+		return writeJSON(w, report)
+	case "csv":
+		return writeCSV(w, report)
+	case "html":
+		return writeHTML(w, report)
+	default:
+		return fmt.Errorf("unsupported report format: %s", format)
+	}
+}
+
+// WriteFile writes the migration report to path in the given format. Unlike
+// Write, "table" is written as aligned columns, one row per schema, since a
+// report file is meant to be read rather than piped.
+func WriteFile(path string, report *models.MigrationReport, format string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create report file: %w", err)
+	}
+
+	if format == "table" {
+		err = writeTable(f, report)
+	} else {
+		err = Write(f, report, format)
+	}
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write report file: %w", err)
+	}
+	return nil
+}
+
+// Load reads a report previously written in JSON format
+func Load(path string) (*models.MigrationReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report: %w", err)
+	}
+
+	var report models.MigrationReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse report %s: %w", path, err)
+	}
+	return &report, nil
+}
+
+func writeJSON(w io.Writer, report *models.MigrationReport) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	return nil
+}
+
+func writeCSV(w io.Writer, report *models.MigrationReport) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return fmt.Errorf("failed to write report header: %w", err)
+	}
+
+	for _, s := range report.Schemas {
+		if err := cw.Write(schemaRow(s)); err != nil {
+			return fmt.Errorf("failed to write report row: %w", err)
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// writeTable writes the CSV columns as whitespace-aligned text
+func writeTable(w io.Writer, report *models.MigrationReport) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := make([]string, len(csvHeader))
+	for i, column := range csvHeader {
+		header[i] = strings.ToUpper(column)
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, s := range report.Schemas {
+		fmt.Fprintln(tw, strings.Join(schemaRow(s), "\t"))
+	}
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// schemaRow returns a schema's values in csvHeader order
+func schemaRow(s models.SchemaReport) []string {
+	return []string{
+		s.SourceRegistry,
+		s.SourceSchema,
+		s.TargetContext,
+		s.TargetSubject,
+		string(s.DetectedRole),
+		s.NamingStrategy,
+		strconv.Itoa(s.VersionsMigrated),
+		strings.Join(s.References, ";"),
+		s.Status,
+		s.Warning,
+		s.Error,
+	}
+}
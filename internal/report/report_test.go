@@ -0,0 +1,142 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
+)
+
+func TestWrite_CSV(t *testing.T) {
+	r := &models.MigrationReport{
+		Schemas: []models.SchemaReport{
+			{SourceRegistry: "payments", SourceSchema: "Order", TargetSubject: "order-value", References: []string{"a", "b"}, Status: "ready"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, r, "csv"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("expected header and 1 row, got %d rows", len(rows))
+	}
+	if rows[1][0] != "payments" || rows[1][3] != "order-value" || rows[1][7] != "a;b" {
+		t.Errorf("unexpected row: %v", rows[1])
+	}
+}
+
+func TestWrite_UnsupportedFormat(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, &models.MigrationReport{}, "xml"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	r := &models.MigrationReport{
+		Schemas: []models.SchemaReport{
+			{SourceRegistry: "payments", SourceSchema: "Order", TargetSubject: "order-value", DetectedRole: models.SchemaRoleValue, NamingStrategy: "topic", Status: "ready"},
+			{SourceRegistry: "payments", SourceSchema: "OrderKey", TargetSubject: "order-key", DetectedRole: models.SchemaRoleKey, NamingStrategy: "topic", Status: "ready"},
+		},
+	}
+	dir := t.TempDir()
+
+	t.Run("json", func(t *testing.T) {
+		path := filepath.Join(dir, "report.json")
+		if err := WriteFile(path, r, "json"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got, err := Load(path)
+		if err != nil {
+			t.Fatalf("failed to load report: %v", err)
+		}
+		if len(got.Schemas) != 2 || got.Schemas[1].TargetSubject != "order-key" || got.Schemas[1].DetectedRole != models.SchemaRoleKey {
+			t.Errorf("unexpected schemas: %+v", got.Schemas)
+		}
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		path := filepath.Join(dir, "report.csv")
+		if err := WriteFile(path, r, "csv"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		rows, err := csv.NewReader(f).ReadAll()
+		if err != nil {
+			t.Fatalf("file is not valid CSV: %v", err)
+		}
+		want := []string{"payments", "OrderKey", "", "order-key", "key", "topic"}
+		if len(rows) != 3 || !reflect.DeepEqual(rows[2][:6], want) || rows[2][8] != "ready" {
+			t.Errorf("rows = %v, expected the OrderKey row %v", rows, want)
+		}
+	})
+
+	t.Run("table", func(t *testing.T) {
+		path := filepath.Join(dir, "report.txt")
+		if err := WriteFile(path, r, "table"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+		if len(lines) != 3 {
+			t.Fatalf("expected a header and 2 rows, got:\n%s", data)
+		}
+		if fields := strings.Fields(lines[2]); !reflect.DeepEqual(fields[:5], []string{"payments", "OrderKey", "order-key", "key", "topic"}) {
+			t.Errorf("row = %q, expected the OrderKey columns", lines[2])
+		}
+		// Columns line up: the subject starts at the same offset on every line
+		if strings.Index(lines[0], "TARGET_SUBJECT") != strings.Index(lines[1], "order-value") {
+			t.Errorf("columns are not aligned:\n%s", data)
+		}
+	})
+}
+
+func TestWrite_HTML(t *testing.T) {
+	r := &models.MigrationReport{
+		Results: models.ResultsReport{RegistriesProcessed: 1, SchemasProcessed: 2, VersionsProcessed: 5, Successful: 1, Failed: 1},
+		Schemas: []models.SchemaReport{
+			{SourceRegistry: "payments", SourceSchema: "Order", TargetSubject: "order-value", Status: "success"},
+			{SourceRegistry: "payments", SourceSchema: "<script>alert(1)</script>", TargetSubject: "evil-value", Status: "failed", Error: "incompatible <b>schema</b>"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, r, "html"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		`<tr><th>Schemas</th><td class="count">2</td></tr>`,
+		`<tr><th>Versions</th><td class="count">5</td></tr>`,
+		`<tr><th>Failed</th><td class="count">1</td></tr>`,
+		`<tr class="status-failed">`,
+		"payments.&lt;script&gt;alert(1)&lt;/script&gt;",
+		"incompatible &lt;b&gt;schema&lt;/b&gt;",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in the HTML report", want)
+		}
+	}
+	if strings.Contains(out, "<script>") || strings.Contains(out, "<b>") {
+		t.Errorf("schema name or message was not escaped:\n%s", out)
+	}
+}
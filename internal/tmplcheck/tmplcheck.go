@@ -0,0 +1,265 @@
+// Package tmplcheck checks the fields a text/template refers to against the
+// type it will be executed with, without executing it. Rendering with sample
+// data only reaches the branches the sample selects, so a misspelt field
+// inside an if or range would otherwise pass until real data reached it.
+package tmplcheck
+
+import (
+	"fmt"
+	"reflect"
+	"text/template"
+	"text/template/parse"
+)
+
+// Fields walks every node of tmpl, including branches and the templates it
+// invokes, and reports the first field that data's type doesn't have. Dot is
+// followed into with and range blocks; where its type can't be known, such
+// as after a function call, fields are not checked.
+func Fields(tmpl *template.Template, data any) error {
+	if tmpl.Tree == nil || tmpl.Tree.Root == nil {
+		return nil
+	}
+	root := reflect.TypeOf(data)
+	c := &checker{tmpl: tmpl, root: root, visited: map[visit]bool{}}
+	return c.walk(tmpl.Tree.Root, root, scope{})
+}
+
+// scope holds the types of the variables declared so far; nil is unknown
+type scope map[string]reflect.Type
+
+func (s scope) with(name string, t reflect.Type) scope {
+	next := make(scope, len(s)+1)
+	for k, v := range s {
+		next[k] = v
+	}
+	next[name] = t
+	return next
+}
+
+// visit keys the named templates already walked with a given dot
+type visit struct {
+	name string
+	dot  reflect.Type
+}
+
+type checker struct {
+	tmpl    *template.Template
+	root    reflect.Type
+	visited map[visit]bool
+}
+
+func (c *checker) walk(node parse.Node, dot reflect.Type, vars scope) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			action, ok := child.(*parse.ActionNode)
+			if !ok {
+				if err := c.walk(child, dot, vars); err != nil {
+					return err
+				}
+				continue
+			}
+			// {{$x := .Field}} stays in scope for the rest of the list
+			t, err := c.value(action.Pipe, dot, vars)
+			if err != nil {
+				return err
+			}
+			for _, decl := range action.Pipe.Decl {
+				vars = vars.with(decl.Ident[0], t)
+			}
+		}
+	case *parse.IfNode:
+		return c.branch(&n.BranchNode, dot, vars)
+	case *parse.WithNode:
+		return c.branch(&n.BranchNode, dot, vars)
+	case *parse.RangeNode:
+		return c.branch(&n.BranchNode, dot, vars)
+	case *parse.TemplateNode:
+		// Without a pipeline the named template runs with nil data
+		t, err := c.value(n.Pipe, dot, vars)
+		if err != nil {
+			return err
+		}
+		return c.invoke(n.Name, t)
+	}
+	return nil
+}
+
+// branch checks an if, with or range block. The pipeline and else list see
+// the outer dot; a with body sees the pipeline's value and a range body its
+// elements.
+func (c *checker) branch(n *parse.BranchNode, dot reflect.Type, vars scope) error {
+	t, err := c.value(n.Pipe, dot, vars)
+	if err != nil {
+		return err
+	}
+	inner, bodyVars := dot, vars
+	switch n.NodeType {
+	case parse.NodeWith:
+		inner = t
+		for _, decl := range n.Pipe.Decl {
+			bodyVars = bodyVars.with(decl.Ident[0], t)
+		}
+	case parse.NodeRange:
+		// {{range $i, $e := .List}} binds the index and element
+		inner = elem(t)
+		switch len(n.Pipe.Decl) {
+		case 1:
+			bodyVars = vars.with(n.Pipe.Decl[0].Ident[0], elem(t))
+		case 2:
+			bodyVars = vars.with(n.Pipe.Decl[0].Ident[0], key(t)).with(n.Pipe.Decl[1].Ident[0], elem(t))
+		}
+	default:
+		for _, decl := range n.Pipe.Decl {
+			bodyVars = bodyVars.with(decl.Ident[0], t)
+		}
+	}
+	if err := c.walk(n.List, inner, bodyVars); err != nil {
+		return err
+	}
+	return c.walk(n.ElseList, dot, vars)
+}
+
+// invoke walks a {{template}} call's target with the dot passed to it
+func (c *checker) invoke(name string, dot reflect.Type) error {
+	tmpl := c.tmpl.Lookup(name)
+	if tmpl == nil || tmpl.Tree == nil || c.visited[visit{name, dot}] {
+		return nil
+	}
+	c.visited[visit{name, dot}] = true
+	return c.walk(tmpl.Tree.Root, dot, scope{})
+}
+
+// value checks every command of a pipeline and returns the type of its
+// result when it is a plain field or variable, and nil otherwise
+func (c *checker) value(p *parse.PipeNode, dot reflect.Type, vars scope) (reflect.Type, error) {
+	if p == nil {
+		return nil, nil
+	}
+	var result reflect.Type
+	for i, cmd := range p.Cmds {
+		result = nil
+		for _, arg := range cmd.Args {
+			t, err := c.arg(arg, dot, vars)
+			if err != nil {
+				return nil, err
+			}
+			if len(cmd.Args) == 1 && i == len(p.Cmds)-1 {
+				result = t
+			}
+		}
+	}
+	return result, nil
+}
+
+// arg checks a single command argument and returns its type if known
+func (c *checker) arg(node parse.Node, dot reflect.Type, vars scope) (reflect.Type, error) {
+	switch n := node.(type) {
+	case *parse.DotNode:
+		return dot, nil
+	case *parse.FieldNode:
+		return c.fields(n, dot, n.Ident)
+	case *parse.VariableNode:
+		var t reflect.Type
+		if n.Ident[0] == "$" {
+			t = c.root
+		} else {
+			t = vars[n.Ident[0]]
+		}
+		return c.fields(n, t, n.Ident[1:])
+	case *parse.ChainNode:
+		t, err := c.arg(n.Node, dot, vars)
+		if err != nil {
+			return nil, err
+		}
+		return c.fields(n, t, n.Field)
+	case *parse.PipeNode:
+		return c.value(n, dot, vars)
+	}
+	return nil, nil
+}
+
+// fields follows a chain of field names from t, failing on the first one t
+// doesn't have. An unknown t, an interface or a map ends the check.
+func (c *checker) fields(node parse.Node, t reflect.Type, names []string) (reflect.Type, error) {
+	for _, name := range names {
+		if t == nil {
+			return nil, nil
+		}
+		if m, ok := method(t, name); ok {
+			if m.Type.NumOut() == 0 {
+				return nil, nil
+			}
+			t = m.Type.Out(0)
+			continue
+		}
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		switch t.Kind() {
+		case reflect.Struct:
+			f, ok := t.FieldByName(name)
+			if !ok || !f.IsExported() {
+				location, _ := c.tmpl.ErrorContext(node)
+				return nil, fmt.Errorf("%s: can't evaluate field %s in type %s", location, name, t)
+			}
+			t = f.Type
+		case reflect.Map:
+			t = t.Elem()
+		case reflect.Interface:
+			return nil, nil
+		default:
+			location, _ := c.tmpl.ErrorContext(node)
+			return nil, fmt.Errorf("%s: can't evaluate field %s in type %s", location, name, t)
+		}
+	}
+	return t, nil
+}
+
+// method finds an exported method on t or *t, as template execution does
+func method(t reflect.Type, name string) (reflect.Method, bool) {
+	if m, ok := t.MethodByName(name); ok {
+		return m, true
+	}
+	if t.Kind() != reflect.Pointer && t.Kind() != reflect.Interface {
+		return reflect.PointerTo(t).MethodByName(name)
+	}
+	return reflect.Method{}, false
+}
+
+// elem is the type range binds dot to for a value of type t
+func elem(t reflect.Type) reflect.Type {
+	if t == nil {
+		return nil
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Chan:
+		return t.Elem()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return t
+	}
+	return nil
+}
+
+// key is the type of the index range binds for a value of type t
+func key(t reflect.Type) reflect.Type {
+	if t == nil {
+		return nil
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		return reflect.TypeOf(0)
+	case reflect.Map:
+		return t.Key()
+	}
+	return nil
+}
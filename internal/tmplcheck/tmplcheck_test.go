@@ -0,0 +1,65 @@
+package tmplcheck
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+)
+
+type inner struct {
+	Name string
+}
+
+type data struct {
+	Title  string
+	Items  []inner
+	ByName map[string]inner
+	Nested *inner
+	Any    any
+}
+
+func (data) Upper() string { return "" }
+
+func TestFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantErr string
+	}{
+		{name: "top-level field", text: "{{.Title}}"},
+		{name: "method", text: "{{.Upper}}"},
+		{name: "unknown field", text: "{{.Missing}}", wantErr: "Missing"},
+		{name: "unknown field in if", text: "{{if .Title}}{{.Missing}}{{end}}", wantErr: "Missing"},
+		{name: "unknown field in else", text: "{{if .Title}}{{else}}{{.Missing}}{{end}}", wantErr: "Missing"},
+		{name: "range element field", text: "{{range .Items}}{{.Name}}{{end}}"},
+		{name: "unknown range element field", text: "{{range .Items}}{{.Title}}{{end}}", wantErr: "Title"},
+		{name: "range variables", text: "{{range $i, $item := .Items}}{{$i}}{{$item.Name}}{{$.Title}}{{end}}"},
+		{name: "unknown field on range variable", text: "{{range $item := .Items}}{{$item.Title}}{{end}}", wantErr: "Title"},
+		{name: "unknown field on root variable", text: "{{range .Items}}{{$.Name}}{{end}}", wantErr: "Name"},
+		{name: "with pointer", text: "{{with .Nested}}{{.Name}}{{end}}"},
+		{name: "unknown field in with", text: "{{with .Nested}}{{.Title}}{{end}}", wantErr: "Title"},
+		{name: "declared variable", text: "{{$n := .Nested}}{{$n.Name}}"},
+		{name: "unknown field on declared variable", text: "{{$n := .Nested}}{{$n.Title}}", wantErr: "Title"},
+		{name: "map values", text: "{{.ByName.anything.Name}}"},
+		{name: "interface is not checked", text: "{{.Any.Whatever}}"},
+		{name: "function results are not checked", text: "{{(index .Items 0).Name}}{{with index .Items 0}}{{.Whatever}}{{end}}"},
+		{name: "field on string", text: "{{.Title.Length}}", wantErr: "Length"},
+		{name: "named template", text: `{{define "item"}}{{.Title}}{{end}}{{range .Items}}{{template "item" .}}{{end}}`, wantErr: "Title"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := template.Must(template.New("test").Parse(tt.text))
+			err := Fields(tmpl, data{})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Fields() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Fields() error = %v, want one naming %s", err, tt.wantErr)
+			}
+		})
+	}
+}
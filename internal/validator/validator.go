@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/normalizer"
 	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
 )
 
@@ -40,33 +41,19 @@ func New(cfg *config.Config) *Validator {
 func (v *Validator) ValidateAll(mappings []*models.SchemaMapping) *ValidationResult {
 	result := &ValidationResult{}
 
-	// Track subject names for collision detection
-	subjectMap := make(map[string][]string)
-
 	for _, mapping := range mappings {
 		// Validate individual mapping
 		errs, warns := v.ValidateMapping(mapping)
 		result.Errors = append(result.Errors, errs...)
 		result.Warnings = append(result.Warnings, warns...)
-
-		// Track for collision detection
-		// Format full subject with context (only add prefix if context is not empty)
-		fullSubject := mapping.TargetSubject
-		if mapping.TargetContext != "" {
-			fullSubject = mapping.TargetContext + ":" + mapping.TargetSubject
-		}
-		sourceKey := mapping.SourceRegistry + "." + mapping.SourceSchemaName
-		subjectMap[fullSubject] = append(subjectMap[fullSubject], sourceKey)
 	}
 
-	// Check for collisions
-	for subject, sources := range subjectMap {
-		if len(sources) > 1 {
-			result.Errors = append(result.Errors, models.Error{
-				Schema:  strings.Join(sources, ", "),
-				Message: "Naming collision: multiple schemas map to " + subject,
-			})
-		}
+	// Check for collisions the same way the migrator does
+	for _, c := range normalizer.Collisions(mappings) {
+		result.Errors = append(result.Errors, models.Error{
+			Schema:  strings.Join(c.SourceSchemas, ", "),
+			Message: "Naming collision: multiple schemas map to " + c.NormalizedName,
+		})
 	}
 
 	return result
@@ -86,6 +73,16 @@ func (v *Validator) ValidateMapping(mapping *models.SchemaMapping) ([]models.Err
 		})
 	}
 
+	// A key subject is registered alongside the target subject
+	if mapping.KeySubject != "" {
+		if err := v.validateSubjectName(mapping.KeySubject); err != nil {
+			errors = append(errors, models.Error{
+				Schema:  sourceKey,
+				Message: "key " + err.Error(),
+			})
+		}
+	}
+
 	// Validate context name
 	if mapping.TargetContext != "" {
 		if err := v.validateContextName(mapping.TargetContext); err != nil {
@@ -96,6 +93,14 @@ func (v *Validator) ValidateMapping(mapping *models.SchemaMapping) ([]models.Err
 		}
 	}
 
+	// Enforce the context allow-list
+	if err := v.checkAllowedContext(mapping.TargetContext); err != nil {
+		errors = append(errors, models.Error{
+			Schema:  sourceKey,
+			Message: err.Error(),
+		})
+	}
+
 	// Check for potential issues (warnings)
 	warns := v.checkWarnings(mapping)
 	warnings = append(warnings, warns...)
@@ -141,15 +146,44 @@ func (v *Validator) validateContextName(context string) error {
 	// Remove the leading dot for validation
 	contextName := strings.TrimPrefix(context, ".")
 
-	// Check for invalid characters
+	// Hierarchical contexts such as .payments.refunds are allowed, but every
+	// dot-separated segment must be non-empty
 	validPattern := regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
-	if !validPattern.MatchString(contextName) {
-		return &ValidationError{Message: "context name contains invalid characters"}
+	for _, segment := range strings.Split(contextName, ".") {
+		if segment == "" {
+			return &ValidationError{Message: "context name cannot have leading, trailing or consecutive dots"}
+		}
+		if !validPattern.MatchString(segment) {
+			return &ValidationError{Message: "context name contains invalid characters"}
+		}
 	}
 
 	return nil
 }
 
+// checkAllowedContext rejects contexts missing from naming.allowed_contexts.
+// Entries match with or without their leading dot, and "." is the default
+// context. An empty list allows every context.
+func (v *Validator) checkAllowedContext(context string) error {
+	allowed := v.config.Naming.AllowedContexts
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	name := strings.TrimPrefix(context, ".")
+	for _, a := range allowed {
+		if strings.TrimPrefix(strings.TrimSpace(a), ".") == name {
+			return nil
+		}
+	}
+
+	display := context
+	if display == "" {
+		display = ". (default)"
+	}
+	return &ValidationError{Message: "context " + display + " is not in naming.allowed_contexts"}
+}
+
 func (v *Validator) checkWarnings(mapping *models.SchemaMapping) []models.Warning {
 	var warnings []models.Warning
 	sourceKey := mapping.SourceRegistry + "." + mapping.SourceSchemaName
@@ -208,6 +242,21 @@ func (v *Validator) checkWarnings(mapping *models.SchemaMapping) []models.Warnin
 		}
 	}
 
+	// Note source names that already carry a subject role suffix, since the
+	// naming strategy reinterprets them
+	switch suffixes := existingSubjectSuffixes(mapping.SourceSchemaName); {
+	case len(suffixes) == 1:
+		warnings = append(warnings, models.Warning{
+			Schema:  sourceKey,
+			Message: "Source name already ends in subject suffix '" + suffixes[0] + "', which is treated as the role suffix; mapped to " + mapping.TargetSubject + " (use a name mapping to override)",
+		})
+	case len(suffixes) > 1:
+		warnings = append(warnings, models.Warning{
+			Schema:  sourceKey,
+			Message: "Source name ends in more than one subject suffix ('" + strings.Join(suffixes, "', '") + "'), so its role is ambiguous; mapped to " + mapping.TargetSubject + " (use a name mapping to override)",
+		})
+	}
+
 	// Warn about unresolved references
 	if len(mapping.References) > 0 && v.config.Migration.ReferenceStrategy != "rewrite" {
 		warnings = append(warnings, models.Warning{
@@ -219,6 +268,30 @@ func (v *Validator) checkWarnings(mapping *models.SchemaMapping) []models.Warnin
 	return warnings
 }
 
+// subjectSuffixes are the role suffixes Confluent subject names end in
+var subjectSuffixes = []string{"-key", "-value", ".key", ".value"}
+
+// existingSubjectSuffixes returns the role suffixes name already ends in,
+// innermost first, e.g. ["-value", "-key"] for "foo-value-key"
+func existingSubjectSuffixes(name string) []string {
+	var found []string
+	for {
+		lower := strings.ToLower(name)
+		matched := false
+		for _, suffix := range subjectSuffixes {
+			if len(lower) > len(suffix) && strings.HasSuffix(lower, suffix) {
+				found = append([]string{suffix}, found...)
+				name = name[:len(name)-len(suffix)]
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return found
+		}
+	}
+}
+
 // ValidationError represents a validation error
 type ValidationError struct {
 	Message string
@@ -1,9 +1,11 @@
 package validator
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/normalizer"
 	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
 )
 
@@ -51,6 +53,11 @@ func TestValidateContextName(t *testing.T) {
 		{"empty context (default)", "", false},
 		{"missing dot prefix", "payments", true},
 		{"invalid char in context", ".payment/context", true},
+		{"valid hierarchical context", ".payments.refunds", false},
+		{"double dot", ".payments..x", true},
+		{"trailing dot", ".payments.", true},
+		{"leading double dot", "..payments", true},
+		{"only a dot", ".", true},
 	}
 
 	for _, tt := range tests {
@@ -100,6 +107,75 @@ func TestValidateAll_Collisions(t *testing.T) {
 	}
 }
 
+func TestValidateMapping_KeySubject(t *testing.T) {
+	v := New(config.NewDefaultConfig())
+
+	mapping := &models.SchemaMapping{
+		SourceRegistry:   "reg1",
+		SourceSchemaName: "order",
+		TargetSubject:    "order-value",
+		KeySubject:       "order key",
+	}
+	errs, _ := v.ValidateMapping(mapping)
+	if len(errs) != 1 || !strings.HasPrefix(errs[0].Message, "key subject name contains invalid characters") {
+		t.Errorf("expected the invalid key subject to be reported, got %+v", errs)
+	}
+}
+
+func TestValidateAll_SameSubjectDifferentContexts(t *testing.T) {
+	v := New(config.NewDefaultConfig())
+
+	mappings := []*models.SchemaMapping{
+		{SourceRegistry: "a", SourceSchemaName: "x", TargetContext: ".a", TargetSubject: "x"},
+		{SourceRegistry: "b", SourceSchemaName: "x", TargetContext: ".b", TargetSubject: "x"},
+	}
+
+	result := v.ValidateAll(mappings)
+	if result.HasErrors() {
+		t.Errorf("expected no collision errors across contexts, got %+v", result.Errors)
+	}
+	if collisions := normalizer.Collisions(mappings); len(collisions) != 0 {
+		t.Errorf("normalizer disagrees with validator, got collisions %+v", collisions)
+	}
+}
+
+func TestValidateMapping_AllowedContexts(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Naming.AllowedContexts = []string{".payments", "orders"}
+	v := New(cfg)
+
+	tests := []struct {
+		context string
+		wantErr bool
+	}{
+		{".payments", false},
+		{".orders", false},
+		{".inventory", true},
+		{"", true}, // default context isn't listed
+	}
+
+	for _, tt := range tests {
+		mapping := &models.SchemaMapping{
+			SourceRegistry:   "reg",
+			SourceSchemaName: "UserEvent",
+			TargetContext:    tt.context,
+			TargetSubject:    "user-event-value",
+		}
+		errs, _ := v.ValidateMapping(mapping)
+		if (len(errs) > 0) != tt.wantErr {
+			t.Errorf("context %q: errors = %+v, wantErr %v", tt.context, errs, tt.wantErr)
+		}
+		if tt.wantErr && len(errs) > 0 && !strings.Contains(errs[0].Message, "allowed_contexts") {
+			t.Errorf("context %q: error = %q, expected it to name allowed_contexts", tt.context, errs[0].Message)
+		}
+	}
+
+	cfg.Naming.AllowedContexts = []string{".", ".payments"}
+	if errs, _ := v.ValidateMapping(&models.SchemaMapping{SourceRegistry: "reg", SourceSchemaName: "UserEvent", TargetSubject: "user-event-value"}); len(errs) > 0 {
+		t.Errorf("expected \".\" to allow the default context, got %+v", errs)
+	}
+}
+
 func TestCheckWarnings(t *testing.T) {
 	cfg := config.NewDefaultConfig()
 	v := New(cfg)
@@ -157,3 +233,49 @@ func TestCheckWarnings(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckWarnings_ExistingSubjectSuffix(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	v := New(cfg)
+
+	tests := []struct {
+		name        string
+		source      string
+		target      string
+		wantMessage []string // substrings of the suffix note; nil for no note
+	}{
+		{"value suffix", "foo-value", "foo-value", []string{"already ends in subject suffix '-value'", "mapped to foo-value"}},
+		{"dotted key suffix", "orders.key", "orders-key", []string{"'.key'", "mapped to orders-key"}},
+		{"ambiguous suffixes", "foo-value-key", "foo-key", []string{"'-value', '-key'", "ambiguous", "mapped to foo-key"}},
+		{"no suffix", "foo", "foo-value", nil},
+		{"suffix only", "value", "value-value", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings := v.checkWarnings(&models.SchemaMapping{
+				SourceRegistry:   "test",
+				SourceSchemaName: tt.source,
+				TargetSubject:    tt.target,
+			})
+
+			var note string
+			for _, w := range warnings {
+				if strings.Contains(w.Message, "subject suffix") {
+					note = w.Message
+				}
+			}
+			if tt.wantMessage == nil {
+				if note != "" {
+					t.Errorf("unexpected suffix note: %q", note)
+				}
+				return
+			}
+			for _, want := range tt.wantMessage {
+				if !strings.Contains(note, want) {
+					t.Errorf("suffix note = %q, expected it to contain %q", note, want)
+				}
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package worker
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sync"
 	"time"
 
@@ -10,12 +12,17 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
+// ErrNotStarted is returned, wrapped with the context's error, for items a
+// pool never started because its context was cancelled first
+var ErrNotStarted = errors.New("not started")
+
 // Pool manages a pool of workers for concurrent processing
 type Pool struct {
 	config        *config.Config
 	workers       int
 	retryAttempts int
 	retryDelay    time.Duration
+	drain         bool // let started items finish when the context is cancelled
 }
 
 // NewPool creates a new worker pool
@@ -25,6 +32,7 @@ func NewPool(cfg *config.Config) *Pool {
 		workers:       cfg.Concurrency.Workers,
 		retryAttempts: cfg.Concurrency.RetryAttempts,
 		retryDelay:    cfg.Concurrency.RetryDelay,
+		drain:         cfg.Concurrency.DrainOnInterrupt,
 	}
 }
 
@@ -39,18 +47,41 @@ func (p *Pool) Execute(ctx context.Context, mappings []models.SchemaMapping, wor
 	return p.ExecuteWithProgress(ctx, mappings, work, nil)
 }
 
-// ExecuteWithProgress executes work with progress callback
+// ExecuteWithProgress executes work with progress callback. Once ctx is
+// cancelled no more items are started, and those left get ErrNotStarted.
+// When draining, items already started run to completion on a context that
+// ctx's cancellation doesn't reach, but are not retried again, so shutdown
+// waits for at most one attempt per item; otherwise they see it too.
 func (p *Pool) ExecuteWithProgress(ctx context.Context, mappings []models.SchemaMapping, work WorkFunc, progress ProgressCallback) []error {
 	errors := make([]error, len(mappings))
+	if len(mappings) == 0 {
+		return errors
+	}
 	var mu sync.Mutex
 
 	g, ctx := errgroup.WithContext(ctx)
 	g.SetLimit(p.workers)
 
+	workCtx := ctx
+	if p.drain {
+		workCtx = context.WithoutCancel(ctx)
+	}
+
 	for i, mapping := range mappings {
 		i, mapping := i, mapping // capture loop variables
+		if ctx.Err() != nil {
+			errors[i] = notStarted(ctx)
+			continue
+		}
 		g.Go(func() error {
-			err := p.executeWithRetry(ctx, mapping, work)
+			// A slot may only have freed up after the cancellation
+			if ctx.Err() != nil {
+				mu.Lock()
+				errors[i] = notStarted(ctx)
+				mu.Unlock()
+				return nil
+			}
+			err := p.executeWithRetry(workCtx, ctx, mapping, work)
 			mu.Lock()
 			errors[i] = err
 			if progress != nil {
@@ -65,7 +96,34 @@ func (p *Pool) ExecuteWithProgress(ctx context.Context, mappings []models.Schema
 	return errors
 }
 
-func (p *Pool) executeWithRetry(ctx context.Context, mapping models.SchemaMapping, work WorkFunc) error {
+// notStarted returns the error for an item skipped after ctx was cancelled
+func notStarted(ctx context.Context) error {
+	return fmt.Errorf("%w: %w", ErrNotStarted, ctx.Err())
+}
+
+// IsNotStarted reports whether err is an item's ErrNotStarted
+func IsNotStarted(err error) bool {
+	return errors.Is(err, ErrNotStarted)
+}
+
+// permanentError marks a failure that retrying cannot fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so the pool returns it without further retries
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// executeWithRetry runs work on ctx, retrying failures with backoff until
+// interrupt is cancelled. interrupt is ctx itself unless the pool is
+// draining, in which case its cancellation ends retries but lets the
+// attempt in flight finish, and the item fails with that attempt's error.
+func (p *Pool) executeWithRetry(ctx, interrupt context.Context, mapping models.SchemaMapping, work WorkFunc) error {
 	var lastErr error
 
 	for attempt := 0; attempt <= p.retryAttempts; attempt++ {
@@ -74,11 +132,18 @@ func (p *Pool) executeWithRetry(ctx context.Context, mapping models.SchemaMappin
 			return ctx.Err()
 		default:
 		}
+		if lastErr != nil && interrupt.Err() != nil {
+			return lastErr
+		}
 
 		err := work(ctx, mapping)
 		if err == nil {
 			return nil
 		}
+		var perm *permanentError
+		if errors.As(err, &perm) {
+			return perm.err
+		}
 
 		lastErr = err
 
@@ -88,6 +153,11 @@ func (p *Pool) executeWithRetry(ctx context.Context, mapping models.SchemaMappin
 			select {
 			case <-ctx.Done():
 				return ctx.Err()
+			case <-interrupt.Done():
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				return lastErr
 			case <-time.After(delay):
 			}
 		}
@@ -108,7 +178,7 @@ func (p *Pool) ExecuteSequential(ctx context.Context, mappings []models.SchemaMa
 		default:
 		}
 
-		errors[i] = p.executeWithRetry(ctx, mapping, work)
+		errors[i] = p.executeWithRetry(ctx, ctx, mapping, work)
 	}
 
 	return errors
@@ -205,3 +205,143 @@ func TestExecute_ContextCancellation(t *testing.T) {
 		t.Errorf("expected context.Canceled, got %v", errs[0])
 	}
 }
+
+func TestExecute_CancelMidRun(t *testing.T) {
+	for _, drain := range []bool{true, false} {
+		t.Run(fmt.Sprintf("drain=%v", drain), func(t *testing.T) {
+			cfg := newTestConfig()
+			cfg.Concurrency.DrainOnInterrupt = drain
+			pool := NewPool(cfg)
+
+			mappings := make([]models.SchemaMapping, 5)
+			for i := range mappings {
+				mappings[i].SourceSchemaName = fmt.Sprintf("schema%d", i)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			started := make(chan struct{}, len(mappings))
+			release := make(chan struct{})
+			var calls int32
+			work := func(ctx context.Context, mapping models.SchemaMapping) error {
+				atomic.AddInt32(&calls, 1)
+				started <- struct{}{}
+				<-release
+				return ctx.Err()
+			}
+
+			done := make(chan []error, 1)
+			go func() { done <- pool.Execute(ctx, mappings, work) }()
+
+			// Cancel once both workers are busy, then let them finish
+			<-started
+			<-started
+			cancel()
+			close(release)
+			errs := <-done
+
+			if calls != 2 {
+				t.Errorf("work called %d times, expected only the 2 started items", calls)
+			}
+			for i, err := range errs {
+				started := i < 2
+				switch {
+				case started && drain && err != nil:
+					t.Errorf("errs[%d] = %v, expected a started item to finish while draining", i, err)
+				case started && !drain && !errors.Is(err, context.Canceled):
+					t.Errorf("errs[%d] = %v, expected a started item to see the cancellation", i, err)
+				case started && IsNotStarted(err):
+					t.Errorf("errs[%d] = %v, expected a started item not to be reported as not started", i, err)
+				case !started && (!IsNotStarted(err) || !errors.Is(err, context.Canceled)):
+					t.Errorf("errs[%d] = %v, expected ErrNotStarted wrapping context.Canceled", i, err)
+				}
+			}
+		})
+	}
+}
+
+func TestExecute_DrainStopsRetrying(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.Concurrency.DrainOnInterrupt = true
+	cfg.Concurrency.RetryAttempts = 5
+	cfg.Concurrency.RetryDelay = time.Minute
+	pool := NewPool(cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	var calls int32
+	failure := errors.New("registration failed")
+	work := func(ctx context.Context, mapping models.SchemaMapping) error {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			started <- struct{}{}
+			<-release
+		}
+		return failure
+	}
+
+	done := make(chan []error, 1)
+	go func() { done <- pool.Execute(ctx, []models.SchemaMapping{{SourceSchemaName: "schema"}}, work) }()
+
+	// Interrupt during the first attempt: it finishes, but isn't retried
+	<-started
+	cancel()
+	close(release)
+
+	select {
+	case errs := <-done:
+		if !errors.Is(errs[0], failure) {
+			t.Errorf("errs[0] = %v, expected the in-flight attempt's error", errs[0])
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("pool kept retrying after the interrupt")
+	}
+	if calls != 1 {
+		t.Errorf("work called %d times, expected no retries after the interrupt", calls)
+	}
+}
+
+func TestExecute_EmptyMappings(t *testing.T) {
+	pool := NewPool(newTestConfig())
+
+	var calls int32
+	work := func(ctx context.Context, mapping models.SchemaMapping) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	}
+	var progressCalls int32
+	progress := func() { atomic.AddInt32(&progressCalls, 1) }
+
+	runs := map[string]func([]models.SchemaMapping) []error{
+		"Execute": func(m []models.SchemaMapping) []error {
+			return pool.Execute(context.Background(), m, work)
+		},
+		"ExecuteWithProgress": func(m []models.SchemaMapping) []error {
+			return pool.ExecuteWithProgress(context.Background(), m, work, progress)
+		},
+		"ExecuteSequential": func(m []models.SchemaMapping) []error {
+			return pool.ExecuteSequential(context.Background(), m, work)
+		},
+	}
+
+	for name, run := range runs {
+		for _, mappings := range [][]models.SchemaMapping{nil, {}} {
+			done := make(chan []error, 1)
+			go func() { done <- run(mappings) }()
+
+			select {
+			case errs := <-done:
+				if errs == nil || len(errs) != 0 {
+					t.Errorf("%s(%#v) = %#v, expected empty non-nil slice", name, mappings, errs)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("%s(%#v) did not return", name, mappings)
+			}
+		}
+	}
+
+	if calls != 0 || progressCalls != 0 {
+		t.Errorf("work called %d times and progress %d times, expected 0", calls, progressCalls)
+	}
+}
@@ -0,0 +1,88 @@
+package worker
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
+	"github.com/schollz/progressbar/v3"
+)
+
+// unknownTotalStep is how many items pass between log lines when the total
+// isn't known up front
+const unknownTotalStep = 100
+
+// Progress counts the items a step has finished. With output.progress it
+// draws a bar on stdout; without it, it logs a plain line every 10% (or every
+// unknownTotalStep items when the total is unknown), which stays readable in
+// CI logs and redirected output.
+type Progress struct {
+	step   string
+	total  int
+	bar    *progressbar.ProgressBar
+	log    bool
+	mu     sync.Mutex
+	done   int
+	logged int
+}
+
+// NewProgress creates a Progress for total items, or an unknown number when
+// total is negative. The bar is only drawn when output is decorative; options
+// are applied to it after the description.
+func NewProgress(cfg config.OutputConfig, description string, total int, options ...progressbar.Option) *Progress {
+	p := &Progress{
+		step:  strings.ToLower(description),
+		total: total,
+		log:   !cfg.Progress,
+	}
+	if cfg.Progress && cfg.Decorative() {
+		options = append([]progressbar.Option{progressbar.OptionSetDescription("      " + description)}, options...)
+		p.bar = progressbar.NewOptions(total, options...)
+	}
+	return p
+}
+
+// Add records n more finished items
+func (p *Progress) Add(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.done += n
+	if p.bar != nil {
+		p.bar.Add(n)
+		return
+	}
+	if !p.log {
+		return
+	}
+
+	if p.total <= 0 {
+		if mark := p.done / unknownTotalStep; mark > p.logged {
+			p.logged = mark
+			slog.Info(p.step, "completed", p.done)
+		}
+		return
+	}
+	if decile := p.done * 10 / p.total; decile > p.logged {
+		p.logged = decile
+		slog.Info(p.step, "completed", p.done, "total", p.total, "percent", decile*10)
+	}
+}
+
+// Finish completes the bar, or logs the final count when the total was
+// unknown and the last items weren't logged yet
+func (p *Progress) Finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.bar != nil {
+		p.bar.Finish()
+		fmt.Println()
+		return
+	}
+	if p.log && p.total <= 0 && p.done%unknownTotalStep != 0 {
+		slog.Info(p.step, "completed", p.done)
+	}
+}
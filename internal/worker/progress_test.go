@@ -0,0 +1,110 @@
+package worker
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/akrishnanDG/glue-to-ccsr/pkg/config"
+	"github.com/schollz/progressbar/v3"
+)
+
+// captureStdout runs fn with os.Stdout redirected and returns what it wrote
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	fn()
+
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+// captureLogs sends the default logger to a buffer for the rest of the test
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	prev := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(prev) })
+	var buf bytes.Buffer
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+	return &buf
+}
+
+func runProgress(cfg config.OutputConfig, total, items int) {
+	p := NewProgress(cfg, "Registering schemas", total, progressbar.OptionSetWidth(50), progressbar.OptionShowCount())
+	for i := 0; i < items; i++ {
+		p.Add(1)
+	}
+	p.Finish()
+}
+
+func TestProgress_Disabled(t *testing.T) {
+	logs := captureLogs(t)
+	cfg := config.NewDefaultConfig().Output
+	cfg.Progress = false
+
+	out := captureStdout(t, func() { runProgress(cfg, 25, 25) })
+	if out != "" {
+		t.Errorf("expected nothing on stdout with progress disabled, got %q", out)
+	}
+
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	if len(lines) != 10 {
+		t.Fatalf("expected a log line per 10%%, got %d:\n%s", len(lines), logs)
+	}
+	if !strings.Contains(lines[0], `msg="registering schemas" completed=3 total=25 percent=10`) {
+		t.Errorf("first line = %q, want 3 of 25 at 10%%", lines[0])
+	}
+	if !strings.Contains(lines[9], "completed=25 total=25 percent=100") {
+		t.Errorf("last line = %q, want 25 of 25 at 100%%", lines[9])
+	}
+}
+
+func TestProgress_DisabledUnknownTotal(t *testing.T) {
+	logs := captureLogs(t)
+	cfg := config.NewDefaultConfig().Output
+	cfg.Progress = false
+
+	out := captureStdout(t, func() { runProgress(cfg, -1, 250) })
+	if out != "" {
+		t.Errorf("expected nothing on stdout with progress disabled, got %q", out)
+	}
+
+	got := logs.String()
+	for _, want := range []string{"completed=100", "completed=200", "completed=250"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected a %s line, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestProgress_Enabled(t *testing.T) {
+	logs := captureLogs(t)
+	cfg := config.NewDefaultConfig().Output
+
+	out := captureStdout(t, func() { runProgress(cfg, 25, 25) })
+	if !strings.Contains(out, "\r") || !strings.Contains(out, "25/25") {
+		t.Errorf("expected a redrawn bar on stdout, got %q", out)
+	}
+	if logs.Len() != 0 {
+		t.Errorf("expected no progress log lines while the bar is drawn, got:\n%s", logs)
+	}
+
+	cfg.Quiet = true
+	if out := captureStdout(t, func() { runProgress(cfg, 25, 25) }); out != "" {
+		t.Errorf("expected no bar when quiet, got %q", out)
+	}
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -42,34 +44,96 @@ type Config struct {
 
 	// Output configuration
 	Output OutputConfig `yaml:"output"`
+
+	// Problems found while loading the file that don't stop the run, such as
+	// unset environment variables
+	Warnings []string `yaml:"-"`
 }
 
 // AWSConfig holds AWS Glue Schema Registry configuration
 type AWSConfig struct {
-	Region           string   `yaml:"region"`
-	RegistryNames    []string `yaml:"registry_names"`
-	RegistryAll      bool     `yaml:"registry_all"`
-	RegistryExclude  []string `yaml:"registry_exclude"`
-	SchemaFilter     string   `yaml:"schema_filter"`
-	Profile          string   `yaml:"profile"`
-	AccessKeyID      string   `yaml:"access_key_id"`
-	SecretAccessKey  string   `yaml:"secret_access_key"`
+	Region                      string   `yaml:"region"`
+	RegistryNames               []string `yaml:"registry_names"`
+	RegistryAll                 bool     `yaml:"registry_all"`
+	RegistryExclude             []string `yaml:"registry_exclude"`
+	SchemaFilter                string   `yaml:"schema_filter"`
+	SchemaInclude               []string `yaml:"schema_include"`                 // only schemas matching one of these globs (empty = all)
+	SchemaExclude               []string `yaml:"schema_exclude"`                 // skip schemas matching any of these globs; wins over schema_include
+	SchemaFilterCaseInsensitive bool     `yaml:"schema_filter_case_insensitive"` // also applies to registry_exclude and the schema lists
+	Profile                     string   `yaml:"profile"`
+	AccessKeyID                 string   `yaml:"access_key_id"`
+	SecretAccessKey             string   `yaml:"secret_access_key"`
+	RoleARN                     string   `yaml:"role_arn"`         // role assumed on top of the base credentials
+	ExternalID                  string   `yaml:"external_id"`      // optional external ID for the assumed role
+	SessionName                 string   `yaml:"session_name"`     // optional session name for the assumed role
+	LazyDefinitions             bool     `yaml:"lazy_definitions"` // plan from the latest definition only
+	SourceDir                   string   `yaml:"source_dir"`       // read schemas from an export directory instead of Glue
+
+	// Include only schemas carrying all of these tags
+	TagFilter map[string]string `yaml:"tag_filter"`
 }
 
 // ConfluentCloudConfig holds Confluent Cloud Schema Registry configuration
 type ConfluentCloudConfig struct {
-	URL       string `yaml:"url"`
-	APIKey    string `yaml:"api_key"`
-	APISecret string `yaml:"api_secret"`
+	URL         string `yaml:"url"`
+	AuthType    string `yaml:"auth_type"` // basic (api_key/api_secret) or bearer
+	APIKey      string `yaml:"api_key"`
+	APISecret   string `yaml:"api_secret"`
+	BearerToken string `yaml:"bearer_token"` // OAuth/OIDC access token for auth_type bearer
+	ClusterID   string `yaml:"cluster_id"`   // sent as target-sr-cluster, e.g. lsrc-abc123
+
+	// Label for the target, e.g. prod; production targets need --allow-prod
+	Environment string `yaml:"environment"`
+
+	// Schema Registry error_code -> whether failures with it are retried;
+	// overrides the built-in defaults, other codes follow the HTTP status
+	RetryableErrorCodes map[int]bool `yaml:"retryable_error_codes"`
+
+	Transport TransportConfig `yaml:"transport"`
+}
+
+// UsesBearer reports whether requests authenticate with bearer_token rather
+// than the API key and secret
+func (c ConfluentCloudConfig) UsesBearer() bool {
+	return c.AuthType == "bearer"
+}
+
+// HasCredentials reports whether the URL and the credentials for auth_type
+// are all set
+func (c ConfluentCloudConfig) HasCredentials() bool {
+	if c.UsesBearer() {
+		return c.URL != "" && c.BearerToken != ""
+	}
+	return c.URL != "" && c.APIKey != "" && c.APISecret != ""
+}
+
+// IsProduction reports whether environment labels the target as production
+// (prod, production or prd, in any case)
+func (c ConfluentCloudConfig) IsProduction() bool {
+	switch strings.ToLower(strings.TrimSpace(c.Environment)) {
+	case "prod", "production", "prd":
+		return true
+	}
+	return false
+}
+
+// TransportConfig tunes the HTTP connection pool used for Schema Registry
+// requests
+type TransportConfig struct {
+	MaxConnsPerHost   int           `yaml:"max_conns_per_host"`  // 0 = max(workers, followup_concurrency)
+	ForceAttemptHTTP2 bool          `yaml:"force_attempt_http2"` // negotiate HTTP/2 over TLS
+	IdleConnTimeout   time.Duration `yaml:"idle_conn_timeout"`   // 0 = 90s
 }
 
 // NamingConfig holds naming strategy configuration
 type NamingConfig struct {
-	SubjectStrategy    string `yaml:"subject_strategy"`    // topic, record, llm, custom
-	SubjectTemplate    string `yaml:"subject_template"`    // for custom strategy
-	ContextMapping     string `yaml:"context_mapping"`     // registry, flat, custom
-	ContextMappingFile string `yaml:"context_mapping_file"`
-	NameMappingFile    string `yaml:"name_mapping_file"`   // explicit schema-to-subject mappings
+	SubjectStrategy    string   `yaml:"subject_strategy"`    // topic, record, topic-record, llm, custom
+	SubjectTemplate    string   `yaml:"subject_template"`    // for custom strategy
+	ContextMapping     string   `yaml:"context_mapping"`     // registry, flat, custom
+	ContextMappingFile string   `yaml:"context_mapping_file"`
+	NameMappingFile    string   `yaml:"name_mapping_file"`   // explicit schema-to-subject mappings
+	NameMappingFiles   []string `yaml:"name_mapping_files"`  // more mapping files, merged after name_mapping_file
+	AllowedContexts    []string `yaml:"allowed_contexts"`    // contexts mappings may target; empty allows any
 }
 
 // NormalizationConfig holds name normalization configuration
@@ -80,6 +144,7 @@ type NormalizationConfig struct {
 	InvalidCharReplacement string `yaml:"invalid_char_replacement"` // for invalid chars
 	CollisionCheck         bool   `yaml:"collision_check"`
 	CollisionResolution    string `yaml:"collision_resolution"`     // fail, suffix, registry-prefix, prefer-shorter, skip
+	MaxSubjectLength       int    `yaml:"max_subject_length"`       // longer names are truncated with a hash suffix
 }
 
 // KeyValueConfig holds key/value detection configuration
@@ -93,56 +158,125 @@ type KeyValueConfig struct {
 
 // MigrationConfig holds migration behavior configuration
 type MigrationConfig struct {
-	VersionStrategy    string `yaml:"version_strategy"`     // all, latest
-	ReferenceStrategy  string `yaml:"reference_strategy"`   // rewrite, skip, fail
-	CrossRegistryRefs  string `yaml:"cross_registry_refs"`  // resolve, fail, warn
+	VersionStrategy         string        `yaml:"version_strategy"`         // all, latest
+	MaxVersionsPerSchema    int           `yaml:"max_versions_per_schema"`  // with strategy all, keep only the latest N versions (0 = all)
+	ReferenceStrategy       string        `yaml:"reference_strategy"`       // rewrite, skip, fail
+	CrossRegistryRefs       string        `yaml:"cross_registry_refs"`      // resolve, fail, warn
+	DefaultCompatibility    string        `yaml:"default_compatibility"`    // applied only when Glue has none set
+	LevelOrder              string        `yaml:"level_order"`              // source, alpha, versions-desc
+	AbortAfterFailures      string        `yaml:"abort_after_failures"`     // failure count (e.g. 50) or percentage (e.g. 10%)
+	SkipDocOnlyVersions     bool          `yaml:"skip_doc_only_versions"`   // skip Avro versions that only change doc attributes
+	PrecheckCompatibility   bool          `yaml:"precheck_compatibility"`   // test existing subjects for compatibility before registering
+	CanonicalizeDefinitions bool          `yaml:"canonicalize_definitions"` // re-encode Avro/JSON definitions with sorted keys
+	OnPendingVersion        string        `yaml:"on_pending_version"`       // include, skip, wait
+	PendingWaitTimeout      time.Duration `yaml:"pending_wait_timeout"`     // how long wait polls a PENDING version
+	Since                   time.Time     `yaml:"since"`                    // RFC3339; only schemas updated and versions created since then
+	ImportMode              bool          `yaml:"import_mode"`              // switch subjects to IMPORT while registering
+	PreserveSchemaIDs       bool          `yaml:"preserve_schema_ids"`      // send a Glue-derived schema ID; requires import_mode
+	PreserveVersions        bool          `yaml:"preserve_versions"`        // send each Glue version number and its schema ID; requires import_mode
+	ParallelVersions        bool          `yaml:"parallel_versions"`        // register a schema's versions concurrently; requires preserve_versions
+	Roles                   []string      `yaml:"roles"`                    // detected roles to migrate: key, value (empty = all)
+	PreserveCompatibility   bool          `yaml:"preserve_compatibility"`   // set each subject's compatibility from Glue
+	SkipExisting            bool          `yaml:"skip_existing"`            // look each version up and skip it if already registered
+	SkipDependencyGraph     bool          `yaml:"skip_dependency_graph"`    // migrate all schemas as one level without ordering references
+	PerSchemaTimeout        time.Duration `yaml:"per_schema_timeout"`       // fail a schema attempt that runs longer than this (0 = no limit)
+}
+
+// FailureThreshold parses AbortAfterFailures. It returns the tolerated number
+// of failed schemas, or the tolerated percentage when percent is true, and
+// set is false when no threshold is configured.
+func (m MigrationConfig) FailureThreshold() (value float64, percent, set bool, err error) {
+	raw := strings.TrimSpace(m.AbortAfterFailures)
+	if raw == "" {
+		return 0, false, false, nil
+	}
+
+	if strings.HasSuffix(raw, "%") {
+		value, err = strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(raw, "%")), 64)
+		if err != nil || value < 0 || value > 100 {
+			return 0, false, false, fmt.Errorf("invalid percentage %q", m.AbortAfterFailures)
+		}
+		return value, true, true, nil
+	}
+
+	count, err := strconv.Atoi(raw)
+	if err != nil || count < 0 {
+		return 0, false, false, fmt.Errorf("invalid failure count %q", m.AbortAfterFailures)
+	}
+	return float64(count), false, true, nil
 }
 
 // MetadataConfig holds metadata migration configuration
 type MetadataConfig struct {
-	Strategy           string `yaml:"strategy"` // migrate, skip
-	MigrateTags        bool   `yaml:"migrate_tags"`
-	MigrateDescription bool   `yaml:"migrate_description"`
+	Strategy           string            `yaml:"strategy"` // migrate, skip
+	MigrateTags        bool              `yaml:"migrate_tags"`
+	MigrateDescription bool              `yaml:"migrate_description"`
+	Properties         map[string]string `yaml:"properties"`          // static properties added to every registered schema
+	TagsAsProperties   bool              `yaml:"tags_as_properties"` // fetch Glue tags and add them as schema properties
+	RecordOriginalName bool              `yaml:"record_original_name"` // add glue_schema_name and glue_registry properties
 }
 
 // LLMConfig holds LLM configuration
 type LLMConfig struct {
-	Provider        string  `yaml:"provider"`          // openai, anthropic, bedrock, ollama, local
-	Model           string  `yaml:"model"`
-	APIKey          string  `yaml:"api_key"`
-	BaseURL         string  `yaml:"base_url"`          // for local LLMs
-	CacheFile       string  `yaml:"cache_file"`
-	MaxCost         float64 `yaml:"max_cost"`
-	RateLimit       int     `yaml:"rate_limit"`
-	InputTokenCost  float64 `yaml:"input_token_cost"`  // cost per token for input/prompt
-	OutputTokenCost float64 `yaml:"output_token_cost"` // cost per token for output/completion
+	Provider           string  `yaml:"provider"`             // openai, anthropic, bedrock, ollama, local
+	Model              string  `yaml:"model"`
+	APIKey             string  `yaml:"api_key"`
+	BaseURL            string  `yaml:"base_url"`             // for local LLMs and Azure OpenAI
+	APIVersion         string  `yaml:"api_version"`          // Azure OpenAI api-version
+	CacheFile          string  `yaml:"cache_file"`
+	SeedCache          string  `yaml:"seed_cache"`           // prior JSON report whose LLM names are reused
+	PromptTemplateFile string  `yaml:"prompt_template_file"` // text/template replacing the built-in naming prompt
+	EstimateInDryRun   bool    `yaml:"estimate_in_dry_run"`  // dry runs estimate LLM cost instead of calling the provider
+	MaxCost            float64 `yaml:"max_cost"`
+	RateLimit          int     `yaml:"rate_limit"`
+	InputTokenCost     float64 `yaml:"input_token_cost"`     // cost per token for input/prompt
+	OutputTokenCost    float64 `yaml:"output_token_cost"`    // cost per token for output/completion
 }
 
 // ConcurrencyConfig holds concurrency configuration
 type ConcurrencyConfig struct {
-	Workers       int           `yaml:"workers"`
-	BatchSize     int           `yaml:"batch_size"`
-	AWSRateLimit  int           `yaml:"aws_rate_limit"`
-	CCRateLimit   int           `yaml:"cc_rate_limit"`
-	LLMRateLimit  int           `yaml:"llm_rate_limit"`
-	RetryAttempts int           `yaml:"retry_attempts"`
-	RetryDelay    time.Duration `yaml:"retry_delay"`
+	Workers                 int           `yaml:"workers"`
+	BatchSize               int           `yaml:"batch_size"`
+	VersionFetchConcurrency int           `yaml:"version_fetch_concurrency"` // max in-flight Glue version fetches across all schemas (0 = workers)
+	FollowupConcurrency     int           `yaml:"followup_concurrency"`      // max in-flight compatibility and metadata calls (0 = workers)
+	AWSRateLimit            int           `yaml:"aws_rate_limit"`
+	CCRateLimit             int           `yaml:"cc_rate_limit"`
+	LLMRateLimit            int           `yaml:"llm_rate_limit"`
+	RetryAttempts           int           `yaml:"retry_attempts"`
+	RetryDelay              time.Duration `yaml:"retry_delay"`
+	DrainOnInterrupt        bool          `yaml:"drain_on_interrupt"` // on interrupt, let started schemas finish instead of cancelling them
 }
 
 // CheckpointConfig holds checkpoint/resume configuration
 type CheckpointConfig struct {
-	File   string `yaml:"file"`
-	Resume bool   `yaml:"resume"`
+	File         string `yaml:"file"`
+	Resume       bool   `yaml:"resume"`
+	Force        bool   `yaml:"force"`         // resume even if the checkpoint was written for another target URL or config
+	ContinueFrom string `yaml:"continue_from"` // registry:schema to start from in migration order
+	RetryFailed  bool   `yaml:"retry_failed"`  // on resume, migrate only the recorded failures
 }
 
 // OutputConfig holds output configuration
 type OutputConfig struct {
-	DryRun       bool   `yaml:"dry_run"`
-	ReportFile   string `yaml:"report_file"`
-	Format       string `yaml:"format"` // table, json, csv
-	Progress     bool   `yaml:"progress"`
-	LogFile      string `yaml:"log_file"`
-	LogLevel     string `yaml:"log_level"` // debug, info, warn, error
+	DryRun            bool   `yaml:"dry_run"`
+	ReportFile        string `yaml:"report_file"`
+	ReportStdout      bool   `yaml:"report_stdout"`      // write the report to stdout instead of the summary
+	Format            string `yaml:"format"`             // table, json, csv, html
+	Progress          bool   `yaml:"progress"`
+	Quiet             bool   `yaml:"quiet"`              // suppress banners, progress bars and the summary
+	ExplainCollisions bool   `yaml:"explain_collisions"` // detail how each collision was resolved
+	DumpFailuresDir   string `yaml:"dump_failures_dir"`  // write failing definitions and server errors here
+	MapFileTemplate   string `yaml:"map_file_template"`  // write a name mapping file for the plan here and exit
+	LogFile           string `yaml:"log_file"`
+	LogLevel          string `yaml:"log_level"`          // debug, info, warn, error
+	JSONLogs          bool   `yaml:"json_logs"`          // JSON log records with a run_id and per-schema events
+}
+
+// Decorative reports whether human-oriented output (banners, progress bars,
+// summaries) should be written to stdout. It is off when quiet or when
+// stdout is reserved for the machine-readable report.
+func (o OutputConfig) Decorative() bool {
+	return !o.Quiet && !o.ReportStdout
 }
 
 // NewDefaultConfig returns a Config with default values
@@ -151,6 +285,12 @@ func NewDefaultConfig() *Config {
 		AWS: AWSConfig{
 			Region: "us-east-1",
 		},
+		ConfluentCloud: ConfluentCloudConfig{
+			AuthType: "basic",
+			Transport: TransportConfig{
+				ForceAttemptHTTP2: true,
+			},
+		},
 		Naming: NamingConfig{
 			SubjectStrategy: "topic",
 			ContextMapping:  "flat",
@@ -162,15 +302,21 @@ func NewDefaultConfig() *Config {
 			InvalidCharReplacement: "-",
 			CollisionCheck:         true,
 			CollisionResolution:    "suffix", // Default: add -1, -2, etc.
+			MaxSubjectLength:       255,
 		},
 		KeyValue: KeyValueConfig{
 			DefaultRole:            "value",
 			DisableBuiltinPatterns: false,
 		},
 		Migration: MigrationConfig{
-			VersionStrategy:   "all",
-			ReferenceStrategy: "rewrite",
-			CrossRegistryRefs: "resolve",
+			VersionStrategy:    "all",
+			ReferenceStrategy:  "rewrite",
+			CrossRegistryRefs:  "resolve",
+			LevelOrder:         "source",
+			OnPendingVersion:   "include",
+			PendingWaitTimeout: 5 * time.Minute,
+
+			PreserveCompatibility: true,
 		},
 		Metadata: MetadataConfig{
 			Strategy:           "migrate",
@@ -180,18 +326,21 @@ func NewDefaultConfig() *Config {
 		LLM: LLMConfig{
 			Provider:        "openai",
 			Model:           "gpt-4o",
+			APIVersion:      "2024-02-01",
 			RateLimit:       5,
 			InputTokenCost:  0.000005,  // $5 per million input tokens (gpt-4o)
 			OutputTokenCost: 0.000015,  // $15 per million output tokens (gpt-4o)
 		},
 		Concurrency: ConcurrencyConfig{
-			Workers:       10,
-			BatchSize:     100,
-			AWSRateLimit:  10,
-			CCRateLimit:   10,
-			LLMRateLimit:  5,
-			RetryAttempts: 3,
-			RetryDelay:    5 * time.Second,
+			Workers:                 10,
+			BatchSize:               100,
+			VersionFetchConcurrency: 10,
+			AWSRateLimit:            10,
+			CCRateLimit:             10,
+			LLMRateLimit:            5,
+			RetryAttempts:           3,
+			RetryDelay:              5 * time.Second,
+			DrainOnInterrupt:        true,
 		},
 		Output: OutputConfig{
 			Format:   "table",
@@ -208,14 +357,85 @@ func LoadFromFile(path string) (*Config, error) {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
 	config := NewDefaultConfig()
-	if err := yaml.Unmarshal(data, config); err != nil {
+	if len(doc.Content) == 0 {
+		return config, nil
+	}
+	unset := expandEnv(&doc, nil)
+	if err := doc.Decode(config); err != nil {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
+	for _, name := range unset {
+		config.Warnings = append(config.Warnings, fmt.Sprintf("environment variable %s is not set; using an empty value", name))
+	}
 
 	return config, nil
 }
 
+// expandEnv replaces ${VAR} and $VAR in the scalar values under n with the
+// environment, leaving keys and comments alone. Unset variables expand to ""
+// and are appended to unset once each. $$ is a literal $, and $ followed by
+// anything but a variable name is kept as is.
+func expandEnv(n *yaml.Node, unset []string) []string {
+	if n.Kind == yaml.ScalarNode {
+		expanded := os.Expand(n.Value, func(name string) string {
+			if name == "$" {
+				return "$"
+			}
+			if !isEnvName(name) {
+				return "$" + name
+			}
+			value, ok := os.LookupEnv(name)
+			if !ok {
+				for _, u := range unset {
+					if u == name {
+						return ""
+					}
+				}
+				unset = append(unset, name)
+			}
+			return value
+		})
+		if expanded != n.Value {
+			n.Value = expanded
+			// Let plain scalars resolve again, so workers: ${WORKERS} decodes
+			// as an int
+			if n.Style == 0 {
+				n.Tag = ""
+			}
+		}
+		return unset
+	}
+
+	for i, child := range n.Content {
+		// Mapping keys sit at even indexes
+		if n.Kind == yaml.MappingNode && i%2 == 0 {
+			continue
+		}
+		unset = expandEnv(child, unset)
+	}
+	return unset
+}
+
+// isEnvName reports whether name is a valid environment variable name
+func isEnvName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		if r == '_' || (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z') || (i > 0 && r >= '0' && r <= '9') {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
 // SaveToFile saves configuration to a YAML file
 func (c *Config) SaveToFile(path string) error {
 	data, err := yaml.Marshal(c)
@@ -229,3 +449,25 @@ func (c *Config) SaveToFile(path string) error {
 
 	return nil
 }
+
+// redactedValue replaces secrets in Redacted output
+const redactedValue = "********"
+
+// Redacted returns a copy of the config with credentials masked, suitable
+// for printing. Empty secrets stay empty so it is clear they are unset.
+func (c *Config) Redacted() *Config {
+	redacted := *c
+	redact := func(s string) string {
+		if s == "" {
+			return ""
+		}
+		return redactedValue
+	}
+
+	redacted.AWS.SecretAccessKey = redact(c.AWS.SecretAccessKey)
+	redacted.ConfluentCloud.APISecret = redact(c.ConfluentCloud.APISecret)
+	redacted.ConfluentCloud.BearerToken = redact(c.ConfluentCloud.BearerToken)
+	redacted.LLM.APIKey = redact(c.LLM.APIKey)
+
+	return &redacted
+}
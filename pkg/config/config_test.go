@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -94,3 +95,77 @@ func TestLoadFromFile_InvalidYAML(t *testing.T) {
 		t.Fatal("expected error loading invalid YAML config file")
 	}
 }
+
+func TestLoadFromFile_ExpandsEnv(t *testing.T) {
+	t.Setenv("TEST_CC_API_SECRET", "s3cr#t: value")
+	t.Setenv("TEST_AWS_SECRET", "aws-secret")
+	t.Setenv("TEST_LLM_KEY", "sk-test")
+	t.Setenv("TEST_WORKERS", "4")
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	content := []byte(`# Secrets come from $HOME-style variables
+aws:
+  access_key_id: AKIA$TEST_AWS_SECRET
+  secret_access_key: ${TEST_AWS_SECRET}
+confluent_cloud:
+  api_secret: ${TEST_CC_API_SECRET}
+llm:
+  api_key: "${TEST_LLM_KEY}"
+key_value:
+  key_regex:
+    - ".*-id$"
+    - "cost-$$"
+concurrency:
+  workers: ${TEST_WORKERS}
+`)
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	cfg, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile failed: %v", err)
+	}
+
+	if cfg.ConfluentCloud.APISecret != "s3cr#t: value" {
+		t.Errorf("APISecret = %q, expected the environment value", cfg.ConfluentCloud.APISecret)
+	}
+	if cfg.AWS.AccessKeyID != "AKIAaws-secret" || cfg.AWS.SecretAccessKey != "aws-secret" {
+		t.Errorf("AWS keys = %q/%q, expected the environment values", cfg.AWS.AccessKeyID, cfg.AWS.SecretAccessKey)
+	}
+	if cfg.LLM.APIKey != "sk-test" {
+		t.Errorf("LLM APIKey = %q, expected %q", cfg.LLM.APIKey, "sk-test")
+	}
+	if cfg.Concurrency.Workers != 4 {
+		t.Errorf("Workers = %d, expected 4 from TEST_WORKERS", cfg.Concurrency.Workers)
+	}
+	if want := []string{".*-id$", "cost-$"}; len(cfg.KeyValue.KeyRegex) != 2 || cfg.KeyValue.KeyRegex[0] != want[0] || cfg.KeyValue.KeyRegex[1] != want[1] {
+		t.Errorf("KeyRegex = %q, expected %q", cfg.KeyValue.KeyRegex, want)
+	}
+	if len(cfg.Warnings) != 0 {
+		t.Errorf("Warnings = %q, expected none (comments are not expanded)", cfg.Warnings)
+	}
+}
+
+func TestLoadFromFile_UnsetEnv(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	content := []byte("confluent_cloud:\n  api_key: ${TEST_UNSET_VARIABLE}\n  api_secret: $TEST_UNSET_VARIABLE\n")
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	cfg, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile failed: %v", err)
+	}
+
+	if cfg.ConfluentCloud.APIKey != "" || cfg.ConfluentCloud.APISecret != "" {
+		t.Errorf("api_key/api_secret = %q/%q, expected empty strings for an unset variable",
+			cfg.ConfluentCloud.APIKey, cfg.ConfluentCloud.APISecret)
+	}
+	if len(cfg.Warnings) != 1 || !strings.Contains(cfg.Warnings[0], "TEST_UNSET_VARIABLE") {
+		t.Errorf("Warnings = %q, expected one warning naming TEST_UNSET_VARIABLE", cfg.Warnings)
+	}
+}
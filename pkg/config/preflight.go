@@ -0,0 +1,75 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Preflight verifies that the files the tool writes during a run can be
+// written, creating missing parent directories. Unlike Validate it touches
+// the filesystem, so it runs once just before a migration starts; long runs
+// would otherwise only fail when the first checkpoint or cache write happens.
+func (c *Config) Preflight() error {
+	var errs ValidationErrors
+
+	paths := []struct {
+		field string
+		path  string
+	}{
+		{"checkpoint.file", c.Checkpoint.File},
+		{"llm.cache_file", c.LLM.CacheFile},
+		{"output.report_file", c.Output.ReportFile},
+		{"output.map_file_template", c.Output.MapFileTemplate},
+	}
+
+	for _, p := range paths {
+		if p.path == "" {
+			continue
+		}
+		if err := checkWritable(p.path); err != nil {
+			errs = append(errs, ValidationError{Field: p.field, Message: err.Error()})
+		}
+	}
+
+	if dir := c.Output.DumpFailuresDir; dir != "" {
+		if err := checkWritable(filepath.Join(dir, ".probe")); err != nil {
+			errs = append(errs, ValidationError{Field: "output.dump_failures_dir", Message: err.Error()})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// checkWritable ensures path's directory exists and accepts new files, and
+// that path itself can be opened for writing if it already exists.
+func checkWritable(path string) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("cannot create directory %s: %v", dir, err)
+	}
+
+	probe, err := os.CreateTemp(dir, ".glue-to-ccsr-preflight-*")
+	if err != nil {
+		return fmt.Errorf("directory %s is not writable: %v", dir, err)
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	if info, err := os.Stat(path); err == nil {
+		if info.IsDir() {
+			return fmt.Errorf("%s is a directory, expected a file path", path)
+		}
+		f, err := os.OpenFile(path, os.O_WRONLY, 0)
+		if err != nil {
+			return fmt.Errorf("file %s is not writable: %v", path, err)
+		}
+		f.Close()
+	}
+
+	return nil
+}
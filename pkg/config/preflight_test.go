@@ -0,0 +1,45 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPreflight_CreatesMissingDirectories(t *testing.T) {
+	dir := t.TempDir()
+	cfg := NewDefaultConfig()
+	cfg.Checkpoint.File = filepath.Join(dir, "state", "checkpoint.json")
+	cfg.LLM.CacheFile = filepath.Join(dir, "cache", "llm.json")
+
+	if err := cfg.Preflight(); err != nil {
+		t.Fatalf("Preflight() unexpected error: %v", err)
+	}
+
+	for _, sub := range []string{"state", "cache"} {
+		if info, err := os.Stat(filepath.Join(dir, sub)); err != nil || !info.IsDir() {
+			t.Errorf("expected directory %s to be created", sub)
+		}
+	}
+}
+
+func TestPreflight_UnwritableCheckpointDirectory(t *testing.T) {
+	// A regular file in place of the parent directory can't be written to,
+	// even when tests run as root
+	blocker := filepath.Join(t.TempDir(), "not-a-dir")
+	if err := os.WriteFile(blocker, []byte("x"), 0644); err != nil {
+		t.Fatalf("failed to create blocker file: %v", err)
+	}
+
+	cfg := NewDefaultConfig()
+	cfg.Checkpoint.File = filepath.Join(blocker, "checkpoint.json")
+
+	err := cfg.Preflight()
+	if err == nil {
+		t.Fatal("Preflight() expected error for unwritable checkpoint directory")
+	}
+	if !strings.Contains(err.Error(), "checkpoint.file") || !strings.Contains(err.Error(), blocker) {
+		t.Errorf("error = %q, expected it to name checkpoint.file and the directory", err.Error())
+	}
+}
@@ -4,9 +4,13 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
+	"text/template"
 
+	"github.com/akrishnanDG/glue-to-ccsr/internal/models"
+	"github.com/akrishnanDG/glue-to-ccsr/internal/tmplcheck"
 	"gopkg.in/yaml.v3"
 )
 
@@ -34,12 +38,15 @@ func (e ValidationErrors) Error() string {
 	return msg
 }
 
+// roleARNPattern matches IAM role ARNs in any partition
+var roleARNPattern = regexp.MustCompile(`^arn:aws[a-z-]*:iam::\d{12}:role/[\w+=,.@/-]+$`)
+
 // Validate validates the configuration and returns any errors
 func (c *Config) Validate() error {
 	var errs ValidationErrors
 
 	// Validate AWS configuration
-	if c.AWS.Region == "" {
+	if c.AWS.Region == "" && c.AWS.SourceDir == "" {
 		errs = append(errs, ValidationError{Field: "aws.region", Message: "region is required"})
 	}
 
@@ -50,6 +57,36 @@ func (c *Config) Validate() error {
 		})
 	}
 
+	if c.AWS.RoleARN != "" {
+		if !roleARNPattern.MatchString(c.AWS.RoleARN) {
+			errs = append(errs, ValidationError{
+				Field:   "aws.role_arn",
+				Message: "must be an IAM role ARN such as arn:aws:iam::123456789012:role/glue-reader",
+			})
+		}
+	} else if c.AWS.ExternalID != "" || c.AWS.SessionName != "" {
+		errs = append(errs, ValidationError{
+			Field:   "aws.role_arn",
+			Message: "required when external_id or session_name is set",
+		})
+	}
+	for _, list := range []struct {
+		field    string
+		patterns []string
+	}{
+		{"aws.schema_include", c.AWS.SchemaInclude},
+		{"aws.schema_exclude", c.AWS.SchemaExclude},
+	} {
+		for _, pattern := range list.patterns {
+			if _, err := filepath.Match(pattern, ""); err != nil {
+				errs = append(errs, ValidationError{
+					Field:   list.field,
+					Message: fmt.Sprintf("invalid glob pattern %q", pattern),
+				})
+			}
+		}
+	}
+
 	// Validate Confluent Cloud configuration (skip for dry-run)
 	if !c.Output.DryRun {
 		if c.ConfluentCloud.URL == "" {
@@ -60,21 +97,38 @@ func (c *Config) Validate() error {
 			}
 		}
 
-		if c.ConfluentCloud.APIKey == "" {
-			errs = append(errs, ValidationError{Field: "confluent_cloud.api_key", Message: "API key is required"})
+		switch c.ConfluentCloud.AuthType {
+		case "", "basic":
+			if c.ConfluentCloud.APIKey == "" {
+				errs = append(errs, ValidationError{Field: "confluent_cloud.api_key", Message: "API key is required"})
+			}
+
+			if c.ConfluentCloud.APISecret == "" {
+				errs = append(errs, ValidationError{Field: "confluent_cloud.api_secret", Message: "API secret is required"})
+			}
+		case "bearer":
+			if c.ConfluentCloud.BearerToken == "" {
+				errs = append(errs, ValidationError{Field: "confluent_cloud.bearer_token", Message: "bearer token is required when auth_type is bearer"})
+			}
+		default:
+			errs = append(errs, ValidationError{Field: "confluent_cloud.auth_type", Message: "must be one of: basic, bearer"})
+		}
+
+		if c.ConfluentCloud.Transport.MaxConnsPerHost < 0 {
+			errs = append(errs, ValidationError{Field: "confluent_cloud.transport.max_conns_per_host", Message: "cannot be negative"})
 		}
 
-		if c.ConfluentCloud.APISecret == "" {
-			errs = append(errs, ValidationError{Field: "confluent_cloud.api_secret", Message: "API secret is required"})
+		if c.ConfluentCloud.Transport.IdleConnTimeout < 0 {
+			errs = append(errs, ValidationError{Field: "confluent_cloud.transport.idle_conn_timeout", Message: "cannot be negative"})
 		}
 	}
 
 	// Validate naming strategy
-	validSubjectStrategies := map[string]bool{"topic": true, "record": true, "llm": true, "custom": true}
+	validSubjectStrategies := map[string]bool{"topic": true, "record": true, "topic-record": true, "llm": true, "custom": true}
 	if !validSubjectStrategies[c.Naming.SubjectStrategy] {
 		errs = append(errs, ValidationError{
 			Field:   "naming.subject_strategy",
-			Message: "must be one of: topic, record, llm, custom",
+			Message: "must be one of: topic, record, topic-record, llm, custom",
 		})
 	}
 
@@ -107,7 +161,13 @@ func (c *Config) Validate() error {
 
 	// Validate name mapping file if specified
 	if c.Naming.NameMappingFile != "" {
-		if validationErrs := validateNameMappingFile(c.Naming.NameMappingFile); len(validationErrs) > 0 {
+		if validationErrs := validateNameMappingFile("naming.name_mapping_file", c.Naming.NameMappingFile); len(validationErrs) > 0 {
+			errs = append(errs, validationErrs...)
+		}
+	}
+	for i, path := range c.Naming.NameMappingFiles {
+		field := fmt.Sprintf("naming.name_mapping_files[%d]", i)
+		if validationErrs := validateNameMappingFile(field, path); len(validationErrs) > 0 {
 			errs = append(errs, validationErrs...)
 		}
 	}
@@ -129,6 +189,13 @@ func (c *Config) Validate() error {
 		})
 	}
 
+	if c.Normalization.MaxSubjectLength < 16 || c.Normalization.MaxSubjectLength > 255 {
+		errs = append(errs, ValidationError{
+			Field:   "normalization.max_subject_length",
+			Message: "must be between 16 and 255",
+		})
+	}
+
 	// Validate key/value regex patterns
 	for i, pattern := range c.KeyValue.KeyRegex {
 		if _, err := regexp.Compile(pattern); err != nil {
@@ -164,6 +231,12 @@ func (c *Config) Validate() error {
 			Message: "must be one of: all, latest",
 		})
 	}
+	if c.Migration.MaxVersionsPerSchema < 0 {
+		errs = append(errs, ValidationError{
+			Field:   "migration.max_versions_per_schema",
+			Message: "must not be negative",
+		})
+	}
 
 	validReferenceStrategies := map[string]bool{"rewrite": true, "skip": true, "fail": true}
 	if !validReferenceStrategies[c.Migration.ReferenceStrategy] {
@@ -181,13 +254,82 @@ func (c *Config) Validate() error {
 		})
 	}
 
+	validLevelOrders := map[string]bool{"source": true, "alpha": true, "versions-desc": true}
+	if !validLevelOrders[c.Migration.LevelOrder] {
+		errs = append(errs, ValidationError{
+			Field:   "migration.level_order",
+			Message: "must be one of: source, alpha, versions-desc",
+		})
+	}
+
+	validPendingActions := map[string]bool{"include": true, "skip": true, "wait": true}
+	if !validPendingActions[c.Migration.OnPendingVersion] {
+		errs = append(errs, ValidationError{
+			Field:   "migration.on_pending_version",
+			Message: "must be one of: include, skip, wait",
+		})
+	}
+	for _, role := range c.Migration.Roles {
+		if role != "key" && role != "value" {
+			errs = append(errs, ValidationError{
+				Field:   "migration.roles",
+				Message: fmt.Sprintf("invalid role %q: must be key or value", role),
+			})
+		}
+	}
+	if c.Migration.PreserveSchemaIDs && !c.Migration.ImportMode {
+		errs = append(errs, ValidationError{
+			Field:   "migration.preserve_schema_ids",
+			Message: "requires migration.import_mode, since Schema Registry only accepts explicit IDs in IMPORT mode",
+		})
+	}
+	if c.Migration.PreserveVersions && !c.Migration.ImportMode {
+		errs = append(errs, ValidationError{
+			Field:   "migration.preserve_versions",
+			Message: "requires migration.import_mode, since Schema Registry only accepts explicit versions in IMPORT mode",
+		})
+	}
+	if c.Migration.ParallelVersions && !c.Migration.PreserveVersions {
+		errs = append(errs, ValidationError{
+			Field:   "migration.parallel_versions",
+			Message: "requires migration.preserve_versions, since versions registered concurrently without explicit numbers would be numbered in arrival order",
+		})
+	}
+	if c.Migration.OnPendingVersion == "wait" && c.Migration.PendingWaitTimeout <= 0 {
+		errs = append(errs, ValidationError{
+			Field:   "migration.pending_wait_timeout",
+			Message: "must be positive when on_pending_version is wait",
+		})
+	}
+	if c.Migration.PerSchemaTimeout < 0 {
+		errs = append(errs, ValidationError{Field: "migration.per_schema_timeout", Message: "cannot be negative"})
+	}
+
+	validCompatibility := map[string]bool{
+		"": true, "NONE": true, "BACKWARD": true, "BACKWARD_TRANSITIVE": true,
+		"FORWARD": true, "FORWARD_TRANSITIVE": true, "FULL": true, "FULL_TRANSITIVE": true,
+	}
+	if !validCompatibility[c.Migration.DefaultCompatibility] {
+		errs = append(errs, ValidationError{
+			Field:   "migration.default_compatibility",
+			Message: "must be one of: NONE, BACKWARD, BACKWARD_TRANSITIVE, FORWARD, FORWARD_TRANSITIVE, FULL, FULL_TRANSITIVE",
+		})
+	}
+
+	if _, _, _, err := c.Migration.FailureThreshold(); err != nil {
+		errs = append(errs, ValidationError{
+			Field:   "migration.abort_after_failures",
+			Message: "must be a non-negative count (e.g. 50) or a percentage from 0% to 100% (e.g. 10%)",
+		})
+	}
+
 	// Validate LLM configuration if using LLM strategy
 	if c.Naming.SubjectStrategy == "llm" {
-		validProviders := map[string]bool{"openai": true, "anthropic": true, "bedrock": true, "ollama": true, "local": true}
+		validProviders := map[string]bool{"openai": true, "anthropic": true, "azure": true, "bedrock": true, "ollama": true, "local": true}
 		if !validProviders[c.LLM.Provider] {
 			errs = append(errs, ValidationError{
 				Field:   "llm.provider",
-				Message: "must be one of: openai, anthropic, bedrock, ollama, local",
+				Message: "must be one of: openai, anthropic, azure, bedrock, ollama, local",
 			})
 		}
 
@@ -196,7 +338,7 @@ func (c *Config) Validate() error {
 		}
 
 		// API key required for cloud providers
-		cloudProviders := map[string]bool{"openai": true, "anthropic": true}
+		cloudProviders := map[string]bool{"openai": true, "anthropic": true, "azure": true}
 		if cloudProviders[c.LLM.Provider] && c.LLM.APIKey == "" {
 			errs = append(errs, ValidationError{
 				Field:   "llm.api_key",
@@ -212,6 +354,23 @@ func (c *Config) Validate() error {
 				Message: "base URL is required for local LLM providers",
 			})
 		}
+
+		// Azure OpenAI is addressed by resource endpoint and API version
+		if c.LLM.Provider == "azure" {
+			if c.LLM.BaseURL == "" {
+				errs = append(errs, ValidationError{
+					Field:   "llm.base_url",
+					Message: "base URL is required for Azure OpenAI (e.g. https://my-resource.openai.azure.com)",
+				})
+			}
+			if c.LLM.APIVersion == "" {
+				errs = append(errs, ValidationError{Field: "llm.api_version", Message: "API version is required for Azure OpenAI"})
+			}
+		}
+
+		if c.LLM.PromptTemplateFile != "" {
+			errs = append(errs, validatePromptTemplateFile(c.LLM.PromptTemplateFile)...)
+		}
 	}
 
 	// Validate concurrency configuration
@@ -223,16 +382,39 @@ func (c *Config) Validate() error {
 		errs = append(errs, ValidationError{Field: "concurrency.batch_size", Message: "must be at least 1"})
 	}
 
+	if c.Concurrency.VersionFetchConcurrency < 0 {
+		errs = append(errs, ValidationError{Field: "concurrency.version_fetch_concurrency", Message: "cannot be negative"})
+	}
+	if c.Concurrency.FollowupConcurrency < 0 {
+		errs = append(errs, ValidationError{Field: "concurrency.followup_concurrency", Message: "cannot be negative"})
+	}
+
 	if c.Concurrency.RetryAttempts < 0 {
 		errs = append(errs, ValidationError{Field: "concurrency.retry_attempts", Message: "cannot be negative"})
 	}
 
+	if c.Checkpoint.ContinueFrom != "" {
+		registry, schema, ok := strings.Cut(c.Checkpoint.ContinueFrom, ":")
+		if !ok || registry == "" || schema == "" {
+			errs = append(errs, ValidationError{
+				Field:   "checkpoint.continue_from",
+				Message: "must be a registry:schema key",
+			})
+		}
+	}
+	if c.Checkpoint.RetryFailed && !c.Checkpoint.Resume {
+		errs = append(errs, ValidationError{
+			Field:   "checkpoint.retry_failed",
+			Message: "requires checkpoint.resume, since the failures are read from the checkpoint",
+		})
+	}
+
 	// Validate output configuration
-	validFormats := map[string]bool{"table": true, "json": true, "csv": true}
+	validFormats := map[string]bool{"table": true, "json": true, "csv": true, "html": true}
 	if !validFormats[c.Output.Format] {
 		errs = append(errs, ValidationError{
 			Field:   "output.format",
-			Message: "must be one of: table, json, csv",
+			Message: "must be one of: table, json, csv, html",
 		})
 	}
 
@@ -259,19 +441,21 @@ type nameMappingFile struct {
 }
 
 type nameMappingExtended struct {
-	Source  string `yaml:"source"`
-	Subject string `yaml:"subject"`
-	Role    string `yaml:"role"`
-	Context string `yaml:"context"`
+	Source       string `yaml:"source"`
+	Subject      string `yaml:"subject"`
+	Role         string `yaml:"role"`
+	Context      string `yaml:"context"`
+	KeySubject   string `yaml:"key_subject"`
+	ValueSubject string `yaml:"value_subject"`
 }
 
-func validateNameMappingFile(path string) ValidationErrors {
+func validateNameMappingFile(field, path string) ValidationErrors {
 	var errs ValidationErrors
 
 	data, err := os.ReadFile(path)
 	if err != nil {
 		errs = append(errs, ValidationError{
-			Field:   "naming.name_mapping_file",
+			Field:   field,
 			Message: fmt.Sprintf("cannot read file: %v", err),
 		})
 		return errs
@@ -280,7 +464,7 @@ func validateNameMappingFile(path string) ValidationErrors {
 	var file nameMappingFile
 	if err := yaml.Unmarshal(data, &file); err != nil {
 		errs = append(errs, ValidationError{
-			Field:   "naming.name_mapping_file",
+			Field:   field,
 			Message: fmt.Sprintf("invalid YAML: %v", err),
 		})
 		return errs
@@ -293,13 +477,13 @@ func validateNameMappingFile(path string) ValidationErrors {
 	for source, subject := range file.Mappings {
 		if subject == "" {
 			errs = append(errs, ValidationError{
-				Field:   "naming.name_mapping_file",
+				Field:   field,
 				Message: fmt.Sprintf("empty subject for mapping %q", source),
 			})
 		}
 		if seen[source] {
 			errs = append(errs, ValidationError{
-				Field:   "naming.name_mapping_file",
+				Field:   field,
 				Message: fmt.Sprintf("duplicate source %q", source),
 			})
 		}
@@ -310,19 +494,19 @@ func validateNameMappingFile(path string) ValidationErrors {
 	for source, subject := range file.QualifiedMappings {
 		if subject == "" {
 			errs = append(errs, ValidationError{
-				Field:   "naming.name_mapping_file",
+				Field:   field,
 				Message: fmt.Sprintf("empty subject for qualified mapping %q", source),
 			})
 		}
 		if !strings.Contains(source, ":") {
 			errs = append(errs, ValidationError{
-				Field:   "naming.name_mapping_file",
+				Field:   field,
 				Message: fmt.Sprintf("qualified mapping %q must contain ':' (registry:schema)", source),
 			})
 		}
 		if seen[source] {
 			errs = append(errs, ValidationError{
-				Field:   "naming.name_mapping_file",
+				Field:   field,
 				Message: fmt.Sprintf("duplicate source %q", source),
 			})
 		}
@@ -333,25 +517,25 @@ func validateNameMappingFile(path string) ValidationErrors {
 	for i, ext := range file.ExtendedMappings {
 		if ext.Source == "" {
 			errs = append(errs, ValidationError{
-				Field:   "naming.name_mapping_file",
+				Field:   field,
 				Message: fmt.Sprintf("extended_mappings[%d]: source is required", i),
 			})
 		}
-		if ext.Subject == "" {
+		if ext.Subject == "" && ext.KeySubject == "" && ext.ValueSubject == "" {
 			errs = append(errs, ValidationError{
-				Field:   "naming.name_mapping_file",
-				Message: fmt.Sprintf("extended_mappings[%d]: subject is required", i),
+				Field:   field,
+				Message: fmt.Sprintf("extended_mappings[%d]: subject, key_subject or value_subject is required", i),
 			})
 		}
 		if ext.Role != "" && !validRoles[ext.Role] {
 			errs = append(errs, ValidationError{
-				Field:   "naming.name_mapping_file",
+				Field:   field,
 				Message: fmt.Sprintf("extended_mappings[%d]: role must be 'key' or 'value', got %q", i, ext.Role),
 			})
 		}
 		if ext.Source != "" && seen[ext.Source] {
 			errs = append(errs, ValidationError{
-				Field:   "naming.name_mapping_file",
+				Field:   field,
 				Message: fmt.Sprintf("duplicate source %q in extended_mappings[%d]", ext.Source, i),
 			})
 		}
@@ -363,6 +547,23 @@ func validateNameMappingFile(path string) ValidationErrors {
 	return errs
 }
 
+// validatePromptTemplateFile checks that the prompt template parses and
+// refers only to fields of the data the namer renders it with
+func validatePromptTemplateFile(path string) ValidationErrors {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ValidationErrors{{Field: "llm.prompt_template_file", Message: fmt.Sprintf("cannot read file: %v", err)}}
+	}
+	tmpl, err := template.New("prompt").Parse(string(data))
+	if err != nil {
+		return ValidationErrors{{Field: "llm.prompt_template_file", Message: fmt.Sprintf("invalid template: %v", err)}}
+	}
+	if err := tmplcheck.Fields(tmpl, models.PromptData{}); err != nil {
+		return ValidationErrors{{Field: "llm.prompt_template_file", Message: fmt.Sprintf("invalid template: %v", err)}}
+	}
+	return nil
+}
+
 func validateContextMappingFile(path string) ValidationErrors {
 	var errs ValidationErrors
 
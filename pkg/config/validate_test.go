@@ -1,6 +1,8 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -47,6 +49,13 @@ func TestValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "topic-record strategy passes",
+			modify: func(cfg *Config) {
+				cfg.Naming.SubjectStrategy = "topic-record"
+			},
+			wantErr: false,
+		},
 		{
 			name: "custom strategy without template fails",
 			modify: func(cfg *Config) {
@@ -90,6 +99,27 @@ func TestValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "failure threshold percentage passes",
+			modify: func(cfg *Config) {
+				cfg.Migration.AbortAfterFailures = "10%"
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid failure threshold fails",
+			modify: func(cfg *Config) {
+				cfg.Migration.AbortAfterFailures = "many"
+			},
+			wantErr: true,
+		},
+		{
+			name: "failure threshold over 100% fails",
+			modify: func(cfg *Config) {
+				cfg.Migration.AbortAfterFailures = "150%"
+			},
+			wantErr: true,
+		},
 		{
 			name: "workers less than 1 fails",
 			modify: func(cfg *Config) {
@@ -119,6 +149,103 @@ func TestValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "azure llm provider without base url fails",
+			modify: func(cfg *Config) {
+				cfg.Naming.SubjectStrategy = "llm"
+				cfg.LLM.Provider = "azure"
+				cfg.LLM.APIKey = "key"
+				cfg.LLM.BaseURL = ""
+			},
+			wantErr: true,
+		},
+		{
+			name: "azure llm provider with base url passes",
+			modify: func(cfg *Config) {
+				cfg.Naming.SubjectStrategy = "llm"
+				cfg.LLM.Provider = "azure"
+				cfg.LLM.APIKey = "key"
+				cfg.LLM.BaseURL = "https://my-resource.openai.azure.com"
+			},
+			wantErr: false,
+		},
+		{
+			name: "missing llm prompt template fails",
+			modify: func(cfg *Config) {
+				cfg.Naming.SubjectStrategy = "llm"
+				cfg.LLM.Provider = "ollama"
+				cfg.LLM.BaseURL = "http://localhost:11434"
+				cfg.LLM.PromptTemplateFile = "/nonexistent/prompt.tmpl"
+			},
+			wantErr: true,
+		},
+		{
+			name: "basic auth without api key fails",
+			modify: func(cfg *Config) {
+				cfg.Output.DryRun = false
+				cfg.ConfluentCloud.URL = "https://psrc-xxx.confluent.cloud"
+				cfg.ConfluentCloud.APISecret = "secret"
+			},
+			wantErr: true,
+		},
+		{
+			name: "bearer auth without api key passes",
+			modify: func(cfg *Config) {
+				cfg.Output.DryRun = false
+				cfg.ConfluentCloud.URL = "https://psrc-xxx.confluent.cloud"
+				cfg.ConfluentCloud.AuthType = "bearer"
+				cfg.ConfluentCloud.BearerToken = "token"
+			},
+			wantErr: false,
+		},
+		{
+			name: "bearer auth without token fails",
+			modify: func(cfg *Config) {
+				cfg.Output.DryRun = false
+				cfg.ConfluentCloud.URL = "https://psrc-xxx.confluent.cloud"
+				cfg.ConfluentCloud.AuthType = "bearer"
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid auth type fails",
+			modify: func(cfg *Config) {
+				cfg.Output.DryRun = false
+				cfg.ConfluentCloud.URL = "https://psrc-xxx.confluent.cloud"
+				cfg.ConfluentCloud.AuthType = "mtls"
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid schema exclude pattern fails",
+			modify: func(cfg *Config) {
+				cfg.AWS.SchemaExclude = []string{"order-[a"}
+			},
+			wantErr: true,
+		},
+		{
+			name: "retry failed without resume fails",
+			modify: func(cfg *Config) {
+				cfg.Checkpoint.RetryFailed = true
+			},
+			wantErr: true,
+		},
+		{
+			name: "parallel versions without preserve versions fails",
+			modify: func(cfg *Config) {
+				cfg.Migration.ParallelVersions = true
+			},
+			wantErr: true,
+		},
+		{
+			name: "parallel versions with preserve versions passes",
+			modify: func(cfg *Config) {
+				cfg.Migration.ImportMode = true
+				cfg.Migration.PreserveVersions = true
+				cfg.Migration.ParallelVersions = true
+			},
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -133,3 +260,109 @@ func TestValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestValidate_NameMappingFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		wantErr  bool
+	}{
+		{
+			name: "subject passes",
+			contents: `
+extended_mappings:
+  - source: "OrderId"
+    subject: "orders-key"
+    role: "key"
+`,
+			wantErr: false,
+		},
+		{
+			name: "key and value subjects pass",
+			contents: `
+extended_mappings:
+  - source: "OrderId"
+    key_subject: "orders-key"
+    value_subject: "orders-value"
+`,
+			wantErr: false,
+		},
+		{
+			name: "key subject alone passes",
+			contents: `
+extended_mappings:
+  - source: "CustomerId"
+    key_subject: "customers-key"
+`,
+			wantErr: false,
+		},
+		{
+			name: "no subject fails",
+			contents: `
+extended_mappings:
+  - source: "OrderId"
+    role: "key"
+`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "mappings.yaml")
+			if err := os.WriteFile(path, []byte(tt.contents), 0644); err != nil {
+				t.Fatalf("failed to write mapping file: %v", err)
+			}
+			cfg := validConfig()
+			cfg.Naming.NameMappingFile = path
+
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_PromptTemplateFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		wantErr  bool
+	}{
+		{
+			name:     "known fields pass",
+			contents: "{{.GlueSchemaName}} {{if .KeyFields}}{{range .KeyFields}}{{.}} {{end}}{{end}}{{.Role}}",
+			wantErr:  false,
+		},
+		{
+			name:     "unknown field in an unreached branch fails",
+			contents: "{{.GlueSchemaName}} {{if .Documentation}}{{.Docs}}{{end}}",
+			wantErr:  true,
+		},
+		{
+			name:     "unknown field inside range fails",
+			contents: "{{range .References}}{{.Name}}{{end}}",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "prompt.tmpl")
+			if err := os.WriteFile(path, []byte(tt.contents), 0644); err != nil {
+				t.Fatalf("failed to write prompt template: %v", err)
+			}
+			cfg := validConfig()
+			cfg.Naming.SubjectStrategy = "llm"
+			cfg.LLM.Provider = "ollama"
+			cfg.LLM.BaseURL = "http://localhost:11434"
+			cfg.LLM.PromptTemplateFile = path
+
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}